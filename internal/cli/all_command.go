@@ -1,19 +1,25 @@
 package cli
 
-import "coastal-geometry/internal/domain/coastline"
+import (
+	"coastal-geometry/pkg/fraes"
+	"os"
+)
 
 func runAllCommand(app *App) error {
 	invalid := false
 
-	sanity := coastline.MainCalculation(app.Base, app.Dataset, app.DataSource)
-	if sanity.Checked && !sanity.Valid {
+	report := fraes.BuildCoastlineReport(app.Base, app.Dataset, app.DataSource, app.Distance)
+	renderCoastlineReport(os.Stdout, report)
+	if sanity := report.Sanity; sanity.Checked && !sanity.Valid {
 		invalid = true
 	}
 	if err := writeCoastlineSVG(app.Base, app.RenderBase, app.Config.OutputPath, "coastline.svg", newExportContext(app)); err != nil {
 		return err
 	}
 
-	runParadoxCommand(app)
+	if err := runParadoxCommand(app); err != nil {
+		return err
+	}
 
 	// Классическая фрактальная аппроксимация (Koch)
 	if err := writeKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, app.Config.ErosionStrength, app.Config.Seed, newExportContext(app)); err != nil {
@@ -21,7 +27,10 @@ func runAllCommand(app *App) error {
 	}
 
 	// Органическая фрактальная модель
-	runKochOrganicMetrics(app.ModelBase, app.Config.Iterations, organicKochOptions(app))
+	organicReport := runKochOrganicMetrics(app.ModelBase, app.Config.Iterations, organicKochOptions(app))
+	if err := writeDataTable(organicTable("koch-organic", organicReport), app.Config.OutputPath, newExportContext(app)); err != nil {
+		return err
+	}
 	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, organicKochOptions(app), app.Config.ErosionStrength, "koch-organic_iter", "koch-organic", false, newExportContext(app)); err != nil {
 		return err
 	}
@@ -31,13 +40,19 @@ func runAllCommand(app *App) error {
 		return err
 	}
 
-	assessment, err := runDimensionMetrics(app.ModelBase, app.Config.Iterations, organicKochOptions(app))
+	assessment, err := runDimensionMetrics(app.ModelBase, app.Config.Iterations, organicKochOptions(app), app.Projection, dimensionRunOptions{
+		Estimators: []string{fraes.EstimatorBox},
+		Bootstrap:  app.Config.Bootstrap,
+	})
 	if err != nil {
 		return err
 	}
 	if !assessment.Valid {
 		invalid = true
 	}
+	if err := writeDataTable(dimensionTable(assessment), app.Config.OutputPath, newExportContext(app)); err != nil {
+		return err
+	}
 	if invalid {
 		printInvalidResult()
 	}
@@ -1,34 +1,52 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/lithology"
+	"coastal-geometry/internal/domain/simulations/scenario"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
 )
 
 type App struct {
 	Config           config
-	Base             []geometry.LatLon
-	RenderBase       []geometry.LatLon
-	ModelBase        []geometry.LatLon
-	Validation       coastline.ValidationReport
+	Base             []fraes.LatLon
+	Coastline        fraes.Coastline
+	RenderBase       []fraes.LatLon
+	ModelBase        []fraes.LatLon
+	Lithology        lithology.Profile
+	Rocks            lithology.Assignment
+	Scenario         *scenario.Scenario
+	Validation       fraes.ValidationReport
+	Distance         fraes.Distance
+	Area             fraes.AreaMeasure
+	Projection       fraes.Projector
 	DataSource       string
 	Dataset          string
 	LoadNotes        []string
 	ProcessNotes     []string
-	SourceInspection *coastline.SourceInspection
+	SourceInspection *fraes.SourceInspection
 }
 
 func NewApp(cfg config) (*App, error) {
 	app := &App{Config: cfg}
 	setCurrentConfig(cfg)
 
+	distance, err := geodesicDistance(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.Distance = distance
+	if app.Area, err = areaMeasure(cfg); err != nil {
+		return nil, err
+	}
+
 	if cfg.Command == cmdSource {
-		inspection, err := coastline.InspectSource(coastline.InspectOptions{
-			LocalPath:    cfg.InputPath,
-			RemoteURL:    cfg.SourceURL,
-			SnapshotPath: cfg.OutputPath,
-			Refresh:      cfg.Refresh,
-		})
+		inspection, err := fraes.InspectSource(
+			fraes.WithLocalPath(cfg.InputPath),
+			fraes.WithRemoteURL(cfg.SourceURL),
+			fraes.WithSnapshotPath(cfg.OutputPath),
+			fraes.WithRefresh(cfg.Refresh),
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -40,25 +58,51 @@ func NewApp(cfg config) (*App, error) {
 	}
 
 	if commandNeedsCoastline(cfg.Command) {
-		result, err := coastline.Load(coastline.LoadOptions{
-			LocalPath: cfg.InputPath,
-			RemoteURL: cfg.SourceURL,
-			Refresh:   cfg.Refresh,
-		})
+		result, err := fraes.Load(
+			fraes.WithLocalPath(cfg.InputPath),
+			fraes.WithRemoteURL(cfg.SourceURL),
+			fraes.WithRefresh(cfg.Refresh),
+		)
 		if err != nil {
 			return nil, err
 		}
 		app.Base = result.Points
+		app.Coastline = result.Coastline
 		app.Validation = result.Validation
 		app.DataSource = result.Source
 		app.Dataset = result.DatasetName
 		app.LoadNotes = result.LoadWarnings
 
-		views := prepareGeometryViews(app.Base, cfg.Command, cfg.Iterations)
+		// One projection, centred on every ring, serves the whole run.
+		rings := append([][]fraes.LatLon{app.Base}, app.Coastline.RingPoints()...)
+		if app.Projection, err = fraes.NewProjection(cfg.Projection, rings...); err != nil {
+			return nil, err
+		}
+
+		views := prepareGeometryViews(app.Base, cfg.Command, cfg.Iterations, app.Projection)
 		app.RenderBase = views.RenderBase
 		app.ModelBase = views.ModelBase
 		app.ProcessNotes = views.ProcessInfo
 	}
 
+	if cfg.LithologyPath != "" {
+		profile, err := lithology.Load(cfg.LithologyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load lithology: %w", err)
+		}
+		rocks, warnings := profile.Resolve(app.ModelBase)
+		app.Lithology = profile
+		app.Rocks = rocks
+		app.LoadNotes = append(app.LoadNotes, warnings...)
+	}
+
+	if cfg.ScenarioPath != "" {
+		loaded, err := scenario.Load(cfg.ScenarioPath)
+		if err != nil {
+			return nil, fmt.Errorf("load scenario: %w", err)
+		}
+		app.Scenario = &loaded
+	}
+
 	return app, nil
 }
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/simulations/calibration"
+	svgrender "coastal-geometry/internal/render/svg"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func runCalibrateCommand(app *App) error {
+	opts, err := calibrationOptions(app.Config)
+	if err != nil {
+		return err
+	}
+	refs, err := calibration.References(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Калибровка: эталонов %d, оценщиков %d, ориентаций сетки %d, до %d точек на кривую\n", len(refs), len(opts.Estimators), opts.Rotations, opts.MaxPoints)
+	opts.Progress = func(done, total int, reference string, iteration int) {
+		fmt.Printf("[%d/%d] %s, итерация %d\n", done, total, reference, iteration)
+	}
+	result, err := calibration.Run(opts)
+	if err != nil {
+		return err
+	}
+
+	renderCalibrationReport(os.Stdout, result)
+
+	ctx := newExportContext(app)
+	svgFile, err := writeCalibrationChart(result, app.Config.OutputPath)
+	if err != nil {
+		return err
+	}
+	if err := writeCalibrationMetrics(result, svgFile, app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if err := writeDataTable(calibrationTable(result), app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	return writeDataTable(calibrationSummaryTable(result), app.Config.OutputPath, ctx)
+}
+
+func validateCalibrationConfig(cfg config) error {
+	if cfg.Rotations < 1 || cfg.Rotations > calibration.MaxRotations {
+		return fmt.Errorf("rotations must be between 1 and %d", calibration.MaxRotations)
+	}
+	if cfg.MaxPoints < 1 {
+		return fmt.Errorf("max-points must be positive")
+	}
+	if !(cfg.Tolerance > 0) {
+		return fmt.Errorf("tolerance must be positive")
+	}
+	if cfg.Workers < 0 {
+		return fmt.Errorf("workers must be non-negative")
+	}
+	_, err := calibrationOptions(cfg)
+	return err
+}
+
+// calibrationOptions reads the reference and estimator selection; "all" and
+// "none" pick every generator or none of them.
+func calibrationOptions(cfg config) (calibration.Options, error) {
+	estimators, err := dimensionEstimators(cfg)
+	if err != nil {
+		return calibration.Options{}, err
+	}
+	opts := calibration.Options{
+		Estimators: estimators,
+		Rotations:  cfg.Rotations,
+		MaxPoints:  cfg.MaxPoints,
+		Tolerance:  cfg.Tolerance,
+		Seed:       cfg.Seed,
+		Workers:    cfg.Workers,
+	}
+
+	switch spec := strings.TrimSpace(cfg.Generators); spec {
+	case "", "all":
+	case "none":
+		opts.Generators = []string{}
+	default:
+		for _, name := range strings.Split(spec, ",") {
+			opts.Generators = append(opts.Generators, strings.TrimSpace(name))
+		}
+	}
+
+	switch spec := strings.TrimSpace(cfg.Hursts); spec {
+	case "":
+	case "none":
+		opts.Hursts = []float64{}
+	default:
+		for _, part := range strings.Split(spec, ",") {
+			h, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return calibration.Options{}, fmt.Errorf("hurst %q: %q is not a number", spec, part)
+			}
+			opts.Hursts = append(opts.Hursts, h)
+		}
+	}
+	return opts.Normalize()
+}
+
+func formatHursts(hursts []float64) string {
+	parts := make([]string, len(hursts))
+	for i, h := range hursts {
+		parts[i] = strconv.FormatFloat(h, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// calibrationRanking orders the estimators by RMSE at the deepest levels;
+// estimators without a valid estimate come last.
+func calibrationRanking(result calibration.Result) []calibration.EstimatorSummary {
+	ranking := slices.Clone(result.Estimators)
+	slices.SortStableFunc(ranking, func(a, b calibration.EstimatorSummary) int {
+		switch {
+		case math.IsNaN(a.RMSE) && math.IsNaN(b.RMSE):
+			return 0
+		case math.IsNaN(a.RMSE):
+			return 1
+		case math.IsNaN(b.RMSE):
+			return -1
+		case a.RMSE < b.RMSE:
+			return -1
+		case a.RMSE > b.RMSE:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return ranking
+}
+
+func formatCalibrationIteration(iteration int) string {
+	if iteration == 0 {
+		return "нет"
+	}
+	return fmt.Sprintf("с %d", iteration)
+}
+
+func formatCalibrationValue(v float64, format string) string {
+	if math.IsNaN(v) {
+		return "n/a"
+	}
+	return fmt.Sprintf(format, v)
+}
+
+func renderCalibrationReport(w io.Writer, result calibration.Result) {
+	opts := result.Options
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintf(w, "\tКАЛИБРОВКА ОЦЕНЩИКОВ РАЗМЕРНОСТИ: ЭТАЛОНОВ %d, ОЦЕНЩИКОВ %d\n", len(result.References), len(opts.Estimators))
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Ориентаций сетки: %d (шаг %g°), до %d точек, допуск |смещение| ≤ %.3f, seed fBm %d\n", opts.Rotations, 90/float64(opts.Rotations), opts.MaxPoints, opts.Tolerance, opts.Seed)
+	fmt.Fprintln(w, "Смещение и RMSE — на самой глубокой итерации; «сходится» — итерация, с которой |смещение| в допуске,")
+	fmt.Fprintf(w, "«стабильна» — с которой среднее меняется не больше чем на %.2f за итерацию.\n\n", calibration.SettleDelta)
+
+	fmt.Fprintf(w, "%-12s %-8s %-13s %-6s %-7s %-8s %-9s %-8s %-9s %-9s\n", "Эталон", "D ист.", "Оценщик", "Итер.", "Точек", "Оценка", "Смещение", "RMSE", "Сходится", "Стабильна")
+	fmt.Fprintln(w, strings.Repeat("-", 98))
+	for _, s := range result.Summaries {
+		deepest := calibration.Level{Mean: math.NaN()}
+		if len(s.Levels) > 0 {
+			deepest = s.Levels[len(s.Levels)-1]
+		}
+		fmt.Fprintf(w, "%-12s %-8.4f %-13s %-6d %-7d %-8s %-9s %-8s %-9s %-9s\n",
+			s.Reference, s.TrueDimension, s.Estimator, deepest.Iteration, deepest.Points,
+			formatCalibrationValue(deepest.Mean, "%.4f"), formatCalibrationValue(s.Bias, "%+.4f"), formatCalibrationValue(s.RMSE, "%.4f"),
+			formatCalibrationIteration(s.ConvergedAt), formatCalibrationIteration(s.SettledAt))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 98))
+
+	fmt.Fprintln(w, "Рейтинг оценщиков по RMSE на самой глубокой итерации каждого эталона:")
+	for i, rank := range calibrationRanking(result) {
+		fmt.Fprintf(w, "  %d. %-13s RMSE %s, смещение %s, |смещение| %s, сошлись %d из %d\n", i+1, rank.Estimator,
+			formatCalibrationValue(rank.RMSE, "%.4f"), formatCalibrationValue(rank.MeanBias, "%+.4f"), formatCalibrationValue(rank.MeanAbsBias, "%.4f"),
+			rank.Converged, rank.References)
+	}
+}
+
+// writeCalibrationChart draws calibration.svg: the mean estimate at the
+// deepest level of every reference against its true dimension, one series
+// per estimator.
+func writeCalibrationChart(result calibration.Result, output string) (string, error) {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return "", err
+	}
+
+	opts := result.Options
+	scatter := svgrender.Scatter{
+		Title:    "Калибровка оценщиков: оценка D против истинной",
+		Subtitle: fmt.Sprintf("Эталонов %d, оценщиков %d; точка — среднее по %d ориентациям сетки на самой глубокой итерации, усы — разброс между ориентациями", len(result.References), len(opts.Estimators), opts.Rotations),
+		XLabel:   "Истинная D",
+		YLabel:   "Оценка D",
+		Diagonal: true,
+	}
+	for _, name := range opts.Estimators {
+		series := svgrender.ScatterSeries{Label: name}
+		for _, s := range result.Summaries {
+			if s.Estimator != name || len(s.Levels) == 0 {
+				continue
+			}
+			deepest := s.Levels[len(s.Levels)-1]
+			series.X = append(series.X, s.TrueDimension)
+			series.Y = append(series.Y, deepest.Mean)
+			series.Err = append(series.Err, deepest.StdDev)
+		}
+		scatter.Series = append(scatter.Series, series)
+	}
+
+	ranking := svgrender.StatCard{Title: "Рейтинг по RMSE"}
+	for _, rank := range calibrationRanking(result) {
+		ranking.Items = append(ranking.Items, svgrender.StatItem{
+			Label: fmt.Sprintf("%s, сошлись %d из %d", rank.Estimator, rank.Converged, rank.References),
+			Value: formatCalibrationValue(rank.RMSE, "%.4f"),
+		})
+	}
+	scatter.StatCards = []svgrender.StatCard{ranking}
+
+	scatter.Meta = []string{
+		fmt.Sprintf("Допуск сходимости: |смещение| ≤ %.3f", opts.Tolerance),
+		fmt.Sprintf("До %d точек на кривую, seed fBm %d", opts.MaxPoints, opts.Seed),
+	}
+	for _, ref := range result.References {
+		scatter.Meta = append(scatter.Meta, fmt.Sprintf("%s: D = %.4f, итераций %d", ref.Name, ref.TrueDimension, ref.MaxIteration))
+	}
+
+	filename := filepath.Join(outputDir, "calibration.svg")
+	if err := svgrender.DrawScatter(scatter, filename); err != nil {
+		return "", err
+	}
+	fmt.Printf("SVG saved to %s\n", filename)
+	return filename, nil
+}
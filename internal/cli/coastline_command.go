@@ -1,10 +1,15 @@
 package cli
 
-import "coastal-geometry/internal/domain/coastline"
+import (
+	"coastal-geometry/pkg/fraes"
+	"os"
+)
 
 func runCoastlineCommand(app *App) error {
-	sanity := coastline.MainCalculation(app.Base, app.Dataset, app.DataSource)
-	if sanity.Checked && !sanity.Valid {
+	report := fraes.BuildCoastlineReport(app.Base, app.Dataset, app.DataSource, app.Distance)
+	renderCoastlineReport(os.Stdout, report)
+	renderCoastlineParts(os.Stdout, app.Coastline, app.Distance, app.Area)
+	if sanity := report.Sanity; sanity.Checked && !sanity.Valid {
 		printInvalidResult()
 	}
 	return writeCoastlineSVG(app.Base, app.RenderBase, app.Config.OutputPath, "coastline.svg", newExportContext(app))
@@ -8,16 +8,28 @@ func executeCommand(app *App) error {
 		return runAllCommand(app)
 	case cmdCoastline:
 		return runCoastlineCommand(app)
+	case cmdRichardson:
+		return runRichardsonCommand(app)
 	case cmdParadox:
 		return runParadoxCommand(app)
 	case cmdKoch:
 		return runKochCommand(app)
 	case cmdKochOrganic:
 		return runKochOrganicCommand(app)
+	case cmdFBM:
+		return runFBMCommand(app)
+	case cmdGenerate:
+		return runGenerateCommand(app)
 	case cmdDimension:
 		return runDimensionCommand(app)
 	case cmdErosion:
 		return runErosionCommand(app)
+	case cmdSweep:
+		return runSweepCommand(app)
+	case cmdCalibrate:
+		return runCalibrateCommand(app)
+	case cmdFit:
+		return runFitCommand(app)
 	default:
 		return errUnsupportedCommand(app.Config.Command)
 	}
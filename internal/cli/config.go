@@ -1,11 +1,18 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/simulations/calibration"
+	"coastal-geometry/internal/domain/simulations/ensemble"
+	"coastal-geometry/internal/domain/simulations/erosion"
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/internal/domain/simulations/percolation"
+	"coastal-geometry/internal/domain/simulations/sweep"
+	"coastal-geometry/pkg/fraes"
 	"flag"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 )
 
@@ -21,20 +28,79 @@ const (
 	cmdKochOrganic   = "koch-organic"
 	cmdDimension     = "dimension"
 	cmdErosion       = "erosion"
+	cmdRichardson    = "richardson"
+	cmdSweep         = "sweep"
+	cmdFBM           = "fbm"
+	cmdGenerate      = "generate"
+	cmdCalibrate     = "calibrate"
+	cmdFit           = "fit"
+
+	erosionModelGaussian    = "gaussian"
+	erosionModelWave        = "wave"
+	erosionModelPercolation = "percolation"
+
+	sweepPipelineOrganic = "organic"
+	sweepPipelineErosion = "erosion"
 )
 
 type config struct {
-	Command         string
-	InputPath       string
-	SourceURL       string
-	Refresh         bool
-	OutputPath      string
-	Iterations      int
-	Steps           int
-	Seed            int64
-	AngleJitter     float64
-	HeightJitter    float64
+	Command      string
+	InputPath    string
+	SourceURL    string
+	Refresh      bool
+	OutputPath   string
+	Iterations   int
+	Steps        int
+	Seed         int64
+	AngleJitter  float64
+	HeightJitter float64
+	Hurst        float64
+	Amplitude    float64
+	Generator    string
+	CesaroAngle  float64
+	// Generators and Hursts list the reference curves of the calibrate
+	// command.
+	Generators      string
+	Hursts          string
+	Rotations       int
+	MaxPoints       int
+	Tolerance       float64
 	ErosionStrength float64
+	ErosionModel    string
+	WaveClimate     string
+	LithologyPath   string
+	Sediment        bool
+	SedimentRate    float64
+	ScenarioPath    string
+	SeaForce        float64
+	SeaDamping      float64
+	GridCells       int
+	Animate         bool
+	ExportGeometry  string
+	Estimator       string
+	Bootstrap       int
+	Ensemble        int
+	EnsembleWorkers int
+	QRange          string
+	Pipeline        string
+	Sampling        string
+	Samples         int
+	Workers         int
+	Plane           string
+	Fresh           bool
+	// Method, Evaluations, Seeds and Weights configure the fit command.
+	Method      string
+	Evaluations int
+	Seeds       int
+	Weights     string
+	// Ranges holds the parameter ranges of the sweep command and the search
+	// bounds of the fit command by flag name.
+	Ranges          map[string]string
+	Format          string
+	Geodesic        string
+	Ellipsoid       string
+	Area            string
+	Projection      string
 	ModelMaxPoints  int
 	DisableSimplify bool
 }
@@ -61,84 +127,238 @@ func parseConfig(args []string, stdout, stderr io.Writer) (config, error) {
 
 	switch command {
 	case cmdSource:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before saving a snapshot")
 		fs.StringVar(&cfg.OutputPath, "output", "", "snapshot file or directory (default: ./data/snapshots)")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdAll:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
-		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", koch.MaxIterations))
+		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", fraes.MaxKochIterations))
 		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for organic coastline generation")
 		fs.Float64Var(&cfg.AngleJitter, "angle-jitter", 18, "maximum random angle deviation in degrees")
 		fs.Float64Var(&cfg.HeightJitter, "height-jitter", 0.25, "maximum random height deviation as a ratio")
 		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
 		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
 		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		addBootstrapFlag(fs, &cfg)
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Geodesic, "geodesic", fraes.GeodesicHaversine, "distance for the coastline length: haversine (sphere), vincenty or karney (ellipsoid)")
+		fs.StringVar(&cfg.Ellipsoid, "ellipsoid", fraes.WGS84().Name, "reference ellipsoid for vincenty, karney and the ellipsoidal area: WGS84, GRS80 or Krassovsky1940")
+		fs.StringVar(&cfg.Area, "area", fraes.AreaEllipsoidal, "polygon area: ellipsoidal (geodesic, on --ellipsoid), spherical (spherical excess) or planar (local grid, for comparison)")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdCoastline:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output SVG path or directory (default: ./output)")
+		fs.StringVar(&cfg.Geodesic, "geodesic", fraes.GeodesicHaversine, "distance for the coastline length: haversine (sphere), vincenty or karney (ellipsoid)")
+		fs.StringVar(&cfg.Ellipsoid, "ellipsoid", fraes.WGS84().Name, "reference ellipsoid for vincenty, karney and the ellipsoidal area: WGS84, GRS80 or Krassovsky1940")
+		fs.StringVar(&cfg.Area, "area", fraes.AreaEllipsoidal, "polygon area: ellipsoidal (geodesic, on --ellipsoid), spherical (spherical excess) or planar (local grid, for comparison)")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdRichardson:
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
+		fs.StringVar(&cfg.OutputPath, "output", "", "output SVG path or directory (default: ./output)")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdParadox:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
-		fs.IntVar(&cfg.Iterations, "iterations", 4, fmt.Sprintf("maximum paradox detail levels (0-%d)", koch.MaxIterations))
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for metrics tables (default: ./output)")
+		fs.IntVar(&cfg.Iterations, "iterations", 4, fmt.Sprintf("maximum paradox detail levels (0-%d)", fraes.MaxKochIterations))
 		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for paradox erosion/randomness")
 		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
 		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
 		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		fs.IntVar(&cfg.Ensemble, "ensemble", 0, "run the model for N consecutive seeds starting at --seed and summarize length, area and dimension per iteration or step (0 disables, otherwise at least 2)")
+		fs.IntVar(&cfg.EnsembleWorkers, "ensemble-workers", 0, "ensemble runs computed at once (0 uses every CPU)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdKoch:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
-		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum Koch iterations (0-%d)", koch.MaxIterations))
+		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum Koch iterations (0-%d)", fraes.MaxKochIterations))
 		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
 		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
 		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdKochOrganic:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
-		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", koch.MaxIterations))
+		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", fraes.MaxKochIterations))
 		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for organic coastline generation")
 		fs.Float64Var(&cfg.AngleJitter, "angle-jitter", 18, "maximum random angle deviation in degrees")
 		fs.Float64Var(&cfg.HeightJitter, "height-jitter", 0.25, "maximum random height deviation as a ratio")
 		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
 		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
 		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		addBootstrapFlag(fs, &cfg)
+		fs.IntVar(&cfg.Ensemble, "ensemble", 0, "run the model for N consecutive seeds starting at --seed and summarize length, area and dimension per iteration or step (0 disables, otherwise at least 2)")
+		fs.IntVar(&cfg.EnsembleWorkers, "ensemble-workers", 0, "ensemble runs computed at once (0 uses every CPU)")
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdFBM:
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
+		fs.IntVar(&cfg.Iterations, "iterations", 8, fmt.Sprintf("midpoint displacement iterations, each doubles the segments (0-%d)", fbm.MaxIterations))
+		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for the fBm displacements")
+		fs.Float64Var(&cfg.Hurst, "hurst", fbm.DefaultHurst, "Hurst exponent H in (0, 1); the target dimension is D = 2 - H")
+		fs.Float64Var(&cfg.Amplitude, "amplitude", fbm.DefaultAmplitude, "standard deviation of the first midpoint displacement as a share of the base segment length")
+		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
+		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
+		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		addBootstrapFlag(fs, &cfg)
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdGenerate:
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
+		fs.StringVar(&cfg.Generator, "generator", fraes.GeneratorKoch, "deterministic curve: "+strings.Join(fraes.GeneratorNames(), ", "))
+		fs.Float64Var(&cfg.CesaroAngle, "cesaro-angle", fraes.DefaultCesaroAngle, "base angle of the cesaro spike in degrees, in (0, 90); 60 gives the Koch curve")
+		fs.IntVar(&cfg.Iterations, "iterations", 4, "generator iterations (0 up to a per-generator limit: 10 for 4 motif segments, 4 for 32)")
+		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
+		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
+		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		addBootstrapFlag(fs, &cfg)
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdCalibrate:
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for the calibration tables, chart and metrics (default: ./output)")
+		fs.StringVar(&cfg.Generators, "generators", "all", "deterministic reference curves, comma separated, all or none: "+strings.Join(fraes.GeneratorNames(), ", "))
+		fs.StringVar(&cfg.Hursts, "hurst", formatHursts(calibration.DefaultHursts), "Hurst exponents of the fBm reference curves in (0, 1), comma separated or none; the true dimension is D = 2 - H")
+		fs.StringVar(&cfg.Estimator, "estimator", "all", "dimension estimators to calibrate, comma separated or all: "+strings.Join(fraes.EstimatorNames(), ", "))
+		fs.IntVar(&cfg.Rotations, "rotations", calibration.DefaultRotations, fmt.Sprintf("grid orientations per curve, the plane is turned in steps of 90/N degrees (1-%d)", calibration.MaxRotations))
+		fs.IntVar(&cfg.MaxPoints, "max-points", calibration.DefaultMaxPoints, "largest reference curve; every reference is refined up to the last iteration within it")
+		fs.Float64Var(&cfg.Tolerance, "tolerance", calibration.DefaultTolerance, "largest |bias| from the true dimension at which an estimator counts as converged")
+		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed of the fBm reference curves")
+		fs.IntVar(&cfg.Workers, "workers", 0, "reference iterations estimated at once (0 uses every CPU)")
+		fs.StringVar(&cfg.Format, "format", formatCSV, "calibration tables: csv, tsv, json files next to the chart or table (console only)")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdDimension:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
-		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", koch.MaxIterations))
+		fs.IntVar(&cfg.Iterations, "iterations", 5, fmt.Sprintf("maximum organic Koch iterations (0-%d)", fraes.MaxKochIterations))
 		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for organic coastline generation")
 		fs.Float64Var(&cfg.AngleJitter, "angle-jitter", 18, "maximum random angle deviation in degrees")
 		fs.Float64Var(&cfg.HeightJitter, "height-jitter", 0.25, "maximum random height deviation as a ratio")
 		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 0, "Gaussian erosion strength in meters; applied after fractal growth (0 disables)")
 		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
 		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		addBootstrapFlag(fs, &cfg)
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Estimator, "estimator", fraes.EstimatorBox, "dimension estimators compared side by side, comma separated or all: box (boundary boxes), box-filled (boxes on or inside the closed outline), mass-radius (sandbox), information (D1), correlation (D2), multifractal (D(q) and f(alpha) over --q-range)")
+		fs.StringVar(&cfg.QRange, "q-range", fraes.DefaultQRange, "q values of the multifractal spectrum as min:max:step or a comma separated list")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	case cmdErosion:
-		fs.StringVar(&cfg.InputPath, "input", coastline.DefaultCoastlineJSONPath, "path to local coastline JSON/GeoJSON fallback file")
-		fs.StringVar(&cfg.SourceURL, "source-url", coastline.DefaultCoastlineGeoJSONURL, "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
 		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
 		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for generated visualizations (default: ./output)")
 		fs.IntVar(&cfg.Steps, "steps", 5, "number of erosion steps (0+)")
 		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed for erosion simulation")
-		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 50, "Gaussian erosion strength in meters; applied each step (0 disables)")
+		fs.Float64Var(&cfg.ErosionStrength, "erosion-strength", 50, "erosion strength in meters per step: Gaussian sigma or retreat of the most exposed point (0 disables)")
+		fs.StringVar(&cfg.ErosionModel, "erosion-model", erosionModelGaussian, "erosion model: gaussian (isotropic noise), wave (fetch/exposure driven retreat) or percolation (rocky coast on a grid eroded by a sea force damped by coast length)")
+		fs.StringVar(&cfg.WaveClimate, "wave-climate", erosion.DefaultWaveClimate, "wave climate for --erosion-model=wave as bearing:weight pairs (bearing waves come from)")
+		fs.StringVar(&cfg.LithologyPath, "lithology", "", "path to lithology JSON mapping coastline segments to rock types; erosion scales with 1/resistance")
+		fs.BoolVar(&cfg.Sediment, "sediment", false, "carry eroded material along the shore by wave-driven longshore drift so the line can accrete")
+		fs.Float64Var(&cfg.SedimentRate, "sediment-rate", erosion.DefaultSedimentRateM3, "longshore transport in m3 per step for waves at 45 degrees to a fully exposed shore")
+		fs.StringVar(&cfg.ScenarioPath, "scenario", "", "path to scenario JSON (YAML is not supported) with years, background retreat, storms and sea level rise; replaces --steps and --erosion-strength")
+		fs.Float64Var(&cfg.SeaForce, "sea-force", percolation.DefaultForce, "initial sea force f0 of --erosion-model=percolation in (0, 1]: coast cells with a lower resistance erode")
+		fs.Float64Var(&cfg.SeaDamping, "sea-damping", percolation.DefaultDamping, "damping g of the sea force by coast length for --erosion-model=percolation: f = f0 / (1 + g*(P/P0 - 1))")
+		fs.IntVar(&cfg.GridCells, "grid-cells", percolation.DefaultCells, "grid cells along the longer side for --erosion-model=percolation")
+		fs.StringVar(&cfg.Area, "area", fraes.AreaEllipsoidal, "polygon area: ellipsoidal (geodesic, on --ellipsoid), spherical (spherical excess) or planar (local grid, for comparison)")
+		fs.StringVar(&cfg.Ellipsoid, "ellipsoid", fraes.WGS84().Name, "reference ellipsoid for the ellipsoidal area: WGS84, GRS80 or Krassovsky1940")
+		fs.IntVar(&cfg.Ensemble, "ensemble", 0, "run the model for N consecutive seeds starting at --seed and summarize length, area and dimension per iteration or step (0 disables, otherwise at least 2)")
+		fs.IntVar(&cfg.EnsembleWorkers, "ensemble-workers", 0, "ensemble runs computed at once (0 uses every CPU)")
+		fs.BoolVar(&cfg.Animate, "animate", false, "also assemble the series frames into one animated GIF")
+		fs.StringVar(&cfg.ExportGeometry, "export-geometry", "", "also save the model geometries for GIS: geojson (a FeatureCollection per iteration or step) or gpkg (one GeoPackage with a layer per series)")
+		fs.StringVar(&cfg.Format, "format", formatTable, "metrics tables: table (console only) or csv, tsv, json files next to the SVG")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdFit:
+		cfg.Ranges = make(map[string]string)
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for the fitted series, evaluations table and metrics (default: ./output)")
+		for _, name := range fitParameterNames {
+			fs.Func(name, fitParameterUsage(name), func(spec string) error {
+				cfg.Ranges[name] = spec
+				return nil
+			})
+		}
+		fs.StringVar(&cfg.Method, "method", fit.MethodGrid, "search: grid (coarse grid, then Nelder-Mead from its best cells) or nelder-mead (from the middle of the bounds)")
+		fs.IntVar(&cfg.Evaluations, "evaluations", fit.DefaultEvaluations, "model runs per seed")
+		fs.IntVar(&cfg.Seeds, "seeds", fit.DefaultSeeds, "independent fits for consecutive seeds from --seed; the spread of their best parameters is the uncertainty")
+		fs.Int64Var(&cfg.Seed, "seed", 42, "first random seed of the organic growth and erosion")
+		fs.StringVar(&cfg.Weights, "weights", fitDefaultWeights, "misfit weights as name=value pairs of dimension (|delta D| of box counting), richardson (RMS log ratio of divider lengths) and sinuosity (|log ratio| of arc over chord); 0 leaves a term out")
+		fs.IntVar(&cfg.Workers, "workers", 0, "seeds fitted at once (0 uses every CPU)")
+		fs.StringVar(&cfg.Format, "format", formatCSV, "evaluations table: csv, tsv, json files next to the series or table (console only)")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
+		fs.Usage = func() { printCommandUsage(stdout, command) }
+	case cmdSweep:
+		cfg.Ranges = make(map[string]string)
+		fs.StringVar(&cfg.InputPath, "input", fraes.DefaultLocalPath, "path to local coastline JSON/GeoJSON, KML, GPX, WKT or .shp fallback file")
+		fs.StringVar(&cfg.SourceURL, "source-url", fraes.DefaultSourceURL(), "remote GeoJSON URL for coastline data; empty string disables HTTP loading")
+		fs.BoolVar(&cfg.Refresh, "refresh", false, "force refresh of the remote GeoJSON cache before running")
+		fs.StringVar(&cfg.OutputPath, "output", "", "output directory for the results table, heatmaps and journal (default: ./output)")
+		fs.StringVar(&cfg.Pipeline, "pipeline", sweepPipelineOrganic, "model run for every combination: organic (organic Koch growth with optional Gaussian erosion) or erosion (multi-step erosion)")
+		fs.IntVar(&cfg.Iterations, "iterations", 4, fmt.Sprintf("organic Koch iterations of every organic run (0-%d)", fraes.MaxKochIterations))
+		fs.IntVar(&cfg.Steps, "steps", 5, "erosion steps of every erosion run (0+)")
+		fs.Int64Var(&cfg.Seed, "seed", 42, "random seed shared by every run and by Latin-hypercube sampling")
+		for _, name := range sweepParameterNames() {
+			fs.Func(name, sweepParameterUsage(name), func(spec string) error {
+				cfg.Ranges[name] = spec
+				return nil
+			})
+		}
+		fs.StringVar(&cfg.Sampling, "sampling", sweep.SamplingGrid, "how combinations are chosen: grid (every combination of the ranges) or lhs (Latin hypercube of --samples points within the ranges)")
+		fs.IntVar(&cfg.Samples, "samples", 32, "points drawn by --sampling=lhs")
+		fs.StringVar(&cfg.Plane, "plane", "", "two swept parameters spanning the heatmaps as x,y (default: the first two that vary)")
+		fs.StringVar(&cfg.ErosionModel, "erosion-model", erosionModelGaussian, "erosion model of the erosion pipeline: gaussian or wave")
+		fs.StringVar(&cfg.WaveClimate, "wave-climate", erosion.DefaultWaveClimate, "wave climate for --erosion-model=wave and sediment transport as bearing:weight pairs")
+		fs.IntVar(&cfg.Workers, "workers", 0, "runs computed at once (0 uses every CPU)")
+		fs.BoolVar(&cfg.Fresh, "fresh", false, "discard the journal of an earlier sweep in --output instead of resuming it")
+		fs.IntVar(&cfg.ModelMaxPoints, "model-max-points", 0, "max points for model base (0 keeps default budget); higher preserves details")
+		fs.BoolVar(&cfg.DisableSimplify, "no-model-simplify", false, "disable model base simplification before fractal growth")
+		fs.StringVar(&cfg.Format, "format", formatCSV, "results table: csv, tsv, json files next to the heatmaps or table (console only)")
+		fs.StringVar(&cfg.Projection, "projection", fraes.DefaultProjection, "map projection shared by simplification, box counting, erosion and SVG: laea (equal-area, centred on the data), utm (zone of the centre) or webmercator")
 		fs.Usage = func() { printCommandUsage(stdout, command) }
 	}
 
@@ -154,8 +374,8 @@ func parseConfig(args []string, stdout, stderr io.Writer) (config, error) {
 		return config{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
 	}
 
-	if commandUsesIterations(command) && (cfg.Iterations < 0 || cfg.Iterations > koch.MaxIterations) {
-		return config{}, fmt.Errorf("iterations must be between 0 and %d", koch.MaxIterations)
+	if commandUsesIterations(command) && (cfg.Iterations < 0 || cfg.Iterations > fraes.MaxKochIterations) {
+		return config{}, fmt.Errorf("iterations must be between 0 and %d", fraes.MaxKochIterations)
 	}
 	if command == cmdAll || command == cmdKochOrganic || command == cmdDimension {
 		if cfg.AngleJitter < 0 {
@@ -165,12 +385,110 @@ func parseConfig(args []string, stdout, stderr io.Writer) (config, error) {
 			return config{}, fmt.Errorf("height-jitter must be non-negative")
 		}
 	}
+	if command == cmdFBM {
+		if cfg.Iterations < 0 || cfg.Iterations > fbm.MaxIterations {
+			return config{}, fmt.Errorf("iterations must be between 0 and %d", fbm.MaxIterations)
+		}
+		if !(cfg.Hurst > 0 && cfg.Hurst < 1) {
+			return config{}, fmt.Errorf("hurst must be in (0, 1)")
+		}
+		if cfg.Amplitude < 0 {
+			return config{}, fmt.Errorf("amplitude must be non-negative")
+		}
+	}
+	if command == cmdGenerate {
+		if !(cfg.CesaroAngle > 0 && cfg.CesaroAngle < 90) {
+			return config{}, fmt.Errorf("cesaro-angle must be in (0, 90)")
+		}
+		gen, err := fraes.NewGenerator(cfg.Generator, fraes.GeneratorOptions{CesaroAngleDeg: cfg.CesaroAngle})
+		if err != nil {
+			return config{}, err
+		}
+		if cfg.Iterations < 0 || cfg.Iterations > gen.MaxIterations() {
+			return config{}, fmt.Errorf("iterations must be between 0 and %d for generator %s", gen.MaxIterations(), gen.Name())
+		}
+	}
 	if cfg.ErosionStrength < 0 {
 		return config{}, fmt.Errorf("erosion-strength must be non-negative")
 	}
-	if command == cmdErosion && cfg.Steps < 0 {
+	switch cfg.Format {
+	case "", formatTable, formatCSV, formatTSV, formatJSON:
+	default:
+		return config{}, fmt.Errorf("format must be one of %s, %s, %s, %s", formatTable, formatCSV, formatTSV, formatJSON)
+	}
+	switch cfg.ExportGeometry {
+	case "", geometryGeoJSON, geometryGPKG:
+	default:
+		return config{}, fmt.Errorf("export-geometry must be %q or %q", geometryGeoJSON, geometryGPKG)
+	}
+	if cfg.Bootstrap < 0 {
+		return config{}, fmt.Errorf("bootstrap must be non-negative")
+	}
+	if cfg.Ensemble < 0 || cfg.Ensemble == 1 {
+		return config{}, fmt.Errorf("ensemble must be 0 or at least %d", ensemble.MinMembers)
+	}
+	if cfg.EnsembleWorkers < 0 {
+		return config{}, fmt.Errorf("ensemble-workers must be non-negative")
+	}
+	if _, err := dimensionEstimators(cfg); err != nil {
+		return config{}, err
+	}
+	if _, err := spectrumQRange(cfg); err != nil {
+		return config{}, err
+	}
+	if _, err := geodesicDistance(cfg); err != nil {
+		return config{}, err
+	}
+	if _, err := areaMeasure(cfg); err != nil {
+		return config{}, err
+	}
+	if _, err := fraes.NewProjection(cfg.Projection); err != nil {
+		return config{}, err
+	}
+	if (command == cmdErosion || command == cmdSweep) && cfg.Steps < 0 {
 		return config{}, fmt.Errorf("steps must be non-negative")
 	}
+	if command == cmdSweep {
+		if err := validateSweepConfig(cfg); err != nil {
+			return config{}, err
+		}
+	}
+	if command == cmdCalibrate {
+		if err := validateCalibrationConfig(cfg); err != nil {
+			return config{}, err
+		}
+	}
+	if command == cmdFit {
+		if err := validateFitConfig(cfg); err != nil {
+			return config{}, err
+		}
+	}
+	if command == cmdErosion || (command == cmdSweep && cfg.Pipeline == sweepPipelineErosion) {
+		switch cfg.ErosionModel {
+		case erosionModelGaussian:
+		case erosionModelWave:
+			if _, err := erosion.ParseWaveClimate(cfg.WaveClimate); err != nil {
+				return config{}, fmt.Errorf("wave-climate: %w", err)
+			}
+		case erosionModelPercolation:
+			if command == cmdSweep {
+				return config{}, fmt.Errorf("sweep pipeline %q supports erosion-model %q or %q", sweepPipelineErosion, erosionModelGaussian, erosionModelWave)
+			}
+			if err := validatePercolationConfig(cfg); err != nil {
+				return config{}, err
+			}
+		default:
+			return config{}, fmt.Errorf("erosion-model must be %q, %q or %q", erosionModelGaussian, erosionModelWave, erosionModelPercolation)
+		}
+		if cfg.SedimentRate < 0 {
+			return config{}, fmt.Errorf("sediment-rate must be non-negative")
+		}
+		if cfg.Sediment {
+			if _, err := erosion.ParseWaveClimate(cfg.WaveClimate); err != nil {
+				return config{}, fmt.Errorf("wave-climate: %w", err)
+			}
+		}
+	}
 	if cfg.ModelMaxPoints < 0 {
 		return config{}, fmt.Errorf("model-max-points must be non-negative")
 	}
@@ -178,9 +496,36 @@ func parseConfig(args []string, stdout, stderr io.Writer) (config, error) {
 	return cfg, nil
 }
 
+// validatePercolationConfig rejects the options the grid model has no
+// counterpart for: it erodes cells, not vertices, and runs to a stable coast.
+func validatePercolationConfig(cfg config) error {
+	if !(cfg.SeaForce > 0 && cfg.SeaForce <= 1) {
+		return fmt.Errorf("sea-force must be in (0, 1]")
+	}
+	if cfg.SeaDamping < 0 {
+		return fmt.Errorf("sea-damping must be non-negative")
+	}
+	if cfg.GridCells < 16 || cfg.GridCells > 4096 {
+		return fmt.Errorf("grid-cells must be between 16 and 4096")
+	}
+	for _, option := range []struct {
+		name string
+		set  bool
+	}{
+		{"lithology", cfg.LithologyPath != ""},
+		{"sediment", cfg.Sediment},
+		{"scenario", cfg.ScenarioPath != ""},
+	} {
+		if option.set {
+			return fmt.Errorf("%s is not supported with erosion-model %q", option.name, erosionModelPercolation)
+		}
+	}
+	return nil
+}
+
 func commandNeedsCoastline(command string) bool {
 	switch command {
-	case cmdAll, cmdCoastline, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension, cmdErosion:
+	case cmdAll, cmdCoastline, cmdRichardson, cmdParadox, cmdKoch, cmdKochOrganic, cmdFBM, cmdGenerate, cmdDimension, cmdErosion, cmdSweep, cmdFit:
 		return true
 	default:
 		return false
@@ -189,7 +534,7 @@ func commandNeedsCoastline(command string) bool {
 
 func commandUsesIterations(command string) bool {
 	switch command {
-	case cmdAll, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension:
+	case cmdAll, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension, cmdSweep:
 		return true
 	default:
 		return false
@@ -206,7 +551,7 @@ func resolveCommand(args []string, stdout, stderr io.Writer) (string, []string,
 		return resolveGroupedCommand(cmdReal, args[1:], stdout, stderr)
 	case cmdModel:
 		return resolveGroupedCommand(cmdModel, args[1:], stdout, stderr)
-	case cmdSource, cmdAll, cmdCoastline, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension, cmdErosion:
+	case cmdSource, cmdAll, cmdCoastline, cmdRichardson, cmdParadox, cmdKoch, cmdKochOrganic, cmdFBM, cmdGenerate, cmdDimension, cmdErosion, cmdSweep, cmdCalibrate, cmdFit:
 		return args[0], args[1:], nil
 	default:
 		printRootUsage(stderr)
@@ -232,10 +577,10 @@ func resolveGroupedCommand(group string, args []string, stdout, stderr io.Writer
 func commandBelongsToGroup(command, group string) bool {
 	switch group {
 	case cmdReal:
-		return command == cmdCoastline
+		return command == cmdCoastline || command == cmdRichardson
 	case cmdModel:
 		switch command {
-		case cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension, cmdErosion:
+		case cmdParadox, cmdKoch, cmdKochOrganic, cmdFBM, cmdGenerate, cmdDimension, cmdErosion, cmdSweep, cmdCalibrate, cmdFit:
 			return true
 		default:
 			return false
@@ -253,3 +598,76 @@ func isHelpToken(arg string) bool {
 		return false
 	}
 }
+
+// geodesicDistance builds the distance selected by --geodesic and
+// --ellipsoid; commands without these flags measure with haversine.
+func geodesicDistance(cfg config) (fraes.Distance, error) {
+	if cfg.Geodesic == "" || cfg.Geodesic == fraes.GeodesicHaversine {
+		return fraes.NewDistance(fraes.GeodesicHaversine)
+	}
+	ellipsoid, err := fraes.EllipsoidByName(cfg.Ellipsoid)
+	if err != nil {
+		return nil, err
+	}
+	return fraes.NewDistance(cfg.Geodesic, fraes.WithEllipsoid(ellipsoid))
+}
+
+// areaMeasure builds the polygon area selected by --area; commands without
+// the flag measure on the WGS84 ellipsoid.
+func areaMeasure(cfg config) (fraes.AreaMeasure, error) {
+	if cfg.Area == fraes.AreaPlanar || cfg.Area == fraes.AreaSpherical {
+		return fraes.NewAreaMeasure(cfg.Area)
+	}
+	ellipsoid := fraes.WGS84()
+	if cfg.Ellipsoid != "" {
+		var err error
+		if ellipsoid, err = fraes.EllipsoidByName(cfg.Ellipsoid); err != nil {
+			return nil, err
+		}
+	}
+	return fraes.NewAreaMeasure(cfg.Area, fraes.WithEllipsoid(ellipsoid))
+}
+
+// addBootstrapFlag registers --bootstrap for the commands that report the
+// box-counting dimension. Every replicate is one more box count, so the
+// interval is off unless asked for.
+func addBootstrapFlag(fs *flag.FlagSet, cfg *config) {
+	fs.IntVar(&cfg.Bootstrap, "bootstrap", 0, fmt.Sprintf("bootstrap replicates for the 95%% confidence interval of the box-counting dimension: random grid rotations and offsets and resampled regression points; 0 disables, %d keeps D stable to about 0.005", fraes.DefaultBootstrapReplicates))
+}
+
+// dimensionEstimators lists the --estimator selection in report order;
+// commands without the flag use the boundary box count only.
+func dimensionEstimators(cfg config) ([]string, error) {
+	spec := strings.TrimSpace(cfg.Estimator)
+	if spec == "" {
+		return []string{fraes.EstimatorBox}, nil
+	}
+	names := fraes.EstimatorNames()
+	if spec == "all" {
+		return names, nil
+	}
+
+	selected := map[string]bool{}
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("estimator must be all or a list of %s", strings.Join(names, ", "))
+		}
+		selected[name] = true
+	}
+	var estimators []string
+	for _, name := range names {
+		if selected[name] {
+			estimators = append(estimators, name)
+		}
+	}
+	return estimators, nil
+}
+
+// spectrumQRange is the --q-range of the multifractal estimator.
+func spectrumQRange(cfg config) ([]float64, error) {
+	if cfg.QRange == "" {
+		return fraes.ParseQRange(fraes.DefaultQRange)
+	}
+	return fraes.ParseQRange(cfg.QRange)
+}
@@ -3,8 +3,13 @@ package cli
 import (
 	"bytes"
 	"flag"
+	"slices"
 	"strings"
 	"testing"
+
+	"coastal-geometry/internal/domain/simulations/calibration"
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/pkg/fraes"
 )
 
 func TestParseConfigGroupedRealCommand(t *testing.T) {
@@ -21,6 +26,26 @@ func TestParseConfigGroupedRealCommand(t *testing.T) {
 	}
 }
 
+func TestParseConfigGroupedRichardsonCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdReal, cmdRichardson, "--output", "out/richardson.svg"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+
+	if cfg.Command != cmdRichardson {
+		t.Fatalf("expected command %q, got %q", cmdRichardson, cfg.Command)
+	}
+	if !commandNeedsCoastline(cfg.Command) {
+		t.Fatal("expected richardson to load the coastline")
+	}
+	if cfg.OutputPath != "out/richardson.svg" {
+		t.Fatalf("expected output path to be preserved, got %q", cfg.OutputPath)
+	}
+}
+
 func TestParseConfigSourceCommand(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -111,3 +136,391 @@ func TestParseConfigRejectsWrongGroupCommand(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestParseConfigErosionModelFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdErosion, "--erosion-model", "wave", "--wave-climate", "45:2,225:1"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.ErosionModel != erosionModelWave || cfg.WaveClimate != "45:2,225:1" {
+		t.Fatalf("expected wave model with custom climate, got %q / %q", cfg.ErosionModel, cfg.WaveClimate)
+	}
+
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--erosion-model", "tidal"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown erosion model to be rejected")
+	}
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--erosion-model", "wave", "--wave-climate", "45:0"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected invalid wave climate to be rejected")
+	}
+}
+
+func TestParseConfigPercolationFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdErosion, "--erosion-model", "percolation", "--sea-force", "0.7", "--sea-damping", "0.5", "--grid-cells", "200"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.ErosionModel != erosionModelPercolation || cfg.SeaForce != 0.7 || cfg.SeaDamping != 0.5 || cfg.GridCells != 200 {
+		t.Fatalf("expected percolation with f0=0.7, g=0.5 on 200 cells, got %+v", cfg)
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdErosion, "--erosion-model", "percolation", "--sea-force", "0"},
+		{cmdModel, cmdErosion, "--erosion-model", "percolation", "--sea-damping", "-1"},
+		{cmdModel, cmdErosion, "--erosion-model", "percolation", "--grid-cells", "8"},
+		{cmdModel, cmdErosion, "--erosion-model", "percolation", "--sediment"},
+		{cmdModel, cmdErosion, "--erosion-model", "percolation", "--lithology", "data/black-sea-lithology.json"},
+		{cmdModel, cmdSweep, "--pipeline", "erosion", "--erosion-model", "percolation"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigFBMFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdFBM, "--hurst", "0.4", "--amplitude", "0.5", "--iterations", "11"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Command != cmdFBM || cfg.Hurst != 0.4 || cfg.Amplitude != 0.5 || cfg.Iterations != 11 {
+		t.Fatalf("expected fbm with H=0.4, amplitude 0.5 and 11 iterations, got %+v", cfg)
+	}
+	if !commandNeedsCoastline(cfg.Command) || !commandUsesModelBase(cfg.Command) {
+		t.Fatal("expected fbm to grow from the simplified coastline")
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdFBM, "--hurst", "1"},
+		{cmdModel, cmdFBM, "--hurst", "0"},
+		{cmdModel, cmdFBM, "--amplitude", "-0.1"},
+		{cmdModel, cmdFBM, "--iterations", "13"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigGenerateFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdGenerate, "--generator", "cesaro", "--cesaro-angle", "75", "--iterations", "6"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Command != cmdGenerate || cfg.Generator != "cesaro" || cfg.CesaroAngle != 75 || cfg.Iterations != 6 {
+		t.Fatalf("expected cesaro at 75° with 6 iterations, got %+v", cfg)
+	}
+	if !commandUsesModelBase(cfg.Command) || generatorBaseTargetPoints(cfg, 6) != modelBaseTargetPoints(6) {
+		t.Fatal("expected the cesaro base to be sized like the Koch base")
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdGenerate, "--generator", "dragon"},
+		{cmdModel, cmdGenerate, "--generator", "quadratic-2", "--iterations", "5"},
+		{cmdModel, cmdGenerate, "--generator", "cesaro", "--cesaro-angle", "90"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigCalibrateFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdCalibrate, "--generators", "koch, Minkowski", "--hurst", "none", "--estimator", "box", "--rotations", "2"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Command != cmdCalibrate || commandNeedsCoastline(cfg.Command) || cfg.Format != formatCSV {
+		t.Fatalf("expected calibrate without a coastline and with csv tables, got %+v", cfg)
+	}
+	opts, err := calibrationOptions(cfg)
+	if err != nil {
+		t.Fatalf("calibrationOptions returned error: %v", err)
+	}
+	if !slices.Equal(opts.Generators, []string{"koch", "minkowski"}) || len(opts.Hursts) != 0 || !slices.Equal(opts.Estimators, []string{"box"}) || opts.Rotations != 2 {
+		t.Fatalf("expected koch and minkowski under box counting at 2 orientations, got %+v", opts)
+	}
+
+	cfg, err = parseConfig([]string{cmdModel, cmdCalibrate}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if opts, _ := calibrationOptions(cfg); len(opts.Generators) != len(fraes.GeneratorNames()) || len(opts.Hursts) != len(calibration.DefaultHursts) || len(opts.Estimators) != len(fraes.EstimatorNames()) {
+		t.Fatalf("expected every reference and estimator by default, got %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdCalibrate, "--generators", "dragon"},
+		{cmdModel, cmdCalibrate, "--hurst", "0.5,x"},
+		{cmdModel, cmdCalibrate, "--hurst", "1.2"},
+		{cmdModel, cmdCalibrate, "--generators", "none", "--hurst", "none"},
+		{cmdModel, cmdCalibrate, "--rotations", "0"},
+		{cmdModel, cmdCalibrate, "--tolerance", "0"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigFitFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdFit, "--iterations", "3", "--erosion-strength", "0", "--weights", "dimension=2, sinuosity=0", "--method", fit.MethodNelderMead}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Command != cmdFit || !commandNeedsCoastline(cfg.Command) || !commandUsesModelBase(cfg.Command) || cfg.Format != formatCSV {
+		t.Fatalf("expected fit on the model base of the coastline with csv tables, got %+v", cfg)
+	}
+	opts, err := fitOptions(cfg)
+	if err != nil {
+		t.Fatalf("fitOptions returned error: %v", err)
+	}
+	if opts.Weights != (fit.Weights{Dimension: 2, Richardson: 1}) || opts.Method != fit.MethodNelderMead || opts.Evaluations != fit.DefaultEvaluations {
+		t.Fatalf("expected weights 2, 1, 0 for nelder-mead with the default budget, got %+v", opts)
+	}
+	if p := opts.Parameters; len(p) != 4 || p[0].Free() || p[0].Min != 3 || !p[1].Free() || p[3].Free() {
+		t.Fatalf("expected fixed iterations and erosion with free jitters, got %+v", p)
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdFit, "--iterations", "1:9"},
+		{cmdModel, cmdFit, "--iterations", "1.5"},
+		{cmdModel, cmdFit, "--angle-jitter", "-5:10"},
+		{cmdModel, cmdFit, "--height-jitter", "0.5:0.1"},
+		{cmdModel, cmdFit, "--weights", "dimension=0,richardson=0,sinuosity=0"},
+		{cmdModel, cmdFit, "--weights", "area=1"},
+		{cmdModel, cmdFit, "--weights", "dimension=-1"},
+		{cmdModel, cmdFit, "--method", "annealing"},
+		{cmdModel, cmdFit, "--evaluations", "2"},
+		{cmdModel, cmdFit, "--seeds", "0"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigSedimentFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdErosion, "--sediment", "--sediment-rate", "20000"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if !cfg.Sediment || cfg.SedimentRate != 20000 {
+		t.Fatalf("expected sediment transport at 20000 m3, got %v / %v", cfg.Sediment, cfg.SedimentRate)
+	}
+
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--sediment-rate", "-1"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected negative sediment rate to be rejected")
+	}
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--sediment", "--wave-climate", "x:1"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected invalid wave climate to be rejected for sediment transport")
+	}
+}
+
+func TestParseConfigFormatFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdParadox, "--format", "tsv", "--output", "tables"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Format != formatTSV || cfg.OutputPath != "tables" {
+		t.Fatalf("expected tsv tables in ./tables, got %q / %q", cfg.Format, cfg.OutputPath)
+	}
+
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--format", "xlsx"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown format to be rejected")
+	}
+}
+
+func TestParseConfigExportGeometryFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdKochOrganic, "--export-geometry", "gpkg"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.ExportGeometry != geometryGPKG {
+		t.Fatalf("expected gpkg export, got %q", cfg.ExportGeometry)
+	}
+
+	if _, err := parseConfig([]string{cmdModel, cmdErosion, "--export-geometry", "shp"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown geometry format to be rejected")
+	}
+	if _, err := parseConfig([]string{cmdModel, cmdParadox, "--export-geometry", "geojson"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected paradox to have no --export-geometry flag")
+	}
+}
+
+func TestParseConfigEstimatorFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdDimension, "--estimator", "multifractal, box", "--q-range", "0:2:1"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	estimators, err := dimensionEstimators(cfg)
+	if err != nil || !slices.Equal(estimators, []string{"box", "multifractal"}) {
+		t.Fatalf("expected estimators in report order, got %v (%v)", estimators, err)
+	}
+	if qs, err := spectrumQRange(cfg); err != nil || !slices.Equal(qs, []float64{0, 1, 2}) {
+		t.Fatalf("expected q-range 0, 1, 2, got %v (%v)", qs, err)
+	}
+
+	cfg, err = parseConfig([]string{cmdModel, cmdDimension, "--estimator", "all"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if estimators, _ := dimensionEstimators(cfg); len(estimators) != 6 {
+		t.Fatalf("expected every estimator for all, got %v", estimators)
+	}
+
+	if _, err := parseConfig([]string{cmdModel, cmdDimension, "--estimator", "hausdorff"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown estimator to be rejected")
+	}
+	if _, err := parseConfig([]string{cmdModel, cmdDimension, "--q-range", "3:1:1"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected a decreasing q-range to be rejected")
+	}
+}
+
+func TestParseConfigBootstrapFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdDimension}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Bootstrap != 0 {
+		t.Fatalf("expected no bootstrap replicates by default, got %d", cfg.Bootstrap)
+	}
+
+	cfg, err = parseConfig([]string{cmdModel, cmdKochOrganic, "--bootstrap", "0"}, &stdout, &stderr)
+	if err != nil || cfg.Bootstrap != 0 {
+		t.Fatalf("expected --bootstrap 0 to disable the interval, got %d (%v)", cfg.Bootstrap, err)
+	}
+	if _, err := parseConfig([]string{cmdModel, cmdDimension, "--bootstrap", "-1"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected negative bootstrap to be rejected")
+	}
+}
+
+func TestParseConfigEnsembleFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdErosion, "--ensemble", "5", "--ensemble-workers", "2"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Ensemble != 5 || cfg.EnsembleWorkers != 2 {
+		t.Fatalf("expected 5 members on 2 workers, got %d on %d", cfg.Ensemble, cfg.EnsembleWorkers)
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdKochOrganic, "--ensemble", "1"},
+		{cmdModel, cmdParadox, "--ensemble", "-3"},
+		{cmdModel, cmdErosion, "--ensemble", "4", "--ensemble-workers", "-1"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigSweepFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdSweep, "--angle-jitter", "0:20:10", "--sampling", "lhs", "--samples", "8", "--plane", "height-jitter,angle-jitter"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if cfg.Command != cmdSweep || cfg.Pipeline != sweepPipelineOrganic || cfg.Format != formatCSV {
+		t.Fatalf("expected an organic sweep with a csv table, got %+v", cfg)
+	}
+	if cfg.Ranges["angle-jitter"] != "0:20:10" || cfg.Samples != 8 {
+		t.Fatalf("expected the angle range and 8 samples, got %v / %d", cfg.Ranges, cfg.Samples)
+	}
+
+	for _, args := range [][]string{
+		{cmdModel, cmdSweep, "--pipeline", "erosion", "--angle-jitter", "0:10"},
+		{cmdModel, cmdSweep, "--pipeline", "koch"},
+		{cmdModel, cmdSweep, "--sampling", "random"},
+		{cmdModel, cmdSweep, "--sampling", "lhs", "--samples", "0"},
+		{cmdModel, cmdSweep, "--plane", "seed"},
+		{cmdModel, cmdSweep, "--height-jitter", "-0.1:0.2"},
+	} {
+		if _, err := parseConfig(args, &stdout, &stderr); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseConfigGeodesicFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdReal, cmdCoastline, "--geodesic", "vincenty", "--ellipsoid", "GRS80"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	distance, err := geodesicDistance(cfg)
+	if err != nil || distance.Name() != "vincenty" {
+		t.Fatalf("expected vincenty distance, got %v / %v", distance, err)
+	}
+
+	if _, err := parseConfig([]string{cmdReal, cmdCoastline, "--geodesic", "euclid"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown geodesic to be rejected")
+	}
+	if _, err := parseConfig([]string{cmdAll, "--geodesic", "karney", "--ellipsoid", "clarke"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown ellipsoid to be rejected")
+	}
+}
+
+func TestParseConfigAreaFlag(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cfg, err := parseConfig([]string{cmdModel, cmdErosion, "--ellipsoid", "Krassovsky1940"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	area, err := areaMeasure(cfg)
+	if err != nil || area.Name() != "ellipsoidal" || areaLabel(area) != "ellipsoidal, эллипсоид Krassovsky1940" {
+		t.Fatalf("expected ellipsoidal area on Krassovsky1940 by default, got %v / %v", area, err)
+	}
+
+	cfg, err = parseConfig([]string{cmdReal, cmdCoastline, "--area", "planar"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("parseConfig returned error: %v", err)
+	}
+	if area, _ := areaMeasure(cfg); area.Name() != "planar" {
+		t.Fatalf("expected planar area, got %v", area)
+	}
+	if _, err := parseConfig([]string{cmdAll, "--area", "mercator"}, &stdout, &stderr); err == nil {
+		t.Fatal("expected unknown area method to be rejected")
+	}
+}
@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/fractal"
-	"coastal-geometry/internal/domain/generators/koch"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
 	"math"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -17,29 +17,81 @@ const (
 
 type dimensionIterationResult struct {
 	Iteration int
-	Analysis  fractal.BoxCountingAnalysis
+	Points    int
+	LengthKM  float64
+	Analysis  fraes.BoxCountingAnalysis
+	Interval  fraes.ConfidenceInterval
+	// Estimates follow the --estimator order; Multifractal is set when the
+	// spectrum was requested.
+	Estimates    []fraes.DimensionEstimate
+	Multifractal *fraes.MultifractalAnalysis
 }
 
 type dimensionAssessment struct {
-	Valid bool
+	Valid      bool
+	Options    fraes.OrganicOptions
+	Iterations []dimensionIterationResult
+	Projection fraes.Projector
+	Estimators []string
+	QRange     []float64
+}
+
+// dimensionRunOptions selects what runDimensionMetrics computes besides the
+// box-counting point estimate.
+type dimensionRunOptions struct {
+	Estimators []string
+	QRange     []float64
+	Bootstrap  int
 }
 
 func runDimensionCommand(app *App) error {
 	opts := organicKochOptions(app)
-	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "dimension_iter", "dimension", true, newExportContext(app)); err != nil {
+	ctx := newExportContext(app)
+	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "dimension_iter", "dimension", true, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	estimators, err := dimensionEstimators(app.Config)
+	if err != nil {
 		return err
 	}
-	assessment, err := runDimensionMetrics(app.ModelBase, app.Config.Iterations, opts)
+	qs, err := spectrumQRange(app.Config)
+	if err != nil {
+		return err
+	}
+	run := dimensionRunOptions{Estimators: estimators, QRange: qs, Bootstrap: app.Config.Bootstrap}
+	assessment, err := runDimensionMetrics(app.ModelBase, app.Config.Iterations, opts, app.Projection, run)
 	if err != nil {
 		return err
 	}
 	if !assessment.Valid {
 		printInvalidResult()
 	}
+	if err := writeDataTable(dimensionTable(assessment), app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if !comparesEstimators(estimators) {
+		return nil
+	}
+	if err := writeDimensionEstimatorMetrics(assessment, app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if err := writeDataTable(dimensionEstimatorsTable(assessment), app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if slices.Contains(estimators, fraes.EstimatorMultifractal) {
+		return writeDataTable(dimensionSpectrumTable(assessment), app.Config.OutputPath, ctx)
+	}
 	return nil
 }
 
-func runDimensionMetrics(base []geometry.LatLon, maxIterations int, opts koch.OrganicOptions) (dimensionAssessment, error) {
+// runDimensionMetrics prints the box-counting convergence table and, when
+// estimators other than the boundary box count are selected, a side by side
+// comparison of every estimator per iteration.
+func runDimensionMetrics(base []fraes.LatLon, maxIterations int, opts fraes.OrganicOptions, proj fraes.Projector, run dimensionRunOptions) (dimensionAssessment, error) {
+	estimators := run.Estimators
 	theoreticalDimension := math.Log(4) / math.Log(3)
 
 	fmt.Println(strings.Repeat("=", 80))
@@ -50,18 +102,25 @@ func runDimensionMetrics(base []geometry.LatLon, maxIterations int, opts koch.Or
 		opts.Seed, opts.AngleJitterDeg, opts.HeightJitterPct*100)
 	fmt.Printf("Теоретический ориентир классической кривой Коха: %.5f\n\n", theoreticalDimension)
 
-	fmt.Printf("%-5s %-10s %-12s %-12s %-8s %-8s %-10s %-10s %-8s\n",
-		"Итер.", "Точек", "Длина, км", "D", "Масш.", "R²", "Разброс", "Δ к пред.", "Стаб.")
-	fmt.Println(strings.Repeat("─", 104))
+	fmt.Printf("%-5s %-10s %-12s %-12s %-18s %-8s %-8s %-8s %-10s %-10s %-8s\n",
+		"Итер.", "Точек", "Длина, км", "D", "95% ДИ", "СО", "Масш.", "R²", "Разброс", "Δ к пред.", "Стаб.")
+	fmt.Println(strings.Repeat("─", 132))
 
 	results := make([]dimensionIterationResult, 0, maxIterations+1)
 	prevDimension := 0.0
 	prevValid := false
 	for iter := 0; iter <= maxIterations; iter++ {
-		curve := koch.OrganicKochCurve(base, iter, opts)
-		length := geometry.PolylineLength(curve)
-		analysis := fractal.AnalyzeBoxCounting(curve)
-		results = append(results, dimensionIterationResult{Iteration: iter, Analysis: analysis})
+		curve := fraes.OrganicKochCurve(base, iter, organicCurveOptions(opts)...)
+		length := fraes.PolylineLength(curve)
+		analysis := fraes.AnalyzeBoxCountingWith(curve, proj)
+		interval := fraes.BootstrapBoxCountingWith(curve, proj, analysis, run.Bootstrap, dimensionBootstrapSeed(opts.Seed, iter))
+		result := dimensionIterationResult{Iteration: iter, Points: len(curve), LengthKM: length, Analysis: analysis, Interval: interval}
+		if comparesEstimators(estimators) {
+			if err := estimateDimensions(&result, curve, proj, estimators, run.QRange); err != nil {
+				return dimensionAssessment{}, err
+			}
+		}
+		results = append(results, result)
 
 		delta := "—"
 		if prevValid && analysis.Valid {
@@ -72,6 +131,11 @@ func runDimensionMetrics(base []geometry.LatLon, maxIterations int, opts koch.Or
 		dimensionValue := "n/a"
 		rSquared := "n/a"
 		spread := "n/a"
+		ci, stdError := "n/a", "n/a"
+		if interval.Valid {
+			ci = fmt.Sprintf("[%.4f, %.4f]", interval.Lower, interval.Upper)
+			stdError = fmt.Sprintf("%.4f", interval.StdError)
+		}
 		if analysis.Valid {
 			dimensionValue = fmt.Sprintf("%.5f", analysis.Dimension)
 			rSquared = fmt.Sprintf("%.4f", analysis.RegressionRSquared)
@@ -85,12 +149,111 @@ func runDimensionMetrics(base []geometry.LatLon, maxIterations int, opts koch.Or
 			prevValid = false
 		}
 
-		fmt.Printf("%-5d %-10d %-12.0f %-12s %-8d %-8s %-10s %-10s %-8s\n",
-			iter, len(curve), length, dimensionValue, len(analysis.Samples), rSquared, spread, delta, stable)
+		fmt.Printf("%-5d %-10d %-12.0f %-12s %-18s %-8s %-8d %-8s %-10s %-10s %-8s\n",
+			iter, len(curve), length, dimensionValue, ci, stdError, len(analysis.Samples), rSquared, spread, delta, stable)
+	}
+
+	fmt.Println(strings.Repeat("─", 132))
+	assessment := printDimensionAssessment(results, theoreticalDimension)
+	assessment.Options = opts
+	assessment.Iterations = results
+	assessment.Projection = proj
+	assessment.Estimators = estimators
+	assessment.QRange = run.QRange
+	if comparesEstimators(estimators) {
+		printEstimatorComparison(assessment)
+	}
+	return assessment, nil
+}
+
+// comparesEstimators reports whether the run asks for more than the default
+// boundary box count.
+func comparesEstimators(estimators []string) bool {
+	return len(estimators) > 1 || (len(estimators) == 1 && estimators[0] != fraes.EstimatorBox)
+}
+
+func estimateDimensions(result *dimensionIterationResult, curve []fraes.LatLon, proj fraes.Projector, estimators []string, qs []float64) error {
+	for _, estimator := range estimators {
+		if estimator == fraes.EstimatorBox {
+			analysis := result.Analysis
+			result.Estimates = append(result.Estimates, fraes.DimensionEstimate{
+				Estimator:          estimator,
+				Dimension:          analysis.Dimension,
+				RegressionRSquared: analysis.RegressionRSquared,
+				StableAcrossScales: analysis.StableAcrossScales,
+				StabilitySpread:    analysis.StabilitySpread,
+				SampleCount:        len(analysis.Samples),
+				Valid:              analysis.Valid,
+			})
+			continue
+		}
+		estimate, err := fraes.EstimateDimensionWith(curve, proj, estimator)
+		if err != nil {
+			return err
+		}
+		result.Estimates = append(result.Estimates, estimate)
+		if estimator == fraes.EstimatorMultifractal {
+			spectrum := fraes.AnalyzeMultifractalWith(curve, proj, qs)
+			result.Multifractal = &spectrum
+		}
+	}
+	return nil
+}
+
+func printEstimatorComparison(assessment dimensionAssessment) {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("\tСРАВНЕНИЕ ОЦЕНОК РАЗМЕРНОСТИ")
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("D по каждой оценке; * — стабильна на нескольких масштабах, n/a — оценка невалидна")
+	fmt.Println()
+
+	fmt.Printf("%-5s", "Итер.")
+	for _, estimator := range assessment.Estimators {
+		fmt.Printf(" %-13s", estimator)
 	}
+	fmt.Println()
+	width := 5 + 14*len(assessment.Estimators)
+	fmt.Println(strings.Repeat("─", width))
+	for _, result := range assessment.Iterations {
+		fmt.Printf("%-5d", result.Iteration)
+		for _, estimate := range result.Estimates {
+			value := "n/a"
+			if estimate.Valid {
+				value = fmt.Sprintf("%.5f", estimate.Dimension)
+				if estimate.StableAcrossScales {
+					value += "*"
+				}
+			}
+			fmt.Printf(" %-13s", value)
+		}
+		fmt.Println()
+	}
+	fmt.Println(strings.Repeat("─", width))
+
+	if !slices.Contains(assessment.Estimators, fraes.EstimatorMultifractal) {
+		return
+	}
+	fmt.Printf("Мультифрактальный спектр, q = %s:\n", formatQRange(assessment.QRange))
+	for _, result := range assessment.Iterations {
+		spectrum := result.Multifractal
+		if spectrum == nil || !spectrum.Valid || len(spectrum.Spectrum) == 0 {
+			fmt.Printf("  итер. %d: n/a\n", result.Iteration)
+			continue
+		}
+		first, last := spectrum.Spectrum[0], spectrum.Spectrum[len(spectrum.Spectrum)-1]
+		fmt.Printf("  итер. %d: D(%g)=%.4f … D(%g)=%.4f, α ∈ [%.4f, %.4f], Δα=%.4f\n",
+			result.Iteration, first.Q, first.Dq, last.Q, last.Dq, spectrum.AlphaMin, spectrum.AlphaMax, spectrum.AlphaMax-spectrum.AlphaMin)
+	}
+	fmt.Println("Узкий спектр (Δα → 0) — монофрактал: длина распределена по линии равномерно.")
+}
 
-	fmt.Println(strings.Repeat("─", 104))
-	return printDimensionAssessment(results, theoreticalDimension), nil
+func formatQRange(qs []float64) string {
+	values := make([]string, len(qs))
+	for i, q := range qs {
+		values[i] = strconv.FormatFloat(q, 'g', -1, 64)
+	}
+	return strings.Join(values, ", ")
 }
 
 func printDimensionAssessment(results []dimensionIterationResult, theoreticalDimension float64) dimensionAssessment {
@@ -128,6 +291,11 @@ func printDimensionAssessment(results []dimensionIterationResult, theoreticalDim
 	deltaTheory := math.Abs(finalDimension - theoreticalDimension)
 
 	fmt.Printf("Последняя оценка: D=%.5f, |D-D_theory|=%.5f\n", finalDimension, deltaTheory)
+	if interval := tail[len(tail)-1].Interval; interval.Valid {
+		fmt.Printf("95%% ДИ последней оценки: [%.5f, %.5f], СО=%.5f (%d повторов); теория внутри ДИ: %v\n",
+			interval.Lower, interval.Upper, interval.StdError, interval.Replicates,
+			yesNo(theoreticalDimension >= interval.Lower && theoreticalDimension <= interval.Upper))
+	}
 	fmt.Printf("Сходимость по последним %d итерациям: %v\n", minConvergedIterations, yesNo(convergedAcrossIterations))
 	fmt.Printf("Стабильность на нескольких масштабах: %v\n", yesNo(stableAcrossScales))
 
@@ -142,6 +310,12 @@ func printDimensionAssessment(results []dimensionIterationResult, theoreticalDim
 	return dimensionAssessment{Valid: false}
 }
 
+// dimensionBootstrapSeed derives the bootstrap seed of an iteration from the
+// run seed.
+func dimensionBootstrapSeed(seed int64, iter int) int64 {
+	return seed*1_000 + int64(iter)
+}
+
 func yesNo(value bool) string {
 	if value {
 		return "yes"
@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/simulations/ensemble"
+	"coastal-geometry/internal/domain/simulations/paradox"
+	svgrender "coastal-geometry/internal/render/svg"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ensembleSample is one member's measurements at one iteration or step; NaN
+// marks an invalid box-counting estimate.
+type ensembleSample struct {
+	LengthKM  float64
+	AreaKM2   float64
+	Dimension float64
+}
+
+type ensembleMember struct {
+	Seed    int64
+	Samples []ensembleSample
+	// Final is the last iteration or step, simplified for the SVG.
+	Final []fraes.LatLon
+	Err   error
+}
+
+// ensembleSpec names the model an ensemble runs. Axis is the table column
+// of the iteration or step index; Years label scenario steps.
+type ensembleSpec struct {
+	Name   string
+	Title  string
+	Axis   string
+	Header string
+	Years  []int
+}
+
+type ensembleReport struct {
+	ensembleSpec
+	Members    []ensembleMember
+	Workers    int
+	Length     []ensemble.Summary
+	Area       []ensemble.Summary
+	Dimension  []ensemble.Summary
+	Projection fraes.Projector
+}
+
+// runEnsemble repeats the model for --ensemble consecutive seeds starting at
+// --seed, so member 0 is the run just reported. A zero seed starts at the
+// current time, as the single runs do.
+func runEnsemble(app *App, spec ensembleSpec, member func(seed int64) ensembleMember) error {
+	cfg := app.Config
+	base := cfg.Seed
+	if base == 0 {
+		base = time.Now().UnixNano()
+	}
+	seeds := ensemble.Seeds(base, cfg.Ensemble)
+	members := ensemble.Run(len(seeds), cfg.EnsembleWorkers, func(i int) ensembleMember {
+		result := member(seeds[i])
+		result.Seed = seeds[i]
+		return result
+	})
+	for _, m := range members {
+		if m.Err != nil {
+			return fmt.Errorf("ensemble seed %d: %w", m.Seed, m.Err)
+		}
+	}
+
+	report := summarizeEnsemble(spec, members, app.Projection)
+	report.Workers = ensembleWorkers(cfg.EnsembleWorkers, len(members))
+	renderEnsembleReport(os.Stdout, report)
+
+	ctx := newExportContext(app)
+	svgFile, err := writeEnsembleSVG(app.Base, report, cfg.OutputPath, ctx)
+	if err != nil {
+		return err
+	}
+	if err := writeEnsembleMetrics(report, svgFile, cfg.OutputPath, ctx); err != nil {
+		return err
+	}
+	return writeDataTable(ensembleTable(report), cfg.OutputPath, ctx)
+}
+
+func ensembleWorkers(workers, members int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return min(workers, members)
+}
+
+func summarizeEnsemble(spec ensembleSpec, members []ensembleMember, proj fraes.Projector) ensembleReport {
+	lengths := make([][]float64, len(members))
+	areas := make([][]float64, len(members))
+	dimensions := make([][]float64, len(members))
+	for i, m := range members {
+		for _, s := range m.Samples {
+			lengths[i] = append(lengths[i], s.LengthKM)
+			areas[i] = append(areas[i], s.AreaKM2)
+			dimensions[i] = append(dimensions[i], s.Dimension)
+		}
+	}
+	return ensembleReport{
+		ensembleSpec: spec,
+		Members:      members,
+		Length:       ensemble.Column(lengths),
+		Area:         ensemble.Column(areas),
+		Dimension:    ensemble.Column(dimensions),
+		Projection:   proj,
+	}
+}
+
+func measureEnsembleSample(points []fraes.LatLon, area fraes.AreaMeasure, proj fraes.Projector) ensembleSample {
+	sample := ensembleSample{LengthKM: fraes.PolylineLength(points), Dimension: math.NaN()}
+	if area != nil {
+		sample.AreaKM2 = area.Area(points)
+	} else {
+		sample.AreaKM2 = fraes.Area(points)
+	}
+	if analysis := fraes.AnalyzeBoxCountingWith(points, proj); analysis.Valid {
+		sample.Dimension = analysis.Dimension
+	}
+	return sample
+}
+
+// organicEnsembleMember grows the organic curve the way the koch-organic
+// series does, erosion included.
+func organicEnsembleMember(app *App, opts fraes.OrganicOptions) func(seed int64) ensembleMember {
+	return func(seed int64) ensembleMember {
+		opts := opts
+		opts.Seed = seed
+		var member ensembleMember
+		var curve []fraes.LatLon
+		for iter := 0; iter <= app.Config.Iterations; iter++ {
+			curve = fraes.OrganicKochCurve(app.ModelBase, iter, organicCurveOptions(opts)...)
+			if app.Config.ErosionStrength > 0 {
+				curve = fraes.Erode(curve, app.Config.ErosionStrength, seed+int64(iter), app.Projection)
+			}
+			member.Samples = append(member.Samples, measureEnsembleSample(curve, app.Area, app.Projection))
+		}
+		member.Final = simplifyForSeriesSVG(curve, app.Projection).Points
+		return member
+	}
+}
+
+func paradoxEnsembleMember(app *App) func(seed int64) ensembleMember {
+	return func(seed int64) ensembleMember {
+		var member ensembleMember
+		var curve []fraes.LatLon
+		for level := 0; level <= app.Config.Iterations; level++ {
+			curve = paradox.Curve(app.ModelBase, level, app.Config.ErosionStrength, seed, app.Projection)
+			member.Samples = append(member.Samples, measureEnsembleSample(curve, app.Area, app.Projection))
+		}
+		member.Final = simplifyForSeriesSVG(curve, app.Projection).Points
+		return member
+	}
+}
+
+// erosionEnsembleMember reruns the whole erosion setup with another seed, so
+// scenario storms are redrawn as well.
+func erosionEnsembleMember(app *App) func(seed int64) ensembleMember {
+	return func(seed int64) ensembleMember {
+		run := *app
+		run.Config.Seed = seed
+		series, err := simulateErosion(&run)
+		if err != nil {
+			return ensembleMember{Err: err}
+		}
+		var member ensembleMember
+		for _, state := range series.Snapshots {
+			sample := measureEnsembleSample(state, nil, app.Projection)
+			sample.AreaKM2 = series.areaKM2(state)
+			member.Samples = append(member.Samples, sample)
+		}
+		member.Final = simplifyForSeriesSVG(series.Snapshots[len(series.Snapshots)-1], app.Projection).Points
+		return member
+	}
+}
+
+// erosionEnsembleSpec labels the ensemble steps with the scenario years of
+// the single run.
+func erosionEnsembleSpec(series erosionSeries) ensembleSpec {
+	spec := ensembleSpec{Name: "erosion", Title: "Эрозия", Axis: "step", Header: "Шаг"}
+	if series.Scenario != nil {
+		for i := range series.Snapshots {
+			year := series.Scenario.StartYear
+			if i > 0 && i <= len(series.Timeline) {
+				year = series.Timeline[i-1].ToYear
+			}
+			spec.Years = append(spec.Years, year)
+		}
+		spec.Header = "Год"
+	}
+	return spec
+}
+
+func (r ensembleReport) label(step int) string {
+	if step < len(r.Years) {
+		return fmt.Sprintf("%d", r.Years[step])
+	}
+	return fmt.Sprintf("%d", step)
+}
+
+func (r ensembleReport) seedRange() string {
+	if len(r.Members) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d–%d", r.Members[0].Seed, r.Members[len(r.Members)-1].Seed)
+}
+
+func renderEnsembleReport(w io.Writer, report ensembleReport) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintf(w, "\tАНСАМБЛЬ: %s, ПРОГОНОВ: %d\n", strings.ToUpper(report.Title), len(report.Members))
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Seed %s, параллельно: %d\n\n", report.seedRange(), report.Workers)
+
+	fmt.Fprintf(w, "%-6s %-12s %-12s %-22s %-10s %-14s %-24s %-9s %-20s\n",
+		report.Header, "Длина ср.", "Длина мед.", "Длина P5–P95, км", "СО, км", "Площадь мед.", "Площадь P5–P95, км²", "D мед.", "D P5–P95 (n)")
+	fmt.Fprintln(w, strings.Repeat("-", 140))
+	deterministic := true
+	for i := range report.Length {
+		length, area, dimension := report.Length[i], report.Area[i], report.Dimension[i]
+		if length.StdDev > 0 {
+			deterministic = false
+		}
+		dimensionMedian, dimensionRange := "n/a", fmt.Sprintf("n/a (0/%d)", len(report.Members))
+		if dimension.Count > 0 {
+			dimensionMedian = fmt.Sprintf("%.4f", dimension.Median)
+			dimensionRange = fmt.Sprintf("%.4f–%.4f (%d/%d)", dimension.P5, dimension.P95, dimension.Count, len(report.Members))
+		}
+		fmt.Fprintf(w, "%-6s %-12.1f %-12.1f %-22s %-10.1f %-14.0f %-24s %-9s %-20s\n",
+			report.label(i),
+			length.Mean,
+			length.Median,
+			fmt.Sprintf("%.1f–%.1f", length.P5, length.P95),
+			length.StdDev,
+			area.Median,
+			fmt.Sprintf("%.0f–%.0f", area.P5, area.P95),
+			dimensionMedian,
+			dimensionRange)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 140))
+	if deterministic {
+		fmt.Fprintln(w, "Прогоны совпадают: при этих параметрах модель не зависит от seed.")
+	} else {
+		fmt.Fprintln(w, "P5–P95 — 90% прогонов; n — прогоны с валидной box-counting оценкой D.")
+	}
+}
+
+func writeEnsembleSVG(reference []fraes.LatLon, report ensembleReport, output string, ctx exportContext) (string, error) {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return "", err
+	}
+	filename := filepath.Join(outputDir, report.Name+"-ensemble.svg")
+
+	runs := svgrender.Layer{
+		Label:       fmt.Sprintf("Прогоны ансамбля (%d), %s %s", len(report.Members), strings.ToLower(report.Header), report.label(len(report.Length)-1)),
+		Stroke:      "#8b3f5c",
+		StrokeWidth: 1.2,
+		Opacity:     0.35,
+	}
+	for i, m := range report.Members {
+		if i == 0 {
+			runs.Points = m.Final
+			continue
+		}
+		runs.Parts = append(runs.Parts, m.Final)
+	}
+	layers := []svgrender.Layer{
+		{
+			Label:       "Реальная загруженная полилиния (справочно)",
+			Points:      simplifyForSeriesSVG(reference, ctx.Projection).Points,
+			LengthKM:    fraes.PolylineLength(reference),
+			Stroke:      "#6b7a87",
+			StrokeWidth: 1.6,
+			Opacity:     0.8,
+			DashArray:   "6 4",
+		},
+		runs,
+	}
+
+	var charts []svgrender.Chart
+	for _, chart := range []svgrender.Chart{
+		buildFanChart("Длина, км", report.Length, "#1f6f8b", report),
+		buildFanChart("Площадь, км²", report.Area, "#3f6b4b", report),
+		buildFanChart("Размерность D", report.Dimension, "#8b3f5c", report),
+	} {
+		if len(chart.Series) > 0 {
+			charts = append(charts, chart)
+		}
+	}
+
+	last := len(report.Length) - 1
+	doc := svgrender.Document{
+		Title:    fmt.Sprintf("Ансамбль: %s", report.Title),
+		Subtitle: fmt.Sprintf("Прогонов: %d, seed %s; веер — медиана, полосы P25–P75 и P5–P95, пунктир — среднее", len(report.Members), report.seedRange()),
+		Layers:   layers,
+		StatCards: []svgrender.StatCard{{
+			Title: fmt.Sprintf("%s %s: медиана [P5–P95]", report.Header, report.label(last)),
+			Items: []svgrender.StatItem{
+				{Label: "Длина, км", Value: formatEnsembleRange(report.Length[last], "%.0f")},
+				{Label: "Площадь, км²", Value: formatEnsembleRange(report.Area[last], "%.0f")},
+				{Label: "D", Value: formatEnsembleRange(report.Dimension[last], "%.3f")},
+			},
+		}},
+		Charts: charts,
+		Meta: []string{
+			fmt.Sprintf("Прогонов: %d, одновременно: %d", len(report.Members), report.Workers),
+			fmt.Sprintf("СО длины на последнем шаге: %.1f км", report.Length[last].StdDev),
+		},
+		Projection: ctx.Projection,
+	}
+	if err := svgrender.DrawDocument(doc, filename); err != nil {
+		return "", err
+	}
+	fmt.Printf("SVG saved to %s\n", filename)
+	return filename, nil
+}
+
+func formatEnsembleRange(s ensemble.Summary, format string) string {
+	if s.Count == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf(format+" ["+format+"–"+format+"]", s.Median, s.P5, s.P95)
+}
+
+// buildFanChart draws the median over the P25–P75 band, which in turn sits
+// inside the P5–P95 band; the bands overlap, so the inner one reads darker.
+func buildFanChart(title string, summaries []ensemble.Summary, stroke string, report ensembleReport) svgrender.Chart {
+	n := len(summaries)
+	mean, median := make([]float64, n), make([]float64, n)
+	p5, p25, p75, p95 := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	hasValues := false
+	for i, s := range summaries {
+		if s.Count == 0 {
+			mean[i], median[i], p5[i], p25[i], p75[i], p95[i] = math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		mean[i], median[i], p5[i], p25[i], p75[i], p95[i] = s.Mean, s.Median, s.P5, s.P25, s.P75, s.P95
+		hasValues = true
+	}
+	if !hasValues {
+		return svgrender.Chart{}
+	}
+	return svgrender.Chart{
+		Title: title,
+		Series: []svgrender.ChartSeries{
+			{Label: "P5–P95", Lower: p5, Upper: p95, Stroke: stroke},
+			{Label: "Медиана", Values: median, Lower: p25, Upper: p75, Stroke: stroke},
+			{Label: "Среднее", Values: mean, Stroke: "#6b7a87", DashArray: "5 4"},
+		},
+		XMinLabel: report.label(0),
+		XMaxLabel: report.label(n - 1),
+	}
+}
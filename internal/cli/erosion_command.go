@@ -1,30 +1,397 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/lithology"
+	"coastal-geometry/internal/domain/simulations/erosion"
+	"coastal-geometry/internal/domain/simulations/percolation"
+	"coastal-geometry/internal/domain/simulations/scenario"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
+	"math"
 	"strings"
 )
 
-func runErosionCommand(app *App) error {
-	steps := app.Config.Steps
-	strength := app.Config.ErosionStrength
-	seed := app.Config.Seed
+type erosionSeries struct {
+	Model     string
+	Strength  float64
+	Seed      int64
+	Climate   erosion.WaveClimate
+	Snapshots [][]fraes.LatLon
+	WaveSteps []erosion.WaveStepStats
+	// Sediment holds the longshore transport budget of every step when
+	// --sediment is set, so Sediment[i] belongs to Snapshots[i+1].
+	Sediment     []erosion.SedimentBudget
+	SedimentRate float64
+	Lithology    lithology.Profile
+	Rocks        lithology.Assignment
+	// Timeline is the scenario forcing, so Timeline[i] produced Snapshots[i+1].
+	Scenario *scenario.Scenario
+	Timeline []scenario.Step
+	// Percolation is the grid run of --erosion-model=percolation, so
+	// Percolation.Steps[i] produced Snapshots[i+1]. Dimensions holds the
+	// box-counting D of every snapshot, NaN when the estimate is invalid.
+	Percolation        *percolation.Result
+	PercolationOptions percolation.Options
+	Dimensions         []float64
+	// Area measures the snapshot areas; nil means planar.
+	Area fraes.AreaMeasure
+	// Projection is the plane both models erode in.
+	Projection fraes.Projector
+}
 
-	snapshots := geometry.SimulateErosionWithSeed(app.ModelBase, steps, strength, seed)
+func runErosionCommand(app *App) error {
+	series, err := simulateErosion(app)
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("\n" + strings.Repeat("=", 80))
 	fmt.Println("\tЭРОЗИЯ: МНОГОШАГОВАЯ СИМУЛЯЦИЯ")
 	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("Шаги=%d, σ=%.1f м, seed=%d\n\n", steps, strength, seed)
-	fmt.Printf("%-6s %-10s %-12s %-14s\n", "Шаг", "Точек", "Длина, км", "Площадь, км²")
-	fmt.Println(strings.Repeat("-", 56))
 
-	for i, state := range snapshots {
-		length := geometry.PolylineLength(state)
-		area := geometry.Area(state)
-		fmt.Printf("%-6d %-10d %-12.0f %-14.0f\n", i, len(state), length, area)
+	if len(series.Rocks) > 0 {
+		printLithologyTable(series)
+	}
+	if series.Scenario != nil {
+		printScenarioTable(series)
+	}
+
+	switch series.Model {
+	case erosionModelWave:
+		printWaveErosionTable(series)
+	case erosionModelPercolation:
+		series.Dimensions = snapshotDimensions(series)
+		printPercolationTable(series)
+	default:
+		if series.Scenario != nil {
+			fmt.Printf("Шаги=%d, σ = отступ шага по сценарию, seed=%d\n\n", len(series.Timeline), series.Seed)
+		} else {
+			fmt.Printf("Шаги=%d, σ=%.1f м, seed=%d\n\n", app.Config.Steps, series.Strength, series.Seed)
+		}
+		fmt.Printf("%-6s %-10s %-12s %-14s\n", "Шаг", "Точек", "Длина, км", "Площадь, км²")
+		fmt.Println(strings.Repeat("-", 56))
+
+		for i, state := range series.Snapshots {
+			length := fraes.PolylineLength(state)
+			area := series.areaKM2(state)
+			fmt.Printf("%-6s %-10d %-12.0f %-14.0f\n", stepLabel(series, i), len(state), length, area)
+		}
+	}
+
+	if series.Area != nil && series.Area.Name() != fraes.AreaPlanar {
+		fmt.Printf("Площадь: %s (--area=%s — плоская сетка для сравнения)\n", areaLabel(series.Area), fraes.AreaPlanar)
+	}
+
+	if len(series.Sediment) > 0 {
+		printSedimentTable(series)
+	}
+
+	ctx := newExportContext(app)
+	if err := writeErosionSVGSeries(app.Base, app.ModelBase, series, app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	if err := writeDataTable(erosionTable(series), app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if len(series.Rocks) > 0 {
+		if err := writeDataTable(lithologyTable(series), app.Config.OutputPath, ctx); err != nil {
+			return err
+		}
+	}
+	if app.Config.Ensemble == 0 {
+		return nil
+	}
+	return runEnsemble(app, erosionEnsembleSpec(series), erosionEnsembleMember(app))
+}
+
+// areaKM2 is the snapshot area measured with the series' area measure.
+func (s erosionSeries) areaKM2(points []fraes.LatLon) float64 {
+	if s.Area == nil {
+		return fraes.Area(points)
+	}
+	return s.Area.Area(points)
+}
+
+func simulateErosion(app *App) (erosionSeries, error) {
+	cfg := app.Config
+	series := erosionSeries{
+		Model:      cfg.ErosionModel,
+		Strength:   cfg.ErosionStrength,
+		Seed:       cfg.Seed,
+		Lithology:  app.Lithology,
+		Rocks:      app.Rocks,
+		Area:       app.Area,
+		Projection: app.Projection,
+	}
+	if series.Model == "" {
+		series.Model = erosionModelGaussian
+	}
+
+	strengths := make([]float64, cfg.Steps)
+	for i := range strengths {
+		strengths[i] = cfg.ErosionStrength
+	}
+	if app.Scenario != nil {
+		series.Scenario = app.Scenario
+		series.Timeline = app.Scenario.Timeline(cfg.Seed)
+		strengths = make([]float64, len(series.Timeline))
+		for i, step := range series.Timeline {
+			strengths[i] = step.TotalM
+		}
+	}
+
+	var sediment *erosion.SedimentOptions
+	if cfg.Sediment {
+		climate, err := erosion.ParseWaveClimate(cfg.WaveClimate)
+		if err != nil {
+			return erosionSeries{}, fmt.Errorf("wave-climate: %w", err)
+		}
+		series.Climate = climate
+		series.SedimentRate = cfg.SedimentRate
+		sediment = &erosion.SedimentOptions{RateM3: cfg.SedimentRate, Climate: climate, Projection: app.Projection}
+	}
+
+	if series.Model == erosionModelPercolation {
+		series.Strength = 0
+		series.PercolationOptions = percolation.Options{
+			Steps:      cfg.Steps,
+			Seed:       cfg.Seed,
+			Cells:      cfg.GridCells,
+			Force:      cfg.SeaForce,
+			Damping:    cfg.SeaDamping,
+			Projection: app.Projection,
+		}
+		result := percolation.Simulate(app.ModelBase, series.PercolationOptions)
+		series.Percolation = &result
+		series.Snapshots = result.Snapshots
+		return series, nil
+	}
+
+	if series.Model != erosionModelWave {
+		var weights []float64
+		if len(series.Rocks) > 0 {
+			weights = series.Rocks.Weights()
+		}
+		series.Snapshots, series.Sediment = simulateGaussian(app.ModelBase, strengths, cfg.Seed, weights, sediment, app.Projection)
+		return series, nil
+	}
+
+	climate, err := erosion.ParseWaveClimate(cfg.WaveClimate)
+	if err != nil {
+		return erosionSeries{}, fmt.Errorf("wave-climate: %w", err)
 	}
 
-	return writeErosionSVGSeries(app.Base, app.ModelBase, snapshots, steps, strength, seed, app.Config.OutputPath, newExportContext(app))
+	var obstacles [][]fraes.LatLon
+	if rings := app.Coastline.RingPoints(); len(rings) > 1 {
+		obstacles = rings[1:]
+	}
+
+	opts := erosion.WaveOptions{
+		Steps:      len(strengths),
+		StrengthM:  cfg.ErosionStrength,
+		Seed:       cfg.Seed,
+		Climate:    climate,
+		Obstacles:  obstacles,
+		Jitter:     erosion.DefaultJitter,
+		Resistance: series.Rocks.Resistances(),
+		Sediment:   sediment,
+		Projection: app.Projection,
+	}
+	if series.Scenario != nil {
+		// Scenario rates are mean shoreline retreat, not the retreat of the
+		// most exposed headland.
+		opts.StepStrengthM = strengths
+		opts.MeanRetreat = true
+	}
+	result := erosion.SimulateWave(app.ModelBase, opts)
+	series.Climate = climate
+	series.Snapshots = result.Snapshots
+	series.WaveSteps = result.Steps
+	series.Sediment = result.Sediment
+	return series, nil
+}
+
+// simulateGaussian runs one Gaussian erosion step per strength. With sediment
+// options every step is followed by longshore transport, so the next step
+// starts from the redistributed shore.
+func simulateGaussian(base []fraes.LatLon, strengths []float64, seed int64, weights []float64, sediment *erosion.SedimentOptions, proj fraes.Projector) ([][]fraes.LatLon, []erosion.SedimentBudget) {
+	snapshots := make([][]fraes.LatLon, len(strengths)+1)
+	var budgets []erosion.SedimentBudget
+	current := append([]fraes.LatLon(nil), base...)
+	snapshots[0] = current
+	for i, strength := range strengths {
+		step := i + 1
+		next := fraes.ErosionStep(current, strength, seed, step, weights, proj)
+		if sediment != nil {
+			var budget erosion.SedimentBudget
+			next, budget = erosion.TransportSediment(current, next, *sediment)
+			budgets = append(budgets, budget)
+		}
+		snapshots[step] = next
+		current = next
+	}
+	return snapshots, budgets
+}
+
+// stepLabel names a snapshot by its calendar year in scenario runs and by
+// the step number otherwise.
+func stepLabel(series erosionSeries, step int) string {
+	if series.Scenario == nil {
+		return fmt.Sprintf("%d", step)
+	}
+	if step == 0 || step > len(series.Timeline) {
+		return fmt.Sprintf("%d", series.Scenario.StartYear)
+	}
+	return fmt.Sprintf("%d", series.Timeline[step-1].ToYear)
+}
+
+func printScenarioTable(series erosionSeries) {
+	sc := series.Scenario
+	name := sc.Name
+	if name == "" {
+		name = sc.Source
+	}
+	fmt.Printf("Сценарий: %s, %d–%d, шаг %d г., seed=%d\n", name, sc.StartYear, sc.EndYear, sc.StepYears, series.Seed)
+	fmt.Printf("%-12s %-10s %-14s %-12s %-14s %-10s %-10s\n",
+		"Годы", "Фон, м", "Штормы", "Штормы, м", "Уровень, см", "Брюн, м", "Итого, м")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, step := range series.Timeline {
+		fmt.Printf("%-12s %-10.2f %-14d %-12.2f %-14.1f %-10.2f %-10.2f\n",
+			fmt.Sprintf("%d–%d", step.FromYear, step.ToYear),
+			step.BackgroundM,
+			step.Storms,
+			step.StormM,
+			step.SeaLevelM*100,
+			step.BruunM,
+			step.TotalM)
+	}
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Println("Итого — средний отступ берега за шаг: фон + штормы + отклик на подъём уровня моря по правилу Брюна.")
+	fmt.Println()
+}
+
+func printWaveErosionTable(series erosionSeries) {
+	if series.Scenario != nil {
+		fmt.Printf("Модель: волновая (fetch/экспозиция), шаги=%d, средний отступ по сценарию, seed=%d\n", len(series.WaveSteps), series.Seed)
+	} else {
+		fmt.Printf("Модель: волновая (fetch/экспозиция), шаги=%d, макс. отступ=%.1f м, seed=%d\n", len(series.WaveSteps), series.Strength, series.Seed)
+	}
+	fmt.Printf("Волновой климат (откуда, доля): %s\n\n", series.Climate)
+	fmt.Printf("%-6s %-10s %-12s %-14s %-12s %-10s %-10s %-18s\n",
+		"Шаг", "Точек", "Длина, км", "Площадь, км²", "Fetch ср., км", "Экспоз.", "Открыто", "Отступ ср./макс., м")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for i, state := range series.Snapshots {
+		length := fraes.PolylineLength(state)
+		area := series.areaKM2(state)
+		if i == 0 {
+			fmt.Printf("%-6s %-10d %-12.0f %-14.0f %-12s %-10s %-10s %-18s\n", stepLabel(series, i), len(state), length, area, "—", "—", "—", "—")
+			continue
+		}
+		stats := series.WaveSteps[i-1]
+		fmt.Printf("%-6s %-10d %-12.0f %-14.0f %-12.1f %-10.3f %-10s %-18s\n",
+			stepLabel(series, i), len(state), length, area,
+			stats.MeanFetchKM,
+			stats.MeanExposure,
+			fmt.Sprintf("%.0f%%", stats.ExposedShare*100),
+			fmt.Sprintf("%.1f / %.1f", stats.MeanRetreatM, stats.MaxRetreatM))
+	}
+	fmt.Println(strings.Repeat("-", 100))
+
+	if last := len(series.WaveSteps); last > 0 {
+		stats := series.WaveSteps[last-1]
+		fmt.Printf("Последний шаг: мысы (%d т.) отступают в среднем на %.1f м, бухты (%d т.) — на %.1f м\n",
+			stats.Headlands, stats.HeadlandRetreatM, stats.Bays, stats.BayRetreatM)
+	}
+}
+
+// snapshotDimensions is the box-counting D of every snapshot, NaN where the
+// estimate is invalid.
+func snapshotDimensions(series erosionSeries) []float64 {
+	dimensions := make([]float64, len(series.Snapshots))
+	for i, state := range series.Snapshots {
+		dimensions[i] = math.NaN()
+		if analysis := fraes.AnalyzeBoxCountingWith(state, series.Projection); analysis.Valid {
+			dimensions[i] = analysis.Dimension
+		}
+	}
+	return dimensions
+}
+
+func printPercolationTable(series erosionSeries) {
+	run, opts := series.Percolation, series.PercolationOptions
+	fmt.Printf("Модель: перколяционная (Sapoval), сетка %d×%d, ячейка %.2f км, f0=%.2f, g=%.2f, seed=%d\n",
+		run.Columns, run.Rows, run.CellM/1000, opts.Force, opts.Damping, series.Seed)
+	fmt.Printf("Проходов до стабилизации берега: %d; шаги делят их поровну, шаг 0 — исходная линия на сетке\n\n", run.Sweeps)
+	fmt.Printf("%-6s %-10s %-12s %-12s %-12s %-10s %-12s %-14s %-8s\n",
+		"Шаг", "Проходов", "Сила моря", "Размыто", "Берег, яч.", "Точек", "Длина, км", "Площадь, км²", "D")
+	fmt.Println(strings.Repeat("-", 104))
+	for i, state := range series.Snapshots {
+		sweeps, force, eroded, coast := "0", fmt.Sprintf("%.3f", opts.Force), "0", "—"
+		if i > 0 {
+			stats := run.Steps[i-1]
+			sweeps = fmt.Sprintf("%d", stats.Sweeps)
+			force = fmt.Sprintf("%.3f", stats.Force)
+			eroded = fmt.Sprintf("%d", stats.ErodedCells)
+			coast = fmt.Sprintf("%d", stats.CoastCells)
+		}
+		dimension := "n/a"
+		if !math.IsNaN(series.Dimensions[i]) {
+			dimension = fmt.Sprintf("%.4f", series.Dimensions[i])
+		}
+		fmt.Printf("%-6d %-10s %-12s %-12s %-12s %-10d %-12.0f %-14.0f %-8s\n",
+			i, sweeps, force, eroded, coast, len(state), fraes.PolylineLength(state), series.areaKM2(state), dimension)
+	}
+	fmt.Println(strings.Repeat("-", 104))
+	last := series.Dimensions[len(series.Dimensions)-1]
+	if math.IsNaN(last) {
+		fmt.Printf("Ориентир: D → 4/3 ≈ %.4f (внешний периметр перколяционного кластера); оценка D последнего шага невалидна\n", percolation.HullDimension)
+		return
+	}
+	fmt.Printf("Ориентир: D → 4/3 ≈ %.4f (внешний периметр перколяционного кластера), последний шаг: %.4f (%+.4f)\n",
+		percolation.HullDimension, last, last-percolation.HullDimension)
+}
+
+func printSedimentTable(series erosionSeries) {
+	fmt.Println()
+	fmt.Printf("Баланс наносов (вдольбереговой дрейф, климат %s), тыс. м³\n", series.Climate)
+	fmt.Printf("%-6s %-12s %-12s %-12s %-12s %-12s %-18s\n",
+		"Шаг", "Размыв", "Отложено", "Вынос", "Итог", "Дрейф", "Нараст./отступ, т.")
+	fmt.Println(strings.Repeat("-", 90))
+	for i, budget := range series.Sediment {
+		fmt.Printf("%-6s %-12.1f %-12.1f %-12.1f %-12.1f %-12.1f %-18s\n",
+			stepLabel(series, i+1),
+			budget.ErodedM3/1000,
+			budget.DepositedM3/1000,
+			budget.ExportedM3/1000,
+			budget.NetM3/1000,
+			budget.GrossDriftM3/1000,
+			fmt.Sprintf("%d / %d", budget.AccretingPoints, budget.RetreatingPoints))
+	}
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Println("Итог = отложено − размыв; для замкнутого контура он равен нулю, для открытой линии — минус вынос через концы.")
+}
+
+func printLithologyTable(series erosionSeries) {
+	name := series.Lithology.Name
+	if name == "" {
+		name = series.Lithology.Source
+	}
+	fmt.Printf("Литология: %s\n", name)
+	fmt.Printf("%-22s %-14s %-10s %-12s %-8s\n", "Порода", "Устойчивость", "Точек", "Длина, км", "Доля")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, summary := range series.Rocks.Summaries(series.Snapshots[0]) {
+		fmt.Printf("%-22s %-14.2f %-10d %-12.0f %-8s\n",
+			summary.Rock.Label,
+			summary.Rock.Resistance,
+			summary.Points,
+			summary.LengthKM,
+			fmt.Sprintf("%.0f%%", summary.ShareOfLen*100))
+	}
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Println("Сдвиг точек масштабируется как 1/устойчивость породы.")
+	fmt.Println()
 }
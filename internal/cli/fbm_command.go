@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/pkg/fraes"
+	"math"
+	"os"
+)
+
+func runFBMCommand(app *App) error {
+	opts := fbmOptions(app)
+	report := fbm.Analyze(app.ModelBase, app.Config.Iterations, opts)
+	dimensions := fbmDimensions(app, opts)
+	renderFBMReport(os.Stdout, report, dimensions)
+
+	ctx := newExportContext(app)
+	if err := writeFBMSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	return writeDataTable(fbmTable(report, dimensions), app.Config.OutputPath, ctx)
+}
+
+func fbmOptions(app *App) fbm.Options {
+	return fbm.Options{
+		Seed:       app.Config.Seed,
+		Hurst:      app.Config.Hurst,
+		Amplitude:  app.Config.Amplitude,
+		Projection: app.Projection,
+	}
+}
+
+// fbmDimensions is the box-counting D of every iteration for the console
+// and the tables, NaN where the estimate is invalid; the SVG series adds the
+// bootstrap interval.
+func fbmDimensions(app *App, opts fbm.Options) []float64 {
+	dimensions := make([]float64, app.Config.Iterations+1)
+	for iter := range dimensions {
+		dimensions[iter] = math.NaN()
+		if analysis := fraes.AnalyzeBoxCountingWith(fbm.Curve(app.ModelBase, iter, opts), app.Projection); analysis.Valid {
+			dimensions[iter] = analysis.Dimension
+		}
+	}
+	return dimensions
+}
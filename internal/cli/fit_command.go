@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// fitParameterNames lists the searched parameters in the order the model
+// receives their values.
+var fitParameterNames = []string{"iterations", "angle-jitter", "height-jitter", "erosion-strength"}
+
+var fitDefaultBounds = map[string]string{
+	"iterations":       "1:4",
+	"angle-jitter":     "0:30",
+	"height-jitter":    "0:0.5",
+	"erosion-strength": "0:500",
+}
+
+const fitDefaultWeights = "dimension=1,richardson=1,sinuosity=1"
+
+func fitParameterUsage(name string) string {
+	switch name {
+	case "iterations":
+		return fmt.Sprintf("organic Koch iterations as a value or min:max of whole numbers (0-%d, default %q)", fitMaxIterations(), fitDefaultBounds[name])
+	case "angle-jitter":
+		return fmt.Sprintf("angle deviation in degrees as a value or min:max (default %q)", fitDefaultBounds[name])
+	case "height-jitter":
+		return fmt.Sprintf("height deviation as a ratio, a value or min:max (default %q)", fitDefaultBounds[name])
+	default:
+		return fmt.Sprintf("Gaussian erosion sigma in meters after growth as a value or min:max, 0 disables erosion (default %q)", fitDefaultBounds[name])
+	}
+}
+
+// fitMaxIterations is the deepest iteration whose curve grown from the fit
+// base stays within modelCurvePointBudget.
+func fitMaxIterations() int {
+	n := 0
+	for n < fraes.MaxKochIterations && fitBasePoints*powInt(4, n+1) <= modelCurvePointBudget {
+		n++
+	}
+	return n
+}
+
+func runFitCommand(app *App) error {
+	cfg := app.Config
+	opts, err := fitOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	target := fit.Measure(app.Base, app.Projection, fit.DefaultSinuosityWindows)
+	fmt.Printf("Подбор модели: метод %s, прогонов на seed %d, seed %d–%d, база модели %d точек\n",
+		opts.Method, opts.Evaluations, opts.Seed, opts.Seed+int64(opts.Seeds-1), len(app.ModelBase))
+	fmt.Printf("Цель: %s\n", formatFitSignature(target))
+	opts.Progress = func(best fit.Evaluation) {
+		fmt.Printf("[seed %d, прогон %d/%d] невязка %s: %s\n", best.Seed, best.Index, opts.Evaluations,
+			formatCalibrationValue(best.Misfit.Total, "%.4f"), formatFitValues(opts.Parameters, best.Values))
+	}
+	result, err := fit.Run(opts, target, fitModel(app, opts.Parameters))
+	if err != nil {
+		return err
+	}
+
+	renderFitReport(os.Stdout, result)
+
+	ctx := newExportContext(app)
+	best := fitValues(result.Options.Parameters, result.Best.Values)
+	organic := fraes.OrganicOptions{
+		Seed:            result.Best.Seed,
+		AngleJitterDeg:  best["angle-jitter"],
+		HeightJitterPct: best["height-jitter"],
+	}
+	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, int(best["iterations"]), cfg.OutputPath, organic, best["erosion-strength"], "fit_iter", "fit-model", true, ctx); err != nil {
+		return err
+	}
+	if err := writeFitMetrics(result, len(app.ModelBase), cfg.OutputPath, ctx); err != nil {
+		return err
+	}
+	return writeDataTable(fitTable(result), cfg.OutputPath, ctx)
+}
+
+func validateFitConfig(cfg config) error {
+	if cfg.Evaluations < fit.MinEvaluations {
+		return fmt.Errorf("evaluations must be at least %d", fit.MinEvaluations)
+	}
+	if cfg.Seeds < 1 {
+		return fmt.Errorf("seeds must be positive")
+	}
+	if cfg.Workers < 0 {
+		return fmt.Errorf("workers must be non-negative")
+	}
+	_, err := fitOptions(cfg)
+	return err
+}
+
+func fitOptions(cfg config) (fit.Options, error) {
+	params, err := fitParameters(cfg)
+	if err != nil {
+		return fit.Options{}, err
+	}
+	weights, err := fitWeights(cfg.Weights)
+	if err != nil {
+		return fit.Options{}, err
+	}
+	return fit.Options{
+		Parameters:  params,
+		Method:      cfg.Method,
+		Evaluations: cfg.Evaluations,
+		Seeds:       cfg.Seeds,
+		Seed:        cfg.Seed,
+		Weights:     weights,
+		Workers:     cfg.Workers,
+	}.Normalize()
+}
+
+// fitParameters reads the bounds of every parameter; an absent flag keeps
+// the default bounds.
+func fitParameters(cfg config) ([]fit.Parameter, error) {
+	params := make([]fit.Parameter, 0, len(fitParameterNames))
+	for _, name := range fitParameterNames {
+		spec, ok := cfg.Ranges[name]
+		if !ok {
+			spec = fitDefaultBounds[name]
+		}
+		param, err := fit.ParseParameter(name, spec, name == "iterations")
+		if err != nil {
+			return nil, err
+		}
+		if param.Min < 0 {
+			return nil, fmt.Errorf("%s must be non-negative", name)
+		}
+		if name == "iterations" && param.Max > float64(fitMaxIterations()) {
+			return nil, fmt.Errorf("iterations must be between 0 and %d", fitMaxIterations())
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// fitWeights reads name=value pairs; terms left out keep weight 1.
+func fitWeights(spec string) (fit.Weights, error) {
+	weights := fit.DefaultWeights
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(part, "=")
+		if !ok {
+			return fit.Weights{}, fmt.Errorf("weights %q: %q must be name=value", spec, part)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return fit.Weights{}, fmt.Errorf("weights %q: %q is not a number", spec, raw)
+		}
+		if value < 0 {
+			return fit.Weights{}, fmt.Errorf("weights must be non-negative")
+		}
+		switch strings.TrimSpace(name) {
+		case "dimension":
+			weights.Dimension = value
+		case "richardson":
+			weights.Richardson = value
+		case "sinuosity":
+			weights.Sinuosity = value
+		default:
+			return fit.Weights{}, fmt.Errorf("weights %q: unknown term %q, expected dimension, richardson or sinuosity", spec, name)
+		}
+	}
+	if weights == (fit.Weights{}) {
+		return fit.Weights{}, fmt.Errorf("weights must leave at least one term above 0")
+	}
+	return weights, nil
+}
+
+func fitValues(params []fit.Parameter, values []float64) map[string]float64 {
+	named := make(map[string]float64, len(params))
+	for i, p := range params {
+		named[p.Name] = values[i]
+	}
+	return named
+}
+
+// fitModel grows the organic curve from the model base and erodes it the
+// way the koch-organic series does, so the fitted series reproduces the
+// best run.
+func fitModel(app *App, params []fit.Parameter) fit.Model {
+	return func(seed int64, values []float64) fit.Signature {
+		v := fitValues(params, values)
+		iterations := int(v["iterations"])
+		opts := fraes.OrganicOptions{Seed: seed, AngleJitterDeg: v["angle-jitter"], HeightJitterPct: v["height-jitter"]}
+		curve := fraes.OrganicKochCurve(app.ModelBase, iterations, organicCurveOptions(opts)...)
+		if strength := v["erosion-strength"]; strength > 0 {
+			curve = fraes.Erode(curve, strength, seed+int64(iterations), app.Projection)
+		}
+		return fit.Measure(curve, app.Projection, fit.DefaultSinuosityWindows)
+	}
+}
+
+func formatFitValues(params []fit.Parameter, values []float64) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.Name + "=" + formatFitValue(p, values[i])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatFitValue(p fit.Parameter, v float64) string {
+	if p.Integer {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+func formatFitSignature(sig fit.Signature) string {
+	return fmt.Sprintf("D=%s, D Ричардсона=%s, извилистость=%s, длина %.0f км, точек %d",
+		formatCalibrationValue(sig.Dimension, "%.4f"), formatCalibrationValue(sig.RichardsonDimension, "%.4f"),
+		formatCalibrationValue(sig.Sinuosity, "%.4f"), sig.LengthKM, sig.Points)
+}
+
+func formatFitWeights(w fit.Weights) string {
+	return fmt.Sprintf("D %g, Ричардсон %g, извилистость %g", w.Dimension, w.Richardson, w.Sinuosity)
+}
+
+func renderFitReport(w io.Writer, result fit.Result) {
+	opts := result.Options
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintln(w, "\tПОДБОР ПАРАМЕТРОВ МОДЕЛИ: ORGANIC KOCH + ЭРОЗИЯ")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Метод %s, прогонов на seed %d, seed %d–%d, веса невязки: %s\n", opts.Method, opts.Evaluations, opts.Seed, opts.Seed+int64(opts.Seeds-1), formatFitWeights(opts.Weights))
+	fmt.Fprintln(w, "Невязка: |ΔD| box counting + RMS ln(L_модель/L_цель) по общим шагам циркуля + |ln(S_модель/S_цель)| извилистости.")
+	fmt.Fprintf(w, "Цель:   %s\n", formatFitSignature(result.Target))
+	fmt.Fprintf(w, "Модель: %s\n\n", formatFitSignature(result.Best.Signature))
+
+	fmt.Fprintf(w, "%-6s %-8s", "Seed", "Прогон")
+	for _, p := range opts.Parameters {
+		fmt.Fprintf(w, " %-16s", p.Name)
+	}
+	fmt.Fprintf(w, " %-8s %-8s %-10s %-12s\n", "Невязка", "|ΔD|", "Ричардсон", "Извилистость")
+	width := 15 + 17*len(opts.Parameters) + 42
+	fmt.Fprintln(w, strings.Repeat("-", width))
+	for _, f := range result.Fits {
+		fmt.Fprintf(w, "%-6d %-8s", f.Seed, fmt.Sprintf("%d/%d", f.Best.Index, len(f.Evaluations)))
+		for i, p := range opts.Parameters {
+			fmt.Fprintf(w, " %-16s", formatFitValue(p, f.Best.Values[i]))
+		}
+		m := f.Best.Misfit
+		fmt.Fprintf(w, " %-8s %-8s %-10s %-12s\n", formatCalibrationValue(m.Total, "%.4f"), formatCalibrationValue(m.Dimension, "%.4f"),
+			formatCalibrationValue(m.Richardson, "%.4f"), formatCalibrationValue(m.Sinuosity, "%.4f"))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", width))
+
+	fmt.Fprintf(w, "Подобранные параметры (лучший прогон — seed %d, прогон %d; разброс — по лучшим прогонам %d seed):\n", result.Best.Seed, result.Best.Index, len(result.Fits))
+	for _, e := range result.Estimates {
+		if !e.Parameter.Free() {
+			fmt.Fprintf(w, "  %-16s %s (зафиксирован)\n", e.Parameter.Name, formatFitValue(e.Parameter, e.Best))
+			continue
+		}
+		fmt.Fprintf(w, "  %-16s %-8s среднее %.3f ± %.3f, по seed %.3f–%.3f, границы %g–%g\n", e.Parameter.Name, formatFitValue(e.Parameter, e.Best),
+			e.Spread.Mean, e.Spread.StdDev, e.Spread.Min, e.Spread.Max, e.Parameter.Min, e.Parameter.Max)
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"coastal-geometry/pkg/fraes"
+	"os"
+)
+
+func runGenerateCommand(app *App) error {
+	gen, err := fraes.NewGenerator(app.Config.Generator, fraes.GeneratorOptions{
+		CesaroAngleDeg: app.Config.CesaroAngle,
+		Projection:     app.Projection,
+	})
+	if err != nil {
+		return err
+	}
+
+	report := fraes.CheckGeneratorTheory(gen, app.ModelBase, app.Config.Iterations)
+	renderGeneratorReport(os.Stdout, gen, report)
+	if !report.Valid {
+		printInvalidResult()
+	}
+	ctx := newExportContext(app)
+	if err := writeGeneratorSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, gen, report, app.Config.ErosionStrength, app.Config.Seed, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	return writeDataTable(generatorTable(gen, report), app.Config.OutputPath, ctx)
+}
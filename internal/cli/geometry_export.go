@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"coastal-geometry/internal/render/geo"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	geometryGeoJSON = "geojson"
+	geometryGPKG    = "gpkg"
+)
+
+// geometryExport collects the series of one run for --export-geometry, so
+// every series of a command ends up as a layer of the same GeoPackage.
+type geometryExport struct {
+	Format    string
+	OutputDir string
+	Layers    []geo.Layer
+}
+
+func newGeometryExport(format string) *geometryExport {
+	if format == "" {
+		return nil
+	}
+	return &geometryExport{Format: format}
+}
+
+// writeSeriesGeometry saves the features of a series next to its SVG files:
+// <prefix>_<i>.geojson per feature, or a layer kept for the GeoPackage. It
+// returns the GeoJSON file names and the GeoPackage layer.
+func writeSeriesGeometry(layer geo.Layer, outputDir, prefix string, ctx exportContext) ([]string, string, error) {
+	export := ctx.Geometry
+	if export == nil {
+		return nil, "", nil
+	}
+
+	if export.Format == geometryGPKG {
+		export.OutputDir = outputDir
+		export.Layers = append(export.Layers, layer)
+		return nil, layer.Name, nil
+	}
+
+	files := make([]string, len(layer.Features))
+	for i, feature := range layer.Features {
+		files[i] = filepath.Join(outputDir, fmt.Sprintf("%s_%d.geojson", prefix, i))
+		single := geo.Layer{Name: layer.Name, Description: layer.Description, Features: []geo.Feature{feature}}
+		if err := geo.WriteGeoJSON(files[i], single); err != nil {
+			return nil, "", err
+		}
+		fmt.Printf("GeoJSON saved to %s\n", files[i])
+	}
+	return files, "", nil
+}
+
+// geometryPackagePath is <command>.gpkg in the series output directory.
+func geometryPackagePath(outputDir, command string) string {
+	return filepath.Join(outputDir, command+".gpkg")
+}
+
+// writeGeometryPackage writes the layers collected during the run; it is a
+// no-op unless --export-geometry=gpkg.
+func writeGeometryPackage(ctx exportContext) error {
+	export := ctx.Geometry
+	if export == nil || export.Format != geometryGPKG || len(export.Layers) == 0 {
+		return nil
+	}
+	filename := geometryPackagePath(export.OutputDir, ctx.Command)
+	if err := geo.WriteGeoPackage(filename, export.Layers); err != nil {
+		return err
+	}
+	fmt.Printf("GeoPackage saved to %s (%d layers)\n", filename, len(export.Layers))
+	return nil
+}
+
+func geometryPackageMetricsFor(outputDir, layer string, ctx exportContext) *geometryPackageMetrics {
+	if layer == "" {
+		return nil
+	}
+	return &geometryPackageMetrics{File: geometryPackagePath(outputDir, ctx.Command), Layer: layer}
+}
+
+// fractalGeometryLayer is one feature per iteration. The seed is the
+// organic, fBm or erosion seed and null for the deterministic Koch curve;
+// dimension is null unless the series estimates it.
+func fractalGeometryLayer(opts fractalSeriesOptions, curves [][]fraes.LatLon, lengths []float64, dimensions []*dimensionMetrics) geo.Layer {
+	var seed any
+	switch {
+	case opts.OrganicOptions != nil:
+		seed = opts.OrganicOptions.Seed
+	case opts.FBMOptions != nil:
+		seed = opts.FBMOptions.Seed
+	case opts.ErosionStrength > 0:
+		seed = opts.ErosionSeed
+	}
+
+	layer := geo.Layer{Name: opts.MetricsBaseName, Description: opts.Title}
+	for iter, curve := range curves {
+		var dimension any
+		if d := dimensions[iter]; d != nil && d.Valid {
+			dimension = d.Dimension
+		}
+		layer.Features = append(layer.Features, geo.Feature{
+			Points: curve,
+			Properties: []geo.Property{
+				{Name: "iteration", Value: iter},
+				{Name: "seed", Value: seed},
+				{Name: "length_km", Value: lengths[iter]},
+				{Name: "dimension", Value: dimension},
+				{Name: "points", Value: len(curve)},
+			},
+		})
+	}
+	return layer
+}
+
+// erosionGeometryLayer is one feature per snapshot, the initial state
+// included; year is set in scenario runs.
+func erosionGeometryLayer(series erosionSeries, lengths, areas []float64) geo.Layer {
+	layer := geo.Layer{Name: "erosion", Description: "Эрозия: " + series.Model}
+	for step, snapshot := range series.Snapshots {
+		var year any
+		if y := scenarioYearForStep(series, step); y != 0 {
+			year = y
+		}
+		layer.Features = append(layer.Features, geo.Feature{
+			Points: snapshot,
+			Properties: []geo.Property{
+				{Name: "step", Value: step},
+				{Name: "year", Value: year},
+				{Name: "model", Value: series.Model},
+				{Name: "seed", Value: series.Seed},
+				{Name: "strength_m", Value: series.Strength},
+				{Name: "length_km", Value: lengths[step]},
+				{Name: "area_km2", Value: areas[step]},
+				{Name: "points", Value: len(snapshot)},
+			},
+		})
+	}
+	return layer
+}
@@ -1,12 +1,17 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/simulations/calibration"
+	"coastal-geometry/internal/domain/simulations/erosion"
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/internal/domain/simulations/percolation"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func printRootUsage(w io.Writer) {
@@ -24,11 +29,20 @@ func printRootUsage(w io.Writer) {
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdSource), getCommandUX(cmdSource).Summary)
 	fmt.Fprintln(w, "  Анализ реальных данных:")
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdCoastline), getCommandUX(cmdCoastline).Summary)
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdRichardson), getCommandUX(cmdRichardson).Summary)
 	fmt.Fprintln(w, "  Синтетические демонстрации:")
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdParadox), getCommandUX(cmdParadox).Summary)
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdKoch), getCommandUX(cmdKoch).Summary)
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdKochOrganic), getCommandUX(cmdKochOrganic).Summary)
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdFBM), getCommandUX(cmdFBM).Summary)
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdGenerate), getCommandUX(cmdGenerate).Summary)
 	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdDimension), getCommandUX(cmdDimension).Summary)
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdErosion), getCommandUX(cmdErosion).Summary)
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdSweep), getCommandUX(cmdSweep).Summary)
+	fmt.Fprintln(w, "  Калибровка методов:")
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdCalibrate), getCommandUX(cmdCalibrate).Summary)
+	fmt.Fprintln(w, "  Обратное моделирование:")
+	fmt.Fprintf(w, "    %-18s %s\n", canonicalCommandPath(cmdFit), getCommandUX(cmdFit).Summary)
 	fmt.Fprintln(w, "  Смешанный сценарий:")
 	fmt.Fprintf(w, "    %-18s %s\n", cmdAll, getCommandUX(cmdAll).Summary)
 	fmt.Fprintln(w, "")
@@ -38,15 +52,23 @@ func printRootUsage(w io.Writer) {
 	fmt.Fprintf(w, "  %s %s             -> %s %s\n", bin, cmdKoch, bin, canonicalCommandPath(cmdKoch))
 	fmt.Fprintf(w, "  %s %s     -> %s %s\n", bin, cmdKochOrganic, bin, canonicalCommandPath(cmdKochOrganic))
 	fmt.Fprintf(w, "  %s %s        -> %s %s\n", bin, cmdDimension, bin, canonicalCommandPath(cmdDimension))
+	fmt.Fprintf(w, "  %s %s          -> %s %s\n", bin, cmdErosion, bin, canonicalCommandPath(cmdErosion))
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Примеры:")
 	fmt.Fprintf(w, "  %s %s\n", bin, canonicalCommandPath(cmdSource))
 	fmt.Fprintf(w, "  %s %s --refresh --output ./data/snapshots\n", bin, canonicalCommandPath(cmdSource))
 	fmt.Fprintf(w, "  %s %s\n", bin, canonicalCommandPath(cmdCoastline))
-	fmt.Fprintf(w, "  %s %s --source-url %s\n", bin, canonicalCommandPath(cmdCoastline), coastline.DefaultCoastlineGeoJSONURL)
+	fmt.Fprintf(w, "  %s %s --source-url %s\n", bin, canonicalCommandPath(cmdCoastline), fraes.DefaultSourceURL())
+	fmt.Fprintf(w, "  %s %s --output ./output/richardson.svg\n", bin, canonicalCommandPath(cmdRichardson))
 	fmt.Fprintf(w, "  %s %s --iterations 4 --output ./output/koch\n", bin, canonicalCommandPath(cmdKoch))
 	fmt.Fprintf(w, "  %s %s --iterations 4 --seed 42 --angle-jitter 18 --height-jitter 0.25 --output ./output/koch-organic\n", bin, canonicalCommandPath(cmdKochOrganic))
+	fmt.Fprintf(w, "  %s %s --hurst 0.5 --iterations 8 --output ./output/fbm\n", bin, canonicalCommandPath(cmdFBM))
+	fmt.Fprintf(w, "  %s %s --generator gosper --iterations 6 --output ./output/gosper\n", bin, canonicalCommandPath(cmdGenerate))
 	fmt.Fprintf(w, "  %s %s --iterations 6 --input data/black-sea.json\n", bin, canonicalCommandPath(cmdDimension))
+	fmt.Fprintf(w, "  %s %s --erosion-model wave --steps 5 --seed 42\n", bin, canonicalCommandPath(cmdErosion))
+	fmt.Fprintf(w, "  %s %s --angle-jitter 0:30:10 --height-jitter 0:0.3:0.1 --output ./output/sweep\n", bin, canonicalCommandPath(cmdSweep))
+	fmt.Fprintf(w, "  %s %s --output ./output/calibration\n", bin, canonicalCommandPath(cmdCalibrate))
+	fmt.Fprintf(w, "  %s %s --evaluations 60 --seeds 3 --output ./output/fit\n", bin, canonicalCommandPath(cmdFit))
 	fmt.Fprintf(w, "  %s all --output ./output/full-run\n", bin)
 	fmt.Fprintln(w, "")
 	fmt.Fprintf(w, "См. '%s %s --help', '%s %s <command> --help', '%s %s <command> --help' или '%s all --help'.\n", bin, cmdSource, bin, cmdReal, bin, cmdModel, bin)
@@ -63,10 +85,12 @@ func printGroupUsage(w io.Writer, group string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Команды:")
 		fmt.Fprintf(w, "  %-12s %s\n", cmdCoastline, getCommandUX(cmdCoastline).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdRichardson, getCommandUX(cmdRichardson).Summary)
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Примеры:")
 		fmt.Fprintf(w, "  %s %s\n", bin, canonicalCommandPath(cmdCoastline))
-		fmt.Fprintf(w, "  %s %s --source-url %s\n", bin, canonicalCommandPath(cmdCoastline), coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "  %s %s --source-url %s\n", bin, canonicalCommandPath(cmdCoastline), fraes.DefaultSourceURL())
+		fmt.Fprintf(w, "  %s %s --output ./output/richardson.svg\n", bin, canonicalCommandPath(cmdRichardson))
 		fmt.Fprintln(w, "")
 		fmt.Fprintf(w, "Алиас совместимости: %s %s\n", bin, cmdCoastline)
 	case cmdModel:
@@ -78,19 +102,35 @@ func printGroupUsage(w io.Writer, group string) {
 		fmt.Fprintf(w, "  %-12s %s\n", cmdParadox, getCommandUX(cmdParadox).Summary)
 		fmt.Fprintf(w, "  %-12s %s\n", cmdKoch, getCommandUX(cmdKoch).Summary)
 		fmt.Fprintf(w, "  %-12s %s\n", cmdKochOrganic, getCommandUX(cmdKochOrganic).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdFBM, getCommandUX(cmdFBM).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdGenerate, getCommandUX(cmdGenerate).Summary)
 		fmt.Fprintf(w, "  %-12s %s\n", cmdDimension, getCommandUX(cmdDimension).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdErosion, getCommandUX(cmdErosion).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdSweep, getCommandUX(cmdSweep).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdCalibrate, getCommandUX(cmdCalibrate).Summary)
+		fmt.Fprintf(w, "  %-12s %s\n", cmdFit, getCommandUX(cmdFit).Summary)
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Примеры:")
 		fmt.Fprintf(w, "  %s %s --iterations 1\n", bin, canonicalCommandPath(cmdParadox))
 		fmt.Fprintf(w, "  %s %s --iterations 4 --output ./output/koch\n", bin, canonicalCommandPath(cmdKoch))
 		fmt.Fprintf(w, "  %s %s --iterations 4 --seed 42 --angle-jitter 18 --height-jitter 0.25 --output ./output/koch-organic\n", bin, canonicalCommandPath(cmdKochOrganic))
+		fmt.Fprintf(w, "  %s %s --hurst 0.5 --iterations 8 --output ./output/fbm\n", bin, canonicalCommandPath(cmdFBM))
+		fmt.Fprintf(w, "  %s %s --generator cesaro --cesaro-angle 80 --iterations 5 --output ./output/cesaro\n", bin, canonicalCommandPath(cmdGenerate))
 		fmt.Fprintf(w, "  %s %s --iterations 6 --output ./output/dimension\n", bin, canonicalCommandPath(cmdDimension))
+		fmt.Fprintf(w, "  %s %s --erosion-model wave --steps 5 --erosion-strength 500 --output ./output/erosion\n", bin, canonicalCommandPath(cmdErosion))
+		fmt.Fprintf(w, "  %s %s --lithology data/black-sea-lithology.json --steps 5\n", bin, canonicalCommandPath(cmdErosion))
+		fmt.Fprintf(w, "  %s %s --erosion-model percolation --steps 6 --seed 42\n", bin, canonicalCommandPath(cmdErosion))
+		fmt.Fprintf(w, "  %s %s --angle-jitter 0:30:10 --height-jitter 0:0.3:0.1 --output ./output/sweep\n", bin, canonicalCommandPath(cmdSweep))
+		fmt.Fprintf(w, "  %s %s --pipeline erosion --erosion-model wave --sampling lhs --samples 64 --erosion-strength 50:500 --sediment-rate 0:40000\n", bin, canonicalCommandPath(cmdSweep))
+		fmt.Fprintf(w, "  %s %s --generators koch,minkowski --hurst 0.5,0.8 --estimator box,mass-radius --rotations 8\n", bin, canonicalCommandPath(cmdCalibrate))
+		fmt.Fprintf(w, "  %s %s --method nelder-mead --iterations 3 --erosion-strength 0 --weights dimension=2,richardson=1,sinuosity=0\n", bin, canonicalCommandPath(cmdFit))
 		fmt.Fprintln(w, "")
-		fmt.Fprintf(w, "Алиасы совместимости: %s %s, %s %s, %s %s, %s %s\n",
+		fmt.Fprintf(w, "Алиасы совместимости: %s %s, %s %s, %s %s, %s %s, %s %s\n",
 			bin, cmdParadox,
 			bin, cmdKoch,
 			bin, cmdKochOrganic,
-			bin, cmdDimension)
+			bin, cmdDimension,
+			bin, cmdErosion)
 	}
 }
 
@@ -110,13 +150,13 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-, KML-, GPX-, WKT-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед сохранением snapshot")
 		fmt.Fprintln(w, "  --output string")
-		fmt.Fprintf(w, "        путь к snapshot-файлу или директории (по умолчанию ./%s)\n", coastline.DefaultCoastlineSnapshotDir)
+		fmt.Fprintf(w, "        путь к snapshot-файлу или директории (по умолчанию ./%s)\n", fraes.DefaultSnapshotDir)
 	case cmdAll:
 		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, cmdAll)
 		ux := getCommandUX(command)
@@ -127,19 +167,32 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
 		fmt.Fprintln(w, "  --iterations int")
-		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", koch.MaxIterations)
+		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", fraes.MaxKochIterations)
 		fmt.Fprintln(w, "  --seed int")
 		fmt.Fprintln(w, "        seed для генерации organic-береговой линии")
 		fmt.Fprintln(w, "  --angle-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение угла в градусах")
 		fmt.Fprintln(w, "  --height-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение высоты как доля")
+		printBootstrapUsage(w)
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --geodesic string")
+		fmt.Fprintln(w, "        метод измерения длины береговой линии: haversine (сфера R=6371 км), vincenty или karney (эллипсоид); для эллипсоидальных методов выводится и длина на сфере с разницей (по умолчанию \"haversine\")")
+		fmt.Fprintln(w, "  --ellipsoid string")
+		fmt.Fprintln(w, "        эллипсоид для vincenty, karney и эллипсоидальной площади: WGS84, GRS80 или Krassovsky1940 (по умолчанию \"WGS84\")")
+		fmt.Fprintln(w, "  --area string")
+		fmt.Fprintln(w, "        метод площади полигонов: ellipsoidal (геодезический многоугольник на эллипсоиде), spherical (сферический избыток) или planar (локальная сетка); для непланарных методов выводится и площадь на плоской сетке (по умолчанию \"ellipsoidal\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
 		fmt.Fprintln(w, "  --output string")
 		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
 	case cmdCoastline:
@@ -153,11 +206,38 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --geodesic string")
+		fmt.Fprintln(w, "        метод измерения длины береговой линии: haversine (сфера R=6371 км), vincenty или karney (эллипсоид); для эллипсоидальных методов выводится и длина на сфере с разницей (по умолчанию \"haversine\")")
+		fmt.Fprintln(w, "  --ellipsoid string")
+		fmt.Fprintln(w, "        эллипсоид для vincenty, karney и эллипсоидальной площади: WGS84, GRS80 или Krassovsky1940 (по умолчанию \"WGS84\")")
+		fmt.Fprintln(w, "  --area string")
+		fmt.Fprintln(w, "        метод площади полигонов: ellipsoidal (геодезический многоугольник на эллипсоиде), spherical (сферический избыток) или planar (локальная сетка); для непланарных методов выводится и площадь на плоской сетке (по умолчанию \"ellipsoidal\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        путь к SVG-файлу или директории вывода (по умолчанию: ./output)")
+	case cmdRichardson:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Проходит реальную береговую линию циркулем с уменьшающимся геодезическим шагом ε, строит регрессию log L(ε) от log ε по наиболее устойчивому окну масштабов и сохраняет `richardson.svg` с `richardson.metrics.json`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
 		fmt.Fprintln(w, "  --output string")
 		fmt.Fprintln(w, "        путь к SVG-файлу или директории вывода (по умолчанию: ./output)")
 	case cmdParadox:
@@ -171,13 +251,23 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
 		fmt.Fprintln(w, "  --iterations int")
-		fmt.Fprintf(w, "        максимальное число уровней детализации парадокса (0-%d)\n", koch.MaxIterations)
+		fmt.Fprintf(w, "        максимальное число уровней детализации парадокса (0-%d)\n", fraes.MaxKochIterations)
+		fmt.Fprintln(w, "  --ensemble int")
+		fmt.Fprintln(w, "        прогнать модель для N последовательных seed начиная с --seed и свести длину, площадь и размерность по уровням: среднее, медиана, P5–P95, разброс; веерные графики в `paradox-ensemble.svg`, строка на прогон в таблицах (0 отключает, иначе не меньше 2)")
+		fmt.Fprintln(w, "  --ensemble-workers int")
+		fmt.Fprintln(w, "        сколько прогонов ансамбля считать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для таблиц метрик (по умолчанию: ./output)")
 	case cmdKoch:
 		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
 		ux := getCommandUX(command)
@@ -189,13 +279,21 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
 		fmt.Fprintln(w, "  --iterations int")
-		fmt.Fprintf(w, "        максимальное число итераций Коха (0-%d)\n", koch.MaxIterations)
+		fmt.Fprintf(w, "        максимальное число итераций Коха (0-%d)\n", fraes.MaxKochIterations)
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
 		fmt.Fprintln(w, "  --output string")
 		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
 	case cmdKochOrganic:
@@ -209,25 +307,118 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
 		fmt.Fprintln(w, "  --iterations int")
-		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", koch.MaxIterations)
+		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", fraes.MaxKochIterations)
 		fmt.Fprintln(w, "  --seed int")
 		fmt.Fprintln(w, "        seed для генерации organic-береговой линии")
 		fmt.Fprintln(w, "  --angle-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение угла в градусах")
 		fmt.Fprintln(w, "  --height-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение высоты как доля")
+		printBootstrapUsage(w)
+		fmt.Fprintln(w, "  --ensemble int")
+		fmt.Fprintln(w, "        прогнать модель для N последовательных seed начиная с --seed и свести длину, площадь и размерность по итерациям: среднее, медиана, P5–P95, разброс; веерные графики в `koch-organic-ensemble.svg`, строка на прогон в таблицах (0 отключает, иначе не меньше 2)")
+		fmt.Fprintln(w, "  --ensemble-workers int")
+		fmt.Fprintln(w, "        сколько прогонов ансамбля считать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
+	case cmdFBM:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Смещает каждый сегмент упрощённой базы вдоль его нормали мостом фрактального броуновского движения (случайное смещение середин), печатает box-counting размерность каждой итерации рядом с целью D = 2 − H и сохраняет `fbm_iter_0..N.svg`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --iterations int")
+		fmt.Fprintf(w, "        число итераций смещения середин, каждая вдвое дробит сегменты (0-%d, по умолчанию 8)\n", fbm.MaxIterations)
+		fmt.Fprintln(w, "  --seed int")
+		fmt.Fprintln(w, "        seed для смещений fBm")
+		fmt.Fprintln(w, "  --hurst float")
+		fmt.Fprintf(w, "        показатель Хёрста H в (0, 1): меньше H — изрезаннее линия, цель D = 2 − H (по умолчанию %g)\n", fbm.DefaultHurst)
+		fmt.Fprintln(w, "  --amplitude float")
+		fmt.Fprintf(w, "        стандартное отклонение первого смещения середины как доля длины сегмента базы (по умолчанию %g)\n", fbm.DefaultAmplitude)
+		printBootstrapUsage(w)
+		fmt.Fprintln(w, "  --model-max-points int")
+		fmt.Fprintf(w, "        максимум точек модельной базы (0 — по умолчанию %d, чтобы сегменты были крупнее ячеек box-counting)\n", fbmBasePoints)
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
+	case cmdGenerate:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Заменяет каждый сегмент упрощённой базы мотивом детерминированного генератора, сверяет длину итераций с Lₙ = L₀ × kⁿ, а box-counting размерность — с размерностью подобия и сохраняет `{генератор}_iter_0..N.svg`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Генераторы:")
+		for _, name := range fraes.GeneratorNames() {
+			gen, _ := fraes.NewGenerator(name, fraes.GeneratorOptions{})
+			fmt.Fprintf(w, "  %-12s сегментов %2d, длина ×%.4f, D = %.4f, итераций до %d\n", name, gen.Segments(), gen.TheoreticalLengthFactor(), gen.TheoreticalDimension(), gen.MaxIterations())
+		}
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --generator string")
+		fmt.Fprintf(w, "        генератор из списка выше (по умолчанию %q)\n", fraes.GeneratorKoch)
+		fmt.Fprintln(w, "  --cesaro-angle float")
+		fmt.Fprintf(w, "        угол при основании пика генератора cesaro в градусах, в (0, 90); 60 даёт кривую Коха (по умолчанию %g)\n", fraes.DefaultCesaroAngle)
+		fmt.Fprintln(w, "  --iterations int")
+		fmt.Fprintln(w, "        число итераций генератора, предел зависит от числа сегментов мотива (по умолчанию 4)")
+		fmt.Fprintln(w, "  --erosion-strength float")
+		fmt.Fprintln(w, "        σ гауссовской эрозии в метрах после каждой итерации (0 отключает)")
+		printBootstrapUsage(w)
+		fmt.Fprintln(w, "  --model-max-points int")
+		fmt.Fprintln(w, "        максимум точек модельной базы (0 — бюджет по числу сегментов мотива и итераций)")
+		fmt.Fprintln(w, "  --no-model-simplify")
+		fmt.Fprintln(w, "        отключить упрощение модельной базы перед ростом")
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
 		fmt.Fprintln(w, "  --output string")
 		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
 	case cmdDimension:
 		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
 		ux := getCommandUX(command)
-		fmt.Fprintln(w, "Считает эмпирическую box-counting размерность по organic-итерациям роста береговой линии, проверяет сходимость и сохраняет `dimension_iter_0..N.svg`.")
+		fmt.Fprintln(w, "Считает эмпирическую box-counting размерность по organic-итерациям роста береговой линии, проверяет сходимость и сохраняет `dimension_iter_0..N.svg`; с `--estimator` сравнивает её с заполненным box-counting, mass-radius, D1, D2 и спектром D(q)/f(α).")
 		fmt.Fprintln(w, "")
 		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
 		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
@@ -235,20 +426,225 @@ func printCommandUsage(w io.Writer, command string) {
 		fmt.Fprintln(w, "")
 		fmt.Fprintln(w, "Флаги:")
 		fmt.Fprintln(w, "  --input string")
-		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу береговой линии, используемому как fallback (по умолчанию %q)\n", coastline.DefaultCoastlineJSONPath)
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
 		fmt.Fprintln(w, "  --source-url string")
-		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", coastline.DefaultCoastlineGeoJSONURL)
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
 		fmt.Fprintln(w, "  --refresh")
 		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
 		fmt.Fprintln(w, "  --iterations int")
-		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", koch.MaxIterations)
+		fmt.Fprintf(w, "        максимальное число итераций organic Koch (0-%d)\n", fraes.MaxKochIterations)
 		fmt.Fprintln(w, "  --seed int")
 		fmt.Fprintln(w, "        seed для генерации organic-береговой линии")
 		fmt.Fprintln(w, "  --angle-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение угла в градусах")
 		fmt.Fprintln(w, "  --height-jitter float")
 		fmt.Fprintln(w, "        максимальное случайное отклонение высоты как доля")
+		printBootstrapUsage(w)
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --estimator string")
+		fmt.Fprintln(w, "        оценки размерности для сравнения, через запятую или all: box (ячейки на линии), box-filled (ячейки на замкнутом контуре и внутри него), mass-radius (метод песочницы), information (D1), correlation (D2), multifractal (D(q) и f(α) по --q-range) (по умолчанию \"box\")")
+		fmt.Fprintln(w, "  --q-range string")
+		fmt.Fprintln(w, "        значения q мультифрактального спектра: min:max:step или список через запятую (по умолчанию \"-5:5:1\")")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
 		fmt.Fprintln(w, "  --output string")
 		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
+	case cmdErosion:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Пошагово размывает загруженную береговую линию гауссовским шумом, волновой моделью с fetch и экспозицией или перколяционной моделью скального берега на сетке и сохраняет `erosion_step_0..N.svg` с `erosion.metrics.json`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintf(w, "Алиас совместимости: %s %s\n", bin, alias)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --steps int")
+		fmt.Fprintln(w, "        число шагов эрозии (0+)")
+		fmt.Fprintln(w, "  --seed int")
+		fmt.Fprintln(w, "        seed случайной составляющей; одинаковый seed даёт одинаковый результат")
+		fmt.Fprintln(w, "  --erosion-model string")
+		fmt.Fprintln(w, "        модель эрозии: gaussian (изотропный шум), wave (отступ по волновой экспозиции) или percolation (скальный берег на сетке со случайной устойчивостью ячеек, сила моря затухает с ростом длины берега; D по шагам сходится к 4/3)")
+		fmt.Fprintln(w, "  --erosion-strength float")
+		fmt.Fprintln(w, "        сила эрозии за шаг в метрах: σ для gaussian, отступ самой открытой точки для wave (0 отключает)")
+		fmt.Fprintln(w, "  --wave-climate string")
+		fmt.Fprintf(w, "        волновой климат для wave: пары направление:доля, направление — откуда идут волны (по умолчанию %q)\n", erosion.DefaultWaveClimate)
+		fmt.Fprintln(w, "  --lithology string")
+		fmt.Fprintln(w, "        JSON-профиль литологии: породы по диапазонам индексов или полигонам; сдвиг точек ∝ 1/устойчивость")
+		fmt.Fprintln(w, "  --sediment")
+		fmt.Fprintln(w, "        переносить размытый материал вдоль берега волновым дрейфом: часть линии наращивается, баланс наносов попадает в метрики")
+		fmt.Fprintln(w, "  --sediment-rate float")
+		fmt.Fprintf(w, "        вдольбереговой перенос за шаг в м³ при подходе волн под 45° к открытому берегу (по умолчанию %.0f)\n", float64(erosion.DefaultSedimentRateM3))
+		fmt.Fprintln(w, "  --scenario string")
+		fmt.Fprintln(w, "        JSON-сценарий (YAML не поддерживается) в календарных годах: фоновый отступ, штормы с периодом повторяемости, подъём уровня моря (линейный или таблица); заменяет --steps и --erosion-strength, SVG и метрики подписываются годами")
+		fmt.Fprintln(w, "  --sea-force float")
+		fmt.Fprintf(w, "        начальная сила моря f0 для percolation, от 0 до 1: размывается береговая ячейка с меньшей устойчивостью (по умолчанию %.2f)\n", percolation.DefaultForce)
+		fmt.Fprintln(w, "  --sea-damping float")
+		fmt.Fprintf(w, "        затухание силы моря с длиной берега для percolation: f = f0 / (1 + g·(P/P0 − 1)), P — число береговых ячеек (по умолчанию %.2f)\n", percolation.DefaultDamping)
+		fmt.Fprintln(w, "  --grid-cells int")
+		fmt.Fprintf(w, "        ячеек сетки percolation по длинной стороне; --steps делят проходы до стабилизации берега поровну (по умолчанию %d)\n", percolation.DefaultCells)
+		fmt.Fprintln(w, "  --area string")
+		fmt.Fprintln(w, "        метод площади в таблице шагов: ellipsoidal, spherical или planar; planar_area_km2 в экспорте хранит площадь на плоской сетке для сравнения (по умолчанию \"ellipsoidal\")")
+		fmt.Fprintln(w, "  --ellipsoid string")
+		fmt.Fprintln(w, "        эллипсоид для --area=ellipsoidal: WGS84, GRS80 или Krassovsky1940 (по умолчанию \"WGS84\")")
+		fmt.Fprintln(w, "  --ensemble int")
+		fmt.Fprintln(w, "        прогнать модель для N последовательных seed начиная с --seed и свести длину, площадь и размерность по шагам: среднее, медиана, P5–P95, разброс; веерные графики в `erosion-ensemble.svg`, строка на прогон в таблицах (0 отключает, иначе не меньше 2)")
+		fmt.Fprintln(w, "  --ensemble-workers int")
+		fmt.Fprintln(w, "        сколько прогонов ансамбля считать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --animate")
+		fmt.Fprintln(w, "        дополнительно собрать кадры серии в один анимированный GIF рядом с SVG")
+		fmt.Fprintln(w, "  --export-geometry string")
+		fmt.Fprintln(w, "        дополнительно сохранить геометрии модели для ГИС: geojson — FeatureCollection на итерацию или шаг рядом с SVG, gpkg — один GeoPackage со слоем на серию")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        формат таблиц метрик: table (только консоль), csv, tsv или json — по файлу на таблицу рядом с SVG, строка на итерацию или шаг с seed и параметрами (по умолчанию \"table\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для выходных визуализаций (по умолчанию: ./output)")
+	case cmdSweep:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Прогоняет organic- или erosion-модель для каждой комбинации параметров из диапазонов или латинского гиперкуба, сохраняет таблицу прогонов, `sweep.metrics.json` и тепловые карты `sweep-dimension.svg` и `sweep-length-growth.svg`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Диапазон параметра: одно значение, min:max (оба конца), min:max:step или список через запятую.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-, KML-, GPX-, WKT-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --pipeline string")
+		fmt.Fprintln(w, "        модель каждого прогона: organic (органическая кривая Коха с необязательной гауссовской эрозией) или erosion (пошаговая эрозия) (по умолчанию \"organic\")")
+		fmt.Fprintln(w, "  --iterations int")
+		fmt.Fprintln(w, "        итерации органической кривой Коха в каждом organic-прогоне (по умолчанию 4)")
+		fmt.Fprintln(w, "  --steps int")
+		fmt.Fprintln(w, "        шаги эрозии в каждом erosion-прогоне (по умолчанию 5)")
+		fmt.Fprintln(w, "  --seed int")
+		fmt.Fprintln(w, "        seed, общий для всех прогонов и для выборки латинского гиперкуба (по умолчанию 42)")
+		fmt.Fprintln(w, "  --angle-jitter range")
+		fmt.Fprintf(w, "        разброс угла вершины в градусах, только organic (по умолчанию %q)\n", sweepDefaultRange(sweepPipelineOrganic, "angle-jitter"))
+		fmt.Fprintln(w, "  --height-jitter range")
+		fmt.Fprintf(w, "        разброс высоты вершины, доля от 0 до 1, только organic (по умолчанию %q)\n", sweepDefaultRange(sweepPipelineOrganic, "height-jitter"))
+		fmt.Fprintln(w, "  --erosion-strength range")
+		fmt.Fprintf(w, "        сила эрозии в метрах: σ гауссовского шума после роста для organic, отступ за шаг для erosion (по умолчанию %q для organic, %q для erosion)\n", sweepDefaultRange(sweepPipelineOrganic, "erosion-strength"), sweepDefaultRange(sweepPipelineErosion, "erosion-strength"))
+		fmt.Fprintln(w, "  --sediment-rate range")
+		fmt.Fprintf(w, "        вдольбереговой перенос наносов за шаг в м³, только erosion; 0 отключает перенос (по умолчанию %q)\n", sweepDefaultRange(sweepPipelineErosion, "sediment-rate"))
+		fmt.Fprintln(w, "  --sampling string")
+		fmt.Fprintln(w, "        выбор комбинаций: grid (все комбинации значений) или lhs (латинский гиперкуб из --samples точек внутри диапазонов) (по умолчанию \"grid\")")
+		fmt.Fprintln(w, "  --samples int")
+		fmt.Fprintln(w, "        число точек для --sampling=lhs (по умолчанию 32)")
+		fmt.Fprintln(w, "  --plane string")
+		fmt.Fprintln(w, "        два параметра осей тепловых карт как x,y; остальные параметры усредняются по клетке (по умолчанию — первые два меняющихся)")
+		fmt.Fprintln(w, "  --erosion-model string")
+		fmt.Fprintln(w, "        модель эрозии для --pipeline=erosion: gaussian или wave")
+		fmt.Fprintln(w, "  --wave-climate string")
+		fmt.Fprintf(w, "        волновой климат для wave и переноса наносов: пары направление:доля (по умолчанию %q)\n", erosion.DefaultWaveClimate)
+		fmt.Fprintln(w, "  --workers int")
+		fmt.Fprintln(w, "        сколько прогонов считать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --fresh")
+		fmt.Fprintln(w, "        начать заново, отбросив журнал прошлого sweep в --output, вместо продолжения")
+		fmt.Fprintln(w, "  --model-max-points int")
+		fmt.Fprintln(w, "        максимум точек модельной базы organic-прогонов (0 — бюджет по умолчанию)")
+		fmt.Fprintln(w, "  --no-model-simplify")
+		fmt.Fprintln(w, "        отключить упрощение модельной базы перед ростом")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        таблица прогонов: csv, tsv или json — файл `sweep.*` рядом с тепловыми картами, table — только консоль (по умолчанию \"csv\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для таблицы, тепловых карт и журнала `sweep.jsonl` (по умолчанию: ./output)")
+	case cmdCalibrate:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Строит эталонные кривые известной размерности — детерминированные генераторы и fBm с D = 2 − H — на каждой итерации до --max-points точек, оценивает их размерность каждым оценщиком при нескольких ориентациях сетки и сохраняет таблицы `calibration.*` и `calibration-summary.*`, `calibration.metrics.json` и диаграмму оценки против истинной D `calibration.svg`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --generators string")
+		fmt.Fprintf(w, "        детерминированные эталоны через запятую, all или none: %s (по умолчанию \"all\")\n", strings.Join(fraes.GeneratorNames(), ", "))
+		fmt.Fprintln(w, "  --hurst string")
+		fmt.Fprintf(w, "        показатели Хёрста эталонов fBm в (0, 1) через запятую или none; истинная размерность D = 2 − H (по умолчанию %q)\n", formatHursts(calibration.DefaultHursts))
+		fmt.Fprintln(w, "  --estimator string")
+		fmt.Fprintf(w, "        калибруемые оценщики через запятую или all: %s (по умолчанию \"all\")\n", strings.Join(fraes.EstimatorNames(), ", "))
+		fmt.Fprintln(w, "  --rotations int")
+		fmt.Fprintf(w, "        ориентаций сетки на кривую: плоскость поворачивается с шагом 90°/N, от 1 до %d (по умолчанию %d)\n", calibration.MaxRotations, calibration.DefaultRotations)
+		fmt.Fprintln(w, "  --max-points int")
+		fmt.Fprintf(w, "        предел точек эталонной кривой: каждый эталон уточняется до последней итерации, которая в него укладывается (по умолчанию %d)\n", calibration.DefaultMaxPoints)
+		fmt.Fprintln(w, "  --tolerance float")
+		fmt.Fprintf(w, "        наибольшее |смещение| от истинной D, при котором оценщик считается сошедшимся (по умолчанию %g)\n", calibration.DefaultTolerance)
+		fmt.Fprintln(w, "  --seed int")
+		fmt.Fprintln(w, "        seed эталонов fBm (по умолчанию 42)")
+		fmt.Fprintln(w, "  --workers int")
+		fmt.Fprintln(w, "        сколько итераций эталонов оценивать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        таблицы калибровки: csv, tsv или json — файлы `calibration.*` и `calibration-summary.*` рядом с диаграммой, table — только консоль (по умолчанию \"csv\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для таблиц, диаграммы и метрик (по умолчанию: ./output)")
+	case cmdFit:
+		fmt.Fprintf(w, "Использование: %s %s [flags]\n\n", bin, usagePath)
+		ux := getCommandUX(command)
+		fmt.Fprintln(w, "Ищет параметры organic-модели Коха и гауссовской эрозии, при которых модель от упрощённой базы ближе всего к загруженной линии по box-counting D, кривой Ричардсона и извилистости. Поиск повторяется для --seeds seed; печатает лучшие параметры с разбросом по seed, сохраняет серию подобранной модели `fit_iter_*.svg`, таблицу прогонов `fit.*` и `fit.metrics.json`.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintf(w, "Режим: %s\n", ux.Mode)
+		fmt.Fprintf(w, "Примечание: %s\n", ux.RuntimeNote)
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Границы параметра: одно значение фиксирует его, min:max задаёт интервал поиска.")
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "Флаги:")
+		fmt.Fprintln(w, "  --input string")
+		fmt.Fprintf(w, "        путь к локальному JSON/GeoJSON-, KML-, GPX-, WKT-файлу или shapefile (.shp рядом с .dbf) береговой линии, используемому как fallback (по умолчанию %q)\n", fraes.DefaultLocalPath)
+		fmt.Fprintln(w, "  --source-url string")
+		fmt.Fprintf(w, "        удалённый URL GeoJSON-источника береговой линии (по умолчанию %q; пустая строка отключает HTTP-загрузку)\n", fraes.DefaultSourceURL())
+		fmt.Fprintln(w, "  --refresh")
+		fmt.Fprintln(w, "        принудительно обновить кэш удалённого GeoJSON перед запуском")
+		fmt.Fprintln(w, "  --iterations bounds")
+		fmt.Fprintf(w, "        итерации органической кривой Коха, целые от 0 до %d (по умолчанию %q)\n", fitMaxIterations(), fitDefaultBounds["iterations"])
+		fmt.Fprintln(w, "  --angle-jitter bounds")
+		fmt.Fprintf(w, "        разброс угла вершины в градусах (по умолчанию %q)\n", fitDefaultBounds["angle-jitter"])
+		fmt.Fprintln(w, "  --height-jitter bounds")
+		fmt.Fprintf(w, "        разброс высоты вершины, доля (по умолчанию %q)\n", fitDefaultBounds["height-jitter"])
+		fmt.Fprintln(w, "  --erosion-strength bounds")
+		fmt.Fprintf(w, "        σ гауссовской эрозии после роста в метрах, 0 отключает эрозию (по умолчанию %q)\n", fitDefaultBounds["erosion-strength"])
+		fmt.Fprintln(w, "  --method string")
+		fmt.Fprintln(w, "        поиск: grid (грубая сетка на половину прогонов, затем Нелдер–Мид из лучших клеток) или nelder-mead (Нелдер–Мид из середины границ с перезапусками) (по умолчанию \"grid\")")
+		fmt.Fprintln(w, "  --evaluations int")
+		fmt.Fprintf(w, "        прогонов модели на seed, не меньше %d (по умолчанию %d)\n", fit.MinEvaluations, fit.DefaultEvaluations)
+		fmt.Fprintln(w, "  --seeds int")
+		fmt.Fprintf(w, "        независимых подборов для seed подряд начиная с --seed; разброс их лучших параметров — неопределённость (по умолчанию %d)\n", fit.DefaultSeeds)
+		fmt.Fprintln(w, "  --seed int")
+		fmt.Fprintln(w, "        первый seed органического роста и эрозии (по умолчанию 42)")
+		fmt.Fprintln(w, "  --weights string")
+		fmt.Fprintf(w, "        веса невязки как имя=значение: dimension (|ΔD| box counting), richardson (RMS логарифма отношения длин циркуля), sinuosity (|логарифм отношения| дуги к хорде); 0 исключает член, неуказанные остаются 1 (по умолчанию %q)\n", fitDefaultWeights)
+		fmt.Fprintln(w, "  --workers int")
+		fmt.Fprintln(w, "        сколько seed подбирать одновременно (по умолчанию 0 — все CPU)")
+		fmt.Fprintln(w, "  --format string")
+		fmt.Fprintln(w, "        таблица прогонов: csv, tsv или json — файл `fit.*` рядом с серией, table — только консоль (по умолчанию \"csv\")")
+		fmt.Fprintln(w, "  --projection string")
+		fmt.Fprintln(w, "        картографическая проекция для упрощения, box-counting, эрозии и SVG: laea (равновеликая азимутальная Ламберта с центром в данных), utm (зона центра данных) или webmercator; выбор записывается в метрики (по умолчанию \"laea\")")
+		fmt.Fprintln(w, "  --output string")
+		fmt.Fprintln(w, "        директория для серии подобранной модели, таблицы и метрик (по умолчанию: ./output)")
 	}
 }
+
+func printBootstrapUsage(w io.Writer) {
+	fmt.Fprintln(w, "  --bootstrap int")
+	fmt.Fprintf(w, "        число бутстреп-повторов для 95%% доверительного интервала box-counting размерности: случайные повороты и сдвиги сетки и перевыборка точек регрессии; 0 отключает, %d дают интервал, устойчивый примерно до 0.005 по D (по умолчанию 0)\n", fraes.DefaultBootstrapReplicates)
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/generators/koch"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/pkg/fraes"
+	"os"
 )
 
 func runKochCommand(app *App) error {
@@ -10,9 +10,18 @@ func runKochCommand(app *App) error {
 	if !report.Valid {
 		printInvalidResult()
 	}
-	return writeKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, app.Config.ErosionStrength, app.Config.Seed, newExportContext(app))
+	ctx := newExportContext(app)
+	if err := writeKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, app.Config.ErosionStrength, app.Config.Seed, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	return writeDataTable(kochTable(report), app.Config.OutputPath, ctx)
 }
 
-func runKochMetrics(base []geometry.LatLon, iterations int) koch.TheoryCheckReport {
-	return koch.Demonstrate(base, iterations)
+func runKochMetrics(base []fraes.LatLon, iterations int) fraes.TheoryCheckReport {
+	report := fraes.CheckTheoryConsistency(base, iterations)
+	renderKochReport(os.Stdout, report)
+	return report
 }
@@ -1,27 +1,50 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/generators/koch"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/pkg/fraes"
+	"os"
 )
 
 func runKochOrganicCommand(app *App) error {
 	opts := organicKochOptions(app)
-	runKochOrganicMetrics(app.ModelBase, app.Config.Iterations, opts)
-	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "koch_iter", "koch-organic", false, newExportContext(app)); err != nil {
+	report := runKochOrganicMetrics(app.ModelBase, app.Config.Iterations, opts)
+	ctx := newExportContext(app)
+	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "koch_iter", "koch-organic", false, ctx); err != nil {
 		return err
 	}
-	return writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "dimension_iter", "dimension-organic", true, newExportContext(app))
+	if err := writeOrganicKochSVGSeries(app.Base, app.ModelBase, app.Config.Iterations, app.Config.OutputPath, opts, app.Config.ErosionStrength, "dimension_iter", "dimension-organic", true, ctx); err != nil {
+		return err
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		return err
+	}
+	if err := writeDataTable(organicTable("koch-organic", report), app.Config.OutputPath, ctx); err != nil {
+		return err
+	}
+	if app.Config.Ensemble == 0 {
+		return nil
+	}
+	return runEnsemble(app, ensembleSpec{Name: "koch-organic", Title: "Органическая кривая Коха", Axis: "iteration", Header: "Итер."}, organicEnsembleMember(app, opts))
 }
 
-func runKochOrganicMetrics(base []geometry.LatLon, iterations int, opts koch.OrganicOptions) {
-	koch.DemonstrateOrganic(base, iterations, opts)
+func runKochOrganicMetrics(base []fraes.LatLon, iterations int, opts fraes.OrganicOptions) fraes.OrganicReport {
+	report := fraes.AnalyzeOrganic(base, iterations, organicCurveOptions(opts)...)
+	renderOrganicReport(os.Stdout, report)
+	return report
 }
 
-func organicKochOptions(app *App) koch.OrganicOptions {
-	return koch.OrganicOptions{
+func organicKochOptions(app *App) fraes.OrganicOptions {
+	return fraes.OrganicOptions{
 		Seed:            app.Config.Seed,
 		AngleJitterDeg:  app.Config.AngleJitter,
 		HeightJitterPct: app.Config.HeightJitter,
 	}
 }
+
+func organicCurveOptions(opts fraes.OrganicOptions) []fraes.OrganicOption {
+	return []fraes.OrganicOption{
+		fraes.WithSeed(opts.Seed),
+		fraes.WithAngleJitter(opts.AngleJitterDeg),
+		fraes.WithHeightJitter(opts.HeightJitterPct),
+	}
+}
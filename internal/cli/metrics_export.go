@@ -1,13 +1,20 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/fractal"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/lithology"
+	"coastal-geometry/internal/domain/simulations/calibration"
+	"coastal-geometry/internal/domain/simulations/ensemble"
+	"coastal-geometry/internal/domain/simulations/erosion"
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/internal/domain/simulations/percolation"
+	"coastal-geometry/internal/domain/simulations/sweep"
+	"coastal-geometry/pkg/fraes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,7 +23,17 @@ type exportContext struct {
 	Command    string
 	Dataset    string
 	Source     string
-	Validation coastline.ValidationReport
+	Validation fraes.ValidationReport
+	Coastline  fraes.Coastline
+	Distance   fraes.Distance
+	Area       fraes.AreaMeasure
+	Projection fraes.Projector
+	Animate    bool
+	Geometry   *geometryExport
+	Format     string
+	// Bootstrap is the number of replicates behind the confidence interval
+	// of D; 0 reports the point estimate only.
+	Bootstrap int
 }
 
 type polylineMetrics struct {
@@ -57,11 +74,11 @@ type coastlineHighlightsMetrics struct {
 }
 
 type segmentHighlightMetrics struct {
-	StartIndex int             `json:"start_index"`
-	EndIndex   int             `json:"end_index"`
-	LengthKM   float64         `json:"length_km"`
-	Start      geometry.LatLon `json:"start"`
-	End        geometry.LatLon `json:"end"`
+	StartIndex int          `json:"start_index"`
+	EndIndex   int          `json:"end_index"`
+	LengthKM   float64      `json:"length_km"`
+	Start      fraes.LatLon `json:"start"`
+	End        fraes.LatLon `json:"end"`
 }
 
 type coastlineArtifactMetrics struct {
@@ -69,12 +86,66 @@ type coastlineArtifactMetrics struct {
 	Command              string                     `json:"command"`
 	Dataset              string                     `json:"dataset,omitempty"`
 	Source               string                     `json:"source,omitempty"`
+	Projection           *projectionMetrics         `json:"projection,omitempty"`
 	SVGFile              string                     `json:"svg_file"`
 	Real                 polylineMetrics            `json:"real"`
+	Geodesic             geodesicMetrics            `json:"geodesic"`
 	Render               polylineMetrics            `json:"render"`
 	RenderSimplification simplificationMetrics      `json:"render_simplification"`
 	Highlights           coastlineHighlightsMetrics `json:"highlights"`
 	Validation           validationMetrics          `json:"validation"`
+	Parts                []coastlinePartMetrics     `json:"parts,omitempty"`
+	Aggregate            *coastlineAggregateMetrics `json:"aggregate,omitempty"`
+}
+
+// projectionMetrics records the map projection every plane computation of
+// the run used: Name is the --projection value, Description includes the
+// zone or centre.
+type projectionMetrics struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// geodesicMetrics compares the length measured with --geodesic against the
+// spherical haversine length of the same line.
+type geodesicMetrics struct {
+	Method        string  `json:"method"`
+	Ellipsoid     string  `json:"ellipsoid,omitempty"`
+	LengthKM      float64 `json:"length_km"`
+	HaversineKM   float64 `json:"haversine_km"`
+	DifferenceKM  float64 `json:"difference_km"`
+	DifferencePct float64 `json:"difference_pct"`
+}
+
+type coastlinePartMetrics struct {
+	Name       string                 `json:"name"`
+	Properties map[string]any         `json:"properties,omitempty"`
+	LengthKM   float64                `json:"length_km"`
+	AreaKM2    float64                `json:"area_km2"`
+	Rings      []coastlineRingMetrics `json:"rings"`
+}
+
+type coastlineRingMetrics struct {
+	Name        string  `json:"name"`
+	Role        string  `json:"role"`
+	Main        bool    `json:"main,omitempty"`
+	PointsCount int     `json:"points_count"`
+	LengthKM    float64 `json:"length_km"`
+}
+
+type coastlineAggregateMetrics struct {
+	PartCount   int     `json:"part_count"`
+	RingCount   int     `json:"ring_count"`
+	PointsCount int     `json:"points_count"`
+	LengthKM    float64 `json:"length_km"`
+	AreaKM2     float64 `json:"area_km2"`
+	// AreaMethod names the measure behind AreaKM2; PlanarAreaKM2 is the
+	// local-grid shoelace area for comparison.
+	AreaMethod    string  `json:"area_method"`
+	AreaEllipsoid string  `json:"area_ellipsoid,omitempty"`
+	PlanarAreaKM2 float64 `json:"planar_area_km2"`
+	MainRingKM    float64 `json:"main_ring_km"`
+	OtherRingsKM  float64 `json:"other_rings_km"`
 }
 
 type fractalSeriesArtifactMetrics struct {
@@ -82,6 +153,7 @@ type fractalSeriesArtifactMetrics struct {
 	Command             string                     `json:"command"`
 	Dataset             string                     `json:"dataset,omitempty"`
 	Source              string                     `json:"source,omitempty"`
+	Projection          *projectionMetrics         `json:"projection,omitempty"`
 	Title               string                     `json:"title"`
 	OutputDir           string                     `json:"output_dir"`
 	ReferenceCoastline  polylineMetrics            `json:"reference_coastline"`
@@ -91,6 +163,10 @@ type fractalSeriesArtifactMetrics struct {
 	ErosionStrength     float64                    `json:"erosion_strength_meters,omitempty"`
 	ErosionSeed         int64                      `json:"erosion_seed,omitempty"`
 	OrganicOptions      *organicOptionsMetrics     `json:"organic_options,omitempty"`
+	FBMOptions          *fbmOptionsMetrics         `json:"fbm_options,omitempty"`
+	Generator           *generatorMetrics          `json:"generator,omitempty"`
+	AnimationFile       string                     `json:"animation_file,omitempty"`
+	GeometryPackage     *geometryPackageMetrics    `json:"geometry_package,omitempty"`
 	Iterations          []fractalIterationMetrics  `json:"iterations"`
 	Highlights          coastlineHighlightsMetrics `json:"highlights"`
 	Validation          validationMetrics          `json:"validation"`
@@ -102,6 +178,20 @@ type organicOptionsMetrics struct {
 	HeightJitterPct float64 `json:"height_jitter_pct"`
 }
 
+type fbmOptionsMetrics struct {
+	Seed            int64   `json:"seed"`
+	Hurst           float64 `json:"hurst"`
+	Amplitude       float64 `json:"amplitude"`
+	TargetDimension float64 `json:"target_dimension"`
+}
+
+type generatorMetrics struct {
+	Name                    string  `json:"name"`
+	Segments                int     `json:"segments"`
+	TheoreticalDimension    float64 `json:"theoretical_dimension"`
+	TheoreticalLengthFactor float64 `json:"theoretical_length_factor"`
+}
+
 type fractalIterationMetrics struct {
 	Iteration           int               `json:"iteration"`
 	SVGFile             string            `json:"svg_file"`
@@ -112,6 +202,14 @@ type fractalIterationMetrics struct {
 	RelativeToReference float64           `json:"relative_to_reference"`
 	Theory              *theoryMetrics    `json:"theory,omitempty"`
 	Dimension           *dimensionMetrics `json:"dimension,omitempty"`
+	GeometryFile        string            `json:"geometry_file,omitempty"`
+}
+
+// geometryPackageMetrics locates the series in the GeoPackage written with
+// --export-geometry=gpkg.
+type geometryPackageMetrics struct {
+	File  string `json:"file"`
+	Layer string `json:"layer"`
 }
 
 type theoryMetrics struct {
@@ -127,6 +225,334 @@ type dimensionMetrics struct {
 	StableAcrossScales bool    `json:"stable_across_scales"`
 	StabilitySpread    float64 `json:"stability_spread,omitempty"`
 	SampleCount        int     `json:"sample_count"`
+	// ConfidenceInterval is the bootstrap interval of a valid estimate.
+	ConfidenceInterval *confidenceIntervalMetrics `json:"confidence_interval,omitempty"`
+}
+
+type confidenceIntervalMetrics struct {
+	Level      float64 `json:"level"`
+	Lower      float64 `json:"lower"`
+	Upper      float64 `json:"upper"`
+	StdError   float64 `json:"std_error"`
+	Replicates int     `json:"replicates"`
+}
+
+// dimensionEstimatorArtifactMetrics puts every --estimator result of an
+// iteration side by side.
+type dimensionEstimatorArtifactMetrics struct {
+	GeneratedAt string                               `json:"generated_at"`
+	Command     string                               `json:"command"`
+	Dataset     string                               `json:"dataset,omitempty"`
+	Source      string                               `json:"source,omitempty"`
+	Projection  *projectionMetrics                   `json:"projection,omitempty"`
+	Estimators  []string                             `json:"estimators"`
+	QRange      []float64                            `json:"q_range,omitempty"`
+	Iterations  []dimensionEstimatorIterationMetrics `json:"iterations"`
+}
+
+type dimensionEstimatorIterationMetrics struct {
+	Iteration    int                        `json:"iteration"`
+	PointsCount  int                        `json:"points_count"`
+	LengthKM     float64                    `json:"length_km"`
+	Estimates    []dimensionEstimateMetrics `json:"estimates"`
+	Multifractal *multifractalMetrics       `json:"multifractal,omitempty"`
+}
+
+type dimensionEstimateMetrics struct {
+	Estimator          string  `json:"estimator"`
+	Valid              bool    `json:"valid"`
+	Dimension          float64 `json:"dimension,omitempty"`
+	RegressionRSquared float64 `json:"regression_r_squared,omitempty"`
+	StableAcrossScales bool    `json:"stable_across_scales"`
+	StabilitySpread    float64 `json:"stability_spread,omitempty"`
+	SampleCount        int     `json:"sample_count"`
+}
+
+type multifractalMetrics struct {
+	Valid       bool                       `json:"valid"`
+	SampleCount int                        `json:"sample_count"`
+	WindowStart int                        `json:"window_start"`
+	WindowEnd   int                        `json:"window_end"`
+	AlphaMin    float64                    `json:"alpha_min"`
+	AlphaMax    float64                    `json:"alpha_max"`
+	Width       float64                    `json:"width"`
+	Spectrum    []multifractalPointMetrics `json:"spectrum"`
+}
+
+type multifractalPointMetrics struct {
+	Q                  float64 `json:"q"`
+	Valid              bool    `json:"valid"`
+	Tau                float64 `json:"tau"`
+	Dq                 float64 `json:"dq"`
+	Alpha              float64 `json:"alpha"`
+	FAlpha             float64 `json:"f_alpha"`
+	RegressionRSquared float64 `json:"regression_r_squared"`
+	StableAcrossScales bool    `json:"stable_across_scales"`
+}
+
+// ensembleArtifactMetrics summarizes an --ensemble run per iteration or
+// step; the per-seed values go to the --format table.
+type ensembleArtifactMetrics struct {
+	GeneratedAt string                `json:"generated_at"`
+	Command     string                `json:"command"`
+	Dataset     string                `json:"dataset,omitempty"`
+	Source      string                `json:"source,omitempty"`
+	Projection  *projectionMetrics    `json:"projection,omitempty"`
+	OutputDir   string                `json:"output_dir"`
+	SVGFile     string                `json:"svg_file"`
+	Axis        string                `json:"axis"`
+	Members     int                   `json:"members"`
+	Workers     int                   `json:"workers"`
+	Seeds       []int64               `json:"seeds"`
+	Steps       []ensembleStepMetrics `json:"steps"`
+}
+
+type ensembleStepMetrics struct {
+	Index     int                     `json:"index"`
+	Year      int                     `json:"year,omitempty"`
+	LengthKM  *ensembleSummaryMetrics `json:"length_km,omitempty"`
+	AreaKM2   *ensembleSummaryMetrics `json:"area_km2,omitempty"`
+	Dimension *ensembleSummaryMetrics `json:"dimension,omitempty"`
+}
+
+type ensembleSummaryMetrics struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Min    float64 `json:"min"`
+	P5     float64 `json:"p5"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+}
+
+// sweepArtifactMetrics describes a model sweep; the journal next to it is
+// what a resumed sweep reads back.
+type sweepArtifactMetrics struct {
+	GeneratedAt string                    `json:"generated_at"`
+	Command     string                    `json:"command"`
+	Dataset     string                    `json:"dataset,omitempty"`
+	Source      string                    `json:"source,omitempty"`
+	Projection  *projectionMetrics        `json:"projection,omitempty"`
+	OutputDir   string                    `json:"output_dir"`
+	JournalFile string                    `json:"journal_file"`
+	Pipeline    string                    `json:"pipeline"`
+	Sampling    string                    `json:"sampling"`
+	Samples     int                       `json:"samples,omitempty"`
+	Seed        int64                     `json:"seed"`
+	Settings    map[string]string         `json:"settings"`
+	Parameters  []sweep.Parameter         `json:"parameters"`
+	Resumed     int                       `json:"resumed_runs"`
+	Heatmaps    []sweepHeatmapMetrics     `json:"heatmaps"`
+	Sensitivity []sweepSensitivityMetrics `json:"sensitivity"`
+	Runs        []sweepRunMetrics         `json:"runs"`
+}
+
+type sweepHeatmapMetrics struct {
+	Metric  string       `json:"metric"`
+	SVGFile string       `json:"svg_file"`
+	X       string       `json:"x"`
+	Y       string       `json:"y,omitempty"`
+	XValues []float64    `json:"x_values"`
+	YValues []float64    `json:"y_values,omitempty"`
+	Cells   [][]*float64 `json:"cells"`
+}
+
+type sweepSensitivityMetrics struct {
+	Parameter    string  `json:"parameter"`
+	Dimension    float64 `json:"dimension_delta"`
+	LengthGrowth float64 `json:"length_growth_delta"`
+}
+
+type sweepRunMetrics struct {
+	Index          int                `json:"index"`
+	Parameters     map[string]float64 `json:"parameters"`
+	LengthKM       float64            `json:"length_km"`
+	LengthGrowth   float64            `json:"length_growth"`
+	AreaKM2        float64            `json:"area_km2"`
+	Dimension      *float64           `json:"dimension,omitempty"`
+	DimensionValid bool               `json:"dimension_valid"`
+}
+
+// fitArtifactMetrics describes a fit of the organic model to the loaded
+// coastline; the runs themselves are in the fit table.
+type fitArtifactMetrics struct {
+	GeneratedAt     string               `json:"generated_at"`
+	Command         string               `json:"command"`
+	Dataset         string               `json:"dataset,omitempty"`
+	Source          string               `json:"source,omitempty"`
+	Projection      *projectionMetrics   `json:"projection,omitempty"`
+	OutputDir       string               `json:"output_dir"`
+	SeriesMetrics   string               `json:"series_metrics_file"`
+	Method          string               `json:"method"`
+	Evaluations     int                  `json:"evaluations_per_seed"`
+	Seeds           int                  `json:"seeds"`
+	Seed            int64                `json:"seed"`
+	Weights         fitWeightsMetrics    `json:"weights"`
+	SinuosityWindow int                  `json:"sinuosity_windows"`
+	ModelBasePoints int                  `json:"model_base_points"`
+	Target          fitSignatureMetrics  `json:"target"`
+	Best            fitEvaluationMetrics `json:"best"`
+	Estimates       []fitEstimateMetrics `json:"estimates"`
+	Fits            []fitSeedMetrics     `json:"fits"`
+}
+
+type fitWeightsMetrics struct {
+	Dimension  float64 `json:"dimension"`
+	Richardson float64 `json:"richardson"`
+	Sinuosity  float64 `json:"sinuosity"`
+}
+
+type fitSignatureMetrics struct {
+	Points              int               `json:"points"`
+	LengthKM            float64           `json:"length_km"`
+	Dimension           *float64          `json:"dimension"`
+	RichardsonDimension *float64          `json:"richardson_dimension"`
+	Sinuosity           *float64          `json:"sinuosity"`
+	Richardson          []fitRulerMetrics `json:"richardson"`
+}
+
+type fitRulerMetrics struct {
+	ScaleFactor float64 `json:"scale_factor"`
+	RulerKM     float64 `json:"ruler_km"`
+	LengthKM    float64 `json:"length_km"`
+}
+
+type fitMisfitMetrics struct {
+	Dimension  *float64 `json:"dimension"`
+	Richardson *float64 `json:"richardson"`
+	Rulers     int      `json:"rulers"`
+	Sinuosity  *float64 `json:"sinuosity"`
+	Total      *float64 `json:"total"`
+}
+
+type fitEvaluationMetrics struct {
+	Seed       int64               `json:"seed"`
+	Run        int                 `json:"run"`
+	Parameters map[string]float64  `json:"parameters"`
+	Signature  fitSignatureMetrics `json:"signature"`
+	Misfit     fitMisfitMetrics    `json:"misfit"`
+}
+
+type fitEstimateMetrics struct {
+	Parameter string                  `json:"parameter"`
+	Min       float64                 `json:"min"`
+	Max       float64                 `json:"max"`
+	Integer   bool                    `json:"integer"`
+	Free      bool                    `json:"free"`
+	Best      float64                 `json:"best"`
+	Spread    *ensembleSummaryMetrics `json:"spread"`
+}
+
+type fitSeedMetrics struct {
+	Seed int64                `json:"seed"`
+	Runs int                  `json:"runs"`
+	Best fitEvaluationMetrics `json:"best"`
+}
+
+// calibrationArtifactMetrics describes an estimator calibration against
+// curves of known dimension; NaN biases and means are null.
+type calibrationArtifactMetrics struct {
+	GeneratedAt string                        `json:"generated_at"`
+	Command     string                        `json:"command"`
+	OutputDir   string                        `json:"output_dir"`
+	SVGFile     string                        `json:"svg_file"`
+	Options     calibrationOptionsMetrics     `json:"options"`
+	References  []calibrationReferenceMetrics `json:"references"`
+	Estimators  []calibrationEstimatorMetrics `json:"estimators"`
+	Summaries   []calibrationSummaryMetrics   `json:"summaries"`
+}
+
+type calibrationOptionsMetrics struct {
+	Generators  []string  `json:"generators"`
+	Hursts      []float64 `json:"hursts"`
+	Estimators  []string  `json:"estimators"`
+	Rotations   int       `json:"rotations"`
+	MaxPoints   int       `json:"max_points"`
+	Tolerance   float64   `json:"tolerance"`
+	SettleDelta float64   `json:"settle_delta"`
+	Seed        int64     `json:"seed"`
+}
+
+type calibrationReferenceMetrics struct {
+	Name          string  `json:"name"`
+	Family        string  `json:"family"`
+	TrueDimension float64 `json:"true_dimension"`
+	MaxIteration  int     `json:"max_iteration"`
+}
+
+type calibrationEstimatorMetrics struct {
+	Estimator   string   `json:"estimator"`
+	References  int      `json:"references"`
+	Valid       int      `json:"valid_references"`
+	MeanBias    *float64 `json:"mean_bias"`
+	MeanAbsBias *float64 `json:"mean_abs_bias"`
+	RMSE        *float64 `json:"rmse"`
+	Converged   int      `json:"converged_references"`
+}
+
+type calibrationSummaryMetrics struct {
+	Reference     string                    `json:"reference"`
+	Estimator     string                    `json:"estimator"`
+	TrueDimension float64                   `json:"true_dimension"`
+	Bias          *float64                  `json:"bias"`
+	RMSE          *float64                  `json:"rmse"`
+	ConvergedAt   *int                      `json:"converged_at_iteration"`
+	SettledAt     *int                      `json:"settled_at_iteration"`
+	Levels        []calibrationLevelMetrics `json:"levels"`
+}
+
+type calibrationLevelMetrics struct {
+	Iteration int      `json:"iteration"`
+	Points    int      `json:"points"`
+	Valid     int      `json:"valid_estimates"`
+	Total     int      `json:"estimates"`
+	Mean      *float64 `json:"mean"`
+	StdDev    *float64 `json:"std_dev"`
+	Bias      *float64 `json:"bias"`
+	RMSE      *float64 `json:"rmse"`
+}
+
+type richardsonArtifactMetrics struct {
+	GeneratedAt string             `json:"generated_at"`
+	Command     string             `json:"command"`
+	Dataset     string             `json:"dataset,omitempty"`
+	Source      string             `json:"source,omitempty"`
+	Projection  *projectionMetrics `json:"projection,omitempty"`
+	SVGFile     string             `json:"svg_file"`
+	Real        polylineMetrics    `json:"real"`
+	Render      polylineMetrics    `json:"render"`
+	Richardson  richardsonMetrics  `json:"richardson"`
+	Validation  validationMetrics  `json:"validation"`
+}
+
+type richardsonMetrics struct {
+	Valid              bool                      `json:"valid"`
+	Dimension          float64                   `json:"dimension,omitempty"`
+	Exponent           float64                   `json:"exponent,omitempty"`
+	Prefactor          float64                   `json:"prefactor,omitempty"`
+	RegressionRSquared float64                   `json:"regression_r_squared,omitempty"`
+	StableAcrossScales bool                      `json:"stable_across_scales"`
+	StabilitySpread    float64                   `json:"stability_spread,omitempty"`
+	ExtentKM           float64                   `json:"extent_km"`
+	WindowStart        int                       `json:"window_start"`
+	WindowEnd          int                       `json:"window_end"`
+	LocalDimensions    []float64                 `json:"local_dimensions"`
+	Samples            []richardsonSampleMetrics `json:"samples"`
+}
+
+// richardsonSampleMetrics reports both divider walks; length_km is their
+// mean.
+type richardsonSampleMetrics struct {
+	ScaleFactor         float64 `json:"scale_factor"`
+	RulerKM             float64 `json:"ruler_km"`
+	ForwardSteps        int     `json:"forward_steps"`
+	ForwardRemainderKM  float64 `json:"forward_remainder_km"`
+	BackwardSteps       int     `json:"backward_steps"`
+	BackwardRemainderKM float64 `json:"backward_remainder_km"`
+	LengthKM            float64 `json:"length_km"`
 }
 
 type erosionStepMetrics struct {
@@ -136,6 +562,125 @@ type erosionStepMetrics struct {
 	RenderPoints int     `json:"render_points"`
 	LengthKM     float64 `json:"length_km"`
 	AreaKM       float64 `json:"area_km2"`
+	// Exposure describes the wave exposure that produced this step; it is
+	// absent for the initial state and for the Gaussian model.
+	Exposure *erosionExposureMetrics `json:"exposure,omitempty"`
+	// Lithology is the mean displacement of the step by rock type.
+	Lithology []lithologyStepMetrics `json:"lithology,omitempty"`
+	// Sediment is the longshore transport budget of the step with --sediment.
+	Sediment *sedimentBudgetMetrics `json:"sediment,omitempty"`
+	// Year and Scenario label the step with calendar years in --scenario runs.
+	Year     int                  `json:"year,omitempty"`
+	Scenario *scenarioStepMetrics `json:"scenario,omitempty"`
+	// Percolation describes the grid after the step of the percolation
+	// model, whose box-counting D is tracked per step.
+	Percolation *percolationStepMetrics `json:"percolation,omitempty"`
+	Dimension   *float64                `json:"dimension,omitempty"`
+	// GeometryFile is the GeoJSON of the step with --export-geometry=geojson.
+	GeometryFile string `json:"geometry_file,omitempty"`
+}
+
+type scenarioStepMetrics struct {
+	FromYear      int     `json:"from_year"`
+	ToYear        int     `json:"to_year"`
+	BackgroundM   float64 `json:"background_retreat_m"`
+	Storms        int     `json:"storms"`
+	StormM        float64 `json:"storm_retreat_m"`
+	MaxStormM     float64 `json:"max_storm_retreat_m"`
+	SeaLevelM     float64 `json:"sea_level_m"`
+	SeaLevelRiseM float64 `json:"sea_level_rise_m"`
+	BruunM        float64 `json:"bruun_retreat_m"`
+	TotalM        float64 `json:"total_retreat_m"`
+}
+
+type scenarioMetrics struct {
+	Name               string             `json:"name,omitempty"`
+	Source             string             `json:"source,omitempty"`
+	Projection         *projectionMetrics `json:"projection,omitempty"`
+	StartYear          int                `json:"start_year"`
+	EndYear            int                `json:"end_year"`
+	StepYears          int                `json:"step_years"`
+	BackgroundRetreatM float64            `json:"background_retreat_m_per_year"`
+	StormReturnPeriod  float64            `json:"storm_return_period_years,omitempty"`
+	StormRetreatM      float64            `json:"storm_retreat_m,omitempty"`
+	SeaLevelCurve      string             `json:"sea_level_curve,omitempty"`
+	BruunSlope         float64            `json:"bruun_slope"`
+	TotalRetreatM      float64            `json:"total_retreat_m"`
+	TotalStorms        int                `json:"total_storms"`
+	FinalSeaLevelM     float64            `json:"final_sea_level_m"`
+}
+
+type sedimentBudgetMetrics struct {
+	ReleasedM3       float64 `json:"released_m3"`
+	ErodedM3         float64 `json:"eroded_m3"`
+	DepositedM3      float64 `json:"deposited_m3"`
+	ExportedM3       float64 `json:"exported_m3"`
+	NetM3            float64 `json:"net_m3"`
+	GrossDriftM3     float64 `json:"gross_drift_m3"`
+	NetDriftM3       float64 `json:"net_drift_m3"`
+	AccretingPoints  int     `json:"accreting_points"`
+	RetreatingPoints int     `json:"retreating_points"`
+}
+
+type lithologyStepMetrics struct {
+	Rock              string  `json:"rock"`
+	Resistance        float64 `json:"resistance"`
+	MeanDisplacementM float64 `json:"mean_displacement_m"`
+	MaxDisplacementM  float64 `json:"max_displacement_m"`
+	Points            int     `json:"points"`
+}
+
+type lithologyMetrics struct {
+	Name       string                 `json:"name,omitempty"`
+	Source     string                 `json:"source,omitempty"`
+	Projection *projectionMetrics     `json:"projection,omitempty"`
+	Rocks      []lithologyRockMetrics `json:"rocks"`
+}
+
+type lithologyRockMetrics struct {
+	Rock       string  `json:"rock"`
+	Label      string  `json:"label"`
+	Resistance float64 `json:"resistance"`
+	Points     int     `json:"points"`
+	LengthKM   float64 `json:"length_km"`
+	Share      float64 `json:"length_share"`
+}
+
+type percolationMetrics struct {
+	Columns       int     `json:"columns"`
+	Rows          int     `json:"rows"`
+	CellM         float64 `json:"cell_m"`
+	SeaForce      float64 `json:"sea_force"`
+	SeaDamping    float64 `json:"sea_damping"`
+	Sweeps        int     `json:"sweeps"`
+	HullDimension float64 `json:"hull_dimension"`
+}
+
+type percolationStepMetrics struct {
+	Sweeps      int     `json:"sweeps"`
+	SeaForce    float64 `json:"sea_force"`
+	ErodedCells int     `json:"eroded_cells"`
+	CoastCells  int     `json:"coast_cells"`
+	Stable      bool    `json:"stable"`
+}
+
+type erosionExposureMetrics struct {
+	MeanFetchKM      float64 `json:"mean_fetch_km"`
+	MaxFetchKM       float64 `json:"max_fetch_km"`
+	MeanExposure     float64 `json:"mean_exposure"`
+	P90Exposure      float64 `json:"p90_exposure"`
+	ExposedShare     float64 `json:"exposed_share"`
+	MeanRetreatM     float64 `json:"mean_retreat_m"`
+	MaxRetreatM      float64 `json:"max_retreat_m"`
+	HeadlandRetreatM float64 `json:"headland_retreat_m"`
+	BayRetreatM      float64 `json:"bay_retreat_m"`
+	Headlands        int     `json:"headland_points"`
+	Bays             int     `json:"bay_points"`
+}
+
+type waveDirectionMetrics struct {
+	FromDeg float64 `json:"from_deg"`
+	Weight  float64 `json:"weight"`
 }
 
 type erosionSeriesArtifactMetrics struct {
@@ -143,13 +688,23 @@ type erosionSeriesArtifactMetrics struct {
 	Command             string                     `json:"command"`
 	Dataset             string                     `json:"dataset,omitempty"`
 	Source              string                     `json:"source,omitempty"`
+	Projection          *projectionMetrics         `json:"projection,omitempty"`
 	OutputDir           string                     `json:"output_dir"`
 	ReferenceCoastline  polylineMetrics            `json:"reference_coastline"`
 	ReferenceRender     polylineMetrics            `json:"reference_render"`
 	ModelBase           polylineMetrics            `json:"model_base"`
 	ModelSimplification simplificationMetrics      `json:"model_simplification"`
+	ErosionModel        string                     `json:"erosion_model"`
+	AreaMethod          string                     `json:"area_method"`
 	ErosionStrength     float64                    `json:"erosion_strength_meters,omitempty"`
 	ErosionSeed         int64                      `json:"erosion_seed,omitempty"`
+	WaveClimate         []waveDirectionMetrics     `json:"wave_climate,omitempty"`
+	Lithology           *lithologyMetrics          `json:"lithology,omitempty"`
+	SedimentRateM3      float64                    `json:"sediment_rate_m3,omitempty"`
+	Scenario            *scenarioMetrics           `json:"scenario,omitempty"`
+	Percolation         *percolationMetrics        `json:"percolation,omitempty"`
+	AnimationFile       string                     `json:"animation_file,omitempty"`
+	GeometryPackage     *geometryPackageMetrics    `json:"geometry_package,omitempty"`
 	Steps               []erosionStepMetrics       `json:"steps"`
 	Highlights          coastlineHighlightsMetrics `json:"highlights"`
 	Validation          validationMetrics          `json:"validation"`
@@ -165,17 +720,51 @@ func newExportContext(app *App) exportContext {
 		Dataset:    app.Dataset,
 		Source:     app.DataSource,
 		Validation: app.Validation,
+		Coastline:  app.Coastline,
+		Distance:   app.Distance,
+		Area:       app.Area,
+		Projection: app.Projection,
+		Animate:    app.Config.Animate,
+		Geometry:   newGeometryExport(app.Config.ExportGeometry),
+		Bootstrap:  app.Config.Bootstrap,
+		Format:     app.Config.Format,
 	}
 }
 
-func summarizePolyline(points []geometry.LatLon) polylineMetrics {
+func projectionMetricsFor(p fraes.Projector) *projectionMetrics {
+	if p == nil {
+		return nil
+	}
+	return &projectionMetrics{Name: p.Name(), Description: p.String()}
+}
+
+func summarizePolyline(points []fraes.LatLon) polylineMetrics {
 	return polylineMetrics{
 		PointsCount: len(points),
-		LengthKM:    geometry.PolylineLength(points),
+		LengthKM:    fraes.PolylineLength(points),
+	}
+}
+
+func summarizeGeodesic(points []fraes.LatLon, distance fraes.Distance) geodesicMetrics {
+	if distance == nil {
+		distance, _ = fraes.NewDistance(fraes.GeodesicHaversine)
+	}
+	metrics := geodesicMetrics{
+		Method:      distance.Name(),
+		LengthKM:    fraes.PolylineLengthWith(points, distance),
+		HaversineKM: fraes.PolylineLength(points),
+	}
+	if ellipsoid, ok := fraes.DistanceEllipsoid(distance); ok {
+		metrics.Ellipsoid = ellipsoid.Name
 	}
+	metrics.DifferenceKM = metrics.LengthKM - metrics.HaversineKM
+	if metrics.HaversineKM > 0 {
+		metrics.DifferencePct = metrics.DifferenceKM / metrics.HaversineKM * 100
+	}
+	return metrics
 }
 
-func summarizeSimplification(before, after []geometry.LatLon) simplificationMetrics {
+func summarizeSimplification(before, after []fraes.LatLon) simplificationMetrics {
 	beforeSummary := summarizePolyline(before)
 	afterSummary := summarizePolyline(after)
 	deltaKM := afterSummary.LengthKM - beforeSummary.LengthKM
@@ -221,7 +810,7 @@ func nowTimestamp() string {
 	return time.Now().UTC().Format(time.RFC3339)
 }
 
-func dimensionMetricsFromAnalysis(analysis fractal.BoxCountingAnalysis) *dimensionMetrics {
+func dimensionMetricsFromAnalysis(analysis fraes.BoxCountingAnalysis, interval fraes.ConfidenceInterval) *dimensionMetrics {
 	if len(analysis.Samples) == 0 && !analysis.Valid {
 		return nil
 	}
@@ -236,10 +825,379 @@ func dimensionMetricsFromAnalysis(analysis fractal.BoxCountingAnalysis) *dimensi
 	if analysis.Valid {
 		result.Dimension = analysis.Dimension
 	}
+	result.ConfidenceInterval = confidenceIntervalMetricsFrom(interval)
+	return result
+}
+
+func confidenceIntervalMetricsFrom(interval fraes.ConfidenceInterval) *confidenceIntervalMetrics {
+	if !interval.Valid {
+		return nil
+	}
+	return &confidenceIntervalMetrics{
+		Level:      interval.Level,
+		Lower:      interval.Lower,
+		Upper:      interval.Upper,
+		StdError:   interval.StdError,
+		Replicates: interval.Replicates,
+	}
+}
+
+// writeDimensionEstimatorMetrics saves dimension-estimators.metrics.json
+// next to the series SVG.
+func writeDimensionEstimatorMetrics(assessment dimensionAssessment, output string, ctx exportContext) error {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+
+	report := dimensionEstimatorArtifactMetrics{
+		GeneratedAt: nowTimestamp(),
+		Command:     canonicalCommandPath(ctx.Command),
+		Dataset:     ctx.Dataset,
+		Source:      ctx.Source,
+		Projection:  projectionMetricsFor(assessment.Projection),
+		Estimators:  assessment.Estimators,
+	}
+	if slices.Contains(assessment.Estimators, fraes.EstimatorMultifractal) {
+		report.QRange = assessment.QRange
+	}
+	for _, result := range assessment.Iterations {
+		iteration := dimensionEstimatorIterationMetrics{
+			Iteration:    result.Iteration,
+			PointsCount:  result.Points,
+			LengthKM:     result.LengthKM,
+			Estimates:    make([]dimensionEstimateMetrics, 0, len(result.Estimates)),
+			Multifractal: multifractalMetricsFromAnalysis(result.Multifractal),
+		}
+		for _, estimate := range result.Estimates {
+			metrics := dimensionEstimateMetrics{
+				Estimator:          estimate.Estimator,
+				Valid:              estimate.Valid,
+				RegressionRSquared: estimate.RegressionRSquared,
+				StableAcrossScales: estimate.StableAcrossScales,
+				StabilitySpread:    estimate.StabilitySpread,
+				SampleCount:        estimate.SampleCount,
+			}
+			if estimate.Valid {
+				metrics.Dimension = estimate.Dimension
+			}
+			iteration.Estimates = append(iteration.Estimates, metrics)
+		}
+		report.Iterations = append(report.Iterations, iteration)
+	}
+
+	metricsPath := metricsPathForSeries(outputDir, "dimension-estimators")
+	if err := writeMetricsJSON(metricsPath, report); err != nil {
+		return err
+	}
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
+
+// writeEnsembleMetrics saves {name}-ensemble.metrics.json next to the
+// ensemble SVG.
+func writeEnsembleMetrics(report ensembleReport, svgFile, output string, ctx exportContext) error {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+
+	metrics := ensembleArtifactMetrics{
+		GeneratedAt: nowTimestamp(),
+		Command:     canonicalCommandPath(ctx.Command),
+		Dataset:     ctx.Dataset,
+		Source:      ctx.Source,
+		Projection:  projectionMetricsFor(report.Projection),
+		OutputDir:   outputDir,
+		SVGFile:     svgFile,
+		Axis:        report.Axis,
+		Members:     len(report.Members),
+		Workers:     report.Workers,
+		Seeds:       make([]int64, 0, len(report.Members)),
+		Steps:       make([]ensembleStepMetrics, 0, len(report.Length)),
+	}
+	for _, m := range report.Members {
+		metrics.Seeds = append(metrics.Seeds, m.Seed)
+	}
+	for i := range report.Length {
+		step := ensembleStepMetrics{
+			Index:     i,
+			LengthKM:  ensembleSummaryMetricsFrom(report.Length[i]),
+			AreaKM2:   ensembleSummaryMetricsFrom(report.Area[i]),
+			Dimension: ensembleSummaryMetricsFrom(report.Dimension[i]),
+		}
+		if i < len(report.Years) {
+			step.Year = report.Years[i]
+		}
+		metrics.Steps = append(metrics.Steps, step)
+	}
+
+	metricsPath := metricsPathForSeries(outputDir, report.Name+"-ensemble")
+	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
+		return err
+	}
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
+
+// writeCalibrationMetrics saves calibration.metrics.json next to the chart.
+func writeCalibrationMetrics(result calibration.Result, svgFile, output string, ctx exportContext) error {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+
+	opts := result.Options
+	metrics := calibrationArtifactMetrics{
+		GeneratedAt: nowTimestamp(),
+		Command:     canonicalCommandPath(ctx.Command),
+		OutputDir:   outputDir,
+		SVGFile:     svgFile,
+		Options: calibrationOptionsMetrics{
+			Generators:  opts.Generators,
+			Hursts:      opts.Hursts,
+			Estimators:  opts.Estimators,
+			Rotations:   opts.Rotations,
+			MaxPoints:   opts.MaxPoints,
+			Tolerance:   opts.Tolerance,
+			SettleDelta: calibration.SettleDelta,
+			Seed:        opts.Seed,
+		},
+		References: make([]calibrationReferenceMetrics, 0, len(result.References)),
+		Estimators: make([]calibrationEstimatorMetrics, 0, len(result.Estimators)),
+		Summaries:  make([]calibrationSummaryMetrics, 0, len(result.Summaries)),
+	}
+	for _, ref := range result.References {
+		metrics.References = append(metrics.References, calibrationReferenceMetrics{
+			Name:          ref.Name,
+			Family:        ref.Family,
+			TrueDimension: ref.TrueDimension,
+			MaxIteration:  ref.MaxIteration,
+		})
+	}
+	for _, rank := range result.Estimators {
+		metrics.Estimators = append(metrics.Estimators, calibrationEstimatorMetrics{
+			Estimator:   rank.Estimator,
+			References:  rank.References,
+			Valid:       rank.Valid,
+			MeanBias:    finiteOrNil(rank.MeanBias),
+			MeanAbsBias: finiteOrNil(rank.MeanAbsBias),
+			RMSE:        finiteOrNil(rank.RMSE),
+			Converged:   rank.Converged,
+		})
+	}
+	for _, s := range result.Summaries {
+		summary := calibrationSummaryMetrics{
+			Reference:     s.Reference,
+			Estimator:     s.Estimator,
+			TrueDimension: s.TrueDimension,
+			Bias:          finiteOrNil(s.Bias),
+			RMSE:          finiteOrNil(s.RMSE),
+			Levels:        make([]calibrationLevelMetrics, 0, len(s.Levels)),
+		}
+		if s.ConvergedAt > 0 {
+			summary.ConvergedAt = &s.ConvergedAt
+		}
+		if s.SettledAt > 0 {
+			summary.SettledAt = &s.SettledAt
+		}
+		for _, level := range s.Levels {
+			summary.Levels = append(summary.Levels, calibrationLevelMetrics{
+				Iteration: level.Iteration,
+				Points:    level.Points,
+				Valid:     level.Valid,
+				Total:     level.Total,
+				Mean:      finiteOrNil(level.Mean),
+				StdDev:    finiteOrNil(level.StdDev),
+				Bias:      finiteOrNil(level.Bias),
+				RMSE:      finiteOrNil(level.RMSE),
+			})
+		}
+		metrics.Summaries = append(metrics.Summaries, summary)
+	}
+
+	metricsPath := metricsPathForSeries(outputDir, "calibration")
+	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
+		return err
+	}
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
+
+func writeFitMetrics(result fit.Result, modelBasePoints int, output string, ctx exportContext) error {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+
+	opts := result.Options
+	metrics := fitArtifactMetrics{
+		GeneratedAt:     nowTimestamp(),
+		Command:         canonicalCommandPath(ctx.Command),
+		Dataset:         ctx.Dataset,
+		Source:          ctx.Source,
+		Projection:      projectionMetricsFor(ctx.Projection),
+		OutputDir:       outputDir,
+		SeriesMetrics:   metricsPathForSeries(outputDir, "fit-model"),
+		Method:          opts.Method,
+		Evaluations:     opts.Evaluations,
+		Seeds:           opts.Seeds,
+		Seed:            opts.Seed,
+		Weights:         fitWeightsMetrics(opts.Weights),
+		SinuosityWindow: fit.DefaultSinuosityWindows,
+		ModelBasePoints: modelBasePoints,
+		Target:          fitSignatureMetricsFrom(result.Target),
+		Best:            fitEvaluationMetricsFrom(opts.Parameters, result.Best),
+		Estimates:       make([]fitEstimateMetrics, 0, len(result.Estimates)),
+		Fits:            make([]fitSeedMetrics, 0, len(result.Fits)),
+	}
+	for _, e := range result.Estimates {
+		metrics.Estimates = append(metrics.Estimates, fitEstimateMetrics{
+			Parameter: e.Parameter.Name,
+			Min:       e.Parameter.Min,
+			Max:       e.Parameter.Max,
+			Integer:   e.Parameter.Integer,
+			Free:      e.Parameter.Free(),
+			Best:      e.Best,
+			Spread:    ensembleSummaryMetricsFrom(e.Spread),
+		})
+	}
+	for _, f := range result.Fits {
+		metrics.Fits = append(metrics.Fits, fitSeedMetrics{
+			Seed: f.Seed,
+			Runs: len(f.Evaluations),
+			Best: fitEvaluationMetricsFrom(opts.Parameters, f.Best),
+		})
+	}
+
+	metricsPath := metricsPathForSeries(outputDir, "fit")
+	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
+		return err
+	}
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
+
+func fitSignatureMetricsFrom(sig fit.Signature) fitSignatureMetrics {
+	metrics := fitSignatureMetrics{
+		Points:              sig.Points,
+		LengthKM:            sig.LengthKM,
+		Dimension:           finiteOrNil(sig.Dimension),
+		RichardsonDimension: finiteOrNil(sig.RichardsonDimension),
+		Sinuosity:           finiteOrNil(sig.Sinuosity),
+		Richardson:          make([]fitRulerMetrics, 0, len(sig.Richardson)),
+	}
+	for _, r := range sig.Richardson {
+		metrics.Richardson = append(metrics.Richardson, fitRulerMetrics(r))
+	}
+	return metrics
+}
+
+func fitEvaluationMetricsFrom(params []fit.Parameter, e fit.Evaluation) fitEvaluationMetrics {
+	return fitEvaluationMetrics{
+		Seed:       e.Seed,
+		Run:        e.Index,
+		Parameters: fitValues(params, e.Values),
+		Signature:  fitSignatureMetricsFrom(e.Signature),
+		Misfit: fitMisfitMetrics{
+			Dimension:  finiteOrNil(e.Misfit.Dimension),
+			Richardson: finiteOrNil(e.Misfit.Richardson),
+			Rulers:     e.Misfit.Rulers,
+			Sinuosity:  finiteOrNil(e.Misfit.Sinuosity),
+			Total:      finiteOrNil(e.Misfit.Total),
+		},
+	}
+}
+
+// finiteOrNil turns NaN, which marks a missing estimate, into null.
+func finiteOrNil(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func ensembleSummaryMetricsFrom(s ensemble.Summary) *ensembleSummaryMetrics {
+	if s.Count == 0 {
+		return nil
+	}
+	return &ensembleSummaryMetrics{
+		Count:  s.Count,
+		Mean:   s.Mean,
+		StdDev: s.StdDev,
+		Min:    s.Min,
+		P5:     s.P5,
+		P25:    s.P25,
+		Median: s.Median,
+		P75:    s.P75,
+		P95:    s.P95,
+		Max:    s.Max,
+	}
+}
+
+func multifractalMetricsFromAnalysis(analysis *fraes.MultifractalAnalysis) *multifractalMetrics {
+	if analysis == nil {
+		return nil
+	}
+	result := &multifractalMetrics{
+		Valid:       analysis.Valid,
+		SampleCount: analysis.SampleCount,
+		WindowStart: analysis.WindowStart,
+		WindowEnd:   analysis.WindowEnd,
+		AlphaMin:    analysis.AlphaMin,
+		AlphaMax:    analysis.AlphaMax,
+		Width:       analysis.AlphaMax - analysis.AlphaMin,
+		Spectrum:    make([]multifractalPointMetrics, 0, len(analysis.Spectrum)),
+	}
+	for _, point := range analysis.Spectrum {
+		result.Spectrum = append(result.Spectrum, multifractalPointMetrics{
+			Q:                  point.Q,
+			Valid:              point.Valid,
+			Tau:                point.Tau,
+			Dq:                 point.Dq,
+			Alpha:              point.Alpha,
+			FAlpha:             point.FAlpha,
+			RegressionRSquared: point.RegressionRSquared,
+			StableAcrossScales: point.StableAcrossScales,
+		})
+	}
+	return result
+}
+
+func richardsonMetricsFromAnalysis(analysis fraes.RichardsonAnalysis) richardsonMetrics {
+	samples := make([]richardsonSampleMetrics, 0, len(analysis.Samples))
+	for _, sample := range analysis.Samples {
+		samples = append(samples, richardsonSampleMetrics{
+			ScaleFactor:         sample.ScaleFactor,
+			RulerKM:             sample.RulerKM,
+			ForwardSteps:        sample.ForwardSteps,
+			ForwardRemainderKM:  sample.ForwardRemainderKM,
+			BackwardSteps:       sample.BackwardSteps,
+			BackwardRemainderKM: sample.BackwardRemainderKM,
+			LengthKM:            sample.LengthKM,
+		})
+	}
+
+	result := richardsonMetrics{
+		Valid:              analysis.Valid,
+		RegressionRSquared: analysis.RegressionRSquared,
+		StableAcrossScales: analysis.StableAcrossScales,
+		StabilitySpread:    analysis.StabilitySpread,
+		ExtentKM:           analysis.ExtentKM,
+		WindowStart:        analysis.WindowStart,
+		WindowEnd:          analysis.WindowEnd,
+		LocalDimensions:    append([]float64{}, analysis.LocalDimensions...),
+		Samples:            samples,
+	}
+	if analysis.Valid {
+		result.Dimension = analysis.Dimension
+		result.Exponent = analysis.Exponent
+		result.Prefactor = analysis.Prefactor
+	}
 	return result
 }
 
-func validationMetricsFromData(report coastline.ValidationReport, summary coastline.ValidationSummary) validationMetrics {
+func validationMetricsFromData(report fraes.ValidationReport, summary fraes.ValidationSummary) validationMetrics {
 	issues := make([]validationIssueMetrics, 0, len(summary.Issues))
 	for _, issue := range summary.Issues {
 		issues = append(issues, validationIssueMetrics{
@@ -265,7 +1223,52 @@ func validationMetricsFromData(report coastline.ValidationReport, summary coastl
 	}
 }
 
-func coastlineHighlightsMetricsFromHints(hints coastline.VisualizationHints) coastlineHighlightsMetrics {
+func coastlinePartsMetrics(coast fraes.Coastline, distance fraes.Distance, area fraes.AreaMeasure) ([]coastlinePartMetrics, *coastlineAggregateMetrics) {
+	if len(coast.Parts) == 0 {
+		return nil, nil
+	}
+
+	parts := make([]coastlinePartMetrics, 0, len(coast.Parts))
+	for _, part := range coast.Parts {
+		rings := make([]coastlineRingMetrics, 0, len(part.Rings))
+		for _, ring := range part.Rings {
+			rings = append(rings, coastlineRingMetrics{
+				Name:        ring.Name,
+				Role:        string(ring.Role),
+				Main:        ring.Main,
+				PointsCount: len(ring.Points),
+				LengthKM:    fraes.PolylineLengthWith(ring.Points, distance),
+			})
+		}
+		parts = append(parts, coastlinePartMetrics{
+			Name:       part.Name,
+			Properties: part.Properties,
+			LengthKM:   part.LengthKM(distance),
+			AreaKM2:    part.AreaKM2(area),
+			Rings:      rings,
+		})
+	}
+
+	total := coast.LengthKM(distance)
+	main := fraes.PolylineLengthWith(coast.MainPoints(), distance)
+	aggregate := &coastlineAggregateMetrics{
+		PartCount:     len(coast.Parts),
+		RingCount:     coast.RingCount(),
+		PointsCount:   coast.PointCount(),
+		LengthKM:      total,
+		AreaKM2:       coast.AreaKM2(area),
+		AreaMethod:    areaMethodName(area),
+		PlanarAreaKM2: coast.AreaKM2(nil),
+		MainRingKM:    main,
+		OtherRingsKM:  total - main,
+	}
+	if ellipsoid, ok := fraes.AreaEllipsoid(area); ok {
+		aggregate.AreaEllipsoid = ellipsoid.Name
+	}
+	return parts, aggregate
+}
+
+func coastlineHighlightsMetricsFromHints(hints fraes.VisualizationHints) coastlineHighlightsMetrics {
 	segments := make([]segmentHighlightMetrics, 0, len(hints.LongSegments))
 	for _, segment := range hints.LongSegments {
 		segments = append(segments, segmentHighlightMetrics{
@@ -287,3 +1290,277 @@ func cloneStrings(values []string) []string {
 	cloned = append(cloned, values...)
 	return cloned
 }
+
+func erosionExposureMetricsForStep(series erosionSeries, step int) *erosionExposureMetrics {
+	if step <= 0 || step > len(series.WaveSteps) {
+		return nil
+	}
+	stats := series.WaveSteps[step-1]
+	return &erosionExposureMetrics{
+		MeanFetchKM:      stats.MeanFetchKM,
+		MaxFetchKM:       stats.MaxFetchKM,
+		MeanExposure:     stats.MeanExposure,
+		P90Exposure:      stats.P90Exposure,
+		ExposedShare:     stats.ExposedShare,
+		MeanRetreatM:     stats.MeanRetreatM,
+		MaxRetreatM:      stats.MaxRetreatM,
+		HeadlandRetreatM: stats.HeadlandRetreatM,
+		BayRetreatM:      stats.BayRetreatM,
+		Headlands:        stats.Headlands,
+		Bays:             stats.Bays,
+	}
+}
+
+func percolationMetricsForSeries(series erosionSeries) *percolationMetrics {
+	run := series.Percolation
+	if run == nil {
+		return nil
+	}
+	return &percolationMetrics{
+		Columns:       run.Columns,
+		Rows:          run.Rows,
+		CellM:         run.CellM,
+		SeaForce:      series.PercolationOptions.Force,
+		SeaDamping:    series.PercolationOptions.Damping,
+		Sweeps:        run.Sweeps,
+		HullDimension: percolation.HullDimension,
+	}
+}
+
+func percolationStepMetricsForStep(series erosionSeries, step int) *percolationStepMetrics {
+	if series.Percolation == nil || step <= 0 || step > len(series.Percolation.Steps) {
+		return nil
+	}
+	stats := series.Percolation.Steps[step-1]
+	return &percolationStepMetrics{
+		Sweeps:      stats.Sweeps,
+		SeaForce:    stats.Force,
+		ErodedCells: stats.ErodedCells,
+		CoastCells:  stats.CoastCells,
+		Stable:      stats.Stable,
+	}
+}
+
+// snapshotDimensionForStep is the tracked D of a step, nil when it is not
+// tracked or invalid.
+func snapshotDimensionForStep(series erosionSeries, step int) *float64 {
+	if step >= len(series.Dimensions) || math.IsNaN(series.Dimensions[step]) {
+		return nil
+	}
+	dimension := series.Dimensions[step]
+	return &dimension
+}
+
+func sedimentBudgetMetricsForStep(series erosionSeries, step int) *sedimentBudgetMetrics {
+	if step <= 0 || step > len(series.Sediment) {
+		return nil
+	}
+	budget := series.Sediment[step-1]
+	return &sedimentBudgetMetrics{
+		ReleasedM3:       budget.ReleasedM3,
+		ErodedM3:         budget.ErodedM3,
+		DepositedM3:      budget.DepositedM3,
+		ExportedM3:       budget.ExportedM3,
+		NetM3:            budget.NetM3,
+		GrossDriftM3:     budget.GrossDriftM3,
+		NetDriftM3:       budget.NetDriftM3,
+		AccretingPoints:  budget.AccretingPoints,
+		RetreatingPoints: budget.RetreatingPoints,
+	}
+}
+
+func scenarioYearForStep(series erosionSeries, step int) int {
+	if series.Scenario == nil {
+		return 0
+	}
+	if step == 0 || step > len(series.Timeline) {
+		return series.Scenario.StartYear
+	}
+	return series.Timeline[step-1].ToYear
+}
+
+func scenarioStepMetricsForStep(series erosionSeries, step int) *scenarioStepMetrics {
+	if series.Scenario == nil || step <= 0 || step > len(series.Timeline) {
+		return nil
+	}
+	forcing := series.Timeline[step-1]
+	return &scenarioStepMetrics{
+		FromYear:      forcing.FromYear,
+		ToYear:        forcing.ToYear,
+		BackgroundM:   forcing.BackgroundM,
+		Storms:        forcing.Storms,
+		StormM:        forcing.StormM,
+		MaxStormM:     forcing.MaxStormM,
+		SeaLevelM:     forcing.SeaLevelM,
+		SeaLevelRiseM: forcing.SeaLevelRiseM,
+		BruunM:        forcing.BruunM,
+		TotalM:        forcing.TotalM,
+	}
+}
+
+func scenarioMetricsForSeries(series erosionSeries) *scenarioMetrics {
+	sc := series.Scenario
+	if sc == nil {
+		return nil
+	}
+	metrics := &scenarioMetrics{
+		Name:               sc.Name,
+		Source:             sc.Source,
+		StartYear:          sc.StartYear,
+		EndYear:            sc.EndYear,
+		StepYears:          sc.StepYears,
+		BackgroundRetreatM: sc.BackgroundRetreatM,
+		StormReturnPeriod:  sc.Storms.ReturnPeriodYears,
+		StormRetreatM:      sc.Storms.RetreatM,
+		SeaLevelCurve:      sc.SeaLevel.Type,
+		BruunSlope:         sc.SeaLevel.BruunSlope,
+	}
+	for _, step := range series.Timeline {
+		metrics.TotalRetreatM += step.TotalM
+		metrics.TotalStorms += step.Storms
+		metrics.FinalSeaLevelM = step.SeaLevelM
+	}
+	return metrics
+}
+
+func waveClimateMetrics(climate erosion.WaveClimate) []waveDirectionMetrics {
+	if len(climate) == 0 {
+		return nil
+	}
+	out := make([]waveDirectionMetrics, 0, len(climate))
+	for _, dir := range climate {
+		out = append(out, waveDirectionMetrics{FromDeg: dir.FromDeg, Weight: dir.Weight})
+	}
+	return out
+}
+
+func lithologyMetricsForSeries(series erosionSeries) *lithologyMetrics {
+	if len(series.Rocks) == 0 || len(series.Snapshots) == 0 {
+		return nil
+	}
+	metrics := &lithologyMetrics{Name: series.Lithology.Name, Source: series.Lithology.Source}
+	for _, summary := range series.Rocks.Summaries(series.Snapshots[0]) {
+		metrics.Rocks = append(metrics.Rocks, lithologyRockMetrics{
+			Rock:       summary.Rock.Name,
+			Label:      summary.Rock.Label,
+			Resistance: summary.Rock.Resistance,
+			Points:     summary.Points,
+			LengthKM:   summary.LengthKM,
+			Share:      summary.ShareOfLen,
+		})
+	}
+	return metrics
+}
+
+func lithologyStepMetricsForStep(series erosionSeries, step int) []lithologyStepMetrics {
+	if step <= 0 || step >= len(series.Snapshots) || len(series.Rocks) == 0 {
+		return nil
+	}
+	prev, current := series.Snapshots[step-1], series.Snapshots[step]
+	if len(prev) != len(series.Rocks) || len(current) != len(series.Rocks) {
+		return nil
+	}
+
+	byRock := map[lithology.Rock]*lithologyStepMetrics{}
+	var order []lithology.Rock
+	for i, rock := range series.Rocks {
+		metrics, ok := byRock[rock]
+		if !ok {
+			metrics = &lithologyStepMetrics{Rock: rock.Name, Resistance: rock.Resistance}
+			byRock[rock] = metrics
+			order = append(order, rock)
+		}
+		displacement := fraes.Haversine(prev[i], current[i]) * 1000
+		metrics.MeanDisplacementM += displacement
+		metrics.MaxDisplacementM = max(metrics.MaxDisplacementM, displacement)
+		metrics.Points++
+	}
+
+	out := make([]lithologyStepMetrics, 0, len(order))
+	for _, rock := range order {
+		metrics := *byRock[rock]
+		metrics.MeanDisplacementM /= float64(metrics.Points)
+		out = append(out, metrics)
+	}
+	return out
+}
+
+// writeSweepMetrics saves sweep.metrics.json next to the heatmaps; empty
+// heatmap cells are null.
+func writeSweepMetrics(report sweepReport, heatmaps []sweepHeatmap, output string, ctx exportContext) error {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+
+	params := report.Header.Parameters
+	metrics := sweepArtifactMetrics{
+		GeneratedAt: nowTimestamp(),
+		Command:     canonicalCommandPath(ctx.Command),
+		Dataset:     ctx.Dataset,
+		Source:      ctx.Source,
+		Projection:  projectionMetricsFor(report.Projection),
+		OutputDir:   outputDir,
+		JournalFile: report.Journal,
+		Pipeline:    report.Header.Pipeline,
+		Sampling:    report.Header.Sampling,
+		Samples:     report.Header.Samples,
+		Seed:        report.Header.Seed,
+		Settings:    report.Header.Settings,
+		Parameters:  params,
+		Resumed:     report.Resumed,
+		Heatmaps:    make([]sweepHeatmapMetrics, 0, len(heatmaps)),
+		Sensitivity: make([]sweepSensitivityMetrics, 0, len(params)),
+		Runs:        make([]sweepRunMetrics, 0, len(report.Results)),
+	}
+	for _, heatmap := range heatmaps {
+		entry := sweepHeatmapMetrics{
+			Metric:  heatmap.Metric,
+			SVGFile: heatmap.SVGFile,
+			X:       params[heatmap.Plane.X].Name,
+			XValues: heatmap.Plane.XCenters,
+			Cells:   make([][]*float64, len(heatmap.Plane.Cells)),
+		}
+		if heatmap.Plane.Y >= 0 {
+			entry.Y = params[heatmap.Plane.Y].Name
+			entry.YValues = heatmap.Plane.YCenters
+		}
+		for row, cells := range heatmap.Plane.Cells {
+			entry.Cells[row] = make([]*float64, len(cells))
+			for col, v := range cells {
+				if !math.IsNaN(v) {
+					entry.Cells[row][col] = &v
+				}
+			}
+		}
+		metrics.Heatmaps = append(metrics.Heatmaps, entry)
+	}
+	for _, s := range sweepSensitivity(report) {
+		metrics.Sensitivity = append(metrics.Sensitivity, sweepSensitivityMetrics(s))
+	}
+	for _, result := range report.Results {
+		run := sweepRunMetrics{
+			Index:          result.Index,
+			Parameters:     make(map[string]float64, len(params)),
+			LengthKM:       result.LengthKM,
+			LengthGrowth:   result.LengthGrowth,
+			AreaKM2:        result.AreaKM2,
+			DimensionValid: result.DimensionValid,
+		}
+		for i, p := range params {
+			run.Parameters[p.Name] = result.Values[i]
+		}
+		if result.DimensionValid {
+			dimension := result.Dimension
+			run.Dimension = &dimension
+		}
+		metrics.Runs = append(metrics.Runs, run)
+	}
+
+	metricsPath := metricsPathForSeries(outputDir, "sweep")
+	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
+		return err
+	}
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
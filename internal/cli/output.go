@@ -1,11 +1,12 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/fractal"
-	"coastal-geometry/internal/domain/generators/koch"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/lithology"
+	"coastal-geometry/internal/domain/simulations/erosion"
+	"coastal-geometry/internal/domain/simulations/percolation"
 	svgrender "coastal-geometry/internal/render/svg"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
 	"math"
 	"os"
@@ -19,17 +20,19 @@ type fractalSeriesOptions struct {
 	Prefix           string
 	MetricsBaseName  string
 	Iterations       int
-	OriginalBase     []geometry.LatLon
-	ModelBase        []geometry.LatLon
-	OrganicOptions   *koch.OrganicOptions
+	OriginalBase     []fraes.LatLon
+	ModelBase        []fraes.LatLon
+	OrganicOptions   *fraes.OrganicOptions
+	FBMOptions       *fbm.Options
+	Generator        fraes.Generator
 	ErosionStrength  float64
 	ErosionSeed      int64
 	IncludeDimension bool
-	TheoryByIter     map[int]koch.TheoryCheckSample
-	Builder          func([]geometry.LatLon, int) []geometry.LatLon
+	TheoryByIter     map[int]fraes.TheoryCheckSample
+	Builder          func([]fraes.LatLon, int) []fraes.LatLon
 }
 
-func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultName string, ctx exportContext) error {
+func writeCoastlineSVG(points, renderPoints []fraes.LatLon, output, defaultName string, ctx exportContext) error {
 	filename, err := resolveOutputPath(output, defaultName, ctx.Command)
 	if err != nil {
 		return err
@@ -41,8 +44,30 @@ func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultNa
 
 	realSummary := summarizePolyline(points)
 	renderSummary := summarizePolyline(renderPoints)
-	visualHints := coastline.BuildVisualizationHints(points)
-	validationSummary := coastline.BuildValidationSummary(points)
+	geodesic := summarizeGeodesic(points, ctx.Distance)
+	visualHints := fraes.BuildVisualizationHints(points)
+	validationSummary := fraes.BuildValidationSummary(points)
+	secondaryRings := renderSecondaryRings(ctx.Coastline, ctx.Projection)
+	parts, aggregate := coastlinePartsMetrics(ctx.Coastline, ctx.Distance, ctx.Area)
+
+	meta := []string{
+		fmt.Sprintf("Точек в расчёте: %d", realSummary.PointsCount),
+		fmt.Sprintf("Точек в SVG: %d", renderSummary.PointsCount),
+		fmt.Sprintf("Длина в расчёте: %.0f км", realSummary.LengthKM),
+		fmt.Sprintf("Длина SVG-копии: %.0f км", renderSummary.LengthKM),
+	}
+	if geodesic.Method != fraes.GeodesicHaversine {
+		meta = append(meta, fmt.Sprintf("Длина на эллипсоиде %s (%s): %.1f км, %+.1f км к сфере", geodesic.Ellipsoid, geodesic.Method, geodesic.LengthKM, geodesic.DifferenceKM))
+	}
+	meta = append(meta,
+		fmt.Sprintf("Подсвечено длинных сегментов: %d", len(visualHints.LongSegments)),
+		fmt.Sprintf("Валидация: %d исправлений, %d предупреждений", len(ctx.Validation.Fixes), len(ctx.Validation.Warnings)),
+	)
+	layerLength := realSummary.LengthKM
+	if aggregate != nil && aggregate.RingCount > 1 {
+		layerLength = aggregate.LengthKM
+		meta = append(meta, fmt.Sprintf("Все кольца: %d частей, %d колец, %.0f км", aggregate.PartCount, aggregate.RingCount, aggregate.LengthKM))
+	}
 
 	if err := svgrender.DrawDocument(svgrender.Document{
 		Title:    "Береговая линия",
@@ -51,7 +76,8 @@ func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultNa
 			{
 				Label:       "Реальная исходная полилиния",
 				Points:      renderPoints,
-				LengthKM:    realSummary.LengthKM,
+				Parts:       secondaryRings,
+				LengthKM:    layerLength,
 				Stroke:      "#1f6f8b",
 				StrokeWidth: 3.5,
 				Opacity:     1,
@@ -60,14 +86,8 @@ func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultNa
 		Highlights: makeCoastlineHighlights(visualHints),
 		StatCards:  makeValidationStatCards(ctx.Validation, validationSummary),
 		Alerts:     makeCoastlineAlerts(ctx.Validation, visualHints),
-		Meta: []string{
-			fmt.Sprintf("Точек в расчёте: %d", realSummary.PointsCount),
-			fmt.Sprintf("Точек в SVG: %d", renderSummary.PointsCount),
-			fmt.Sprintf("Длина в расчёте: %.0f км", realSummary.LengthKM),
-			fmt.Sprintf("Длина SVG-копии: %.0f км", renderSummary.LengthKM),
-			fmt.Sprintf("Подсвечено длинных сегментов: %d", len(visualHints.LongSegments)),
-			fmt.Sprintf("Валидация: %d исправлений, %d предупреждений", len(ctx.Validation.Fixes), len(ctx.Validation.Warnings)),
-		},
+		Meta:       meta,
+		Projection: ctx.Projection,
 	}, filename); err != nil {
 		return err
 	}
@@ -78,12 +98,16 @@ func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultNa
 		Command:              canonicalCommandPath(ctx.Command),
 		Dataset:              ctx.Dataset,
 		Source:               ctx.Source,
+		Projection:           projectionMetricsFor(ctx.Projection),
 		SVGFile:              filename,
 		Real:                 realSummary,
+		Geodesic:             geodesic,
 		Render:               renderSummary,
 		RenderSimplification: summarizeSimplification(points, renderPoints),
 		Highlights:           coastlineHighlightsMetricsFromHints(visualHints),
 		Validation:           validationMetricsFromData(ctx.Validation, validationSummary),
+		Parts:                parts,
+		Aggregate:            aggregate,
 	}
 	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
 		return err
@@ -94,9 +118,98 @@ func writeCoastlineSVG(points, renderPoints []geometry.LatLon, output, defaultNa
 	return nil
 }
 
-func writeKochSVGSeries(originalBase, modelBase []geometry.LatLon, iterations int, output string, erosionStrength float64, erosionSeed int64, ctx exportContext) error {
-	report := koch.CheckTheoryConsistency(modelBase, iterations)
-	theoryByIter := make(map[int]koch.TheoryCheckSample, len(report.Samples))
+// renderSecondaryRings returns every ring except the main one, simplified for rendering.
+func renderSecondaryRings(coast fraes.Coastline, proj fraes.Projector) [][]fraes.LatLon {
+	rings := coast.RingPoints()
+	if len(rings) <= 1 {
+		return nil
+	}
+
+	rendered := make([][]fraes.LatLon, 0, len(rings)-1)
+	for _, ring := range rings[1:] {
+		rendered = append(rendered, fraes.SimplifyPolyline(ring, fraes.WithMaxPoints(coastlineSVGMaxPoints), fraes.WithProjection(proj)).Points)
+	}
+	return rendered
+}
+
+func writeRichardsonSVG(points, renderPoints []fraes.LatLon, analysis fraes.RichardsonAnalysis, output, defaultName string, ctx exportContext) error {
+	filename, err := resolveOutputPath(output, defaultName, ctx.Command)
+	if err != nil {
+		return err
+	}
+
+	if len(renderPoints) == 0 {
+		renderPoints = points
+	}
+
+	realSummary := summarizePolyline(points)
+	renderSummary := summarizePolyline(renderPoints)
+	validationSummary := fraes.BuildValidationSummary(points)
+
+	layers := []svgrender.Layer{
+		{
+			Label:       "Реальная исходная полилиния",
+			Points:      renderPoints,
+			LengthKM:    realSummary.LengthKM,
+			Stroke:      "#7a8b99",
+			StrokeWidth: 1.8,
+			Opacity:     0.85,
+			DashArray:   "8 6",
+		},
+	}
+	layers = append(layers, makeDividerLayers(points, analysis)...)
+
+	meta := []string{
+		fmt.Sprintf("Точек в расчёте: %d", realSummary.PointsCount),
+		fmt.Sprintf("Длина в расчёте: %.0f км", realSummary.LengthKM),
+		fmt.Sprintf("Габарит: %.0f км, масштабов: %d", analysis.ExtentKM, len(analysis.Samples)),
+	}
+	if analysis.Valid {
+		meta = append(meta,
+			fmt.Sprintf("D = 1 - наклон: %.5f, R²=%.4f", analysis.Dimension, analysis.RegressionRSquared),
+			fmt.Sprintf("Показатель Ричардсона: %.5f, стаб=%t", analysis.Exponent, analysis.StableAcrossScales),
+		)
+	} else {
+		meta = append(meta, "D: n/a")
+	}
+
+	if err := svgrender.DrawDocument(svgrender.Document{
+		Title:      "Метод Ричардсона",
+		Subtitle:   "Реальные загруженные данные: линия пройдена циркулем с уменьшающимся геодезическим шагом; цветные ломаные — обходы для отдельных шагов",
+		Layers:     layers,
+		StatCards:  makeValidationStatCards(ctx.Validation, validationSummary),
+		Charts:     makeRichardsonCharts(analysis),
+		Meta:       meta,
+		Projection: ctx.Projection,
+	}, filename); err != nil {
+		return err
+	}
+
+	metricsPath := metricsPathForSVG(filename)
+	metrics := richardsonArtifactMetrics{
+		GeneratedAt: nowTimestamp(),
+		Command:     canonicalCommandPath(ctx.Command),
+		Dataset:     ctx.Dataset,
+		Source:      ctx.Source,
+		Projection:  projectionMetricsFor(ctx.Projection),
+		SVGFile:     filename,
+		Real:        realSummary,
+		Render:      renderSummary,
+		Richardson:  richardsonMetricsFromAnalysis(analysis),
+		Validation:  validationMetricsFromData(ctx.Validation, validationSummary),
+	}
+	if err := writeMetricsJSON(metricsPath, metrics); err != nil {
+		return err
+	}
+
+	fmt.Printf("SVG saved to %s\n", filename)
+	fmt.Printf("Metrics saved to %s\n", metricsPath)
+	return nil
+}
+
+func writeKochSVGSeries(originalBase, modelBase []fraes.LatLon, iterations int, output string, erosionStrength float64, erosionSeed int64, ctx exportContext) error {
+	report := fraes.CheckTheoryConsistency(modelBase, iterations)
+	theoryByIter := make(map[int]fraes.TheoryCheckSample, len(report.Samples))
 	for _, sample := range report.Samples {
 		theoryByIter[sample.Iteration] = sample
 	}
@@ -111,13 +224,13 @@ func writeKochSVGSeries(originalBase, modelBase []geometry.LatLon, iterations in
 		ErosionStrength: erosionStrength,
 		ErosionSeed:     erosionSeed,
 		TheoryByIter:    theoryByIter,
-		Builder: func(points []geometry.LatLon, iter int) []geometry.LatLon {
-			return koch.KochCurve(points, iter)
+		Builder: func(points []fraes.LatLon, iter int) []fraes.LatLon {
+			return fraes.KochCurve(points, iter)
 		},
 	}, output, ctx)
 }
 
-func writeOrganicKochSVGSeries(originalBase, modelBase []geometry.LatLon, iterations int, output string, opts koch.OrganicOptions, erosionStrength float64, prefix, metricsBaseName string, includeDimension bool, ctx exportContext) error {
+func writeOrganicKochSVGSeries(originalBase, modelBase []fraes.LatLon, iterations int, output string, opts fraes.OrganicOptions, erosionStrength float64, prefix, metricsBaseName string, includeDimension bool, ctx exportContext) error {
 	title := "Органическая кривая Коха"
 	if includeDimension {
 		title = "Фрактальная размерность (органическая модель)"
@@ -133,13 +246,54 @@ func writeOrganicKochSVGSeries(originalBase, modelBase []geometry.LatLon, iterat
 		ErosionStrength:  erosionStrength,
 		ErosionSeed:      opts.Seed,
 		IncludeDimension: includeDimension,
-		Builder: func(points []geometry.LatLon, iter int) []geometry.LatLon {
-			return koch.OrganicKochCurve(points, iter, opts)
+		Builder: func(points []fraes.LatLon, iter int) []fraes.LatLon {
+			return fraes.OrganicKochCurve(points, iter, organicCurveOptions(opts)...)
+		},
+	}, output, ctx)
+}
+
+func writeFBMSVGSeries(originalBase, modelBase []fraes.LatLon, iterations int, output string, opts fbm.Options, erosionStrength float64, ctx exportContext) error {
+	return writeFractalSeries(fractalSeriesOptions{
+		Title:            "Фрактальное броуновское движение",
+		Prefix:           "fbm_iter",
+		MetricsBaseName:  "fbm",
+		Iterations:       iterations,
+		OriginalBase:     originalBase,
+		ModelBase:        modelBase,
+		FBMOptions:       &opts,
+		ErosionStrength:  erosionStrength,
+		ErosionSeed:      opts.Seed,
+		IncludeDimension: true,
+		Builder: func(points []fraes.LatLon, iter int) []fraes.LatLon {
+			return fbm.Curve(points, iter, opts)
 		},
 	}, output, ctx)
 }
 
-func writeErosionSVGSeries(originalBase, modelBase []geometry.LatLon, snapshots [][]geometry.LatLon, steps int, strength float64, seed int64, output string, ctx exportContext) error {
+func writeGeneratorSVGSeries(originalBase, modelBase []fraes.LatLon, iterations int, output string, gen fraes.Generator, report fraes.TheoryCheckReport, erosionStrength float64, erosionSeed int64, ctx exportContext) error {
+	theoryByIter := make(map[int]fraes.TheoryCheckSample, len(report.Samples))
+	for _, sample := range report.Samples {
+		theoryByIter[sample.Iteration] = sample
+	}
+
+	return writeFractalSeries(fractalSeriesOptions{
+		Title:            "Генератор " + gen.Name(),
+		Prefix:           gen.Name() + "_iter",
+		MetricsBaseName:  gen.Name(),
+		Iterations:       iterations,
+		OriginalBase:     originalBase,
+		ModelBase:        modelBase,
+		Generator:        gen,
+		ErosionStrength:  erosionStrength,
+		ErosionSeed:      erosionSeed,
+		IncludeDimension: true,
+		TheoryByIter:     theoryByIter,
+		Builder:          gen.Apply,
+	}, output, ctx)
+}
+
+func writeErosionSVGSeries(originalBase, modelBase []fraes.LatLon, series erosionSeries, output string, ctx exportContext) error {
+	snapshots := series.Snapshots
 	outputDir, err := resolveSeriesOutputDir(output)
 	if err != nil {
 		return err
@@ -152,32 +306,36 @@ func writeErosionSVGSeries(originalBase, modelBase []geometry.LatLon, snapshots
 		modelBase = originalBase
 	}
 
-	referenceRender := simplifyForSeriesSVG(originalBase).Points
+	referenceRender := simplifyForSeriesSVG(originalBase, ctx.Projection).Points
 	if len(referenceRender) == 0 {
 		referenceRender = originalBase
 	}
 
-	renderSnapshots := make([][]geometry.LatLon, len(snapshots))
+	renderSnapshots := make([][]fraes.LatLon, len(snapshots))
 	lengths := make([]float64, len(snapshots))
 	areas := make([]float64, len(snapshots))
 	for i, snap := range snapshots {
-		renderSnapshots[i] = simplifyForSeriesSVG(snap).Points
-		lengths[i] = geometry.PolylineLength(snap)
-		areas[i] = geometry.Area(snap)
+		renderSnapshots[i] = simplifyForSeriesSVG(snap, ctx.Projection).Points
+		lengths[i] = fraes.PolylineLength(snap)
+		areas[i] = series.areaKM2(snap)
 	}
 
 	referenceSummary := summarizePolyline(originalBase)
 	referenceRenderSummary := summarizePolyline(referenceRender)
 	modelSummary := summarizePolyline(modelBase)
 	modelSimplification := summarizeSimplification(originalBase, modelBase)
-	visualHints := coastline.BuildVisualizationHints(originalBase)
-	validationSummary := coastline.BuildValidationSummary(originalBase)
+	visualHints := fraes.BuildVisualizationHints(originalBase)
+	validationSummary := fraes.BuildValidationSummary(originalBase)
 
 	stepMetrics := make([]erosionStepMetrics, 0, len(snapshots))
+	charts := append(makeScenarioCharts(series), makeSedimentCharts(series.Sediment)...)
+	charts = append(charts, makePercolationCharts(series)...)
+	docs := make([]svgrender.Document, 0, len(snapshots))
 
 	for step := 0; step < len(snapshots); step++ {
 		filename := filepath.Join(outputDir, fmt.Sprintf("%s_%d.svg", "erosion_step", step))
 		layers := makeErosionLayers(referenceRender, referenceSummary.LengthKM, renderSnapshots, lengths, step)
+		layers = append(layers, makeLithologyLayers(snapshots[step], series.Rocks, ctx.Projection)...)
 
 		meta := []string{
 			fmt.Sprintf("Реальная линия: %.0f км, %d т.", referenceSummary.LengthKM, referenceSummary.PointsCount),
@@ -185,17 +343,59 @@ func writeErosionSVGSeries(originalBase, modelBase []geometry.LatLon, snapshots
 			fmt.Sprintf("Шаг %d: %.0f км, %d т. расчёт / %d т. SVG", step, lengths[step], len(snapshots[step]), len(renderSnapshots[step])),
 			fmt.Sprintf("Площадь: %.0f км²", areas[step]),
 		}
-		meta = append(meta, fmt.Sprintf("Эрозия: σ=%.0f м, seed=%d", strength, seed))
+		switch series.Model {
+		case erosionModelWave:
+			meta = append(meta, fmt.Sprintf("Волновая эрозия: макс. отступ %.0f м/шаг, seed=%d", series.Strength, series.Seed))
+			if step > 0 {
+				stats := series.WaveSteps[step-1]
+				meta = append(meta, fmt.Sprintf("Экспозиция: средн. %.2f, открыто %.0f%%, отступ мысов %.0f м / бухт %.0f м",
+					stats.MeanExposure, stats.ExposedShare*100, stats.HeadlandRetreatM, stats.BayRetreatM))
+			}
+		case erosionModelPercolation:
+			run := series.Percolation
+			meta = append(meta, fmt.Sprintf("Перколяционная эрозия: f0=%.2f, g=%.2f, сетка %d×%d, ячейка %.2f км, seed=%d",
+				series.PercolationOptions.Force, series.PercolationOptions.Damping, run.Columns, run.Rows, run.CellM/1000, series.Seed))
+			if step > 0 {
+				stats := run.Steps[step-1]
+				meta = append(meta, fmt.Sprintf("Проходов %d из %d, сила моря %.3f, размыто %d ячеек", stats.Sweeps, run.Sweeps, stats.Force, stats.ErodedCells))
+			}
+			if dimension := snapshotDimensionForStep(series, step); dimension != nil {
+				meta = append(meta, fmt.Sprintf("D = %.4f (ориентир 4/3 ≈ %.4f)", *dimension, percolation.HullDimension))
+			}
+		default:
+			meta = append(meta, fmt.Sprintf("Эрозия: σ=%.0f м, seed=%d", series.Strength, series.Seed))
+		}
+		if len(series.Rocks) > 0 {
+			meta = append(meta, "Литология: сдвиг ∝ 1/устойчивость породы")
+		}
+		if series.Scenario != nil && step > 0 && step <= len(series.Timeline) {
+			forcing := series.Timeline[step-1]
+			meta = append(meta, fmt.Sprintf("Сценарий %d–%d: фон %.1f м, штормов %d (%.1f м), уровень моря +%.0f см (Брюн %.1f м), итого %.1f м",
+				forcing.FromYear, forcing.ToYear, forcing.BackgroundM, forcing.Storms, forcing.StormM, forcing.SeaLevelM*100, forcing.BruunM, forcing.TotalM))
+		}
+		if step > 0 && step <= len(series.Sediment) {
+			budget := series.Sediment[step-1]
+			meta = append(meta, fmt.Sprintf("Наносы: размыв %.0f тыс. м³, отложено %.0f тыс. м³, вынос %.0f тыс. м³, нарастает %d т.",
+				budget.ErodedM3/1000, budget.DepositedM3/1000, budget.ExportedM3/1000, budget.AccretingPoints))
+		}
 
-		if err := svgrender.DrawDocument(svgrender.Document{
-			Title:     fmt.Sprintf("Эрозия — шаг %d", step),
-			Subtitle:  "Серая пунктирная линия показывает реальную загруженную береговую линию; цветные слои — результаты пошаговой эрозии",
-			Layers:    layers,
-			StatCards: makeValidationStatCards(ctx.Validation, validationSummary),
-			Meta:      meta,
-		}, filename); err != nil {
+		title := fmt.Sprintf("Эрозия — шаг %d", step)
+		if series.Scenario != nil {
+			title = fmt.Sprintf("Эрозия — %s год", stepLabel(series, step))
+		}
+		doc := svgrender.Document{
+			Title:      title,
+			Subtitle:   "Серая пунктирная линия показывает реальную загруженную береговую линию; цветные слои — результаты пошаговой эрозии",
+			Layers:     layers,
+			StatCards:  makeValidationStatCards(ctx.Validation, validationSummary),
+			Charts:     charts,
+			Meta:       meta,
+			Projection: ctx.Projection,
+		}
+		if err := svgrender.DrawDocument(doc, filename); err != nil {
 			return err
 		}
+		docs = append(docs, doc)
 
 		stepMetrics = append(stepMetrics, erosionStepMetrics{
 			Step:         step,
@@ -204,24 +404,53 @@ func writeErosionSVGSeries(originalBase, modelBase []geometry.LatLon, snapshots
 			LengthKM:     lengths[step],
 			RenderPoints: len(renderSnapshots[step]),
 			AreaKM:       areas[step],
+			Exposure:     erosionExposureMetricsForStep(series, step),
+			Lithology:    lithologyStepMetricsForStep(series, step),
+			Sediment:     sedimentBudgetMetricsForStep(series, step),
+			Year:         scenarioYearForStep(series, step),
+			Scenario:     scenarioStepMetricsForStep(series, step),
+			Percolation:  percolationStepMetricsForStep(series, step),
+			Dimension:    snapshotDimensionForStep(series, step),
 		})
 
 		fmt.Printf("SVG saved to %s\n", filename)
 	}
 
+	animationFile, err := writeSeriesAnimation(docs, outputDir, "erosion", ctx)
+	if err != nil {
+		return err
+	}
+	geometryFiles, geometryLayer, err := writeSeriesGeometry(erosionGeometryLayer(series, lengths, areas), outputDir, "erosion_step", ctx)
+	if err != nil {
+		return err
+	}
+	for i, file := range geometryFiles {
+		stepMetrics[i].GeometryFile = file
+	}
+
 	metricsPath := metricsPathForSeries(outputDir, "erosion")
 	seriesMetrics := erosionSeriesArtifactMetrics{
 		GeneratedAt:         nowTimestamp(),
 		Command:             canonicalCommandPath(ctx.Command),
 		Dataset:             ctx.Dataset,
 		Source:              ctx.Source,
+		Projection:          projectionMetricsFor(ctx.Projection),
 		OutputDir:           outputDir,
 		ReferenceCoastline:  referenceSummary,
 		ReferenceRender:     referenceRenderSummary,
 		ModelBase:           modelSummary,
 		ModelSimplification: modelSimplification,
-		ErosionStrength:     strength,
-		ErosionSeed:         seed,
+		ErosionModel:        series.Model,
+		AreaMethod:          areaMethodName(series.Area),
+		ErosionStrength:     series.Strength,
+		ErosionSeed:         series.Seed,
+		WaveClimate:         waveClimateMetrics(series.Climate),
+		Lithology:           lithologyMetricsForSeries(series),
+		SedimentRateM3:      series.SedimentRate,
+		Scenario:            scenarioMetricsForSeries(series),
+		Percolation:         percolationMetricsForSeries(series),
+		AnimationFile:       animationFile,
+		GeometryPackage:     geometryPackageMetricsFor(outputDir, geometryLayer, ctx),
 		Steps:               stepMetrics,
 		Highlights:          coastlineHighlightsMetricsFromHints(visualHints),
 		Validation:          validationMetricsFromData(ctx.Validation, validationSummary),
@@ -249,15 +478,15 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 		modelBase = originalBase
 	}
 
-	referenceRender := simplifyForSeriesSVG(originalBase).Points
+	referenceRender := simplifyForSeriesSVG(originalBase, ctx.Projection).Points
 	if len(referenceRender) == 0 {
 		referenceRender = originalBase
 	}
 
 	iterations := opts.Iterations
 
-	curves := make([][]geometry.LatLon, iterations+1)
-	renderCurves := make([][]geometry.LatLon, iterations+1)
+	curves := make([][]fraes.LatLon, iterations+1)
+	renderCurves := make([][]fraes.LatLon, iterations+1)
 	lengths := make([]float64, iterations+1)
 	dimensions := make([]*dimensionMetrics, iterations+1)
 	maxRawPoints := 0
@@ -270,10 +499,10 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 			if seed == 0 {
 				seed = time.Now().UnixNano()
 			}
-			curves[iter] = geometry.ErodeWithSeed(curves[iter], opts.ErosionStrength, seed+int64(iter))
+			curves[iter] = fraes.Erode(curves[iter], opts.ErosionStrength, seed+int64(iter), ctx.Projection)
 		}
-		renderCurves[iter] = simplifyForSeriesSVG(curves[iter]).Points
-		lengths[iter] = geometry.PolylineLength(curves[iter])
+		renderCurves[iter] = simplifyForSeriesSVG(curves[iter], ctx.Projection).Points
+		lengths[iter] = fraes.PolylineLength(curves[iter])
 		if len(curves[iter]) > maxRawPoints {
 			maxRawPoints = len(curves[iter])
 		}
@@ -281,7 +510,9 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 			maxRenderPoints = len(renderCurves[iter])
 		}
 		if opts.IncludeDimension {
-			dimensions[iter] = dimensionMetricsFromAnalysis(fractal.AnalyzeBoxCounting(curves[iter]))
+			analysis := fraes.AnalyzeBoxCountingWith(curves[iter], ctx.Projection)
+			interval := fraes.BootstrapBoxCountingWith(curves[iter], ctx.Projection, analysis, ctx.Bootstrap, bootstrapSeed(opts, iter))
+			dimensions[iter] = dimensionMetricsFromAnalysis(analysis, interval)
 		}
 	}
 
@@ -293,14 +524,15 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 	referenceRenderSummary := summarizePolyline(referenceRender)
 	modelSummary := summarizePolyline(modelBase)
 	modelSimplification := summarizeSimplification(originalBase, modelBase)
-	visualHints := coastline.BuildVisualizationHints(originalBase)
-	validationSummary := coastline.BuildValidationSummary(originalBase)
+	visualHints := fraes.BuildVisualizationHints(originalBase)
+	validationSummary := fraes.BuildValidationSummary(originalBase)
 
 	iterationsMetrics := make([]fractalIterationMetrics, 0, iterations+1)
+	docs := make([]svgrender.Document, 0, iterations+1)
 	for iter := 0; iter <= iterations; iter++ {
 		filename := filepath.Join(outputDir, fmt.Sprintf("%s_%d.svg", opts.Prefix, iter))
 		layers := makeFractalLayers(referenceRender, referenceSummary.LengthKM, renderCurves[:iter+1], lengths[:iter+1])
-		charts := makeSeriesCharts(iter, lengths[:iter+1], dimensions[:iter+1], opts.TheoryByIter)
+		charts := makeSeriesCharts(iter, lengths[:iter+1], dimensions[:iter+1], opts.TheoryByIter, opts.theoryDimension())
 		meta := []string{
 			fmt.Sprintf("Реальная линия: %.0f км, %d т.", referenceSummary.LengthKM, referenceSummary.PointsCount),
 			fmt.Sprintf("База модели: %.0f км, %d т. (%+.1f%% к реальной)", modelSummary.LengthKM, modelSummary.PointsCount, modelSimplification.LengthDeltaPercent),
@@ -309,10 +541,20 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 		if dimension := dimensions[iter]; dimension != nil {
 			if dimension.Valid {
 				meta = append(meta, fmt.Sprintf("D: %.5f, R²=%.4f, стаб=%t", dimension.Dimension, dimension.RegressionRSquared, dimension.StableAcrossScales))
+				if opts.FBMOptions != nil {
+					meta = append(meta, fmt.Sprintf("Цель 2 − H: %.3f, отклонение %+.4f", opts.theoryDimension(), dimension.Dimension-opts.theoryDimension()))
+				}
+				if opts.Generator != nil {
+					meta = append(meta, fmt.Sprintf("Теоретическая D: %.4f, отклонение %+.4f", opts.theoryDimension(), dimension.Dimension-opts.theoryDimension()))
+				}
+				if ci := dimension.ConfidenceInterval; ci != nil {
+					meta = append(meta, fmt.Sprintf("95%% ДИ D: [%.4f, %.4f], СО=%.4f", ci.Lower, ci.Upper, ci.StdError))
+				}
 			} else {
 				meta = append(meta, fmt.Sprintf("D: n/a, масштабов=%d", dimension.SampleCount))
 			}
-		} else if theory, ok := opts.TheoryByIter[iter]; ok {
+		}
+		if theory, ok := opts.TheoryByIter[iter]; ok {
 			meta = append(meta, fmt.Sprintf("Теория: %.0f км, ошибка %.2f%%", theory.TheoreticalKM, theory.ErrorPercent))
 		}
 		if opts.ErosionStrength > 0 {
@@ -324,17 +566,29 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 			subtitle = fmt.Sprintf("Органическая модель: seed=%d, угол ±%.0f°, высота ±%.0f%%; серая пунктирная линия — реальная линия, цветные слои — от упрощённой базы",
 				opts.OrganicOptions.Seed, opts.OrganicOptions.AngleJitterDeg, opts.OrganicOptions.HeightJitterPct*100)
 		}
+		if opts.FBMOptions != nil {
+			subtitle = fmt.Sprintf("fBm вдоль нормалей сегментов: seed=%d, H=%.2f, амплитуда %.0f%% сегмента; серая пунктирная линия — реальная линия, цветные слои — от упрощённой базы",
+				opts.FBMOptions.Seed, opts.FBMOptions.Hurst, opts.FBMOptions.Amplitude*100)
+		}
+
+		if opts.Generator != nil {
+			subtitle = fmt.Sprintf("Детерминированный генератор: %d сегментов мотива, длина ×%.4f за итерацию; серая пунктирная линия — реальная линия, цветные слои — от упрощённой базы",
+				opts.Generator.Segments(), opts.Generator.TheoreticalLengthFactor())
+		}
 
-		if err := svgrender.DrawDocument(svgrender.Document{
-			Title:     fmt.Sprintf("%s — итерация %d", opts.Title, iter),
-			Subtitle:  subtitle,
-			Layers:    layers,
-			StatCards: makeValidationStatCards(ctx.Validation, validationSummary),
-			Charts:    charts,
-			Meta:      meta,
-		}, filename); err != nil {
+		doc := svgrender.Document{
+			Title:      fmt.Sprintf("%s — итерация %d", opts.Title, iter),
+			Subtitle:   subtitle,
+			Layers:     layers,
+			StatCards:  makeValidationStatCards(ctx.Validation, validationSummary),
+			Charts:     charts,
+			Meta:       meta,
+			Projection: ctx.Projection,
+		}
+		if err := svgrender.DrawDocument(doc, filename); err != nil {
 			return err
 		}
+		docs = append(docs, doc)
 
 		iterationMetrics := fractalIterationMetrics{
 			Iteration:           iter,
@@ -358,12 +612,25 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 		fmt.Printf("SVG saved to %s\n", filename)
 	}
 
+	animationFile, err := writeSeriesAnimation(docs, outputDir, opts.MetricsBaseName, ctx)
+	if err != nil {
+		return err
+	}
+	geometryFiles, geometryLayer, err := writeSeriesGeometry(fractalGeometryLayer(opts, curves, lengths, dimensions), outputDir, opts.Prefix, ctx)
+	if err != nil {
+		return err
+	}
+	for i, file := range geometryFiles {
+		iterationsMetrics[i].GeometryFile = file
+	}
+
 	metricsPath := metricsPathForSeries(outputDir, opts.MetricsBaseName)
 	seriesMetrics := fractalSeriesArtifactMetrics{
 		GeneratedAt:         nowTimestamp(),
 		Command:             canonicalCommandPath(ctx.Command),
 		Dataset:             ctx.Dataset,
 		Source:              ctx.Source,
+		Projection:          projectionMetricsFor(ctx.Projection),
 		Title:               opts.Title,
 		OutputDir:           outputDir,
 		ReferenceCoastline:  referenceSummary,
@@ -372,6 +639,8 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 		ModelSimplification: modelSimplification,
 		ErosionStrength:     opts.ErosionStrength,
 		ErosionSeed:         opts.ErosionSeed,
+		AnimationFile:       animationFile,
+		GeometryPackage:     geometryPackageMetricsFor(outputDir, geometryLayer, ctx),
 		Iterations:          iterationsMetrics,
 		Highlights:          coastlineHighlightsMetricsFromHints(visualHints),
 		Validation:          validationMetricsFromData(ctx.Validation, validationSummary),
@@ -383,6 +652,22 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 			HeightJitterPct: opts.OrganicOptions.HeightJitterPct,
 		}
 	}
+	if opts.FBMOptions != nil {
+		seriesMetrics.FBMOptions = &fbmOptionsMetrics{
+			Seed:            opts.FBMOptions.Seed,
+			Hurst:           opts.FBMOptions.Hurst,
+			Amplitude:       opts.FBMOptions.Amplitude,
+			TargetDimension: opts.theoryDimension(),
+		}
+	}
+	if opts.Generator != nil {
+		seriesMetrics.Generator = &generatorMetrics{
+			Name:                    opts.Generator.Name(),
+			Segments:                opts.Generator.Segments(),
+			TheoreticalDimension:    opts.Generator.TheoreticalDimension(),
+			TheoreticalLengthFactor: opts.Generator.TheoreticalLengthFactor(),
+		}
+	}
 	if err := writeMetricsJSON(metricsPath, seriesMetrics); err != nil {
 		return err
 	}
@@ -391,7 +676,35 @@ func writeFractalSeries(opts fractalSeriesOptions, output string, ctx exportCont
 	return nil
 }
 
-func makeFractalLayers(reference []geometry.LatLon, referenceLength float64, curves [][]geometry.LatLon, lengths []float64) []svgrender.Layer {
+// theoryDimension is the dimension the series converges to: 2 − H for fBm,
+// the similarity dimension of a generator and log 4 / log 3 for the Koch
+// curves.
+func (opts fractalSeriesOptions) theoryDimension() float64 {
+	switch {
+	case opts.FBMOptions != nil:
+		return fbm.TargetDimension(opts.FBMOptions.Hurst)
+	case opts.Generator != nil:
+		return opts.Generator.TheoreticalDimension()
+	default:
+		return math.Log(4) / math.Log(3)
+	}
+}
+
+// writeSeriesAnimation assembles the frames of a series into
+// <baseName>.gif when --animate is set and returns the file name.
+func writeSeriesAnimation(docs []svgrender.Document, outputDir, baseName string, ctx exportContext) (string, error) {
+	if !ctx.Animate || len(docs) == 0 {
+		return "", nil
+	}
+	filename := filepath.Join(outputDir, baseName+".gif")
+	if err := svgrender.DrawAnimation(docs, filename, svgrender.AnimationOptions{}); err != nil {
+		return "", err
+	}
+	fmt.Printf("GIF saved to %s\n", filename)
+	return filename, nil
+}
+
+func makeFractalLayers(reference []fraes.LatLon, referenceLength float64, curves [][]fraes.LatLon, lengths []float64) []svgrender.Layer {
 	palette := []string{
 		"#1f6f8b",
 		"#2c7a7b",
@@ -443,7 +756,7 @@ func makeFractalLayers(reference []geometry.LatLon, referenceLength float64, cur
 	return layers
 }
 
-func makeErosionLayers(reference []geometry.LatLon, referenceLength float64, snapshots [][]geometry.LatLon, lengths []float64, current int) []svgrender.Layer {
+func makeErosionLayers(reference []fraes.LatLon, referenceLength float64, snapshots [][]fraes.LatLon, lengths []float64, current int) []svgrender.Layer {
 	palette := []string{
 		"#1f6f8b",
 		"#c06c3f",
@@ -484,6 +797,122 @@ func makeErosionLayers(reference []geometry.LatLon, referenceLength float64, sna
 	return layers
 }
 
+// makeLithologyLayers draws one layer per rock type over the current step,
+// each made of the runs of consecutive segments of that rock.
+func makeLithologyLayers(points []fraes.LatLon, rocks lithology.Assignment, proj fraes.Projector) []svgrender.Layer {
+	runs := rocks.Runs(points)
+	if len(runs) == 0 {
+		return nil
+	}
+
+	var layers []svgrender.Layer
+	index := map[lithology.Rock]int{}
+	for _, run := range runs {
+		budget := max(seriesSVGMaxPoints*len(run.Points)/len(points), 2)
+		rendered := fraes.SimplifyPolyline(run.Points, fraes.WithMaxPoints(budget), fraes.WithProjection(proj)).Points
+
+		i, ok := index[run.Rock]
+		if !ok {
+			i = len(layers)
+			index[run.Rock] = i
+			layers = append(layers, svgrender.Layer{
+				Label:       fmt.Sprintf("Литология: %s (устойчивость %.2g)", run.Rock.Label, run.Rock.Resistance),
+				Stroke:      run.Rock.Color,
+				StrokeWidth: 3.2,
+				Opacity:     0.9,
+			})
+		}
+		layers[i].Parts = append(layers[i].Parts, rendered)
+		layers[i].LengthKM += fraes.PolylineLength(run.Points)
+	}
+	return layers
+}
+
+// makeDividerLayers draws the coarsest, middle and finest divider walks of the fitted window.
+func makeDividerLayers(points []fraes.LatLon, analysis fraes.RichardsonAnalysis) []svgrender.Layer {
+	if len(analysis.Samples) == 0 {
+		return nil
+	}
+
+	start, end := analysis.WindowStart, analysis.WindowEnd
+	if analysis.RegressionRSquared == 0 {
+		start, end = 0, len(analysis.Samples)-1
+	}
+	indexes := []int{start, (start + end) / 2, end}
+	palette := []string{"#c06c3f", "#2c7a7b", "#1f6f8b"}
+
+	layers := make([]svgrender.Layer, 0, len(indexes))
+	for i, index := range indexes {
+		if i > 0 && index == indexes[i-1] {
+			continue
+		}
+		sample := analysis.Samples[index]
+		walk := fraes.DividerWalk(points, sample.RulerKM)
+		layers = append(layers, svgrender.Layer{
+			Label:       fmt.Sprintf("Циркуль ε=%.1f км", sample.RulerKM),
+			Points:      walk,
+			LengthKM:    sample.LengthKM,
+			Stroke:      palette[i%len(palette)],
+			StrokeWidth: 2.2 + float64(i)*0.6,
+			Opacity:     0.55 + float64(i)*0.2,
+		})
+	}
+	return layers
+}
+
+func makeRichardsonCharts(analysis fraes.RichardsonAnalysis) []svgrender.Chart {
+	if len(analysis.Samples) == 0 {
+		return nil
+	}
+
+	measured := make([]float64, len(analysis.Samples))
+	fit := make([]float64, len(analysis.Samples))
+	for i, sample := range analysis.Samples {
+		measured[i] = sample.LogLength
+		fit[i] = math.NaN()
+		if analysis.RegressionRSquared > 0 && i >= analysis.WindowStart && i <= analysis.WindowEnd {
+			fit[i] = math.Log(analysis.Prefactor) - analysis.Exponent*sample.LogInvRuler
+		}
+	}
+
+	first := analysis.Samples[0]
+	last := analysis.Samples[len(analysis.Samples)-1]
+	charts := []svgrender.Chart{
+		{
+			Title: "log L от log(1/ε)",
+			Series: []svgrender.ChartSeries{
+				{
+					Label:  "Измерено",
+					Values: measured,
+					Stroke: "#1f6f8b",
+				},
+				{
+					Label:     "Регрессия",
+					Values:    fit,
+					Stroke:    "#c06c3f",
+					DashArray: "5 4",
+				},
+			},
+			XMinLabel: fmt.Sprintf("ε=%.1f км", first.RulerKM),
+			XMaxLabel: fmt.Sprintf("ε=%.2f км", last.RulerKM),
+		},
+	}
+
+	if len(analysis.LocalDimensions) > 0 {
+		charts = append(charts, svgrender.Chart{
+			Title: "Локальная размерность D",
+			Series: []svgrender.ChartSeries{
+				{
+					Label:  "Оценка",
+					Values: append([]float64(nil), analysis.LocalDimensions...),
+					Stroke: "#8b3f5c",
+				},
+			},
+		})
+	}
+	return charts
+}
+
 func safeRatio(value, base float64) float64 {
 	if base == 0 {
 		return 0
@@ -491,7 +920,7 @@ func safeRatio(value, base float64) float64 {
 	return value / base
 }
 
-func makeCoastlineHighlights(hints coastline.VisualizationHints) []svgrender.HighlightSegment {
+func makeCoastlineHighlights(hints fraes.VisualizationHints) []svgrender.HighlightSegment {
 	highlights := make([]svgrender.HighlightSegment, 0, len(hints.LongSegments))
 	for _, segment := range hints.LongSegments {
 		highlights = append(highlights, svgrender.HighlightSegment{
@@ -505,7 +934,7 @@ func makeCoastlineHighlights(hints coastline.VisualizationHints) []svgrender.Hig
 	return highlights
 }
 
-func makeCoastlineAlerts(report coastline.ValidationReport, hints coastline.VisualizationHints) []string {
+func makeCoastlineAlerts(report fraes.ValidationReport, hints fraes.VisualizationHints) []string {
 	alerts := make([]string, 0, len(report.Warnings)+2)
 	if len(hints.LongSegments) > 0 {
 		alerts = append(alerts, fmt.Sprintf("Длинные сегменты > 450 км: %d", len(hints.LongSegments)))
@@ -534,9 +963,9 @@ func makeCoastlineAlerts(report coastline.ValidationReport, hints coastline.Visu
 	return alerts
 }
 
-func makeValidationStatCards(report coastline.ValidationReport, summary coastline.ValidationSummary) []svgrender.StatCard {
-	longSegments, threshold := validationIssueCount(summary, coastline.WarningTypeLongSegment)
-	duplicateLocations, _ := validationIssueCount(summary, coastline.WarningTypeDuplicateLocation)
+func makeValidationStatCards(report fraes.ValidationReport, summary fraes.ValidationSummary) []svgrender.StatCard {
+	longSegments, threshold := validationIssueCount(summary, fraes.WarningTypeLongSegment)
+	duplicateLocations, _ := validationIssueCount(summary, fraes.WarningTypeDuplicateLocation)
 
 	return []svgrender.StatCard{
 		{
@@ -562,7 +991,7 @@ func makeValidationStatCards(report coastline.ValidationReport, summary coastlin
 	}
 }
 
-func validationIssueCount(summary coastline.ValidationSummary, warningType string) (int, float64) {
+func validationIssueCount(summary fraes.ValidationSummary, warningType string) (int, float64) {
 	for _, issue := range summary.Issues {
 		if issue.WarningType == warningType {
 			return issue.Count, issue.ThresholdKM
@@ -585,12 +1014,12 @@ func fixStatTone(count int) string {
 	return "#3f6b4b"
 }
 
-func makeSeriesCharts(currentIter int, lengths []float64, dimensions []*dimensionMetrics, theoryByIter map[int]koch.TheoryCheckSample) []svgrender.Chart {
+func makeSeriesCharts(currentIter int, lengths []float64, dimensions []*dimensionMetrics, theoryByIter map[int]fraes.TheoryCheckSample, theoryDimension float64) []svgrender.Chart {
 	charts := []svgrender.Chart{
 		buildLengthChart(lengths, theoryByIter),
 	}
 
-	dimensionChart := buildDimensionChart(dimensions)
+	dimensionChart := buildDimensionChart(dimensions, theoryDimension)
 	if len(dimensionChart.Series) > 0 {
 		charts = append(charts, dimensionChart)
 	}
@@ -601,7 +1030,109 @@ func makeSeriesCharts(currentIter int, lengths []float64, dimensions []*dimensio
 	return charts
 }
 
-func buildLengthChart(lengths []float64, theoryByIter map[int]koch.TheoryCheckSample) svgrender.Chart {
+// makeScenarioCharts plots the retreat components of every scenario step
+// against calendar years.
+func makeScenarioCharts(series erosionSeries) []svgrender.Chart {
+	if series.Scenario == nil || len(series.Timeline) == 0 {
+		return nil
+	}
+
+	background := []float64{math.NaN()}
+	storms := []float64{math.NaN()}
+	bruun := []float64{math.NaN()}
+	total := []float64{math.NaN()}
+	for _, step := range series.Timeline {
+		background = append(background, step.BackgroundM)
+		storms = append(storms, step.StormM)
+		bruun = append(bruun, step.BruunM)
+		total = append(total, step.TotalM)
+	}
+
+	return []svgrender.Chart{
+		{
+			Title: "Отступ берега за шаг, м",
+			Series: []svgrender.ChartSeries{
+				{Label: "Итого", Values: total, Stroke: "#444444"},
+				{Label: "Фон", Values: background, Stroke: "#1f6f8b", DashArray: "5 4"},
+				{Label: "Штормы", Values: storms, Stroke: "#b5651d"},
+				{Label: "Брюн", Values: bruun, Stroke: "#c06c3f", DashArray: "2 3"},
+			},
+			XMinLabel: fmt.Sprintf("%d", series.Scenario.StartYear),
+			XMaxLabel: fmt.Sprintf("%d", series.Timeline[len(series.Timeline)-1].ToYear),
+		},
+	}
+}
+
+// makePercolationCharts plots the box-counting D of every step against the
+// 4/3 of the percolation hull and the damped sea force.
+func makePercolationCharts(series erosionSeries) []svgrender.Chart {
+	if series.Percolation == nil || len(series.Dimensions) == 0 {
+		return nil
+	}
+
+	hull := make([]float64, len(series.Dimensions))
+	force := []float64{series.PercolationOptions.Force}
+	for i := range hull {
+		hull[i] = percolation.HullDimension
+	}
+	for _, stats := range series.Percolation.Steps {
+		force = append(force, stats.Force)
+	}
+
+	return []svgrender.Chart{
+		{
+			Title: "Размерность D по шагам",
+			Series: []svgrender.ChartSeries{
+				{Label: "Box-counting", Values: append([]float64(nil), series.Dimensions...), Stroke: "#1f6f8b"},
+				{Label: "4/3", Values: hull, Stroke: "#b5651d", DashArray: "5 4"},
+			},
+		},
+		{
+			Title: "Сила моря",
+			Series: []svgrender.ChartSeries{
+				{Label: "f", Values: force, Stroke: "#444444"},
+			},
+		},
+	}
+}
+
+// makeSedimentCharts plots the per-step sediment budget in thousands of m³;
+// step 0 has no transport and is left empty.
+func makeSedimentCharts(budgets []erosion.SedimentBudget) []svgrender.Chart {
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	eroded := []float64{math.NaN()}
+	deposited := []float64{math.NaN()}
+	net := []float64{math.NaN()}
+	drift := []float64{math.NaN()}
+	for _, budget := range budgets {
+		eroded = append(eroded, budget.ErodedM3/1000)
+		deposited = append(deposited, budget.DepositedM3/1000)
+		net = append(net, budget.NetM3/1000)
+		drift = append(drift, budget.GrossDriftM3/1000)
+	}
+
+	return []svgrender.Chart{
+		{
+			Title: "Баланс наносов, тыс. м³",
+			Series: []svgrender.ChartSeries{
+				{Label: "Размыв", Values: eroded, Stroke: "#b5651d"},
+				{Label: "Отложено", Values: deposited, Stroke: "#1f6f8b"},
+				{Label: "Итог", Values: net, Stroke: "#444444", DashArray: "5 4"},
+			},
+		},
+		{
+			Title: "Вдольбереговой дрейф, тыс. м³",
+			Series: []svgrender.ChartSeries{
+				{Label: "Валовый", Values: drift, Stroke: "#c06c3f"},
+			},
+		},
+	}
+}
+
+func buildLengthChart(lengths []float64, theoryByIter map[int]fraes.TheoryCheckSample) svgrender.Chart {
 	chart := svgrender.Chart{
 		Title: "Длина по итерациям",
 		Series: []svgrender.ChartSeries{
@@ -633,35 +1164,63 @@ func buildLengthChart(lengths []float64, theoryByIter map[int]koch.TheoryCheckSa
 	return chart
 }
 
-func buildDimensionChart(dimensions []*dimensionMetrics) svgrender.Chart {
+// bootstrapSeed gives the series the same bootstrap replicates as the
+// dimension table of the same organic run.
+func bootstrapSeed(opts fractalSeriesOptions, iter int) int64 {
+	switch {
+	case opts.OrganicOptions != nil:
+		return dimensionBootstrapSeed(opts.OrganicOptions.Seed, iter)
+	case opts.FBMOptions != nil:
+		return dimensionBootstrapSeed(opts.FBMOptions.Seed, iter)
+	default:
+		return dimensionBootstrapSeed(opts.ErosionSeed, iter)
+	}
+}
+
+// buildDimensionChart plots D with its bootstrap 95% interval as a band
+// against the theoretical dimension.
+func buildDimensionChart(dimensions []*dimensionMetrics, theoreticalDimension float64) svgrender.Chart {
 	values := make([]float64, len(dimensions))
+	lower := make([]float64, len(dimensions))
+	upper := make([]float64, len(dimensions))
 	hasValues := false
+	hasInterval := false
 	for i, dimension := range dimensions {
+		values[i], lower[i], upper[i] = math.NaN(), math.NaN(), math.NaN()
 		if dimension == nil || !dimension.Valid {
-			values[i] = math.NaN()
 			continue
 		}
 		values[i] = dimension.Dimension
 		hasValues = true
+		if ci := dimension.ConfidenceInterval; ci != nil {
+			lower[i], upper[i] = ci.Lower, ci.Upper
+			hasInterval = true
+		}
 	}
 	if !hasValues {
 		return svgrender.Chart{}
 	}
 
 	theory := make([]float64, len(values))
-	theoreticalDimension := math.Log(4) / math.Log(3)
 	for i := range theory {
 		theory[i] = theoreticalDimension
 	}
 
+	estimate := svgrender.ChartSeries{
+		Label:  "Оценка",
+		Values: values,
+		Stroke: "#8b3f5c",
+	}
+	if hasInterval {
+		estimate.Label = "Оценка ± ДИ"
+		estimate.Lower = lower
+		estimate.Upper = upper
+	}
+
 	return svgrender.Chart{
 		Title: "Размерность D",
 		Series: []svgrender.ChartSeries{
-			{
-				Label:  "Оценка",
-				Values: values,
-				Stroke: "#8b3f5c",
-			},
+			estimate,
 			{
 				Label:     "Теория",
 				Values:    theory,
@@ -1,10 +1,14 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
-	"coastal-geometry/internal/domain/generators/koch"
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/simulations/scenario"
+	"coastal-geometry/internal/domain/simulations/sweep"
+	"coastal-geometry/pkg/fraes"
 	"encoding/json"
+	"errors"
+	"image/gif"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,12 +17,12 @@ import (
 
 func TestWriteCoastlineSVGCreatesMetricsSidecar(t *testing.T) {
 	dir := t.TempDir()
-	points := []geometry.LatLon{
+	points := []fraes.LatLon{
 		{Lat: 46.48, Lon: 30.73},
 		{Lat: 46.49, Lon: 30.74},
 		{Lat: 0, Lon: 5},
 	}
-	renderPoints := []geometry.LatLon{
+	renderPoints := []fraes.LatLon{
 		{Lat: 46.48, Lon: 30.73},
 		{Lat: 0, Lon: 5},
 	}
@@ -27,7 +31,7 @@ func TestWriteCoastlineSVGCreatesMetricsSidecar(t *testing.T) {
 		Command: cmdCoastline,
 		Dataset: "test.json",
 		Source:  "unit-test",
-		Validation: coastline.ValidationReport{
+		Validation: fraes.ValidationReport{
 			Fixes:    []string{"normalized"},
 			Warnings: []string{"long segment", "duplicate location"},
 		},
@@ -89,12 +93,12 @@ func TestWriteCoastlineSVGCreatesMetricsSidecar(t *testing.T) {
 
 func TestWriteKochSVGSeriesShowsReferenceAndModelBase(t *testing.T) {
 	dir := t.TempDir()
-	originalBase := []geometry.LatLon{
+	originalBase := []fraes.LatLon{
 		{Lat: 0, Lon: 0},
 		{Lat: 0.03, Lon: 0.10},
 		{Lat: 0, Lon: 0.20},
 	}
-	modelBase := []geometry.LatLon{
+	modelBase := []fraes.LatLon{
 		{Lat: 0, Lon: 0},
 		{Lat: 0, Lon: 0.20},
 	}
@@ -157,15 +161,137 @@ func TestWriteKochSVGSeriesShowsReferenceAndModelBase(t *testing.T) {
 	}
 }
 
+func TestWriteKochSVGSeriesAnimatesFrames(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 0.20},
+	}
+
+	err := writeKochSVGSeries(base, base, 2, dir, 0, 0, exportContext{Command: cmdKoch, Animate: true})
+	if err != nil {
+		t.Fatalf("writeKochSVGSeries returned error: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "koch.gif"))
+	if err != nil {
+		t.Fatalf("expected series gif: %v", err)
+	}
+	defer file.Close()
+	anim, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("invalid gif: %v", err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("expected one frame per iteration, got %d", len(anim.Image))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "koch.metrics.json"))
+	if err != nil {
+		t.Fatalf("read series metrics: %v", err)
+	}
+	var metrics fractalSeriesArtifactMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("unmarshal series metrics: %v", err)
+	}
+	if metrics.AnimationFile != filepath.Join(dir, "koch.gif") {
+		t.Fatalf("expected animation file in metrics, got %q", metrics.AnimationFile)
+	}
+}
+
+func TestWriteKochSVGSeriesExportsGeoJSONPerIteration(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{{Lat: 44, Lon: 30}, {Lat: 44, Lon: 30.2}}
+
+	ctx := exportContext{Command: cmdKoch, Geometry: newGeometryExport(geometryGeoJSON)}
+	if err := writeKochSVGSeries(base, base, 2, dir, 0, 0, ctx); err != nil {
+		t.Fatalf("writeKochSVGSeries returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "koch_iter_2.geojson"))
+	if err != nil {
+		t.Fatalf("expected GeoJSON next to the SVG: %v", err)
+	}
+	var collection struct {
+		Name     string
+		Features []struct {
+			Properties map[string]any
+			Geometry   struct{ Coordinates [][2]float64 }
+		}
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatalf("invalid GeoJSON: %v", err)
+	}
+	if collection.Name != "koch" || len(collection.Features) != 1 {
+		t.Fatalf("expected one koch feature, got %+v", collection)
+	}
+	feature := collection.Features[0]
+	if feature.Properties["iteration"] != 2.0 || feature.Properties["seed"] != nil || len(feature.Geometry.Coordinates) != 17 {
+		t.Fatalf("unexpected iteration feature %v with %d points", feature.Properties, len(feature.Geometry.Coordinates))
+	}
+
+	var metrics fractalSeriesArtifactMetrics
+	data, err = os.ReadFile(filepath.Join(dir, "koch.metrics.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Iterations[2].GeometryFile != filepath.Join(dir, "koch_iter_2.geojson") || metrics.GeometryPackage != nil {
+		t.Fatalf("expected geometry file in iteration metrics, got %+v", metrics.Iterations[2])
+	}
+}
+
+func TestOrganicSeriesShareOneGeoPackage(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{{Lat: 44, Lon: 30}, {Lat: 44.03, Lon: 30.1}, {Lat: 44, Lon: 30.2}}
+	opts := fraes.OrganicOptions{Seed: 7, AngleJitterDeg: 10, HeightJitterPct: 0.2}
+
+	ctx := exportContext{Command: cmdKochOrganic, Geometry: newGeometryExport(geometryGPKG)}
+	if err := writeOrganicKochSVGSeries(base, base, 1, dir, opts, 0, "koch_iter", "koch-organic", false, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOrganicKochSVGSeries(base, base, 1, dir, opts, 0, "dimension_iter", "dimension-organic", true, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeGeometryPackage(ctx); err != nil {
+		t.Fatalf("writeGeometryPackage returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "koch-organic.gpkg"))
+	if err != nil {
+		t.Fatalf("expected one GeoPackage for the run: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "SQLite format 3") || !strings.Contains(string(data), "dimension_organic") {
+		t.Fatal("expected a SQLite file with the dimension layer")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "koch_iter_0.geojson")); !os.IsNotExist(err) {
+		t.Fatalf("expected no GeoJSON files with gpkg export, got %v", err)
+	}
+
+	var metrics fractalSeriesArtifactMetrics
+	data, err = os.ReadFile(filepath.Join(dir, "dimension-organic.metrics.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.GeometryPackage == nil || metrics.GeometryPackage.Layer != "dimension-organic" || filepath.Base(metrics.GeometryPackage.File) != "koch-organic.gpkg" {
+		t.Fatalf("expected the GeoPackage layer in series metrics, got %+v", metrics.GeometryPackage)
+	}
+}
+
 func TestWriteOrganicKochSVGSeriesPersistsDimensionMetrics(t *testing.T) {
 	dir := t.TempDir()
-	base := []geometry.LatLon{
+	base := []fraes.LatLon{
 		{Lat: 0, Lon: 0},
 		{Lat: 0.03, Lon: 0.10},
 		{Lat: 0, Lon: 0.20},
 	}
 
-	err := writeOrganicKochSVGSeries(base, base, 1, dir, koch.OrganicOptions{Seed: 7}, 0, "dimension_iter", "dimension", true, exportContext{
+	err := writeOrganicKochSVGSeries(base, base, 1, dir, fraes.OrganicOptions{Seed: 7}, 0, "dimension_iter", "dimension", true, exportContext{
 		Command: cmdDimension,
 		Dataset: "test.json",
 		Source:  "unit-test",
@@ -206,3 +332,295 @@ func TestWriteOrganicKochSVGSeriesPersistsDimensionMetrics(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteFBMSVGSeriesComparesDimensionWithTarget(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 44.4, Lon: 31.6}}
+	opts := fbm.Options{Seed: 7, Hurst: 0.5, Amplitude: 0.5}
+
+	if err := writeFBMSVGSeries(base, base, 8, dir, opts, 0, exportContext{Command: cmdFBM, Dataset: "test.json"}); err != nil {
+		t.Fatalf("writeFBMSVGSeries returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "fbm.metrics.json"))
+	if err != nil {
+		t.Fatalf("read fbm metrics: %v", err)
+	}
+	var metrics fractalSeriesArtifactMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("unmarshal fbm metrics: %v", err)
+	}
+	if metrics.FBMOptions == nil || metrics.FBMOptions.TargetDimension != 1.5 || metrics.Command != "model fbm" {
+		t.Fatalf("expected fbm options with the target 1.5, got %+v", metrics.FBMOptions)
+	}
+	last := metrics.Iterations[len(metrics.Iterations)-1].Dimension
+	if last == nil || !last.Valid || math.Abs(last.Dimension-1.5) > 0.2 {
+		t.Fatalf("expected the last iteration near the target dimension, got %+v", last)
+	}
+
+	svg, err := os.ReadFile(filepath.Join(dir, "fbm_iter_8.svg"))
+	if err != nil {
+		t.Fatalf("read fbm svg: %v", err)
+	}
+	if !strings.Contains(string(svg), "Цель 2 − H: 1.500") {
+		t.Fatal("expected the SVG to compare D with the target")
+	}
+}
+
+func TestBuildDimensionChartDrawsConfidenceBand(t *testing.T) {
+	chart := buildDimensionChart([]*dimensionMetrics{
+		nil,
+		{Valid: true, Dimension: 1.24, ConfidenceInterval: &confidenceIntervalMetrics{Level: 0.95, Lower: 1.21, Upper: 1.28, StdError: 0.018, Replicates: 100}},
+		{Valid: true, Dimension: 1.26},
+	}, math.Log(4)/math.Log(3))
+	if len(chart.Series) == 0 {
+		t.Fatal("expected a dimension chart")
+	}
+	estimate := chart.Series[0]
+	if estimate.Label != "Оценка ± ДИ" || len(estimate.Lower) != 3 || len(estimate.Upper) != 3 {
+		t.Fatalf("expected the estimate to carry the interval band, got %+v", estimate)
+	}
+	if estimate.Lower[1] != 1.21 || estimate.Upper[1] != 1.28 {
+		t.Fatalf("expected the band [1.21, 1.28] at iteration 1, got [%v, %v]", estimate.Lower[1], estimate.Upper[1])
+	}
+	if !math.IsNaN(estimate.Lower[0]) || !math.IsNaN(estimate.Upper[2]) {
+		t.Fatal("expected gaps in the band where there is no interval")
+	}
+
+	data, err := json.Marshal(dimensionMetricsFromAnalysis(fraes.BoxCountingAnalysis{Valid: true, Dimension: 1.24, Samples: make([]fraes.BoxCountingSample, 8)}, fraes.ConfidenceInterval{Level: 0.95, Lower: 1.21, Upper: 1.28, StdError: 0.018, Replicates: 100, Valid: true}))
+	if err != nil {
+		t.Fatalf("marshal dimension metrics: %v", err)
+	}
+	if !strings.Contains(string(data), `"confidence_interval":{"level":0.95,"lower":1.21,"upper":1.28,"std_error":0.018,"replicates":100}`) {
+		t.Fatalf("expected the interval in the dimension metrics, got %s", data)
+	}
+}
+
+func TestDimensionEstimatorsReportSideBySide(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0.03, Lon: 0.10},
+		{Lat: 0, Lon: 0.20},
+	}
+	estimators := []string{"box", "mass-radius", "multifractal"}
+	assessment, err := runDimensionMetrics(base, 3, fraes.OrganicOptions{Seed: 7}, nil, dimensionRunOptions{Estimators: estimators, QRange: []float64{0, 1, 2}})
+	if err != nil {
+		t.Fatalf("runDimensionMetrics returned error: %v", err)
+	}
+
+	ctx := exportContext{Command: cmdDimension, Format: formatCSV}
+	if err := writeDimensionEstimatorMetrics(assessment, dir, ctx); err != nil {
+		t.Fatalf("writeDimensionEstimatorMetrics returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "dimension-estimators.metrics.json"))
+	if err != nil {
+		t.Fatalf("read estimator metrics: %v", err)
+	}
+	var metrics dimensionEstimatorArtifactMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("unmarshal estimator metrics: %v", err)
+	}
+	if len(metrics.Iterations) != 4 || len(metrics.QRange) != 3 {
+		t.Fatalf("expected 4 iterations over 3 q values, got %+v", metrics)
+	}
+	last := metrics.Iterations[3]
+	if len(last.Estimates) != len(estimators) || last.Estimates[1].Estimator != "mass-radius" {
+		t.Fatalf("expected estimates in --estimator order, got %+v", last.Estimates)
+	}
+	if last.Multifractal == nil || len(last.Multifractal.Spectrum) != 3 {
+		t.Fatalf("expected a 3 point spectrum, got %+v", last.Multifractal)
+	}
+
+	if table := dimensionEstimatorsTable(assessment); len(table.Rows) != 4*len(estimators) {
+		t.Fatalf("expected a row per iteration and estimator, got %d", len(table.Rows))
+	}
+	if table := dimensionSpectrumTable(assessment); len(table.Rows) != 4*3 {
+		t.Fatalf("expected a row per iteration and q, got %d", len(table.Rows))
+	}
+}
+
+func TestWriteErosionSVGSeriesLabelsScenarioYears(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	sc, err := scenario.Parse([]byte(`{
+		"name": "test",
+		"start_year": 2025,
+		"end_year": 2035,
+		"step_years": 5,
+		"background_retreat_m_per_year": 1,
+		"sea_level": {"type": "linear", "rate_mm_per_year": 5}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected scenario error: %v", err)
+	}
+
+	app := &App{
+		Config:    config{Command: cmdErosion, ErosionModel: erosionModelGaussian, Seed: 7},
+		Base:      base,
+		ModelBase: base,
+		Scenario:  &sc,
+	}
+	series, err := simulateErosion(app)
+	if err != nil {
+		t.Fatalf("simulateErosion returned error: %v", err)
+	}
+	if len(series.Snapshots) != 3 {
+		t.Fatalf("expected initial state and 2 scenario steps, got %d snapshots", len(series.Snapshots))
+	}
+
+	if err := writeErosionSVGSeries(base, base, series, dir, exportContext{Command: cmdErosion}); err != nil {
+		t.Fatalf("writeErosionSVGSeries returned error: %v", err)
+	}
+
+	svg, err := os.ReadFile(filepath.Join(dir, "erosion_step_2.svg"))
+	if err != nil {
+		t.Fatalf("expected last step SVG: %v", err)
+	}
+	if !strings.Contains(string(svg), "2035 год") {
+		t.Fatal("expected the last step to be labelled with its calendar year")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "erosion.metrics.json"))
+	if err != nil {
+		t.Fatalf("expected metrics file: %v", err)
+	}
+	var metrics erosionSeriesArtifactMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("invalid metrics json: %v", err)
+	}
+	if metrics.Scenario == nil || metrics.Scenario.StartYear != 2025 {
+		t.Fatalf("expected scenario metadata, got %+v", metrics.Scenario)
+	}
+	last := metrics.Steps[2]
+	// 5 years of 1 m/yr plus 2.5 cm of rise on a 1:50 profile.
+	if last.Year != 2035 || last.Scenario == nil || last.Scenario.TotalM != 6.25 {
+		t.Fatalf("unexpected last step labels %+v / %+v", last.Year, last.Scenario)
+	}
+	if metrics.Steps[0].Year != 2025 || metrics.Steps[0].Scenario != nil {
+		t.Fatalf("expected the initial state at the start year without forcing, got %+v", metrics.Steps[0])
+	}
+}
+
+func TestErosionEnsembleWritesFanChartsAndPerSeedRows(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	app := &App{
+		Config: config{
+			Command: cmdErosion, ErosionModel: erosionModelGaussian, Steps: 2, Seed: 7,
+			Ensemble: 3, EnsembleWorkers: 2, OutputPath: dir, Format: "csv",
+		},
+		Base:      base,
+		ModelBase: base,
+	}
+	if err := runEnsemble(app, ensembleSpec{Name: "erosion", Title: "Эрозия", Axis: "step", Header: "Шаг"}, erosionEnsembleMember(app)); err != nil {
+		t.Fatalf("runEnsemble returned error: %v", err)
+	}
+
+	svg, err := os.ReadFile(filepath.Join(dir, "erosion-ensemble.svg"))
+	if err != nil {
+		t.Fatalf("expected ensemble SVG: %v", err)
+	}
+	if !strings.Contains(string(svg), "<polygon") || !strings.Contains(string(svg), "Медиана") {
+		t.Fatal("expected fan chart bands and a median line in the ensemble SVG")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "erosion-ensemble.metrics.json"))
+	if err != nil {
+		t.Fatalf("expected ensemble metrics: %v", err)
+	}
+	var metrics ensembleArtifactMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("invalid metrics json: %v", err)
+	}
+	if metrics.Members != 3 || len(metrics.Seeds) != 3 || metrics.Seeds[0] != 7 || metrics.Seeds[2] != 9 {
+		t.Fatalf("expected seeds 7..9, got %+v", metrics.Seeds)
+	}
+	if len(metrics.Steps) != 3 || metrics.Steps[2].LengthKM == nil || metrics.Steps[2].LengthKM.Count != 3 {
+		t.Fatalf("expected a length summary over 3 members at every step, got %+v", metrics.Steps)
+	}
+	first := metrics.Steps[0].LengthKM
+	if first.StdDev != 0 || first.Min != first.Max {
+		t.Fatalf("expected every member to start from the same coastline, got %+v", first)
+	}
+
+	table, err := os.ReadFile(filepath.Join(dir, "erosion-ensemble.csv"))
+	if err != nil {
+		t.Fatalf("expected per-seed table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(table)), "\n")
+	if lines[0] != "member,seed,step,length_km,area_km2,dimension,projection" || len(lines) != 1+3*3 {
+		t.Fatalf("expected a header and one row per member and step, got %q", lines)
+	}
+}
+
+func TestSweepWritesHeatmapsAndResumesFromJournal(t *testing.T) {
+	dir := t.TempDir()
+	base := []fraes.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	app := &App{
+		Config: config{
+			Command: cmdSweep, Pipeline: sweepPipelineOrganic, Sampling: sweep.SamplingGrid,
+			Iterations: 1, Seed: 42, Workers: 2, OutputPath: dir, Format: "csv",
+			Ranges: map[string]string{"angle-jitter": "0,10", "height-jitter": "0.1,0.2"},
+		},
+		Base:      base,
+		ModelBase: base,
+	}
+	if err := runSweepCommand(app); err != nil {
+		t.Fatalf("runSweepCommand returned error: %v", err)
+	}
+
+	for _, name := range []string{"sweep.jsonl", "sweep-dimension.svg", "sweep-length-growth.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+	}
+	table, err := os.ReadFile(filepath.Join(dir, "sweep.csv"))
+	if err != nil {
+		t.Fatalf("expected sweep table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(table)), "\n")
+	if !strings.HasPrefix(lines[0], "run,angle_jitter,height_jitter,erosion_strength,length_km,length_growth") || len(lines) != 1+4 {
+		t.Fatalf("expected a header and one row per grid point, got %q", lines)
+	}
+
+	readMetrics := func() sweepArtifactMetrics {
+		data, err := os.ReadFile(filepath.Join(dir, "sweep.metrics.json"))
+		if err != nil {
+			t.Fatalf("expected sweep metrics: %v", err)
+		}
+		var metrics sweepArtifactMetrics
+		if err := json.Unmarshal(data, &metrics); err != nil {
+			t.Fatalf("invalid metrics json: %v", err)
+		}
+		return metrics
+	}
+	metrics := readMetrics()
+	if len(metrics.Runs) != 4 || metrics.Resumed != 0 || len(metrics.Heatmaps) != 2 {
+		t.Fatalf("expected 4 fresh runs and 2 heatmaps, got %d runs, %d resumed, %d heatmaps", len(metrics.Runs), metrics.Resumed, len(metrics.Heatmaps))
+	}
+	if heatmap := metrics.Heatmaps[0]; heatmap.X != "angle-jitter" || heatmap.Y != "height-jitter" || len(heatmap.Cells) != 2 || len(heatmap.Cells[0]) != 2 {
+		t.Fatalf("expected a 2x2 angle/height plane, got %+v", heatmap)
+	}
+	if growth := metrics.Runs[0].LengthGrowth; growth <= 1 {
+		t.Fatalf("expected organic growth to lengthen the line, got %v", growth)
+	}
+
+	if err := runSweepCommand(app); err != nil {
+		t.Fatalf("resumed runSweepCommand returned error: %v", err)
+	}
+	if resumed := readMetrics(); resumed.Resumed != 4 || len(resumed.Runs) != 4 {
+		t.Fatalf("expected every run to come from the journal, got %d of %d", resumed.Resumed, len(resumed.Runs))
+	}
+
+	app.Config.Seed = 7
+	if err := runSweepCommand(app); !errors.Is(err, sweep.ErrJournalMismatch) {
+		t.Fatalf("expected another seed to be refused without --fresh, got %v", err)
+	}
+}
@@ -1,8 +1,18 @@
 package cli
 
-import "coastal-geometry/internal/domain/simulations/paradox"
+import (
+	"coastal-geometry/internal/domain/simulations/paradox"
+	"os"
+)
 
 func runParadoxCommand(app *App) error {
-	paradox.Demonstrate(app.ModelBase, app.Config.Iterations, app.Config.ErosionStrength, app.Config.Seed)
-	return nil
+	report := paradox.Analyze(app.ModelBase, app.Config.Iterations, app.Config.ErosionStrength, app.Config.Seed, app.Projection)
+	renderParadoxReport(os.Stdout, report)
+	if err := writeDataTable(paradoxTable(report, app.Projection), app.Config.OutputPath, newExportContext(app)); err != nil {
+		return err
+	}
+	if app.Config.Ensemble == 0 {
+		return nil
+	}
+	return runEnsemble(app, ensembleSpec{Name: "paradox", Title: "Парадокс береговой линии", Axis: "level", Header: "Ур."}, paradoxEnsembleMember(app))
 }
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/simulations/paradox"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Text renderers for the domain reports. CSV and JSON go through the
+// dataTable builders in table_export.go.
+
+func renderCoastlineReport(w io.Writer, report fraes.CoastlineReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+	fmt.Fprintln(w, "\tБЕРЕГОВАЯ ЛИНИЯ ЧЁРНОГО МОРЯ")
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+
+	fmt.Fprintf(w, "\nКоличество точек:                        %d\n", report.Points)
+	fmt.Fprintf(w, "Количество сегментов:                    %d\n", report.Segments)
+	if report.Source != "" {
+		fmt.Fprintf(w, "Источник данных:                         %s\n", report.Source)
+	}
+
+	fmt.Fprintf(w, "Общая длина береговой линии:              %.0f км\n", report.LengthKM)
+	if report.Geodesic != "" && report.Geodesic != fraes.GeodesicHaversine {
+		diff := report.LengthKM - report.HaversineKM
+		fmt.Fprintf(w, "Геодезическая модель:                     %s, эллипсоид %s\n", report.Geodesic, report.Ellipsoid)
+		fmt.Fprintf(w, "Длина на сфере (haversine):               %.0f км\n", report.HaversineKM)
+		fmt.Fprintf(w, "Разница эллипсоид − сфера:                %+.1f км (%+.3f%%)\n", diff, diff/report.HaversineKM*100)
+	}
+	fmt.Fprintf(w, "Средняя длина сегмента:                   %.1f км\n\n", report.MeanSegmentKM)
+
+	if report.Sanity.Warning != "" {
+		fmt.Fprintln(w, report.Sanity.Warning)
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "Ключевые точки береговой линии:")
+	if report.Sampled {
+		fmt.Fprintf(w, "Показаны %d равномерно распределённых точек из %d.\n", len(report.KeyPoints), report.Points)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	fmt.Fprintf(w, "%-4s %-11s %-11s %-25s\n", "№", "Широта", "Долгота", "Город / ориентир")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+
+	for _, point := range report.KeyPoints {
+		fmt.Fprintf(w, "%-4d %-11.4f %-11.4f %-25s\n", point.Index+1, point.Point.Lat, point.Point.Lon, point.Name)
+	}
+
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+	fmt.Fprintf(w, "Итого: %.0f км\n", report.LengthKM)
+}
+
+// renderCoastlineParts prints per-part totals and the aggregate over every
+// ring, so islands and detached shores are measured together with the main
+// line. Lengths are measured with distance, as in the coastline report, and
+// areas with area; a non-planar measure is compared with the planar one.
+func renderCoastlineParts(w io.Writer, coast fraes.Coastline, distance fraes.Distance, area fraes.AreaMeasure) {
+	summaries := coast.Summaries(distance, area)
+	if len(summaries) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Части береговой линии:")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	fmt.Fprintf(w, "%-32s %-7s %-9s %-12s %-12s\n", "Часть", "Колец", "Точек", "Длина, км", "Площадь, км²")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	for _, summary := range summaries {
+		fmt.Fprintf(w, "%-32s %-7d %-9d %-12.0f %-12.0f\n", summary.Name, summary.RingCount, summary.PointCount, summary.LengthKM, summary.AreaKM2)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	total := coast.AreaKM2(area)
+	length := coast.LengthKM(distance)
+	fmt.Fprintf(w, "%-32s %-7d %-9d %-12.0f %-12.0f\n", "Всего", coast.RingCount(), coast.PointCount(), length, total)
+
+	main := fraes.PolylineLengthWith(coast.MainPoints(), distance)
+	fmt.Fprintf(w, "Главное кольцо: %.0f км; остальные кольца: %.0f км\n", main, length-main)
+	if area != nil && area.Name() != fraes.AreaPlanar && total > 0 {
+		planar := coast.AreaKM2(nil)
+		fmt.Fprintf(w, "Площадь: %s; на плоской сетке (planar) %.0f км², разница %+.0f км² (%+.2f%%)\n",
+			areaLabel(area), planar, total-planar, (total-planar)/planar*100)
+	}
+}
+
+// areaMethodName is the --area value of the measure; nil means planar.
+func areaMethodName(area fraes.AreaMeasure) string {
+	if area == nil {
+		return fraes.AreaPlanar
+	}
+	return area.Name()
+}
+
+// projectionName is the --projection value of p; nil means the default.
+func projectionName(p fraes.Projector) string {
+	if p == nil {
+		return fraes.DefaultProjection
+	}
+	return p.Name()
+}
+
+// areaLabel names the area measure and its ellipsoid for the console.
+func areaLabel(area fraes.AreaMeasure) string {
+	if ellipsoid, ok := fraes.AreaEllipsoid(area); ok {
+		return fmt.Sprintf("%s, эллипсоид %s", area.Name(), ellipsoid.Name)
+	}
+	return areaMethodName(area)
+}
+
+func renderKochReport(w io.Writer, report fraes.TheoryCheckReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+	fmt.Fprintln(w, "\tФРАКТАЛЬНАЯ БЕРЕГОВАЯ ЛИНИЯ ЧЁРНОГО МОРЯ — КРИВАЯ КОХА (рекурсивная)")
+	fmt.Fprintln(w, strings.Repeat("═", 90))
+
+	fmt.Fprintf(w, "Исходная полилиния: %d точек, длина = %.0f км\n\n", report.BasePoints, report.BaseLengthKM)
+
+	fmt.Fprintf(w, "%-5s %-10s %-15s %-15s %-15s %-12s\n", "Итер.", "Точек", "Измерено, км", "Теория, км", "Ошибка, км", "Ошибка, %")
+	fmt.Fprintln(w, strings.Repeat("─", 96))
+
+	for _, sample := range report.Samples {
+		fmt.Fprintf(w, "%-5d %-10d %-15.0f %-15.0f %-15.2f %-12.2f\n",
+			sample.Iteration,
+			sample.PointsCount,
+			sample.MeasuredLengthKM,
+			sample.TheoreticalKM,
+			sample.ErrorKM,
+			sample.ErrorPercent)
+
+		if sample.ErrorPercent > fraes.MaxTheoryErrorPct {
+			fmt.Fprintln(w, "WARNING: Koch implementation inconsistent with theory")
+		}
+	}
+
+	fmt.Fprintln(w, strings.Repeat("─", 96))
+	fmt.Fprintf(w, "Математическая формула: Lₙ = L₀ × (4/3)ⁿ\n")
+	fmt.Fprintf(w, "error = |L_measured - L_theory|\n")
+	fmt.Fprintf(w, "Порог предупреждения: %.0f%%\n", fraes.MaxTheoryErrorPct)
+	fmt.Fprintf(w, "Фрактальная размерность D = log(4)/log(3) ≈ %.5f\n", math.Log(4)/math.Log(3))
+	fmt.Fprintf(w, "При n→∞ длина → ∞, но кривая остаётся в ограниченной области\n")
+}
+
+func renderGeneratorReport(w io.Writer, gen fraes.Generator, report fraes.TheoryCheckReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+	fmt.Fprintf(w, "\tДЕТЕРМИНИРОВАННЫЙ ФРАКТАЛЬНЫЙ ГЕНЕРАТОР — %s\n", strings.ToUpper(gen.Name()))
+	fmt.Fprintln(w, strings.Repeat("═", 90))
+
+	fmt.Fprintf(w, "Исходная полилиния: %d точек, длина = %.0f км\n", report.BasePoints, report.BaseLengthKM)
+	fmt.Fprintf(w, "Мотив: сегментов %d, длина ×%.5f за итерацию\n\n", gen.Segments(), gen.TheoreticalLengthFactor())
+
+	fmt.Fprintf(w, "%-5s %-10s %-15s %-15s %-15s %-12s\n", "Итер.", "Точек", "Измерено, км", "Теория, км", "Ошибка, км", "Ошибка, %")
+	fmt.Fprintln(w, strings.Repeat("─", 96))
+
+	for _, sample := range report.Samples {
+		fmt.Fprintf(w, "%-5d %-10d %-15.0f %-15.0f %-15.2f %-12.2f\n",
+			sample.Iteration,
+			sample.PointsCount,
+			sample.MeasuredLengthKM,
+			sample.TheoreticalKM,
+			sample.ErrorKM,
+			sample.ErrorPercent)
+
+		if sample.ErrorPercent > fraes.MaxTheoryErrorPct {
+			fmt.Fprintf(w, "WARNING: generator %s inconsistent with theory\n", gen.Name())
+		}
+	}
+
+	fmt.Fprintln(w, strings.Repeat("─", 96))
+	fmt.Fprintf(w, "Математическая формула: Lₙ = L₀ × %.5fⁿ\n", gen.TheoreticalLengthFactor())
+	fmt.Fprintf(w, "Порог предупреждения: %.0f%%\n", fraes.MaxTheoryErrorPct)
+	fmt.Fprintf(w, "Теоретическая размерность подобия D = %.5f; box-counting D итераций — в SVG серии\n", gen.TheoreticalDimension())
+}
+
+func renderOrganicReport(w io.Writer, report fraes.OrganicReport) {
+	opts := report.Options
+
+	fmt.Fprintln(w, strings.Repeat("═", 80))
+	fmt.Fprintln(w, "\tОРГАНИЧЕСКАЯ ФРАКТАЛЬНАЯ БЕРЕГОВАЯ ЛИНИЯ — KOCH ORGANIC")
+	fmt.Fprintln(w, strings.Repeat("═", 90))
+
+	fmt.Fprintf(w, "Исходная полилиния: %d точек, длина = %.0f км\n", report.BasePoints, report.BaseLengthKM)
+	fmt.Fprintf(w, "Seed=%d, angle jitter=±%.1f°, height jitter=±%.0f%%\n\n",
+		opts.Seed, opts.AngleJitterDeg, opts.HeightJitterPct*100)
+
+	fmt.Fprintf(w, "%-5s %-10s %-15s %-15s %-12s\n", "Итер.", "Точек", "Длина, км", "Прирост", "× от исходной")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+
+	for _, sample := range report.Samples {
+		growth := ""
+		multiplier := "1.000×"
+		if sample.Iteration > 0 {
+			growth = fmt.Sprintf("+%.0f км", sample.GrowthKM)
+			multiplier = fmt.Sprintf("%.3f×", sample.RatioToBase)
+		}
+
+		fmt.Fprintf(w, "%-5d %-10d %-15.0f %-15s %-12s\n",
+			sample.Iteration, sample.PointsCount, sample.LengthKM, growth, multiplier)
+	}
+
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	fmt.Fprintln(w, "Organic Koch нарушает идеальную самоподобность, поэтому выглядит ближе к природной береговой линии.")
+}
+
+// renderFBMReport prints the growth of the fBm curve with the box-counting D
+// of every iteration against the target 2 − H.
+func renderFBMReport(w io.Writer, report fbm.Report, dimensions []float64) {
+	opts := report.Options
+	target := fbm.TargetDimension(opts.Hurst)
+
+	fmt.Fprintln(w, strings.Repeat("═", 90))
+	fmt.Fprintln(w, "\tФРАКТАЛЬНОЕ БРОУНОВСКОЕ ДВИЖЕНИЕ — fBm ВДОЛЬ НОРМАЛЕЙ")
+	fmt.Fprintln(w, strings.Repeat("═", 90))
+
+	fmt.Fprintf(w, "Исходная полилиния: %d точек, длина = %.0f км\n", report.BasePoints, report.BaseLengthKM)
+	fmt.Fprintf(w, "Seed=%d, H=%.2f, амплитуда=%.0f%% сегмента, цель D = 2 − H = %.3f\n\n",
+		opts.Seed, opts.Hurst, opts.Amplitude*100, target)
+
+	fmt.Fprintf(w, "%-5s %-10s %-15s %-15s %-14s %-10s %-10s\n", "Итер.", "Точек", "Длина, км", "Прирост", "× от исходной", "D", "D − цель")
+	fmt.Fprintln(w, strings.Repeat("─", 90))
+
+	for _, sample := range report.Samples {
+		growth := ""
+		multiplier := "1.000×"
+		if sample.Iteration > 0 {
+			growth = fmt.Sprintf("+%.0f км", sample.GrowthKM)
+			multiplier = fmt.Sprintf("%.3f×", sample.RatioToBase)
+		}
+		dimension, delta := "n/a", ""
+		if d := dimensions[sample.Iteration]; !math.IsNaN(d) {
+			dimension = fmt.Sprintf("%.4f", d)
+			delta = fmt.Sprintf("%+.4f", d-target)
+		}
+
+		fmt.Fprintf(w, "%-5d %-10d %-15.0f %-15s %-14s %-10s %-10s\n",
+			sample.Iteration, sample.PointsCount, sample.LengthKM, growth, multiplier, dimension, delta)
+	}
+
+	fmt.Fprintln(w, strings.Repeat("─", 90))
+	fmt.Fprintln(w, "На масштабах меньше сегмента базы каждый сегмент — график fBm с D = 2 − H; на крупных масштабах видна сама база, поэтому box-counting обычно чуть ниже цели.")
+}
+
+func renderParadoxReport(w io.Writer, report paradox.Report) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintln(w, "\tПАРАДОКС БЕРЕГОВОЙ ЛИНИИ")
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintln(w, "Демонстрация использует изменение масштаба измерения и добавление новых")
+	fmt.Fprintln(w, "геометрических деталей через кривую Коха. Простое деление сегментов без")
+	fmt.Fprintln(w, "изменения формы здесь не используется.")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+
+	fmt.Fprintf(w, "%-8s %-12s %-16s %-18s %-24s\n", "Уровень", "Точек", "Сегментов", "Средний шаг, км", "Длина, км")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+
+	for _, level := range report.Levels {
+		growth := " | —"
+		if level.Level > 0 {
+			growth = fmt.Sprintf(" | +%.0f км (%.3fx)", level.GrowthKM, level.GrowthRatio)
+		}
+		fmt.Fprintf(w, "%-8d %-12d %-16d %-18.2f %-24s\n", level.Level, level.Points, level.Segments, level.MeanStepKM, fmt.Sprintf("%.0f%s", level.LengthKM, growth))
+	}
+
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	fmt.Fprintln(w, "Вывод: длина растёт при уменьшении шага измерения, потому что на каждом")
+	fmt.Fprintln(w, "уровне появляются новые геометрические детали, а не только дополнительные точки.")
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"coastal-geometry/internal/domain/simulations/paradox"
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderParadoxReport(t *testing.T) {
+	var out bytes.Buffer
+	renderParadoxReport(&out, paradox.Report{Levels: []paradox.Level{
+		{Level: 0, Points: 2, Segments: 1, MeanStepKM: 11.12, LengthKM: 11.12},
+		{Level: 1, Points: 5, Segments: 4, MeanStepKM: 3.71, LengthKM: 14.83, GrowthKM: 3.71, GrowthRatio: 1.3333},
+	}})
+
+	text := out.String()
+	for _, expected := range []string{
+		"ПАРАДОКС БЕРЕГОВОЙ ЛИНИИ",
+		"0        2            1                11.12              11 | —",
+		"1        5            4                3.71               15 | +4 км (1.333x)",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRenderKochReportWarnsAboveThreshold(t *testing.T) {
+	var out bytes.Buffer
+	renderKochReport(&out, fraes.TheoryCheckReport{
+		BasePoints:   2,
+		BaseLengthKM: 100,
+		Samples: []fraes.TheoryCheckSample{
+			{Iteration: 0, PointsCount: 2, MeasuredLengthKM: 100, TheoreticalKM: 100},
+			{Iteration: 1, PointsCount: 5, MeasuredLengthKM: 120, TheoreticalKM: 133.33, ErrorKM: 13.33, ErrorPercent: 10},
+		},
+	})
+
+	text := out.String()
+	if !strings.Contains(text, "Исходная полилиния: 2 точек, длина = 100 км") {
+		t.Fatalf("expected the base line summary, got:\n%s", text)
+	}
+	if strings.Count(text, "WARNING: Koch implementation inconsistent with theory") != 1 {
+		t.Fatalf("expected one warning for the inconsistent iteration, got:\n%s", text)
+	}
+}
+
+func TestRenderCoastlineReportNotesSampling(t *testing.T) {
+	coast := make([]fraes.LatLon, 40)
+	for i := range coast {
+		coast[i] = fraes.LatLon{Lat: 43, Lon: 28 + float64(i)*0.01}
+	}
+
+	var out bytes.Buffer
+	renderCoastlineReport(&out, fraes.BuildCoastlineReport(coast, "unit.json", "unit-test", nil))
+
+	text := out.String()
+	for _, expected := range []string{
+		"Источник данных:                         unit-test",
+		"Показаны 30 равномерно распределённых точек из 40.",
+		"40   43.0000     28.3900     —",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestRenderCoastlinePartsComparesAreaWithPlanar(t *testing.T) {
+	ring := []fraes.LatLon{{Lat: 41, Lon: 28}, {Lat: 41.5, Lon: 41.7}, {Lat: 46.6, Lon: 38}, {Lat: 45, Lon: 29.7}, {Lat: 41, Lon: 28}}
+	coast := fraes.Coastline{Parts: []fraes.Part{{
+		Name:  "basin",
+		Rings: []fraes.Ring{{Name: "basin/outer", Role: fraes.RingOuter, Points: ring, Main: true}},
+	}}}
+
+	ellipsoidal, err := fraes.NewAreaMeasure(fraes.AreaEllipsoidal)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	planar, err := fraes.NewAreaMeasure(fraes.AreaPlanar)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var out bytes.Buffer
+	renderCoastlineParts(&out, coast, nil, ellipsoidal)
+	if text := out.String(); !strings.Contains(text, "Площадь: ellipsoidal, эллипсоид WGS84; на плоской сетке (planar)") {
+		t.Fatalf("expected planar comparison, got:\n%s", text)
+	}
+
+	out.Reset()
+	renderCoastlineParts(&out, coast, nil, planar)
+	if text := out.String(); strings.Contains(text, "на плоской сетке") {
+		t.Fatalf("expected no comparison for planar area, got:\n%s", text)
+	}
+}
+
+func TestRenderCoastlinePartsMeasuresWithTheReportDistance(t *testing.T) {
+	ring := []fraes.LatLon{{Lat: 41, Lon: 28}, {Lat: 41.5, Lon: 41.7}, {Lat: 46.6, Lon: 38}, {Lat: 45, Lon: 29.7}, {Lat: 41, Lon: 28}}
+	coast := fraes.Coastline{Parts: []fraes.Part{{
+		Name:  "basin",
+		Rings: []fraes.Ring{{Name: "basin/outer", Role: fraes.RingOuter, Points: ring, Main: true}},
+	}}}
+	karney, err := fraes.NewDistance(fraes.GeodesicKarney)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var out bytes.Buffer
+	renderCoastlineParts(&out, coast, karney, nil)
+	report := fraes.BuildCoastlineReport(ring, "unit.json", "unit-test", karney)
+	if fmt.Sprintf("%.0f", report.LengthKM) == fmt.Sprintf("%.0f", report.HaversineKM) {
+		t.Fatal("expected karney and haversine lengths to differ by more than rounding")
+	}
+	if want := fmt.Sprintf("Главное кольцо: %.0f км; остальные кольца: 0 км", report.LengthKM); !strings.Contains(out.String(), want) {
+		t.Fatalf("expected %q, got:\n%s", want, out.String())
+	}
+	if want := fmt.Sprintf("%-12.0f", report.LengthKM); !strings.Contains(out.String(), "basin                            1       5         "+want) {
+		t.Fatalf("expected the part row to use the karney length %s, got:\n%s", want, out.String())
+	}
+}
+
+func TestRenderCoastlineReportComparesGeodesics(t *testing.T) {
+	coast := []fraes.LatLon{{Lat: 46.48, Lon: 30.73}, {Lat: 44.62, Lon: 33.53}, {Lat: 41.65, Lon: 41.63}}
+
+	karney, err := fraes.NewDistance(fraes.GeodesicKarney)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var out bytes.Buffer
+	renderCoastlineReport(&out, fraes.BuildCoastlineReport(coast, "unit.json", "unit-test", karney))
+
+	text := out.String()
+	for _, expected := range []string{
+		"Геодезическая модель:                     karney, эллипсоид WGS84",
+		"Длина на сфере (haversine):",
+		"Разница эллипсоид − сфера:                +",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
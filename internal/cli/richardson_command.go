@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"coastal-geometry/pkg/fraes"
+	"fmt"
+	"strings"
+)
+
+func runRichardsonCommand(app *App) error {
+	analysis := fraes.AnalyzeRichardson(app.Base)
+	printRichardsonTable(analysis)
+	if err := writeRichardsonSVG(app.Base, app.RenderBase, analysis, app.Config.OutputPath, "richardson.svg", newExportContext(app)); err != nil {
+		return err
+	}
+	if !analysis.Valid {
+		printInvalidResult()
+	}
+	return nil
+}
+
+func printRichardsonTable(analysis fraes.RichardsonAnalysis) {
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("\tМЕТОД РИЧАРДСОНА (циркуль с геодезическим шагом)")
+	fmt.Println(strings.Repeat("=", 80))
+
+	fmt.Printf("Габарит линии: %.0f км\n\n", analysis.ExtentKM)
+	fmt.Println("Обход идёт в обе стороны: шагов и остаток — прямой / обратный, L(ε) — среднее двух длин шаги × ε + остаток.")
+	fmt.Printf("%-8s %-12s %-13s %-19s %-14s %-8s\n",
+		"Делит.", "Шаг ε, км", "Шагов", "Остаток, км", "L(ε), км", "Окно")
+	fmt.Println(strings.Repeat("─", 80))
+
+	for i, sample := range analysis.Samples {
+		inWindow := ""
+		if analysis.RegressionRSquared > 0 && i >= analysis.WindowStart && i <= analysis.WindowEnd {
+			inWindow = "*"
+		}
+		fmt.Printf("%-8.0f %-12.3f %-13s %-19s %-14.1f %-8s\n",
+			sample.ScaleFactor, sample.RulerKM,
+			fmt.Sprintf("%d / %d", sample.ForwardSteps, sample.BackwardSteps),
+			fmt.Sprintf("%.3f / %.3f", sample.ForwardRemainderKM, sample.BackwardRemainderKM),
+			sample.LengthKM, inWindow)
+	}
+	fmt.Println(strings.Repeat("─", 80))
+
+	if analysis.RegressionRSquared == 0 {
+		fmt.Printf("Недостаточно масштабов для регрессии: %d\n", len(analysis.Samples))
+		return
+	}
+
+	fmt.Printf("Показатель Ричардсона (наклон log L от log ε): %.5f\n", analysis.Exponent)
+	fmt.Printf("Размерность D = 1 - наклон: %.5f\n", analysis.Dimension)
+	fmt.Printf("R²=%.4f, разброс локальных D=%.4f, масштабов в окне=%d\n",
+		analysis.RegressionRSquared, analysis.StabilitySpread, analysis.WindowEnd-analysis.WindowStart+1)
+	fmt.Printf("Стабильность на нескольких масштабах: %v\n", yesNo(analysis.StableAcrossScales))
+}
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/coastline"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
 	"io"
 	"os"
@@ -36,7 +36,7 @@ func exitWithError(stderr io.Writer, err error) {
 	os.Exit(1)
 }
 
-func printValidationReport(w io.Writer, report coastline.ValidationReport) {
+func printValidationReport(w io.Writer, report fraes.ValidationReport) {
 	for _, fix := range report.Fixes {
 		fmt.Fprintf(w, "fix: %s\n", fix)
 	}
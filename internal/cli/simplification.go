@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/pkg/fraes"
 	"fmt"
 )
 
@@ -10,11 +10,19 @@ const (
 	seriesSVGMaxPoints    = 1800
 	modelBaseMaxPointsCap = 3072
 	modelCurvePointBudget = 400000
+	// fbmBasePoints keeps the fBm base segments longer than most boxes of
+	// box counting, so the measured D is that of the displacements. Even at
+	// the last iteration the curve stays within modelCurvePointBudget.
+	fbmBasePoints = 64
+	// fitBasePoints keeps a model run of fit short enough for a search of
+	// hundreds of them; the deepest iteration within modelCurvePointBudget
+	// bounds --iterations.
+	fitBasePoints = 128
 )
 
 type geometryViews struct {
-	RenderBase  []geometry.LatLon
-	ModelBase   []geometry.LatLon
+	RenderBase  []fraes.LatLon
+	ModelBase   []fraes.LatLon
 	ProcessInfo []string
 }
 
@@ -24,7 +32,7 @@ func setCurrentConfig(cfg config) {
 	currentConfig = cfg
 }
 
-func prepareGeometryViews(points []geometry.LatLon, command string, iterations int) geometryViews {
+func prepareGeometryViews(points []fraes.LatLon, command string, iterations int, proj fraes.Projector) geometryViews {
 	cfg := currentConfig // set via setter before prepareGeometryViews is called
 
 	views := geometryViews{
@@ -33,7 +41,7 @@ func prepareGeometryViews(points []geometry.LatLon, command string, iterations i
 	}
 
 	if commandUsesCoastlineSVG(command) {
-		renderResult := geometry.SimplifyPolyline(points, geometry.SimplifyOptions{MaxPoints: coastlineSVGMaxPoints})
+		renderResult := fraes.SimplifyPolyline(points, fraes.WithMaxPoints(coastlineSVGMaxPoints), fraes.WithProjection(proj))
 		views.RenderBase = renderResult.Points
 		if renderResult.Applied {
 			views.ProcessInfo = append(views.ProcessInfo, formatSimplificationNote(
@@ -45,15 +53,24 @@ func prepareGeometryViews(points []geometry.LatLon, command string, iterations i
 		}
 	}
 
-	if commandUsesModelBase(command) {
+	// The erosion pipeline of a sweep erodes the full line, as erosion does.
+	if commandUsesModelBase(command) || (command == cmdSweep && cfg.Pipeline != sweepPipelineErosion) {
 		if cfg.DisableSimplify {
 			views.ModelBase = points
 		} else {
 			target := modelBaseTargetPoints(iterations)
+			switch command {
+			case cmdFBM:
+				target = fbmBasePoints
+			case cmdGenerate:
+				target = generatorBaseTargetPoints(cfg, iterations)
+			case cmdFit:
+				target = fitBasePoints
+			}
 			if cfg.ModelMaxPoints > 0 && cfg.ModelMaxPoints < target {
 				target = cfg.ModelMaxPoints
 			}
-			modelResult := geometry.SimplifyPolyline(points, geometry.SimplifyOptions{MaxPoints: target})
+			modelResult := fraes.SimplifyPolyline(points, fraes.WithMaxPoints(target), fraes.WithProjection(proj))
 			views.ModelBase = modelResult.Points
 			if modelResult.Applied {
 				views.ProcessInfo = append(views.ProcessInfo, formatSimplificationNote(
@@ -69,24 +86,24 @@ func prepareGeometryViews(points []geometry.LatLon, command string, iterations i
 	return views
 }
 
-func simplifyForSeriesSVG(points []geometry.LatLon) geometry.SimplifyResult {
-	return geometry.SimplifyPolyline(points, geometry.SimplifyOptions{MaxPoints: seriesSVGMaxPoints})
+func simplifyForSeriesSVG(points []fraes.LatLon, proj fraes.Projector) fraes.SimplifyResult {
+	return fraes.SimplifyPolyline(points, fraes.WithMaxPoints(seriesSVGMaxPoints), fraes.WithProjection(proj))
 }
 
-func formatSimplificationNote(label string, original, simplified []geometry.LatLon, suffix string) string {
+func formatSimplificationNote(label string, original, simplified []fraes.LatLon, suffix string) string {
 	return fmt.Sprintf("%s: %d -> %d points, %.0f -> %.0f km %s",
 		label,
 		len(original),
 		len(simplified),
-		geometry.PolylineLength(original),
-		geometry.PolylineLength(simplified),
+		fraes.PolylineLength(original),
+		fraes.PolylineLength(simplified),
 		suffix,
 	)
 }
 
 func commandUsesCoastlineSVG(command string) bool {
 	switch command {
-	case cmdCoastline, cmdAll:
+	case cmdCoastline, cmdRichardson, cmdAll:
 		return true
 	default:
 		return false
@@ -95,7 +112,7 @@ func commandUsesCoastlineSVG(command string) bool {
 
 func commandUsesModelBase(command string) bool {
 	switch command {
-	case cmdAll, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension:
+	case cmdAll, cmdParadox, cmdKoch, cmdKochOrganic, cmdFBM, cmdGenerate, cmdDimension, cmdFit:
 		return true
 	default:
 		return false
@@ -103,7 +120,21 @@ func commandUsesModelBase(command string) bool {
 }
 
 func modelBaseTargetPoints(iterations int) int {
-	growthFactor := powInt(4, iterations)
+	return modelBaseTargetPointsFor(4, iterations)
+}
+
+// generatorBaseTargetPoints sizes the base of model generate by the number
+// of motif segments of the selected generator.
+func generatorBaseTargetPoints(cfg config, iterations int) int {
+	gen, err := fraes.NewGenerator(cfg.Generator, fraes.GeneratorOptions{CesaroAngleDeg: cfg.CesaroAngle})
+	if err != nil {
+		return modelBaseTargetPoints(iterations)
+	}
+	return modelBaseTargetPointsFor(gen.Segments(), iterations)
+}
+
+func modelBaseTargetPointsFor(segments, iterations int) int {
+	growthFactor := powInt(segments, iterations)
 	if growthFactor < 1 {
 		growthFactor = 1
 	}
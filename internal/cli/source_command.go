@@ -23,6 +23,7 @@ func runSourceCommand(app *App) error {
 	fmt.Printf("Количество features:                   %d\n", meta.FeatureCount)
 	fmt.Printf("Типы геометрии:                        %s\n", valueOrDash(strings.Join(meta.GeometryTypes, ", ")))
 	fmt.Printf("Точек в извлечённой береговой линии:   %d\n", meta.CoastlinePointCount)
+	fmt.Printf("Колец в наборе:                        %d\n", meta.CoastlineRingCount)
 	fmt.Printf("Размер payload:                        %d байт\n", meta.PayloadBytes)
 	fmt.Printf("Имя набора:                            %s\n", valueOrDash(meta.Name))
 	fmt.Printf("Marine Regions ID:                     %s\n", valueOrDash(meta.RegionID))
@@ -0,0 +1,590 @@
+package cli
+
+import (
+	"coastal-geometry/internal/domain/simulations/ensemble"
+	"coastal-geometry/internal/domain/simulations/sweep"
+	svgrender "coastal-geometry/internal/render/svg"
+	"coastal-geometry/pkg/fraes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+const sweepJournalFile = "sweep.jsonl"
+
+// sweepReport is a finished sweep: Results[i] ran Points[i].
+type sweepReport struct {
+	Header     sweep.Header
+	Points     [][]float64
+	Results    []sweep.Result
+	Resumed    int
+	Workers    int
+	Journal    string
+	X, Y       int
+	Projection fraes.Projector
+}
+
+func runSweepCommand(app *App) error {
+	cfg := app.Config
+	params, err := sweepParameters(cfg)
+	if err != nil {
+		return err
+	}
+	points, err := sweepPoints(cfg, params)
+	if err != nil {
+		return err
+	}
+	x, y, err := sweepPlane(cfg, params)
+	if err != nil {
+		return err
+	}
+	outputDir, err := resolveSeriesOutputDir(cfg.OutputPath)
+	if err != nil {
+		return err
+	}
+
+	report := sweepReport{
+		Header:     sweepHeader(app, params),
+		Points:     points,
+		Journal:    filepath.Join(outputDir, sweepJournalFile),
+		Workers:    ensembleWorkers(cfg.Workers, len(points)),
+		X:          x,
+		Y:          y,
+		Projection: app.Projection,
+	}
+	journal, done, err := sweep.OpenJournal(report.Journal, report.Header, cfg.Fresh)
+	if errors.Is(err, sweep.ErrJournalMismatch) {
+		return fmt.Errorf("%w; pass --fresh to start over or choose another --output", err)
+	}
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+
+	results := make([]sweep.Result, len(points))
+	finished := make([]bool, len(points))
+	for _, result := range done {
+		if result.Index < len(points) {
+			results[result.Index] = result
+			finished[result.Index] = true
+			report.Resumed++
+		}
+	}
+	var pending []int
+	for i := range points {
+		if !finished[i] {
+			pending = append(pending, i)
+		}
+	}
+
+	fmt.Printf("Sweep, прогонов: %d, %s; журнал %s\n", len(points), sweepSamplingLabel(report.Header), report.Journal)
+	if report.Resumed > 0 {
+		fmt.Printf("Продолжение: в журнале уже %d из %d\n", report.Resumed, len(points))
+	}
+
+	run := sweepRunner(app, params)
+	var completed atomic.Int32
+	errs := ensemble.Run(len(pending), cfg.Workers, func(i int) error {
+		index := pending[i]
+		result, err := run(points[index])
+		if err != nil {
+			return fmt.Errorf("sweep run %d: %w", index, err)
+		}
+		result.Index = index
+		if err := journal.Append(result); err != nil {
+			return err
+		}
+		results[index] = result
+		fmt.Printf("[%d/%d] прогон %d: %s\n", report.Resumed+int(completed.Add(1)), len(points), index, formatSweepPoint(params, points[index]))
+		return nil
+	})
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	report.Results = results
+
+	renderSweepReport(os.Stdout, report)
+
+	ctx := newExportContext(app)
+	heatmaps, err := writeSweepHeatmaps(report, cfg.OutputPath)
+	if err != nil {
+		return err
+	}
+	if err := writeSweepMetrics(report, heatmaps, cfg.OutputPath, ctx); err != nil {
+		return err
+	}
+	return writeDataTable(sweepTable(report), cfg.OutputPath, ctx)
+}
+
+// sweepParameterNames lists every flag a sweep can vary, in table order.
+func sweepParameterNames() []string {
+	return []string{"angle-jitter", "height-jitter", "erosion-strength", "sediment-rate"}
+}
+
+func sweepPipelineParameters(pipeline string) []string {
+	if pipeline == sweepPipelineErosion {
+		return []string{"erosion-strength", "sediment-rate"}
+	}
+	return []string{"angle-jitter", "height-jitter", "erosion-strength"}
+}
+
+func sweepDefaultRange(pipeline, name string) string {
+	switch name {
+	case "angle-jitter":
+		return "0:30:10"
+	case "height-jitter":
+		return "0:0.3:0.1"
+	case "erosion-strength":
+		if pipeline == sweepPipelineErosion {
+			return "25:100:25"
+		}
+		return "0"
+	default:
+		return "0"
+	}
+}
+
+func sweepParameterUsage(name string) string {
+	switch name {
+	case "angle-jitter":
+		return "organic pipeline: angle deviation range in degrees as a value, min:max, min:max:step or a list (default \"0:30:10\")"
+	case "height-jitter":
+		return "organic pipeline: height deviation range as a ratio (default \"0:0.3:0.1\")"
+	case "erosion-strength":
+		return "erosion strength range in meters: Gaussian sigma after growth for organic, strength per step for erosion (default \"0\" for organic, \"25:100:25\" for erosion)"
+	default:
+		return "erosion pipeline: longshore transport range in m3 per step, 0 runs without sediment transport (default \"0\")"
+	}
+}
+
+// sweepParameters parses the ranges of the pipeline's parameters; flags of
+// another pipeline are rejected rather than silently ignored.
+func sweepParameters(cfg config) ([]sweep.Parameter, error) {
+	names := sweepPipelineParameters(cfg.Pipeline)
+	given := make([]string, 0, len(cfg.Ranges))
+	for name := range cfg.Ranges {
+		given = append(given, name)
+	}
+	sort.Strings(given)
+	for _, name := range given {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("%s is not swept by the %s pipeline", name, cfg.Pipeline)
+		}
+	}
+
+	params := make([]sweep.Parameter, 0, len(names))
+	for _, name := range names {
+		spec, ok := cfg.Ranges[name]
+		if !ok {
+			spec = sweepDefaultRange(cfg.Pipeline, name)
+		}
+		param, err := sweep.ParseParameter(name, spec)
+		if err != nil {
+			return nil, err
+		}
+		if param.Min() < 0 {
+			return nil, fmt.Errorf("%s must be non-negative", name)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func sweepPoints(cfg config, params []sweep.Parameter) ([][]float64, error) {
+	if cfg.Sampling == sweep.SamplingLHS {
+		return sweep.LatinHypercube(params, cfg.Samples, cfg.Seed)
+	}
+	return sweep.Grid(params)
+}
+
+// sweepPlane picks the heatmap axes: --plane, or the first two parameters
+// that vary. y is -1 when only one parameter spans the heatmap.
+func sweepPlane(cfg config, params []sweep.Parameter) (x, y int, err error) {
+	index := func(name string) (int, error) {
+		name = strings.TrimSpace(name)
+		for i, p := range params {
+			if p.Name == name {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("plane: %q is not a swept parameter of the %s pipeline", name, cfg.Pipeline)
+	}
+
+	if cfg.Plane != "" {
+		names := strings.Split(cfg.Plane, ",")
+		if len(names) > 2 {
+			return 0, 0, fmt.Errorf("plane must name one or two parameters as x,y")
+		}
+		if x, err = index(names[0]); err != nil {
+			return 0, 0, err
+		}
+		if len(names) == 1 {
+			return x, -1, nil
+		}
+		if y, err = index(names[1]); err != nil {
+			return 0, 0, err
+		}
+		if x == y {
+			return 0, 0, fmt.Errorf("plane must name two different parameters")
+		}
+		return x, y, nil
+	}
+
+	var varying []int
+	for i, p := range params {
+		if p.Varies() {
+			varying = append(varying, i)
+		}
+	}
+	switch len(varying) {
+	case 0:
+		return 0, -1, nil
+	case 1:
+		return varying[0], -1, nil
+	default:
+		return varying[0], varying[1], nil
+	}
+}
+
+func validateSweepConfig(cfg config) error {
+	switch cfg.Pipeline {
+	case sweepPipelineOrganic, sweepPipelineErosion:
+	default:
+		return fmt.Errorf("pipeline must be %q or %q", sweepPipelineOrganic, sweepPipelineErosion)
+	}
+	switch cfg.Sampling {
+	case sweep.SamplingGrid:
+	case sweep.SamplingLHS:
+		if cfg.Samples < 1 {
+			return fmt.Errorf("samples must be positive")
+		}
+	default:
+		return fmt.Errorf("sampling must be %q or %q", sweep.SamplingGrid, sweep.SamplingLHS)
+	}
+	if cfg.Workers < 0 {
+		return fmt.Errorf("workers must be non-negative")
+	}
+	params, err := sweepParameters(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := sweepPoints(cfg, params); err != nil {
+		return err
+	}
+	_, _, err = sweepPlane(cfg, params)
+	return err
+}
+
+// sweepHeader records everything a run depends on, so a journal written
+// for other settings is not resumed.
+func sweepHeader(app *App, params []sweep.Parameter) sweep.Header {
+	cfg := app.Config
+	header := sweep.Header{
+		Pipeline:   cfg.Pipeline,
+		Sampling:   cfg.Sampling,
+		Seed:       cfg.Seed,
+		Parameters: params,
+		Settings: map[string]string{
+			"dataset":           app.Dataset,
+			"model_base_points": fmt.Sprintf("%d", len(app.ModelBase)),
+			"model_base_km":     fmt.Sprintf("%.6f", fraes.PolylineLength(app.ModelBase)),
+			"projection":        projectionName(app.Projection),
+		},
+	}
+	if cfg.Sampling == sweep.SamplingLHS {
+		header.Samples = cfg.Samples
+	}
+	if cfg.Pipeline == sweepPipelineErosion {
+		header.Settings["steps"] = fmt.Sprintf("%d", cfg.Steps)
+		header.Settings["erosion_model"] = cfg.ErosionModel
+		header.Settings["wave_climate"] = cfg.WaveClimate
+		header.Settings["area"] = areaLabel(app.Area)
+	} else {
+		header.Settings["iterations"] = fmt.Sprintf("%d", cfg.Iterations)
+	}
+	return header
+}
+
+// sweepRunner returns the run of one parameter combination. Every run uses
+// --seed, so differences between runs come from the parameters alone.
+func sweepRunner(app *App, params []sweep.Parameter) func(values []float64) (sweep.Result, error) {
+	value := func(values []float64, name string) float64 {
+		for i, p := range params {
+			if p.Name == name {
+				return values[i]
+			}
+		}
+		return 0
+	}
+	result := func(values []float64, sample ensembleSample, baseKM float64) sweep.Result {
+		r := sweep.Result{
+			Values:         values,
+			LengthKM:       sample.LengthKM,
+			AreaKM2:        sample.AreaKM2,
+			Dimension:      sample.Dimension,
+			DimensionValid: !math.IsNaN(sample.Dimension),
+		}
+		if baseKM > 0 {
+			r.LengthGrowth = sample.LengthKM / baseKM
+		}
+		if !r.DimensionValid {
+			r.Dimension = 0
+		}
+		return r
+	}
+
+	if app.Config.Pipeline == sweepPipelineErosion {
+		return func(values []float64) (sweep.Result, error) {
+			run := *app
+			run.Config.ErosionStrength = value(values, "erosion-strength")
+			run.Config.SedimentRate = value(values, "sediment-rate")
+			run.Config.Sediment = run.Config.SedimentRate > 0
+			series, err := simulateErosion(&run)
+			if err != nil {
+				return sweep.Result{}, err
+			}
+			final := series.Snapshots[len(series.Snapshots)-1]
+			sample := measureEnsembleSample(final, nil, app.Projection)
+			sample.AreaKM2 = series.areaKM2(final)
+			return result(values, sample, fraes.PolylineLength(series.Snapshots[0])), nil
+		}
+	}
+
+	baseKM := fraes.PolylineLength(app.ModelBase)
+	return func(values []float64) (sweep.Result, error) {
+		opts := fraes.OrganicOptions{
+			Seed:            app.Config.Seed,
+			AngleJitterDeg:  value(values, "angle-jitter"),
+			HeightJitterPct: value(values, "height-jitter"),
+		}
+		curve := fraes.OrganicKochCurve(app.ModelBase, app.Config.Iterations, organicCurveOptions(opts)...)
+		if strength := value(values, "erosion-strength"); strength > 0 {
+			curve = fraes.Erode(curve, strength, app.Config.Seed+int64(app.Config.Iterations), app.Projection)
+		}
+		return result(values, measureEnsembleSample(curve, app.Area, app.Projection), baseKM), nil
+	}
+}
+
+func sweepSamplingLabel(header sweep.Header) string {
+	if header.Sampling == sweep.SamplingLHS {
+		return fmt.Sprintf("латинский гиперкуб из %d точек, seed %d", header.Samples, header.Seed)
+	}
+	return "полная сетка"
+}
+
+func formatSweepPoint(params []sweep.Parameter, values []float64) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = fmt.Sprintf("%s=%g", p.Name, roundSweepValue(values[i]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// roundSweepValue keeps Latin-hypercube values readable in labels; tables
+// and metrics keep full precision.
+func roundSweepValue(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+func renderSweepReport(w io.Writer, report sweepReport) {
+	params := report.Header.Parameters
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintf(w, "\tSWEEP ПАРАМЕТРОВ: %s, ПРОГОНОВ: %d\n", strings.ToUpper(report.Header.Pipeline), len(report.Results))
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "%s, параллельно: %d, из журнала: %d\n\n", sweepSamplingLabel(report.Header), report.Workers, report.Resumed)
+
+	fmt.Fprintf(w, "%-6s", "№")
+	for _, p := range params {
+		fmt.Fprintf(w, " %-17s", p.Name)
+	}
+	fmt.Fprintf(w, " %-12s %-12s %-14s %-8s\n", "Длина, км", "Рост длины", "Площадь, км²", "D")
+	width := 6 + 18*len(params) + 50
+	fmt.Fprintln(w, strings.Repeat("-", width))
+	for _, result := range report.Results {
+		fmt.Fprintf(w, "%-6d", result.Index)
+		for _, v := range result.Values {
+			fmt.Fprintf(w, " %-17g", roundSweepValue(v))
+		}
+		dimension := "n/a"
+		if result.DimensionValid {
+			dimension = fmt.Sprintf("%.4f", result.Dimension)
+		}
+		fmt.Fprintf(w, " %-12.1f %-12.4f %-14.0f %-8s\n", result.LengthKM, result.LengthGrowth, result.AreaKM2, dimension)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", width))
+
+	fmt.Fprintln(w, "Чувствительность (изменение по всему диапазону параметра, линейная регрессия по прогонам):")
+	for _, s := range sweepSensitivity(report) {
+		fmt.Fprintf(w, "  %-17s ΔD=%+.4f  Δрост длины=%+.4f\n", s.Parameter, s.Dimension, s.LengthGrowth)
+	}
+}
+
+type sweepParameterSensitivity struct {
+	Parameter    string
+	Dimension    float64
+	LengthGrowth float64
+}
+
+// sweepSensitivity reports, for every varying parameter, how much D and the
+// length growth change across its range.
+func sweepSensitivity(report sweepReport) []sweepParameterSensitivity {
+	dimensions, growth := sweepValues(report)
+	var out []sweepParameterSensitivity
+	for j, p := range report.Header.Parameters {
+		if !p.Varies() {
+			continue
+		}
+		out = append(out, sweepParameterSensitivity{
+			Parameter:    p.Name,
+			Dimension:    sweep.Sensitivity(report.Points, dimensions, j),
+			LengthGrowth: sweep.Sensitivity(report.Points, growth, j),
+		})
+	}
+	return out
+}
+
+// sweepValues returns D (NaN when invalid) and the length growth of every
+// run in point order.
+func sweepValues(report sweepReport) (dimensions, growth []float64) {
+	dimensions = make([]float64, len(report.Results))
+	growth = make([]float64, len(report.Results))
+	for i, result := range report.Results {
+		dimensions[i] = result.Dimension
+		if !result.DimensionValid {
+			dimensions[i] = math.NaN()
+		}
+		growth[i] = result.LengthGrowth
+	}
+	return dimensions, growth
+}
+
+type sweepHeatmap struct {
+	Metric  string
+	SVGFile string
+	Plane   sweep.Plane
+}
+
+// writeSweepHeatmaps draws D and the length growth over the sweep plane as
+// sweep-dimension.svg and sweep-length-growth.svg.
+func writeSweepHeatmaps(report sweepReport, output string) ([]sweepHeatmap, error) {
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return nil, err
+	}
+	dimensions, growth := sweepValues(report)
+	params := report.Header.Parameters
+
+	var heatmaps []sweepHeatmap
+	for _, metric := range []struct {
+		name, title, label, format string
+		values                     []float64
+	}{
+		{name: "dimension", title: "Размерность D", label: "D (box-counting)", format: "%.3f", values: dimensions},
+		{name: "length-growth", title: "Рост длины", label: "Длина / длина базы", format: "%.3f", values: growth},
+	} {
+		plane := sweep.NewPlane(report.Points, metric.values, report.X, report.Y)
+		if _, _, ok := sweepPlaneExtremes(plane); !ok {
+			fmt.Printf("warning: sweep heatmap %s skipped: no run has a valid value\n", metric.name)
+			continue
+		}
+		heatmap := svgrender.Heatmap{
+			Title:       fmt.Sprintf("Sweep: %s по %s", metric.title, sweepPlaneLabel(params, report.X, report.Y)),
+			Subtitle:    fmt.Sprintf("Конвейер %s, %s, %d прогонов; остальные параметры усреднены по клетке", report.Header.Pipeline, sweepSamplingLabel(report.Header), len(report.Results)),
+			XLabel:      params[report.X].Name,
+			XTicks:      formatSweepTicks(plane.XCenters),
+			Values:      plane.Cells,
+			ValueLabel:  metric.label,
+			ValueFormat: metric.format,
+			StatCards:   []svgrender.StatCard{sweepExtremesCard(plane, params, metric.format)},
+			Meta:        sweepHeatmapMeta(report),
+		}
+		if report.Y >= 0 {
+			heatmap.YLabel = params[report.Y].Name
+			heatmap.YTicks = formatSweepTicks(plane.YCenters)
+		}
+
+		filename := filepath.Join(outputDir, "sweep-"+metric.name+".svg")
+		if err := svgrender.DrawHeatmap(heatmap, filename); err != nil {
+			return nil, err
+		}
+		fmt.Printf("SVG saved to %s\n", filename)
+		heatmaps = append(heatmaps, sweepHeatmap{Metric: metric.name, SVGFile: filename, Plane: plane})
+	}
+	return heatmaps, nil
+}
+
+func sweepPlaneLabel(params []sweep.Parameter, x, y int) string {
+	if y < 0 {
+		return params[x].Name
+	}
+	return params[x].Name + " × " + params[y].Name
+}
+
+func formatSweepTicks(centers []float64) []string {
+	ticks := make([]string, len(centers))
+	for i, c := range centers {
+		ticks[i] = fmt.Sprintf("%g", roundSweepValue(c))
+	}
+	return ticks
+}
+
+// sweepPlaneExtremes returns the cells holding the smallest and largest
+// value as [row, col].
+func sweepPlaneExtremes(plane sweep.Plane) (low, high [2]int, ok bool) {
+	for row, cells := range plane.Cells {
+		for col, v := range cells {
+			if math.IsNaN(v) {
+				continue
+			}
+			if !ok || v < plane.Cells[low[0]][low[1]] {
+				low = [2]int{row, col}
+			}
+			if !ok || v > plane.Cells[high[0]][high[1]] {
+				high = [2]int{row, col}
+			}
+			ok = true
+		}
+	}
+	return low, high, ok
+}
+
+func sweepExtremesCard(plane sweep.Plane, params []sweep.Parameter, format string) svgrender.StatCard {
+	low, high, _ := sweepPlaneExtremes(plane)
+	at := func(cell [2]int) string {
+		label := fmt.Sprintf(format+" при %s=%g", plane.Cells[cell[0]][cell[1]], params[plane.X].Name, roundSweepValue(plane.XCenters[cell[1]]))
+		if plane.Y >= 0 {
+			label += fmt.Sprintf(", %s=%g", params[plane.Y].Name, roundSweepValue(plane.YCenters[cell[0]]))
+		}
+		return label
+	}
+	return svgrender.StatCard{
+		Title: "Диапазон по клеткам",
+		Items: []svgrender.StatItem{
+			{Label: "Минимум", Value: at(low), Tone: "#1f6f8b"},
+			{Label: "Максимум", Value: at(high), Tone: "#8b3f5c"},
+		},
+	}
+}
+
+func sweepHeatmapMeta(report sweepReport) []string {
+	meta := []string{fmt.Sprintf("Seed %d во всех прогонах", report.Header.Seed)}
+	if report.Header.Pipeline == sweepPipelineErosion {
+		meta = append(meta, fmt.Sprintf("Шагов эрозии: %s, модель %s", report.Header.Settings["steps"], report.Header.Settings["erosion_model"]))
+	} else {
+		meta = append(meta, fmt.Sprintf("Итераций organic Koch: %s", report.Header.Settings["iterations"]))
+	}
+	for _, p := range report.Header.Parameters {
+		meta = append(meta, fmt.Sprintf("%s: %g–%g, значений: %d", p.Name, p.Min(), p.Max(), len(p.Values)))
+	}
+	for _, s := range sweepSensitivity(report) {
+		meta = append(meta, fmt.Sprintf("Чувствительность к %s: ΔD=%+.4f, Δрост=%+.4f", s.Parameter, s.Dimension, s.LengthGrowth))
+	}
+	return append(meta, fmt.Sprintf("Журнал: %s", filepath.Base(report.Journal)))
+}
@@ -0,0 +1,482 @@
+package cli
+
+import (
+	"bytes"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/simulations/calibration"
+	"coastal-geometry/internal/domain/simulations/fit"
+	"coastal-geometry/internal/domain/simulations/paradox"
+	"coastal-geometry/pkg/fraes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+	formatTSV   = "tsv"
+	formatJSON  = "json"
+)
+
+// missingValue marks empty cells in CSV/TSV; R and pandas read it as NA.
+const missingValue = "NA"
+
+// dataTable is a tidy table: one row per iteration or step, every column a
+// plain value. Cells are int, int64, float64, string, bool or nil.
+type dataTable struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+func (t *dataTable) addRow(values ...any) {
+	t.Rows = append(t.Rows, values)
+}
+
+// writeDataTable saves the table as <Name>.csv, .tsv or .json in the series
+// output directory. The console format writes nothing.
+func writeDataTable(table dataTable, output string, ctx exportContext) error {
+	if ctx.Format == "" || ctx.Format == formatTable {
+		return nil
+	}
+	outputDir, err := resolveSeriesOutputDir(output)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(outputDir, table.Name+"."+ctx.Format)
+
+	switch ctx.Format {
+	case formatJSON:
+		records := make([]tableRecord, 0, len(table.Rows))
+		for _, row := range table.Rows {
+			records = append(records, tableRecord{columns: table.Columns, values: row})
+		}
+		if err := writeMetricsJSON(filename, records); err != nil {
+			return err
+		}
+	case formatCSV, formatTSV:
+		if err := writeDelimitedTable(filename, table, ctx.Format == formatTSV); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown table format %q", ctx.Format)
+	}
+
+	fmt.Printf("Table saved to %s\n", filename)
+	return nil
+}
+
+func writeDelimitedTable(filename string, table dataTable, tabs bool) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create table %q: %w", filename, err)
+	}
+
+	writer := csv.NewWriter(file)
+	if tabs {
+		writer.Comma = '\t'
+	}
+	records := make([][]string, 0, len(table.Rows)+1)
+	records = append(records, table.Columns)
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = textCell(value)
+		}
+		records = append(records, record)
+	}
+	if err := writer.WriteAll(records); err != nil {
+		file.Close()
+		return fmt.Errorf("write table %q: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write table %q: %w", filename, err)
+	}
+	return nil
+}
+
+func textCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return missingValue
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return missingValue
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tableRecord is one row of a JSON table. Its keys follow the column order
+// of the CSV header; a map would sort them.
+type tableRecord struct {
+	columns []string
+	values  []any
+}
+
+func (r tableRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(jsonCell(r.values[i]))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func jsonCell(value any) any {
+	if v, ok := value.(float64); ok && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return nil
+	}
+	return value
+}
+
+// optional turns a missing value into nil so that it is written as NA.
+func optional(value float64, ok bool) any {
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func kochTable(report fraes.TheoryCheckReport) dataTable {
+	table := dataTable{
+		Name:    "koch",
+		Columns: []string{"iteration", "points", "measured_km", "theory_km", "error_km", "error_pct", "base_points", "base_length_km"},
+	}
+	for _, sample := range report.Samples {
+		table.addRow(sample.Iteration, sample.PointsCount, sample.MeasuredLengthKM, sample.TheoreticalKM,
+			sample.ErrorKM, sample.ErrorPercent, report.BasePoints, report.BaseLengthKM)
+	}
+	return table
+}
+
+func generatorTable(gen fraes.Generator, report fraes.TheoryCheckReport) dataTable {
+	table := dataTable{
+		Name: gen.Name(),
+		Columns: []string{"iteration", "points", "measured_km", "theory_km", "error_km", "error_pct",
+			"generator", "length_factor", "theoretical_dimension", "base_points", "base_length_km"},
+	}
+	for _, sample := range report.Samples {
+		table.addRow(sample.Iteration, sample.PointsCount, sample.MeasuredLengthKM, sample.TheoreticalKM, sample.ErrorKM, sample.ErrorPercent,
+			gen.Name(), gen.TheoreticalLengthFactor(), gen.TheoreticalDimension(), report.BasePoints, report.BaseLengthKM)
+	}
+	return table
+}
+
+func organicTable(name string, report fraes.OrganicReport) dataTable {
+	table := dataTable{
+		Name: name,
+		Columns: []string{"iteration", "points", "length_km", "growth_km", "ratio_to_base",
+			"seed", "angle_jitter_deg", "height_jitter_pct", "base_points", "base_length_km"},
+	}
+	for _, sample := range report.Samples {
+		table.addRow(sample.Iteration, sample.PointsCount, sample.LengthKM, optional(sample.GrowthKM, sample.Iteration > 0), sample.RatioToBase,
+			report.Options.Seed, report.Options.AngleJitterDeg, report.Options.HeightJitterPct*100, report.BasePoints, report.BaseLengthKM)
+	}
+	return table
+}
+
+// fbmTable carries the box-counting D next to the target 2 − H, NA where
+// the estimate is invalid.
+func fbmTable(report fbm.Report, dimensions []float64) dataTable {
+	table := dataTable{
+		Name: "fbm",
+		Columns: []string{"iteration", "points", "length_km", "growth_km", "ratio_to_base", "dimension", "target_dimension",
+			"seed", "hurst", "amplitude", "base_points", "base_length_km"},
+	}
+	target := fbm.TargetDimension(report.Options.Hurst)
+	for _, sample := range report.Samples {
+		dimension := dimensions[sample.Iteration]
+		table.addRow(sample.Iteration, sample.PointsCount, sample.LengthKM, optional(sample.GrowthKM, sample.Iteration > 0), sample.RatioToBase,
+			optional(dimension, !math.IsNaN(dimension)), target,
+			report.Options.Seed, report.Options.Hurst, report.Options.Amplitude, report.BasePoints, report.BaseLengthKM)
+	}
+	return table
+}
+
+func paradoxTable(report paradox.Report, proj fraes.Projector) dataTable {
+	table := dataTable{
+		Name:    "paradox",
+		Columns: []string{"level", "points", "segments", "mean_step_km", "length_km", "growth_km", "growth_ratio", "erosion_strength_m", "seed", "projection"},
+	}
+	for _, level := range report.Levels {
+		table.addRow(level.Level, level.Points, level.Segments, level.MeanStepKM, level.LengthKM,
+			optional(level.GrowthKM, level.Level > 0), optional(level.GrowthRatio, level.Level > 0), report.ErosionStrength, report.Seed, projectionName(proj))
+	}
+	return table
+}
+
+func dimensionTable(assessment dimensionAssessment) dataTable {
+	table := dataTable{
+		Name: "dimension",
+		Columns: []string{"iteration", "points", "length_km", "dimension", "ci_low", "ci_high", "std_error", "scales", "r_squared", "spread", "delta_prev", "stable", "valid",
+			"seed", "angle_jitter_deg", "height_jitter_pct", "projection"},
+	}
+	opts := assessment.Options
+	prev := -1
+	for i, result := range assessment.Iterations {
+		analysis := result.Analysis
+		var delta any
+		if analysis.Valid && prev >= 0 && prev == i-1 {
+			delta = analysis.Dimension - assessment.Iterations[prev].Analysis.Dimension
+		}
+		if analysis.Valid {
+			prev = i
+		}
+		interval := result.Interval
+		table.addRow(result.Iteration, result.Points, result.LengthKM,
+			optional(analysis.Dimension, analysis.Valid), optional(interval.Lower, interval.Valid), optional(interval.Upper, interval.Valid),
+			optional(interval.StdError, interval.Valid), len(analysis.Samples),
+			optional(analysis.RegressionRSquared, analysis.Valid), optional(analysis.StabilitySpread, analysis.Valid),
+			delta, analysis.Valid && analysis.StableAcrossScales, analysis.Valid,
+			opts.Seed, opts.AngleJitterDeg, opts.HeightJitterPct*100, projectionName(assessment.Projection))
+	}
+	return table
+}
+
+// dimensionEstimatorsTable is long: one row per iteration and estimator, so
+// ensembleTable has one row per member and iteration or step, so the
+// spread can be recomputed from the seeds.
+func ensembleTable(report ensembleReport) dataTable {
+	table := dataTable{
+		Name:    report.Name + "-ensemble",
+		Columns: []string{"member", "seed", report.Axis},
+	}
+	if len(report.Years) > 0 {
+		table.Columns = append(table.Columns, "year")
+	}
+	table.Columns = append(table.Columns, "length_km", "area_km2", "dimension", "projection")
+	for i, m := range report.Members {
+		for step, sample := range m.Samples {
+			row := []any{i, m.Seed, step}
+			if len(report.Years) > 0 {
+				row = append(row, report.Years[step])
+			}
+			row = append(row, sample.LengthKM, sample.AreaKM2, optional(sample.Dimension, !math.IsNaN(sample.Dimension)), projectionName(report.Projection))
+			table.addRow(row...)
+		}
+	}
+	return table
+}
+
+// sweepTable has one row per parameter combination; parameter columns use
+// the flag names with underscores.
+func sweepTable(report sweepReport) dataTable {
+	table := dataTable{Name: "sweep", Columns: []string{"run"}}
+	for _, p := range report.Header.Parameters {
+		table.Columns = append(table.Columns, strings.ReplaceAll(p.Name, "-", "_"))
+	}
+	table.Columns = append(table.Columns, "length_km", "length_growth", "area_km2", "dimension", "pipeline", "seed", "projection")
+	for _, result := range report.Results {
+		row := []any{result.Index}
+		for _, v := range result.Values {
+			row = append(row, v)
+		}
+		row = append(row, result.LengthKM, result.LengthGrowth, result.AreaKM2,
+			optional(result.Dimension, result.DimensionValid), report.Header.Pipeline, report.Header.Seed, projectionName(report.Projection))
+		table.addRow(row...)
+	}
+	return table
+}
+
+// calibrationTable has one row per reference iteration, grid orientation and
+// estimator.
+func calibrationTable(result calibration.Result) dataTable {
+	table := dataTable{
+		Name:    "calibration",
+		Columns: []string{"reference", "family", "true_dimension", "iteration", "points", "rotation_deg", "estimator", "dimension", "error", "r_squared", "valid", "seed"},
+	}
+	for _, e := range result.Estimates {
+		table.addRow(e.Reference, e.Family, e.TrueDimension, e.Iteration, e.Points, e.RotationDeg, e.Estimator,
+			optional(e.Dimension, e.Valid), optional(e.Error, e.Valid), optional(e.RSquared, e.Valid), e.Valid, result.Options.Seed)
+	}
+	return table
+}
+
+// calibrationSummaryTable pools the orientations: one row per reference,
+// estimator and iteration, with the convergence iterations repeated on
+// every row of the pair; 0 means never.
+func calibrationSummaryTable(result calibration.Result) dataTable {
+	table := dataTable{
+		Name:    "calibration-summary",
+		Columns: []string{"reference", "family", "true_dimension", "estimator", "iteration", "points", "valid", "total", "mean", "std_dev", "bias", "rmse", "converged_at", "settled_at", "tolerance"},
+	}
+	for _, s := range result.Summaries {
+		for _, level := range s.Levels {
+			ok := level.Valid > 0
+			table.addRow(s.Reference, s.Family, s.TrueDimension, s.Estimator, level.Iteration, level.Points, level.Valid, level.Total,
+				optional(level.Mean, ok), optional(level.StdDev, ok), optional(level.Bias, ok), optional(level.RMSE, ok),
+				s.ConvergedAt, s.SettledAt, result.Options.Tolerance)
+		}
+	}
+	return table
+}
+
+// fitTable has one row per model run of every seed, in the order the
+// search made them; best marks the best run of the seed.
+func fitTable(result fit.Result) dataTable {
+	table := dataTable{Name: "fit", Columns: []string{"seed", "run"}}
+	for _, p := range result.Options.Parameters {
+		table.Columns = append(table.Columns, strings.ReplaceAll(p.Name, "-", "_"))
+	}
+	table.Columns = append(table.Columns, "points", "length_km", "dimension", "richardson_dimension", "sinuosity",
+		"misfit_dimension", "misfit_richardson", "rulers", "misfit_sinuosity", "misfit", "best", "method")
+	finite := func(v float64) any { return optional(v, !math.IsNaN(v) && !math.IsInf(v, 0)) }
+	for _, f := range result.Fits {
+		for _, e := range f.Evaluations {
+			row := []any{e.Seed, e.Index}
+			for _, v := range e.Values {
+				row = append(row, v)
+			}
+			sig, m := e.Signature, e.Misfit
+			row = append(row, sig.Points, sig.LengthKM, finite(sig.Dimension), finite(sig.RichardsonDimension), finite(sig.Sinuosity),
+				finite(m.Dimension), finite(m.Richardson), m.Rulers, finite(m.Sinuosity), finite(m.Total), e.Index == f.Best.Index, result.Options.Method)
+			table.addRow(row...)
+		}
+	}
+	return table
+}
+
+// estimators compare by grouping on iteration.
+func dimensionEstimatorsTable(assessment dimensionAssessment) dataTable {
+	table := dataTable{
+		Name:    "dimension-estimators",
+		Columns: []string{"iteration", "points", "length_km", "estimator", "dimension", "scales", "r_squared", "spread", "stable", "valid", "seed", "projection"},
+	}
+	for _, result := range assessment.Iterations {
+		for _, estimate := range result.Estimates {
+			table.addRow(result.Iteration, result.Points, result.LengthKM, estimate.Estimator,
+				optional(estimate.Dimension, estimate.Valid), estimate.SampleCount,
+				optional(estimate.RegressionRSquared, estimate.Valid), optional(estimate.StabilitySpread, estimate.Valid),
+				estimate.Valid && estimate.StableAcrossScales, estimate.Valid, assessment.Options.Seed, projectionName(assessment.Projection))
+		}
+	}
+	return table
+}
+
+// dimensionSpectrumTable has one row per iteration and q of the multifractal
+// spectrum.
+func dimensionSpectrumTable(assessment dimensionAssessment) dataTable {
+	table := dataTable{
+		Name:    "dimension-spectrum",
+		Columns: []string{"iteration", "q", "tau", "dq", "alpha", "f_alpha", "r_squared", "spread", "stable", "valid", "seed", "projection"},
+	}
+	for _, result := range assessment.Iterations {
+		if result.Multifractal == nil {
+			continue
+		}
+		for _, point := range result.Multifractal.Spectrum {
+			valid := result.Multifractal.Valid && point.Valid
+			table.addRow(result.Iteration, point.Q, optional(point.Tau, valid), optional(point.Dq, valid),
+				optional(point.Alpha, valid), optional(point.FAlpha, valid), optional(point.RegressionRSquared, valid),
+				optional(point.StabilitySpread, valid), valid && point.StableAcrossScales, valid, assessment.Options.Seed, projectionName(assessment.Projection))
+		}
+	}
+	return table
+}
+
+// erosionTable has one row per snapshot. area_km2 uses the --area measure and
+// planar_area_km2 keeps the local-grid area for comparison. Wave, scenario and
+// sediment columns appear only when the run produced them; step 0 leaves them
+// NA.
+func erosionTable(series erosionSeries) dataTable {
+	table := dataTable{
+		Name:    "erosion",
+		Columns: []string{"step", "points", "length_km", "area_km2", "area_method", "planar_area_km2", "model", "strength_m", "seed", "projection"},
+	}
+	wave := series.Model == erosionModelWave
+	if series.Scenario != nil {
+		table.Columns = append(table.Columns, "year", "from_year", "background_m", "storms", "storm_m", "max_storm_m", "sea_level_m", "bruun_m", "total_m")
+	}
+	if wave {
+		table.Columns = append(table.Columns, "mean_fetch_km", "mean_exposure", "exposed_share", "mean_retreat_m", "max_retreat_m",
+			"headlands", "headland_retreat_m", "bays", "bay_retreat_m")
+	}
+	if series.Percolation != nil {
+		table.Columns = append(table.Columns, "sweeps", "sea_force", "eroded_cells", "coast_cells", "dimension")
+	}
+	if len(series.Sediment) > 0 {
+		table.Columns = append(table.Columns, "eroded_m3", "deposited_m3", "exported_m3", "net_m3", "gross_drift_m3", "net_drift_m3",
+			"accreting_points", "retreating_points", "sediment_rate_m3")
+	}
+
+	for step, state := range series.Snapshots {
+		row := []any{step, len(state), fraes.PolylineLength(state), series.areaKM2(state), areaMethodName(series.Area), fraes.Area(state), series.Model, series.Strength, series.Seed, projectionName(series.Projection)}
+		if series.Scenario != nil {
+			if step > 0 && step <= len(series.Timeline) {
+				forcing := series.Timeline[step-1]
+				row = append(row, forcing.ToYear, forcing.FromYear, forcing.BackgroundM, forcing.Storms, forcing.StormM, forcing.MaxStormM,
+					forcing.SeaLevelM, forcing.BruunM, forcing.TotalM)
+			} else {
+				row = append(row, series.Scenario.StartYear, nil, nil, nil, nil, nil, nil, nil, nil)
+			}
+		}
+		if wave {
+			if step > 0 && step <= len(series.WaveSteps) {
+				stats := series.WaveSteps[step-1]
+				row = append(row, stats.MeanFetchKM, stats.MeanExposure, stats.ExposedShare, stats.MeanRetreatM, stats.MaxRetreatM,
+					stats.Headlands, stats.HeadlandRetreatM, stats.Bays, stats.BayRetreatM)
+			} else {
+				row = append(row, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			}
+		}
+		if run := series.Percolation; run != nil {
+			var dimension any
+			if d := snapshotDimensionForStep(series, step); d != nil {
+				dimension = *d
+			}
+			if step > 0 && step <= len(run.Steps) {
+				stats := run.Steps[step-1]
+				row = append(row, stats.Sweeps, stats.Force, stats.ErodedCells, stats.CoastCells, dimension)
+			} else {
+				row = append(row, 0, series.PercolationOptions.Force, 0, nil, dimension)
+			}
+		}
+		if len(series.Sediment) > 0 {
+			if step > 0 && step <= len(series.Sediment) {
+				budget := series.Sediment[step-1]
+				row = append(row, budget.ErodedM3, budget.DepositedM3, budget.ExportedM3, budget.NetM3, budget.GrossDriftM3, budget.NetDriftM3,
+					budget.AccretingPoints, budget.RetreatingPoints, series.SedimentRate)
+			} else {
+				row = append(row, nil, nil, nil, nil, nil, nil, nil, nil, series.SedimentRate)
+			}
+		}
+		table.addRow(row...)
+	}
+	return table
+}
+
+func lithologyTable(series erosionSeries) dataTable {
+	table := dataTable{
+		Name:    "erosion-lithology",
+		Columns: []string{"rock", "label", "resistance", "points", "length_km", "share_of_length"},
+	}
+	for _, summary := range series.Rocks.Summaries(series.Snapshots[0]) {
+		table.addRow(summary.Rock.Name, summary.Rock.Label, summary.Rock.Resistance, summary.Points, summary.LengthKM, summary.ShareOfLen)
+	}
+	return table
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"coastal-geometry/internal/domain/simulations/paradox"
+	"coastal-geometry/internal/domain/simulations/percolation"
+	"coastal-geometry/internal/domain/simulations/scenario"
+	"coastal-geometry/pkg/fraes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func readDelimited(t *testing.T, filename string, comma rune) [][]string {
+	t.Helper()
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("expected table file: %v", err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = comma
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("invalid table: %v", err)
+	}
+	return records
+}
+
+func TestWriteDataTableFormats(t *testing.T) {
+	dir := t.TempDir()
+	table := paradoxTable(paradox.Report{
+		ErosionStrength: 25,
+		Seed:            7,
+		Levels: []paradox.Level{
+			{Level: 0, Points: 3, Segments: 2, MeanStepKM: 50, LengthKM: 100},
+			{Level: 1, Points: 9, Segments: 8, MeanStepKM: 16.5, LengthKM: 132, GrowthKM: 32, GrowthRatio: 1.32},
+		},
+	}, nil)
+
+	if err := writeDataTable(table, dir, exportContext{Format: formatCSV}); err != nil {
+		t.Fatalf("writeDataTable csv returned error: %v", err)
+	}
+	records := readDelimited(t, filepath.Join(dir, "paradox.csv"), ',')
+	if len(records) != 3 || !slices.Equal(records[0], table.Columns) {
+		t.Fatalf("expected header and 2 rows, got %v", records)
+	}
+	if got := records[1]; got[5] != missingValue || got[8] != "7" {
+		t.Fatalf("expected NA growth and the seed at level 0, got %v", got)
+	}
+	if got := records[2]; got[3] != "16.5" || got[6] != "1.32" {
+		t.Fatalf("expected plain decimal numbers, got %v", got)
+	}
+
+	if err := writeDataTable(table, dir, exportContext{Format: formatTSV}); err != nil {
+		t.Fatalf("writeDataTable tsv returned error: %v", err)
+	}
+	if records := readDelimited(t, filepath.Join(dir, "paradox.tsv"), '\t'); len(records) != 3 || len(records[0]) != len(table.Columns) {
+		t.Fatalf("expected a tab separated table, got %v", records)
+	}
+
+	if err := writeDataTable(table, dir, exportContext{Format: formatJSON}); err != nil {
+		t.Fatalf("writeDataTable json returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "paradox.json"))
+	if err != nil {
+		t.Fatalf("expected json table: %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(data, &rows); err != nil {
+		t.Fatalf("invalid json table: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["growth_km"] != nil || rows[1]["length_km"] != 132.0 {
+		t.Fatalf("unexpected json rows %v", rows)
+	}
+	last := -1
+	for _, column := range table.Columns {
+		at := bytes.Index(data, []byte(`"`+column+`":`))
+		if at <= last {
+			t.Fatalf("expected json keys in column order %v, %q is out of place", table.Columns, column)
+		}
+		last = at
+	}
+
+	if err := writeDataTable(table, filepath.Join(dir, "console"), exportContext{Format: formatTable}); err != nil {
+		t.Fatalf("writeDataTable table returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "console")); !os.IsNotExist(err) {
+		t.Fatal("expected the console format to write nothing")
+	}
+}
+
+func TestErosionTableAddsScenarioColumns(t *testing.T) {
+	base := []fraes.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	sc, err := scenario.Parse([]byte(`{"start_year": 2025, "end_year": 2035, "step_years": 5, "background_retreat_m_per_year": 1}`))
+	if err != nil {
+		t.Fatalf("unexpected scenario error: %v", err)
+	}
+	area, err := fraes.NewAreaMeasure(fraes.AreaEllipsoidal)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	series, err := simulateErosion(&App{
+		Config:    config{Command: cmdErosion, ErosionModel: erosionModelGaussian, Seed: 3},
+		ModelBase: base,
+		Scenario:  &sc,
+		Area:      area,
+	})
+	if err != nil {
+		t.Fatalf("simulateErosion returned error: %v", err)
+	}
+
+	table := erosionTable(series)
+	if len(table.Rows) != 3 {
+		t.Fatalf("expected a row per snapshot, got %d", len(table.Rows))
+	}
+	year := slices.Index(table.Columns, "year")
+	total := slices.Index(table.Columns, "total_m")
+	if year < 0 || total < 0 || slices.Contains(table.Columns, "mean_fetch_km") {
+		t.Fatalf("expected scenario columns only, got %v", table.Columns)
+	}
+	if table.Rows[0][year] != 2025 || table.Rows[0][total] != nil {
+		t.Fatalf("expected the initial row at the start year without forcing, got %v", table.Rows[0])
+	}
+	if table.Rows[2][year] != 2035 || table.Rows[2][total] != 5.0 {
+		t.Fatalf("expected 5 m of retreat by 2035, got %v", table.Rows[2])
+	}
+	method := slices.Index(table.Columns, "area_method")
+	if method < 0 || table.Rows[0][method] != "ellipsoidal" || !slices.Contains(table.Columns, "planar_area_km2") {
+		t.Fatalf("expected ellipsoidal area with a planar comparison, got %v / %v", table.Columns, table.Rows[0])
+	}
+	for _, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			t.Fatalf("row width %d does not match %d columns", len(row), len(table.Columns))
+		}
+	}
+}
+
+func TestErosionTableTracksPercolationDimension(t *testing.T) {
+	base := []fraes.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	series, err := simulateErosion(&App{
+		Config: config{
+			Command: cmdErosion, ErosionModel: erosionModelPercolation, Steps: 2, Seed: 5,
+			SeaForce: percolation.DefaultForce, SeaDamping: percolation.DefaultDamping, GridCells: 120,
+		},
+		ModelBase: base,
+	})
+	if err != nil {
+		t.Fatalf("simulateErosion returned error: %v", err)
+	}
+	if series.Percolation == nil || len(series.Snapshots) != 3 || series.Strength != 0 {
+		t.Fatalf("expected a percolation run of 2 steps, got %d snapshots", len(series.Snapshots))
+	}
+
+	// Without tracked dimensions the column stays empty instead of failing.
+	table := erosionTable(series)
+	dimension := slices.Index(table.Columns, "dimension")
+	sweeps := slices.Index(table.Columns, "sweeps")
+	if dimension < 0 || sweeps < 0 || table.Rows[2][dimension] != nil {
+		t.Fatalf("expected percolation columns without D, got %v / %v", table.Columns, table.Rows[2])
+	}
+
+	series.Dimensions = snapshotDimensions(series)
+	table = erosionTable(series)
+	if table.Rows[2][sweeps] != series.Percolation.Sweeps || table.Rows[0][sweeps] != 0 {
+		t.Fatalf("expected the last step to end on the stable coast, got %v", table.Rows)
+	}
+	if d, ok := table.Rows[2][dimension].(float64); !ok || d <= series.Dimensions[0] {
+		t.Fatalf("expected the eroded coast to be rougher than the initial one, got %v", table.Rows)
+	}
+	for _, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			t.Fatalf("row width %d does not match %d columns", len(row), len(table.Columns))
+		}
+	}
+}
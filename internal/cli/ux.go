@@ -12,14 +12,28 @@ func canonicalCommandPath(command string) string {
 		return cmdSource
 	case cmdCoastline:
 		return cmdReal + " " + cmdCoastline
+	case cmdRichardson:
+		return cmdReal + " " + cmdRichardson
 	case cmdParadox:
 		return cmdModel + " " + cmdParadox
 	case cmdKoch:
 		return cmdModel + " " + cmdKoch
 	case cmdKochOrganic:
 		return cmdModel + " " + cmdKochOrganic
+	case cmdFBM:
+		return cmdModel + " " + cmdFBM
+	case cmdGenerate:
+		return cmdModel + " " + cmdGenerate
 	case cmdDimension:
 		return cmdModel + " " + cmdDimension
+	case cmdErosion:
+		return cmdModel + " " + cmdErosion
+	case cmdSweep:
+		return cmdModel + " " + cmdSweep
+	case cmdCalibrate:
+		return cmdModel + " " + cmdCalibrate
+	case cmdFit:
+		return cmdModel + " " + cmdFit
 	default:
 		return command
 	}
@@ -27,7 +41,7 @@ func canonicalCommandPath(command string) string {
 
 func legacyAlias(command string) string {
 	switch command {
-	case cmdCoastline, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension:
+	case cmdCoastline, cmdParadox, cmdKoch, cmdKochOrganic, cmdDimension, cmdErosion:
 		return command
 	default:
 		return ""
@@ -48,6 +62,12 @@ func getCommandUX(command string) commandUX {
 			Summary:     "выводит геометрию и геодезические метрики для самой загруженной береговой линии",
 			RuntimeNote: "показанная длина и `coastline.svg` соответствуют загруженной береговой линии без синтетических преобразований",
 		}
+	case cmdRichardson:
+		return commandUX{
+			Mode:        "анализ реальных данных",
+			Summary:     "измеряет загруженную береговую линию циркулем с уменьшающимся шагом и оценивает размерность D = 1 - наклон log L(ε)",
+			RuntimeNote: "обход циркулем выполняется по полной загруженной полилинии без синтетических деталей; `richardson.svg` рендерит упрощённую копию только для отображения",
+		}
 	case cmdParadox:
 		return commandUX{
 			Mode:        "синтетическая демонстрация",
@@ -66,12 +86,48 @@ func getCommandUX(command string) commandUX {
 			Summary:     "использует загруженную береговую линию как базовую полилинию для organic-фрактальной модели",
 			RuntimeNote: "итерация 0 соответствует загруженной береговой линии; последующие итерации синтетические и настраиваются jitter-параметрами",
 		}
+	case cmdFBM:
+		return commandUX{
+			Mode:        "синтетическая демонстрация",
+			Summary:     "смещает сегменты загруженной береговой линии вдоль нормалей фрактальным броуновским движением с показателем Хёрста H и сверяет box-counting размерность с целью D = 2 − H",
+			RuntimeNote: "итерация 0 соответствует упрощённой базе модели; каждая итерация вдвое дробит сегменты, и на масштабах меньше сегмента базы линия — график fBm с известной размерностью",
+		}
+	case cmdGenerate:
+		return commandUX{
+			Mode:        "синтетическая демонстрация",
+			Summary:     "строит детерминированную фрактальную кривую выбранного генератора (Кох, квадратичные Кохи, сосиска Минковского, Чезаро, Леви, остров Госпера) от загруженной береговой линии и сверяет длину и размерность с теорией",
+			RuntimeNote: "итерация 0 соответствует упрощённой базе модели; каждая итерация заменяет сегменты мотивом генератора в плоскости проекции, поэтому длина растёт в известное число раз",
+		}
 	case cmdDimension:
 		return commandUX{
 			Mode:        "синтетическая демонстрация",
 			Summary:     "оценивает box-counting размерность на синтетических organic-итерациях, построенных от загруженной береговой линии",
 			RuntimeNote: "диагностика размерности относится к сгенерированной organic-модели, а не напрямую к сырой геометрии береговой линии",
 		}
+	case cmdErosion:
+		return commandUX{
+			Mode:        "синтетическая демонстрация",
+			Summary:     "пошагово размывает загруженную береговую линию гауссовским шумом или волновой моделью, в которой открытые мысы отступают быстрее бухт",
+			RuntimeNote: "шаг 0 соответствует загруженной береговой линии; последующие шаги модельные, волновая модель считает береговую линию контуром замкнутого моря, а острова — препятствиями для волн",
+		}
+	case cmdSweep:
+		return commandUX{
+			Mode:        "синтетическая демонстрация",
+			Summary:     "прогоняет organic- или erosion-модель по сетке или латинскому гиперкубу параметров и строит тепловые карты D и роста длины",
+			RuntimeNote: "все прогоны используют один seed и загруженную береговую линию как базу; готовые прогоны пишутся в журнал `sweep.jsonl`, и прерванный sweep продолжается с места остановки",
+		}
+	case cmdCalibrate:
+		return commandUX{
+			Mode:        "калибровка методов",
+			Summary:     "прогоняет все оценщики размерности по эталонным кривым известной размерности (генераторы Коха и fBm) на разных итерациях и ориентациях сетки и сообщает смещение, RMSE и итерацию сходимости",
+			RuntimeNote: "береговая линия не загружается: эталоны растут из отрезка 44° с. ш., 30–32° в. д. в равновеликой проекции; результат показывает погрешность самих оценщиков, а не свойства побережья",
+		}
+	case cmdFit:
+		return commandUX{
+			Mode:        "обратное моделирование",
+			Summary:     "подбирает параметры organic-модели Коха и эрозии, при которых box-counting D, кривая Ричардсона и извилистость модели ближе всего к загруженной береговой линии, и оценивает их разброс по seed",
+			RuntimeNote: "цель измеряется на полной загруженной линии, модель растёт от её упрощённой базы; подобранные параметры описывают статистику формы, а не историю побережья, а разброс по seed показывает, насколько они определены",
+		}
 	case cmdAll:
 		return commandUX{
 			Mode:        "смешанный сценарий",
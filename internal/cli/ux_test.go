@@ -9,10 +9,17 @@ func TestGetCommandUX(t *testing.T) {
 	}{
 		{command: cmdSource, mode: "проверка источника данных"},
 		{command: cmdCoastline, mode: "анализ реальных данных"},
+		{command: cmdRichardson, mode: "анализ реальных данных"},
 		{command: cmdParadox, mode: "синтетическая демонстрация"},
 		{command: cmdKoch, mode: "синтетическая демонстрация"},
 		{command: cmdKochOrganic, mode: "синтетическая демонстрация"},
+		{command: cmdFBM, mode: "синтетическая демонстрация"},
+		{command: cmdGenerate, mode: "синтетическая демонстрация"},
 		{command: cmdDimension, mode: "синтетическая демонстрация"},
+		{command: cmdErosion, mode: "синтетическая демонстрация"},
+		{command: cmdSweep, mode: "синтетическая демонстрация"},
+		{command: cmdCalibrate, mode: "калибровка методов"},
+		{command: cmdFit, mode: "обратное моделирование"},
 		{command: cmdAll, mode: "смешанный сценарий"},
 	}
 
@@ -0,0 +1,202 @@
+package coastline
+
+import (
+	"fmt"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+type RingRole string
+
+const (
+	RingOuter RingRole = "outer"
+	RingInner RingRole = "inner"
+	RingLine  RingRole = "line"
+)
+
+// Ring is one connected shore sequence. Outer and inner rings come from
+// polygons and are closed; line rings come from line strings or from rings
+// clipped by the target bounds.
+type Ring struct {
+	Name   string
+	Role   RingRole
+	Points []geometry.LatLon
+	Main   bool
+}
+
+// Part groups the rings of one source polygon or line geometry together with
+// the properties of the feature it came from.
+type Part struct {
+	Name       string
+	Properties map[string]any
+	Rings      []Ring
+}
+
+// Coastline keeps every ring of the loaded dataset. The main ring is the
+// longest one and is the polyline used by single-line analyses.
+type Coastline struct {
+	Parts []Part
+}
+
+type PartSummary struct {
+	Name       string
+	RingCount  int
+	PointCount int
+	LengthKM   float64
+	AreaKM2    float64
+}
+
+func NewLineCoastline(name string, points []geometry.LatLon) Coastline {
+	return Coastline{
+		Parts: []Part{
+			{
+				Name: name,
+				Rings: []Ring{
+					{Name: name, Role: RingLine, Points: points, Main: true},
+				},
+			},
+		},
+	}
+}
+
+func (c Coastline) Rings() []Ring {
+	var rings []Ring
+	for _, part := range c.Parts {
+		rings = append(rings, part.Rings...)
+	}
+	return rings
+}
+
+func (c Coastline) RingCount() int {
+	count := 0
+	for _, part := range c.Parts {
+		count += len(part.Rings)
+	}
+	return count
+}
+
+func (c Coastline) PointCount() int {
+	count := 0
+	for _, part := range c.Parts {
+		count += part.PointCount()
+	}
+	return count
+}
+
+func (c Coastline) MainPoints() []geometry.LatLon {
+	for _, part := range c.Parts {
+		for _, ring := range part.Rings {
+			if ring.Main {
+				return ring.Points
+			}
+		}
+	}
+	return nil
+}
+
+// RingPoints returns the point sequences of all rings, main ring first.
+func (c Coastline) RingPoints() [][]geometry.LatLon {
+	sequences := make([][]geometry.LatLon, 0, c.RingCount())
+	if main := c.MainPoints(); len(main) > 0 {
+		sequences = append(sequences, main)
+	}
+	for _, part := range c.Parts {
+		for _, ring := range part.Rings {
+			if !ring.Main {
+				sequences = append(sequences, ring.Points)
+			}
+		}
+	}
+	return sequences
+}
+
+// LengthKM sums the part lengths measured with distance; nil means
+// haversine.
+func (c Coastline) LengthKM(distance geometry.Distance) float64 {
+	total := 0.0
+	for _, part := range c.Parts {
+		total += part.LengthKM(distance)
+	}
+	return total
+}
+
+// AreaKM2 sums the part areas measured with area; nil means planar.
+func (c Coastline) AreaKM2(area geometry.AreaMeasure) float64 {
+	total := 0.0
+	for _, part := range c.Parts {
+		total += part.AreaKM2(area)
+	}
+	return total
+}
+
+// Summaries measures every part with distance and area; nil means haversine
+// and planar.
+func (c Coastline) Summaries(distance geometry.Distance, area geometry.AreaMeasure) []PartSummary {
+	summaries := make([]PartSummary, 0, len(c.Parts))
+	for _, part := range c.Parts {
+		summaries = append(summaries, PartSummary{
+			Name:       part.Name,
+			RingCount:  len(part.Rings),
+			PointCount: part.PointCount(),
+			LengthKM:   part.LengthKM(distance),
+			AreaKM2:    part.AreaKM2(area),
+		})
+	}
+	return summaries
+}
+
+func (p Part) PointCount() int {
+	count := 0
+	for _, ring := range p.Rings {
+		count += len(ring.Points)
+	}
+	return count
+}
+
+// LengthKM sums the ring lengths measured with distance; nil means
+// haversine.
+func (p Part) LengthKM(distance geometry.Distance) float64 {
+	total := 0.0
+	for _, ring := range p.Rings {
+		total += geometry.PolylineLengthWith(ring.Points, distance)
+	}
+	return total
+}
+
+// AreaKM2 is the area enclosed by the outer ring minus its inner rings,
+// measured with area (nil means planar); parts without an outer ring have no
+// area.
+func (p Part) AreaKM2(area geometry.AreaMeasure) float64 {
+	var outer []geometry.LatLon
+	var holes [][]geometry.LatLon
+	for _, ring := range p.Rings {
+		switch ring.Role {
+		case RingOuter:
+			outer = ring.Points
+		case RingInner:
+			holes = append(holes, ring.Points)
+		}
+	}
+	if len(outer) == 0 {
+		return 0
+	}
+	return geometry.PolygonAreaWith(outer, holes, area)
+}
+
+func ringName(partName string, role RingRole, index int) string {
+	if role == RingOuter {
+		return fmt.Sprintf("%s/%s", partName, role)
+	}
+	return fmt.Sprintf("%s/%s %d", partName, role, index)
+}
+
+func (c *Coastline) setMainPoints(points []geometry.LatLon) {
+	for i := range c.Parts {
+		for j := range c.Parts[i].Rings {
+			if c.Parts[i].Rings[j].Main {
+				c.Parts[i].Rings[j].Points = points
+				return
+			}
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package coastline
 
 import (
-	"bytes"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
@@ -64,7 +63,9 @@ type LoadOptions struct {
 }
 
 type LoadResult struct {
+	// Points is the main ring of Coastline, kept for single-polyline analyses.
 	Points       []geometry.LatLon
+	Coastline    Coastline
 	Validation   ValidationReport
 	Source       string
 	DatasetName  string
@@ -72,17 +73,17 @@ type LoadResult struct {
 }
 
 func LoadFromJSON(filename string) ([]geometry.LatLon, ValidationReport, error) {
-	data, err := os.ReadFile(filename)
+	data, _, err := readLocalPayload(filename)
 	if err != nil {
-		return nil, ValidationReport{}, fmt.Errorf("read coastline json %q: %w", filename, err)
+		return nil, ValidationReport{}, err
 	}
 
-	normalized, report, err := loadCoastlineData(data, filename, GeoBounds{})
+	coast, report, err := loadCoastlineData(data, filename, GeoBounds{})
 	if err != nil {
 		return nil, ValidationReport{}, err
 	}
 
-	return normalized, report, nil
+	return coast.MainPoints(), report, nil
 }
 
 func Load(options LoadOptions) (LoadResult, error) {
@@ -98,7 +99,7 @@ func Load(options LoadOptions) (LoadResult, error) {
 		return LoadResult{}, err
 	}
 
-	points, report, err := loadCoastlineData(payload.Payload, payload.Source, options.RemoteBounds)
+	coast, report, err := loadCoastlineData(payload.Payload, payload.Source, options.RemoteBounds)
 	if err != nil {
 		return LoadResult{}, err
 	}
@@ -109,7 +110,8 @@ func Load(options LoadOptions) (LoadResult, error) {
 	}
 
 	return LoadResult{
-		Points:       points,
+		Points:       coast.MainPoints(),
+		Coastline:    coast,
 		Validation:   report,
 		Source:       payload.Source,
 		DatasetName:  datasetName,
@@ -127,12 +129,12 @@ func fetchCoastlineData(client *http.Client, url string, bounds GeoBounds) ([]ge
 		return nil, err
 	}
 
-	points, _, err := loadCoastlineData(payload, url, bounds)
+	coast, _, err := loadCoastlineData(payload, url, bounds)
 	if err != nil {
 		return nil, err
 	}
 
-	return points, nil
+	return coast.MainPoints(), nil
 }
 
 func fetchCoastlinePayload(client *http.Client, url string) ([]byte, error) {
@@ -169,14 +171,14 @@ func fetchCoastlinePayload(client *http.Client, url string) ([]byte, error) {
 	return body, nil
 }
 
-func loadCachedCoastline(cachePath string, bounds GeoBounds) ([]geometry.LatLon, ValidationReport, error) {
+func loadCachedCoastline(cachePath string, bounds GeoBounds) (Coastline, ValidationReport, error) {
 	if strings.TrimSpace(cachePath) == "" {
-		return nil, ValidationReport{}, fmt.Errorf("cache path is empty")
+		return Coastline{}, ValidationReport{}, fmt.Errorf("cache path is empty")
 	}
 
 	data, err := os.ReadFile(cachePath)
 	if err != nil {
-		return nil, ValidationReport{}, fmt.Errorf("read coastline cache %q: %w", cachePath, err)
+		return Coastline{}, ValidationReport{}, fmt.Errorf("read coastline cache %q: %w", cachePath, err)
 	}
 
 	return loadCoastlineData(data, cachePath, bounds)
@@ -198,18 +200,20 @@ func writeCoastlineCache(cachePath string, data []byte) error {
 	return nil
 }
 
-func loadCoastlineData(data []byte, source string, bounds GeoBounds) ([]geometry.LatLon, ValidationReport, error) {
-	points, err := parseCoastlineData(data, bounds)
+func loadCoastlineData(data []byte, source string, bounds GeoBounds) (Coastline, ValidationReport, error) {
+	coast, err := parseCoastlineData(data, bounds)
 	if err != nil {
-		return nil, ValidationReport{}, fmt.Errorf("parse coastline data %q: %w", source, err)
+		return Coastline{}, ValidationReport{}, fmt.Errorf("parse coastline data %q: %w", source, err)
 	}
 
-	normalized, report, err := normalizeLoadedPoints(points)
+	normalized, report, err := normalizeLoadedPoints(coast.MainPoints())
 	if err != nil {
-		return nil, ValidationReport{}, fmt.Errorf("validate coastline data %q: %w", source, err)
+		return Coastline{}, ValidationReport{}, fmt.Errorf("validate coastline data %q: %w", source, err)
 	}
+	coast.setMainPoints(normalized)
+	coast = validateSecondaryRings(coast, &report)
 
-	return normalized, report, nil
+	return coast, report, nil
 }
 
 func normalizeLoadedPoints(points []geometry.LatLon) ([]geometry.LatLon, ValidationReport, error) {
@@ -250,34 +254,35 @@ func isClosedPolyline(points []geometry.LatLon) bool {
 	return pointKey(points[0]) == pointKey(points[len(points)-1])
 }
 
-func parseCoastlineData(data []byte, bounds GeoBounds) ([]geometry.LatLon, error) {
-	trimmed := bytes.TrimSpace(data)
-	if len(trimmed) == 0 {
-		return nil, fmt.Errorf("empty coastline payload")
+func parseCoastlineData(data []byte, bounds GeoBounds) (Coastline, error) {
+	payload, err := decodePayload(data)
+	if err != nil {
+		return Coastline{}, err
 	}
 
+	trimmed := payload.Data
 	switch trimmed[0] {
 	case '[':
 		var points []geometry.LatLon
 		if err := json.Unmarshal(trimmed, &points); err != nil {
-			return nil, fmt.Errorf("parse point array: %w", err)
+			return Coastline{}, fmt.Errorf("parse point array: %w", err)
 		}
-		return points, nil
+		return NewLineCoastline("points", points), nil
 	case '{':
 		var envelope struct {
 			Type string `json:"type"`
 		}
 		if err := json.Unmarshal(trimmed, &envelope); err != nil {
-			return nil, fmt.Errorf("parse json envelope: %w", err)
+			return Coastline{}, fmt.Errorf("parse json envelope: %w", err)
 		}
 		switch strings.ToLower(envelope.Type) {
 		case "featurecollection", "feature", "polygon", "multipolygon", "linestring", "multilinestring", "geometrycollection":
-			return parseGeoJSONPoints(trimmed, bounds)
+			return parseGeoJSONCoastline(trimmed, bounds)
 		default:
-			return nil, fmt.Errorf("unsupported json object type %q", envelope.Type)
+			return Coastline{}, fmt.Errorf("unsupported json object type %q", envelope.Type)
 		}
 	default:
-		return nil, fmt.Errorf("unsupported coastline payload")
+		return Coastline{}, fmt.Errorf("unsupported coastline payload")
 	}
 }
 
@@ -287,8 +292,9 @@ type geoJSONFeatureCollection struct {
 }
 
 type geoJSONFeature struct {
-	Type     string           `json:"type"`
-	Geometry *geoJSONGeometry `json:"geometry"`
+	Type       string           `json:"type"`
+	Properties map[string]any   `json:"properties"`
+	Geometry   *geoJSONGeometry `json:"geometry"`
 }
 
 type geoJSONGeometry struct {
@@ -297,146 +303,189 @@ type geoJSONGeometry struct {
 	Geometries  []geoJSONGeometry `json:"geometries"`
 }
 
-func parseGeoJSONPoints(data []byte, bounds GeoBounds) ([]geometry.LatLon, error) {
+func parseGeoJSONCoastline(data []byte, bounds GeoBounds) (Coastline, error) {
 	var collection geoJSONFeatureCollection
 	if err := json.Unmarshal(data, &collection); err != nil {
-		return nil, fmt.Errorf("parse geojson root: %w", err)
+		return Coastline{}, fmt.Errorf("parse geojson root: %w", err)
 	}
 
-	var sequences [][]geometry.LatLon
+	var parts []Part
 	switch strings.ToLower(collection.Type) {
 	case "featurecollection":
-		for _, feature := range collection.Features {
+		for i, feature := range collection.Features {
 			if feature.Geometry == nil {
 				continue
 			}
-			paths, err := geometrySequencesFromGeoJSON(*feature.Geometry)
+			featureParts, err := partsFromGeoJSONFeature(feature, i)
 			if err != nil {
-				return nil, err
+				return Coastline{}, err
 			}
-			sequences = append(sequences, paths...)
+			parts = append(parts, featureParts...)
 		}
 	case "feature":
 		var feature geoJSONFeature
 		if err := json.Unmarshal(data, &feature); err != nil {
-			return nil, fmt.Errorf("parse geojson feature: %w", err)
+			return Coastline{}, fmt.Errorf("parse geojson feature: %w", err)
 		}
 		if feature.Geometry == nil {
-			return nil, fmt.Errorf("geojson feature has no geometry")
+			return Coastline{}, fmt.Errorf("geojson feature has no geometry")
 		}
 
-		paths, err := geometrySequencesFromGeoJSON(*feature.Geometry)
+		featureParts, err := partsFromGeoJSONFeature(feature, 0)
 		if err != nil {
-			return nil, err
+			return Coastline{}, err
 		}
-		sequences = append(sequences, paths...)
+		parts = append(parts, featureParts...)
 	default:
 		var geometry geoJSONGeometry
 		if err := json.Unmarshal(data, &geometry); err != nil {
-			return nil, fmt.Errorf("parse geojson geometry: %w", err)
+			return Coastline{}, fmt.Errorf("parse geojson geometry: %w", err)
 		}
 
-		paths, err := geometrySequencesFromGeoJSON(geometry)
+		shapes, err := geometryShapesFromGeoJSON(geometry)
 		if err != nil {
-			return nil, err
+			return Coastline{}, err
 		}
-		sequences = append(sequences, paths...)
+		parts = append(parts, buildParts("geometry", nil, shapes)...)
 	}
 
-	if len(sequences) == 0 {
-		return nil, fmt.Errorf("geojson does not contain coastline geometry")
+	if (Coastline{Parts: parts}).RingCount() == 0 {
+		return Coastline{}, fmt.Errorf("geojson does not contain coastline geometry")
 	}
 
-	filtered := filterGeoJSONSequences(sequences, bounds)
+	filtered := filterGeoJSONParts(parts, bounds)
 	if len(filtered) == 0 {
 		if !bounds.IsZero() {
-			return nil, fmt.Errorf("geojson does not contain coordinates inside target bounds")
+			return Coastline{}, fmt.Errorf("geojson does not contain coordinates inside target bounds")
 		}
-		return nil, fmt.Errorf("geojson does not contain enough coordinates")
+		return Coastline{}, fmt.Errorf("geojson does not contain enough coordinates")
+	}
+
+	coast := Coastline{Parts: filtered}
+	markMainRing(coast)
+	if len(coast.MainPoints()) < 2 {
+		return Coastline{}, fmt.Errorf("geojson sequence does not contain enough coordinates")
 	}
 
-	best := bestSequence(filtered)
-	if len(best) < 2 {
-		return nil, fmt.Errorf("geojson sequence does not contain enough coordinates")
+	return coast, nil
+}
+
+func partsFromGeoJSONFeature(feature geoJSONFeature, index int) ([]Part, error) {
+	shapes, err := geometryShapesFromGeoJSON(*feature.Geometry)
+	if err != nil {
+		return nil, err
 	}
 
-	return best, nil
+	name := propertyString(feature.Properties, "name")
+	if name == "" {
+		name = fmt.Sprintf("feature %d", index+1)
+	}
+	return buildParts(name, feature.Properties, shapes), nil
 }
 
-func geometrySequencesFromGeoJSON(geom geoJSONGeometry) ([][]geometry.LatLon, error) {
+// buildParts names the shapes of one feature; features with several
+// polygons get a "#n" suffix per polygon.
+func buildParts(name string, properties map[string]any, shapes [][]Ring) []Part {
+	parts := make([]Part, 0, len(shapes))
+	for i, rings := range shapes {
+		partName := name
+		if len(shapes) > 1 {
+			partName = fmt.Sprintf("%s #%d", name, i+1)
+		}
+
+		counters := map[RingRole]int{}
+		named := make([]Ring, 0, len(rings))
+		for _, ring := range rings {
+			counters[ring.Role]++
+			ring.Name = ringName(partName, ring.Role, counters[ring.Role])
+			named = append(named, ring)
+		}
+		parts = append(parts, Part{Name: partName, Properties: properties, Rings: named})
+	}
+	return parts
+}
+
+// geometryShapesFromGeoJSON returns one ring list per polygon or line geometry.
+func geometryShapesFromGeoJSON(geom geoJSONGeometry) ([][]Ring, error) {
 	switch strings.ToLower(geom.Type) {
 	case "linestring":
 		points, err := decodeCoordinateSequence(geom.Coordinates)
 		if err != nil {
 			return nil, fmt.Errorf("parse linestring coordinates: %w", err)
 		}
-		return [][]geometry.LatLon{points}, nil
+		return [][]Ring{{{Role: RingLine, Points: points}}}, nil
 	case "multilinestring":
 		var raw []json.RawMessage
 		if err := json.Unmarshal(geom.Coordinates, &raw); err != nil {
 			return nil, fmt.Errorf("parse multilinestring coordinates: %w", err)
 		}
 
-		sequences := make([][]geometry.LatLon, 0, len(raw))
+		rings := make([]Ring, 0, len(raw))
 		for _, item := range raw {
 			points, err := decodeCoordinateSequence(item)
 			if err != nil {
 				return nil, fmt.Errorf("parse multilinestring path: %w", err)
 			}
-			sequences = append(sequences, points)
+			rings = append(rings, Ring{Role: RingLine, Points: points})
 		}
-		return sequences, nil
+		return [][]Ring{rings}, nil
 	case "polygon":
-		var raw []json.RawMessage
-		if err := json.Unmarshal(geom.Coordinates, &raw); err != nil {
-			return nil, fmt.Errorf("parse polygon coordinates: %w", err)
-		}
-
-		sequences := make([][]geometry.LatLon, 0, len(raw))
-		for _, ring := range raw {
-			points, err := decodeCoordinateSequence(ring)
-			if err != nil {
-				return nil, fmt.Errorf("parse polygon ring: %w", err)
-			}
-			sequences = append(sequences, points)
+		rings, err := decodePolygonRings(geom.Coordinates)
+		if err != nil {
+			return nil, fmt.Errorf("parse polygon ring: %w", err)
 		}
-		return sequences, nil
+		return [][]Ring{rings}, nil
 	case "multipolygon":
 		var polygons []json.RawMessage
 		if err := json.Unmarshal(geom.Coordinates, &polygons); err != nil {
 			return nil, fmt.Errorf("parse multipolygon coordinates: %w", err)
 		}
 
-		sequences := make([][]geometry.LatLon, 0, len(polygons))
+		shapes := make([][]Ring, 0, len(polygons))
 		for _, polygon := range polygons {
-			var rings []json.RawMessage
-			if err := json.Unmarshal(polygon, &rings); err != nil {
-				return nil, fmt.Errorf("parse multipolygon rings: %w", err)
-			}
-			for _, ring := range rings {
-				points, err := decodeCoordinateSequence(ring)
-				if err != nil {
-					return nil, fmt.Errorf("parse multipolygon ring: %w", err)
-				}
-				sequences = append(sequences, points)
+			rings, err := decodePolygonRings(polygon)
+			if err != nil {
+				return nil, fmt.Errorf("parse multipolygon ring: %w", err)
 			}
+			shapes = append(shapes, rings)
 		}
-		return sequences, nil
+		return shapes, nil
 	case "geometrycollection":
-		sequences := make([][]geometry.LatLon, 0, len(geom.Geometries))
+		shapes := make([][]Ring, 0, len(geom.Geometries))
 		for _, item := range geom.Geometries {
-			paths, err := geometrySequencesFromGeoJSON(item)
+			itemShapes, err := geometryShapesFromGeoJSON(item)
 			if err != nil {
 				return nil, err
 			}
-			sequences = append(sequences, paths...)
+			shapes = append(shapes, itemShapes...)
 		}
-		return sequences, nil
+		return shapes, nil
 	default:
 		return nil, fmt.Errorf("unsupported geojson geometry type %q", geom.Type)
 	}
 }
 
+func decodePolygonRings(data json.RawMessage) ([]Ring, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	rings := make([]Ring, 0, len(raw))
+	for i, item := range raw {
+		points, err := decodeCoordinateSequence(item)
+		if err != nil {
+			return nil, err
+		}
+		role := RingInner
+		if i == 0 {
+			role = RingOuter
+		}
+		rings = append(rings, Ring{Role: role, Points: points})
+	}
+	return rings, nil
+}
+
 func decodeCoordinateSequence(data json.RawMessage) ([]geometry.LatLon, error) {
 	var raw [][]float64
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -457,46 +506,82 @@ func decodeCoordinateSequence(data json.RawMessage) ([]geometry.LatLon, error) {
 	return points, nil
 }
 
-func filterGeoJSONSequences(sequences [][]geometry.LatLon, bounds GeoBounds) [][]geometry.LatLon {
+func filterGeoJSONParts(parts []Part, bounds GeoBounds) []Part {
+	filtered := make([]Part, 0, len(parts))
+	for _, part := range parts {
+		rings := make([]Ring, 0, len(part.Rings))
+		for _, ring := range part.Rings {
+			rings = append(rings, clipRing(ring, bounds)...)
+		}
+		if len(rings) == 0 {
+			continue
+		}
+		part.Rings = rings
+		filtered = append(filtered, part)
+	}
+	return filtered
+}
+
+// clipRing keeps the runs of a ring inside bounds. A ring cut into pieces is
+// no longer closed, so its pieces become line rings.
+func clipRing(ring Ring, bounds GeoBounds) []Ring {
 	if bounds.IsZero() {
-		return sequences
+		if len(ring.Points) < 2 {
+			return nil
+		}
+		return []Ring{ring}
 	}
 
-	filtered := make([][]geometry.LatLon, 0, len(sequences))
-	for _, sequence := range sequences {
-		var current []geometry.LatLon
-		for _, point := range sequence {
-			if bounds.Contains(point) {
-				current = append(current, point)
-				continue
-			}
-			if len(current) >= 2 {
-				filtered = append(filtered, current)
-			}
-			current = nil
+	var runs [][]geometry.LatLon
+	var current []geometry.LatLon
+	for _, point := range ring.Points {
+		if bounds.Contains(point) {
+			current = append(current, point)
+			continue
 		}
 		if len(current) >= 2 {
-			filtered = append(filtered, current)
+			runs = append(runs, current)
 		}
+		current = nil
+	}
+	if len(current) >= 2 {
+		runs = append(runs, current)
 	}
-	return filtered
-}
 
-func bestSequence(sequences [][]geometry.LatLon) []geometry.LatLon {
-	best := sequences[0]
-	bestLength := geometry.PolylineLength(best)
-	bestPoints := len(best)
+	if len(runs) == 1 && len(runs[0]) == len(ring.Points) {
+		return []Ring{ring}
+	}
 
-	for _, sequence := range sequences[1:] {
-		length := geometry.PolylineLength(sequence)
-		if length > bestLength || (length == bestLength && len(sequence) > bestPoints) {
-			best = sequence
-			bestLength = length
-			bestPoints = len(sequence)
+	pieces := make([]Ring, 0, len(runs))
+	for i, run := range runs {
+		name := ring.Name
+		if len(runs) > 1 {
+			name = fmt.Sprintf("%s (фрагмент %d)", ring.Name, i+1)
 		}
+		pieces = append(pieces, Ring{Name: name, Role: RingLine, Points: run})
 	}
+	return pieces
+}
 
-	return best
+// markMainRing flags the longest ring as the main polyline, preferring more
+// points on equal length.
+func markMainRing(coast Coastline) {
+	bestPart, bestRing := -1, -1
+	bestLength := 0.0
+	bestPoints := 0
+	for i, part := range coast.Parts {
+		for j, ring := range part.Rings {
+			length := geometry.PolylineLength(ring.Points)
+			if bestPart < 0 || length > bestLength || (length == bestLength && len(ring.Points) > bestPoints) {
+				bestPart, bestRing = i, j
+				bestLength = length
+				bestPoints = len(ring.Points)
+			}
+		}
+	}
+	if bestPart >= 0 {
+		coast.Parts[bestPart].Rings[bestRing].Main = true
+	}
 }
 
 func buildDefaultCoastlineGeoJSONURL() string {
@@ -163,3 +163,115 @@ func TestSanityCheckWarningSkippedForUnknownDataset(t *testing.T) {
 		t.Fatalf("expected unchecked sanity result for unknown dataset, got %+v", result)
 	}
 }
+
+func TestParseGeoJSONCoastlineKeepsAllRingsAndParts(t *testing.T) {
+	payload := `{
+		"type": "FeatureCollection",
+		"features": [
+			{
+				"type": "Feature",
+				"properties": {"name": "Mainland"},
+				"geometry": {
+					"type": "Polygon",
+					"coordinates": [
+						[[30.0, 42.0], [36.0, 42.0], [36.0, 46.0], [30.0, 46.0], [30.0, 42.0]],
+						[[32.0, 43.0], [33.0, 43.0], [33.0, 44.0], [32.0, 44.0], [32.0, 43.0]]
+					]
+				}
+			},
+			{
+				"type": "Feature",
+				"properties": {"name": "Islands"},
+				"geometry": {
+					"type": "MultiLineString",
+					"coordinates": [
+						[[37.0, 43.0], [37.5, 43.2]],
+						[[38.0, 43.0], [38.5, 43.2], [38.8, 43.6]]
+					]
+				}
+			}
+		]
+	}`
+
+	coast, report, err := loadCoastlineData([]byte(payload), "test", GeoBounds{})
+	if err != nil {
+		t.Fatalf("loadCoastlineData returned error: %v", err)
+	}
+
+	if len(coast.Parts) != 2 {
+		t.Fatalf("expected 2 parts, got %d", len(coast.Parts))
+	}
+	if coast.RingCount() != 4 {
+		t.Fatalf("expected 4 rings, got %d", coast.RingCount())
+	}
+	if coast.Parts[0].Name != "Mainland" || coast.Parts[0].Properties["name"] != "Mainland" {
+		t.Fatalf("expected feature properties on first part, got %+v", coast.Parts[0])
+	}
+	if !coast.Parts[0].Rings[0].Main || coast.Parts[0].Rings[0].Role != RingOuter {
+		t.Fatalf("expected outer mainland ring to be the main ring, got %+v", coast.Parts[0].Rings[0])
+	}
+	if coast.Parts[0].Rings[1].Role != RingInner || coast.Parts[0].Rings[1].Name != "Mainland/inner 1" {
+		t.Fatalf("unexpected inner ring: %+v", coast.Parts[0].Rings[1])
+	}
+	if len(report.Warnings) == 0 {
+		t.Fatal("expected long-segment warnings for the coarse main ring")
+	}
+
+	karney := geometry.NewKarneyDistance(geometry.WGS84)
+	expectedLength, expectedKarney := 0.0, 0.0
+	for _, ring := range coast.Rings() {
+		expectedLength += geometry.PolylineLength(ring.Points)
+		expectedKarney += geometry.PolylineLengthWith(ring.Points, karney)
+	}
+	if diff := coast.LengthKM(nil) - expectedLength; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("expected aggregate length %.3f, got %.3f", expectedLength, coast.LengthKM(nil))
+	}
+	if diff := coast.LengthKM(karney) - expectedKarney; diff > 1e-9 || diff < -1e-9 || expectedKarney == expectedLength {
+		t.Fatalf("expected aggregate karney length %.3f, got %.3f", expectedKarney, coast.LengthKM(karney))
+	}
+
+	summaries := coast.Summaries(nil, nil)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 part summaries, got %d", len(summaries))
+	}
+	if summaries[0].AreaKM2 >= geometry.Area(coast.Parts[0].Rings[0].Points) {
+		t.Fatalf("expected hole to reduce mainland area, got %.0f km²", summaries[0].AreaKM2)
+	}
+	if summaries[1].AreaKM2 != 0 {
+		t.Fatalf("expected line-only part to have no area, got %.0f km²", summaries[1].AreaKM2)
+	}
+
+	ellipsoidal := geometry.EllipsoidalArea{Ellipsoid: geometry.WGS84}
+	if got := coast.Parts[0].AreaKM2(ellipsoidal); got >= ellipsoidal.Area(coast.Parts[0].Rings[0].Points) || got <= 0 {
+		t.Fatalf("expected hole to reduce ellipsoidal mainland area, got %.0f km²", got)
+	}
+}
+
+func TestLoadDropsBrokenSecondaryRingWithWarning(t *testing.T) {
+	payload := `{
+		"type": "MultiLineString",
+		"coordinates": [
+			[[30.0, 42.0], [31.0, 42.5], [32.0, 43.0], [33.0, 43.2]],
+			[[34.0, 44.0], [34.0, 44.0]]
+		]
+	}`
+
+	coast, report, err := loadCoastlineData([]byte(payload), "test", GeoBounds{})
+	if err != nil {
+		t.Fatalf("loadCoastlineData returned error: %v", err)
+	}
+
+	if coast.RingCount() != 1 {
+		t.Fatalf("expected degenerate ring to be dropped, got %d rings", coast.RingCount())
+	}
+
+	found := false
+	for _, warning := range report.Warnings {
+		if strings.Contains(warning, "отброшено") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected dropped-ring warning, got %+v", report.Warnings)
+	}
+}
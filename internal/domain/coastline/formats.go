@@ -0,0 +1,134 @@
+package coastline
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Source formats reported in SourceMetadata.Format.
+const (
+	FormatPointArray = "point-array"
+	FormatGeoJSON    = "GeoJSON"
+	FormatKML        = "KML"
+	FormatGPX        = "GPX"
+	FormatWKT        = "WKT"
+	FormatShapefile  = "ESRI Shapefile"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// decodedPayload is a source payload in one of the two native forms, a point
+// array or GeoJSON. KML, GPX and WKT are rewritten as a GeoJSON
+// FeatureCollection; Root keeps their original root element or geometry type.
+type decodedPayload struct {
+	Data   []byte
+	Format string
+	Root   string
+}
+
+// decodePayload sniffs the format of data from its first bytes and converts
+// the formats that are not JSON to GeoJSON, so every source shares one parser
+// and the same validation.
+func decodePayload(data []byte) (decodedPayload, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(trimmed) == 0 {
+		return decodedPayload{}, fmt.Errorf("empty coastline payload")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		return decodedPayload{Data: trimmed, Format: FormatPointArray}, nil
+	case '{':
+		return decodedPayload{Data: trimmed, Format: FormatGeoJSON}, nil
+	case '<':
+		root, err := xmlRootName(trimmed)
+		if err != nil {
+			return decodedPayload{}, err
+		}
+		var converted []byte
+		var format string
+		switch strings.ToLower(root) {
+		case "kml":
+			converted, err = kmlToGeoJSON(trimmed)
+			format = FormatKML
+		case "gpx":
+			converted, err = gpxToGeoJSON(trimmed)
+			format = FormatGPX
+		default:
+			return decodedPayload{}, fmt.Errorf("unsupported xml root element <%s> (known: kml, gpx)", root)
+		}
+		if err != nil {
+			return decodedPayload{}, err
+		}
+		return decodedPayload{Data: converted, Format: format, Root: root}, nil
+	}
+
+	if looksLikeWKT(trimmed) {
+		converted, root, err := wktToGeoJSON(string(trimmed))
+		if err != nil {
+			return decodedPayload{}, err
+		}
+		return decodedPayload{Data: converted, Format: FormatWKT, Root: root}, nil
+	}
+	return decodedPayload{}, fmt.Errorf("unsupported coastline payload")
+}
+
+func xmlRootName(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("parse xml root: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func geoJSONFeatureOf(properties map[string]any, geom map[string]any) map[string]any {
+	return map[string]any{
+		"type":       "Feature",
+		"properties": properties,
+		"geometry":   geom,
+	}
+}
+
+func geoJSONGeometryOf(kind string, coordinates any) map[string]any {
+	return map[string]any{"type": kind, "coordinates": coordinates}
+}
+
+// geoJSONGeometryOfAll wraps several geometries of one feature into a
+// GeometryCollection; a single geometry is returned as is.
+func geoJSONGeometryOfAll(geometries []map[string]any) map[string]any {
+	if len(geometries) == 1 {
+		return geometries[0]
+	}
+	return map[string]any{"type": "GeometryCollection", "geometries": geometries}
+}
+
+// featureCollectionJSON encodes features; a non-empty name becomes the
+// collection name that InspectSource reports.
+func featureCollectionJSON(name string, features []map[string]any) ([]byte, error) {
+	if features == nil {
+		features = []map[string]any{}
+	}
+	collection := map[string]any{
+		"type":     "FeatureCollection",
+		"features": features,
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		collection["properties"] = map[string]any{"name": name}
+	}
+	return json.Marshal(collection)
+}
+
+// setProperty stores a trimmed non-empty text value.
+func setProperty(properties map[string]any, key, value string) {
+	if value = strings.TrimSpace(value); value != "" && key != "" {
+		properties[key] = value
+	}
+}
@@ -0,0 +1,161 @@
+package coastline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Береговая съёмка</name>
+    <Folder>
+      <Placemark>
+        <name>Маршрут 1</name>
+        <ExtendedData><Data name="team"><value>север</value></Data></ExtendedData>
+        <LineString><coordinates>
+          30.73,46.48,0 32.49,45.33,0
+          34.10,44.94,0 39.75,43.70,0
+        </coordinates></LineString>
+      </Placemark>
+      <Placemark>
+        <name>Лиман</name>
+        <MultiGeometry>
+          <Polygon>
+            <outerBoundaryIs><LinearRing><coordinates>30,46 31,46 31,47 30,47 30,46</coordinates></LinearRing></outerBoundaryIs>
+            <innerBoundaryIs><LinearRing><coordinates>30.4,46.4 30.6,46.4 30.6,46.6 30.4,46.4</coordinates></LinearRing></innerBoundaryIs>
+          </Polygon>
+          <LineString><coordinates>31, 46 31.5, 46.2</coordinates></LineString>
+        </MultiGeometry>
+      </Placemark>
+      <Placemark><name>Маяк</name><Point><coordinates>33,44</coordinates></Point></Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+const testGPX = "\xEF\xBB\xBF" + `<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <metadata><name>Обход косы</name></metadata>
+  <wpt lat="44.0" lon="33.0"><name>старт</name></wpt>
+  <trk>
+    <name>Коса</name>
+    <trkseg><trkpt lat="46.48" lon="30.73"/><trkpt lat="45.33" lon="32.49"/><trkpt lat="44.94" lon="34.10"/></trkseg>
+    <trkseg><trkpt lat="43.70" lon="39.75"/><trkpt lat="41.65" lon="41.63"/></trkseg>
+  </trk>
+  <rte><name>Обратно</name><rtept lat="44.0" lon="33.0"/><rtept lat="44.5" lon="33.5"/></rte>
+</gpx>`
+
+const testWKT = `SRID=4326;LINESTRING Z (30.73 46.48 0, 32.49 45.33 0, 34.10 44.94 0, 39.75 43.70 0);
+POLYGON ((30 46, 31 46, 31 47, 30 47, 30 46), (30.4 46.4, 30.6 46.4, 30.6 46.6, 30.4 46.4))
+POINT (33 44)
+MULTIPOLYGON EMPTY
+GEOMETRYCOLLECTION (POINT (1 2), MULTILINESTRING ((31 46, 31.5 46.2), (32 45, 32.5 45.2)))
+`
+
+func writeSource(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadSniffsKML(t *testing.T) {
+	// The extension is irrelevant: the format is taken from the content.
+	result, err := Load(LoadOptions{LocalPath: writeSource(t, "walk.txt", testKML)})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if result.DatasetName != "Береговая съёмка" {
+		t.Fatalf("expected the document name as dataset name, got %q", result.DatasetName)
+	}
+	parts := result.Coastline.Parts
+	if len(parts) != 3 {
+		t.Fatalf("expected the line and both members of the multigeometry, got %d parts", len(parts))
+	}
+	if parts[0].Name != "Маршрут 1" || parts[0].Properties["team"] != "север" {
+		t.Fatalf("expected placemark name and extended data, got %q %v", parts[0].Name, parts[0].Properties)
+	}
+	// Geometries of a MultiGeometry come out grouped by kind: lines first.
+	if len(parts[1].Rings[0].Points) != 2 {
+		t.Fatalf("expected spaces after commas to be tolerated, got %+v", parts[1].Rings[0].Points)
+	}
+	if rings := parts[2].Rings; len(rings) != 2 || rings[0].Role != RingOuter || rings[1].Role != RingInner {
+		t.Fatalf("expected polygon with a hole, got %+v", rings)
+	}
+	if len(result.Points) != 4 {
+		t.Fatalf("expected the walk as main line, got %+v", result.Points)
+	}
+}
+
+func TestLoadSniffsGPXTracksAndRoutes(t *testing.T) {
+	result, err := Load(LoadOptions{LocalPath: writeSource(t, "walk.gpx", testGPX)})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	parts := result.Coastline.Parts
+	if len(parts) != 2 || len(parts[0].Rings) != 2 || len(parts[1].Rings) != 1 {
+		t.Fatalf("expected a two-segment track and a route, got %+v", parts)
+	}
+	if parts[0].Name != "Коса" || parts[0].Properties["gpx"] != "trk" || parts[1].Properties["gpx"] != "rte" {
+		t.Fatalf("unexpected track properties %v and %v", parts[0].Properties, parts[1].Properties)
+	}
+	if result.DatasetName != "Обход косы" {
+		t.Fatalf("expected metadata name as dataset name, got %q", result.DatasetName)
+	}
+}
+
+func TestLoadSniffsWKTAndEWKT(t *testing.T) {
+	result, err := Load(LoadOptions{LocalPath: writeSource(t, "export.wkt", testWKT)})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	parts := result.Coastline.Parts
+	if len(parts) != 3 {
+		t.Fatalf("expected line, polygon and multilinestring features, points and empty geometries skipped; got %d", len(parts))
+	}
+	if len(parts[1].Rings) != 2 || parts[1].Rings[1].Role != RingInner {
+		t.Fatalf("expected polygon with a hole, got %+v", parts[1].Rings)
+	}
+	if len(parts[2].Rings) != 2 {
+		t.Fatalf("expected both lines of the collection, got %+v", parts[2].Rings)
+	}
+	if len(result.Points) != 4 {
+		t.Fatalf("expected Z values to be dropped and the line to be main, got %+v", result.Points)
+	}
+
+	if _, err := Load(LoadOptions{LocalPath: writeSource(t, "mercator.wkt", "SRID=3857;LINESTRING (3339584 5465442, 3450000 5500000)")}); err == nil ||
+		!strings.Contains(err.Error(), "srid 3857") {
+		t.Fatalf("expected a projected EWKT to be rejected, got %v", err)
+	}
+}
+
+func TestInspectSourceReportsSniffedFormat(t *testing.T) {
+	cases := []struct {
+		file, content, format, root, ext string
+	}{
+		{"walk.kml", testKML, FormatKML, "kml", ".kml"},
+		{"walk.gpx", testGPX, FormatGPX, "gpx", ".gpx"},
+		{"export.txt", testWKT, FormatWKT, "LINESTRING", ".wkt"},
+	}
+	for _, tc := range cases {
+		path := writeSource(t, tc.file, tc.content)
+		result, err := InspectSource(InspectOptions{LocalPath: path, SnapshotPath: filepath.Dir(path)})
+		if err != nil {
+			t.Fatalf("%s: InspectSource returned error: %v", tc.file, err)
+		}
+		meta := result.Metadata
+		if meta.Format != tc.format || meta.RootType != tc.root || filepath.Ext(result.SnapshotPath) != tc.ext {
+			t.Fatalf("%s: unexpected format %q, root %q, snapshot %q", tc.file, meta.Format, meta.RootType, result.SnapshotPath)
+		}
+		if meta.FeatureCount == 0 || meta.CoastlineRingCount == 0 {
+			t.Fatalf("%s: expected features and rings, got %+v", tc.file, meta)
+		}
+	}
+}
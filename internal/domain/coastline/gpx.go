@@ -0,0 +1,88 @@
+package coastline
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type gpxDocument struct {
+	// Name is the GPX 1.0 document name; GPX 1.1 moved it into metadata.
+	Name     string     `xml:"name"`
+	Metadata string     `xml:"metadata>name"`
+	Tracks   []gpxTrack `xml:"trk"`
+	Routes   []gpxTrack `xml:"rte"`
+}
+
+// gpxTrack is a track with its segments or a route with its points.
+type gpxTrack struct {
+	Name        string `xml:"name"`
+	Description string `xml:"desc"`
+	Type        string `xml:"type"`
+	Segments    []struct {
+		Points []gpxPoint `xml:"trkpt"`
+	} `xml:"trkseg"`
+	RoutePoints []gpxPoint `xml:"rtept"`
+}
+
+type gpxPoint struct {
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+// gpxToGeoJSON turns every track into a LineString or, with several
+// segments, a MultiLineString feature, and every route into a LineString.
+// Waypoints are not shore geometry and are ignored.
+func gpxToGeoJSON(data []byte) ([]byte, error) {
+	var doc gpxDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse gpx: %w", err)
+	}
+
+	var features []map[string]any
+	add := func(track gpxTrack, kind string, paths [][][2]float64) {
+		var lines [][][2]float64
+		for _, path := range paths {
+			if len(path) >= 2 {
+				lines = append(lines, path)
+			}
+		}
+		if len(lines) == 0 {
+			return
+		}
+
+		properties := map[string]any{"gpx": kind}
+		setProperty(properties, "name", track.Name)
+		setProperty(properties, "desc", track.Description)
+		setProperty(properties, "type", track.Type)
+		geom := geoJSONGeometryOf("MultiLineString", lines)
+		if len(lines) == 1 {
+			geom = geoJSONGeometryOf("LineString", lines[0])
+		}
+		features = append(features, geoJSONFeatureOf(properties, geom))
+	}
+
+	for _, track := range doc.Tracks {
+		paths := make([][][2]float64, 0, len(track.Segments))
+		for _, segment := range track.Segments {
+			paths = append(paths, gpxPath(segment.Points))
+		}
+		add(track, "trk", paths)
+	}
+	for _, route := range doc.Routes {
+		add(route, "rte", [][][2]float64{gpxPath(route.RoutePoints)})
+	}
+
+	name := doc.Metadata
+	if name == "" {
+		name = doc.Name
+	}
+	return featureCollectionJSON(name, features)
+}
+
+func gpxPath(points []gpxPoint) [][2]float64 {
+	out := make([][2]float64, len(points))
+	for i, p := range points {
+		out[i] = [2]float64{p.Lon, p.Lat}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package coastline
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// KML elements are matched by local name, so documents with or without the
+// OGC namespace, and Google Earth extensions next to them, decode alike.
+
+type kmlContainer struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+	Folders    []kmlContainer `xml:"Folder"`
+	Documents  []kmlContainer `xml:"Document"`
+}
+
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	Data        []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	} `xml:"ExtendedData>Data"`
+	SimpleData []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"ExtendedData>SchemaData>SimpleData"`
+	kmlGeometry
+}
+
+type kmlGeometry struct {
+	LineStrings   []kmlCoordinates `xml:"LineString"`
+	LinearRings   []kmlCoordinates `xml:"LinearRing"`
+	Polygons      []kmlPolygon     `xml:"Polygon"`
+	MultiGeometry []kmlGeometry    `xml:"MultiGeometry"`
+}
+
+type kmlCoordinates struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	Outer kmlCoordinates   `xml:"outerBoundaryIs>LinearRing"`
+	Inner []kmlCoordinates `xml:"innerBoundaryIs>LinearRing"`
+}
+
+// kmlToGeoJSON turns every Placemark with a LineString, LinearRing, Polygon
+// or MultiGeometry into a feature. Its name, description and ExtendedData
+// become properties; points and placemarks without lines are skipped.
+func kmlToGeoJSON(data []byte) ([]byte, error) {
+	var root kmlContainer
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse kml: %w", err)
+	}
+
+	var features []map[string]any
+	var walk func(container kmlContainer) error
+	walk = func(container kmlContainer) error {
+		for _, placemark := range container.Placemarks {
+			geometries, err := placemark.geoJSON()
+			if err != nil {
+				return fmt.Errorf("kml placemark %q: %w", placemark.Name, err)
+			}
+			if len(geometries) == 0 {
+				continue
+			}
+
+			properties := map[string]any{}
+			setProperty(properties, "name", placemark.Name)
+			setProperty(properties, "description", placemark.Description)
+			for _, item := range placemark.Data {
+				setProperty(properties, item.Name, item.Value)
+			}
+			for _, item := range placemark.SimpleData {
+				setProperty(properties, item.Name, item.Value)
+			}
+			features = append(features, geoJSONFeatureOf(properties, geoJSONGeometryOfAll(geometries)))
+		}
+		for _, nested := range append(container.Documents, container.Folders...) {
+			if err := walk(nested); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+
+	name := root.Name
+	if name == "" && len(root.Documents) > 0 {
+		name = root.Documents[0].Name
+	}
+	return featureCollectionJSON(name, features)
+}
+
+func (g kmlGeometry) geoJSON() ([]map[string]any, error) {
+	var out []map[string]any
+	for _, line := range g.LineStrings {
+		coordinates, err := parseKMLCoordinates(line.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, geoJSONGeometryOf("LineString", coordinates))
+	}
+	for _, ring := range g.LinearRings {
+		coordinates, err := parseKMLCoordinates(ring.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, geoJSONGeometryOf("Polygon", [][][2]float64{coordinates}))
+	}
+	for _, polygon := range g.Polygons {
+		outer, err := parseKMLCoordinates(polygon.Outer.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		rings := [][][2]float64{outer}
+		for _, inner := range polygon.Inner {
+			hole, err := parseKMLCoordinates(inner.Coordinates)
+			if err != nil {
+				return nil, err
+			}
+			rings = append(rings, hole)
+		}
+		out = append(out, geoJSONGeometryOf("Polygon", rings))
+	}
+	for _, nested := range g.MultiGeometry {
+		geometries, err := nested.geoJSON()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, geometries...)
+	}
+	return out, nil
+}
+
+var kmlTupleSpaces = regexp.MustCompile(`\s*,\s*`)
+
+// parseKMLCoordinates reads whitespace separated lon,lat[,alt] tuples.
+func parseKMLCoordinates(text string) ([][2]float64, error) {
+	tuples := strings.Fields(kmlTupleSpaces.ReplaceAllString(text, ","))
+	out := make([][2]float64, 0, len(tuples))
+	for i, tuple := range tuples {
+		values := strings.Split(tuple, ",")
+		if len(values) < 2 {
+			return nil, fmt.Errorf("coordinate %d %q must contain lon,lat", i, tuple)
+		}
+		lon, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("coordinate %d: %w", i, err)
+		}
+		lat, err := strconv.ParseFloat(values[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("coordinate %d: %w", i, err)
+		}
+		out = append(out, [2]float64{lon, lat})
+	}
+	return out, nil
+}
@@ -1,92 +1,92 @@
 package coastline
 
 import (
-	"fmt"
-	"strings"
-
 	"coastal-geometry/internal/domain/geometry"
 )
 
-const maxConsolePoints = 30
+const maxKeyPoints = 30
 
-func MainCalculation(coast []geometry.LatLon, datasetName, source string) SanityCheckResult {
-	segmentCount := 0
-	if len(coast) > 1 {
-		segmentCount = len(coast) - 1
-	}
+// Report is the summary of the main coastline line shown by `real coastline`.
+type Report struct {
+	Dataset       string
+	Source        string
+	Points        int
+	Segments      int
+	LengthKM      float64
+	MeanSegmentKM float64
+	// Geodesic names the distance LengthKM was measured with, Ellipsoid its
+	// reference ellipsoid if any. HaversineKM is the spherical length for
+	// comparison.
+	Geodesic    string
+	Ellipsoid   string
+	HaversineKM float64
+	Sanity      SanityCheckResult
+	// KeyPoints lists every point of short lines and an even sample of
+	// maxKeyPoints points otherwise; Sampled tells the two apart.
+	KeyPoints []KeyPoint
+	Sampled   bool
+}
 
-	fmt.Println(strings.Repeat("═", 80))
-	fmt.Println("\tБЕРЕГОВАЯ ЛИНИЯ ЧЁРНОГО МОРЯ")
-	fmt.Println(strings.Repeat("═", 80))
+// KeyPoint is a coastline vertex with the nearest known landmark, or "—".
+type KeyPoint struct {
+	Index int
+	Point geometry.LatLon
+	Name  string
+}
 
-	fmt.Printf("\nКоличество точек:                        %d\n", len(coast))
-	fmt.Printf("Количество сегментов:                    %d\n", segmentCount)
-	if source != "" {
-		fmt.Printf("Источник данных:                         %s\n", source)
+// BuildReport measures coast with distance; nil means haversine.
+func BuildReport(coast []geometry.LatLon, datasetName, source string, distance geometry.Distance) Report {
+	if distance == nil {
+		distance = geometry.HaversineDistance{}
 	}
-
-	totalLength := geometry.PolylineLength(coast)
-	sanity := SanityCheck(datasetName, totalLength)
-	fmt.Printf("Общая длина береговой линии:              %.0f км\n", totalLength)
-	if segmentCount > 0 {
-		fmt.Printf("Средняя длина сегмента:                   %.1f км\n\n", totalLength/float64(segmentCount))
-	} else {
-		fmt.Printf("Средняя длина сегмента:                   0.0 км\n\n")
+	report := Report{
+		Dataset:     datasetName,
+		Source:      source,
+		Points:      len(coast),
+		LengthKM:    geometry.PolylineLengthWith(coast, distance),
+		Geodesic:    distance.Name(),
+		HaversineKM: geometry.PolylineLength(coast),
+		Sampled:     len(coast) > maxKeyPoints,
 	}
-
-	if sanity.Warning != "" {
-		fmt.Println(sanity.Warning)
-		fmt.Println()
+	if ellipsoid, ok := geometry.DistanceEllipsoid(distance); ok {
+		report.Ellipsoid = ellipsoid.Name
 	}
-
-	fmt.Println("Ключевые точки береговой линии:")
-	if len(coast) > maxConsolePoints {
-		fmt.Printf("Показаны %d равномерно распределённых точек из %d.\n", maxConsolePoints, len(coast))
+	if len(coast) > 1 {
+		report.Segments = len(coast) - 1
+		report.MeanSegmentKM = report.LengthKM / float64(report.Segments)
 	}
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Printf("%-4s %-11s %-11s %-25s\n", "№", "Широта", "Долгота", "Город / ориентир")
-	fmt.Println(strings.Repeat("─", 80))
+	report.Sanity = SanityCheck(datasetName, report.LengthKM)
 
-	for _, entry := range consolePointSelection(coast) {
-		if entry.placeholder != "" {
-			fmt.Println(entry.placeholder)
-			continue
-		}
-		name := getLocationName(entry.point)
-		fmt.Printf("%-4d %-11.4f %-11.4f %-25s\n", entry.index+1, entry.point.Lat, entry.point.Lon, name)
+	for _, index := range keyPointIndexes(len(coast)) {
+		report.KeyPoints = append(report.KeyPoints, KeyPoint{
+			Index: index,
+			Point: coast[index],
+			Name:  getLocationName(coast[index]),
+		})
 	}
-
-	fmt.Println(strings.Repeat("═", 80))
-	fmt.Printf("Итого: %.0f км\n", totalLength)
-	return sanity
-}
-
-type consolePointEntry struct {
-	index       int
-	point       geometry.LatLon
-	placeholder string
+	return report
 }
 
-func consolePointSelection(points []geometry.LatLon) []consolePointEntry {
-	if len(points) <= maxConsolePoints {
-		result := make([]consolePointEntry, 0, len(points))
-		for i, point := range points {
-			result = append(result, consolePointEntry{index: i, point: point})
+func keyPointIndexes(count int) []int {
+	if count <= maxKeyPoints {
+		result := make([]int, count)
+		for i := range result {
+			result[i] = i
 		}
 		return result
 	}
 
-	result := make([]consolePointEntry, 0, maxConsolePoints)
-	seen := make(map[int]struct{}, maxConsolePoints)
-	lastIndex := len(points) - 1
+	result := make([]int, 0, maxKeyPoints)
+	seen := make(map[int]struct{}, maxKeyPoints)
+	lastIndex := count - 1
 
-	for i := 0; i < maxConsolePoints; i++ {
-		index := i * lastIndex / (maxConsolePoints - 1)
+	for i := 0; i < maxKeyPoints; i++ {
+		index := i * lastIndex / (maxKeyPoints - 1)
 		if _, ok := seen[index]; ok {
 			continue
 		}
 		seen[index] = struct{}{}
-		result = append(result, consolePointEntry{index: index, point: points[index]})
+		result = append(result, index)
 	}
 	return result
 }
@@ -0,0 +1,50 @@
+package coastline
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestBuildReportSamplesLongLines(t *testing.T) {
+	coast := make([]geometry.LatLon, 100)
+	for i := range coast {
+		coast[i] = geometry.LatLon{Lat: 43, Lon: 28 + float64(i)*0.01}
+	}
+
+	report := BuildReport(coast, "unit.json", "unit-test", nil)
+	if report.Points != 100 || report.Segments != 99 {
+		t.Fatalf("unexpected counts %d/%d", report.Points, report.Segments)
+	}
+	if math.Abs(report.MeanSegmentKM*99-report.LengthKM) > 1e-9 {
+		t.Fatalf("expected mean segment to be length/segments, got %.4f", report.MeanSegmentKM)
+	}
+	if !report.Sampled || len(report.KeyPoints) != maxKeyPoints {
+		t.Fatalf("expected %d sampled key points, got %d (sampled=%v)", maxKeyPoints, len(report.KeyPoints), report.Sampled)
+	}
+	if first, last := report.KeyPoints[0], report.KeyPoints[len(report.KeyPoints)-1]; first.Index != 0 || last.Index != 99 {
+		t.Fatalf("expected the sample to keep both ends, got %d..%d", first.Index, last.Index)
+	}
+	if report.KeyPoints[0].Name != "Варна, Болгария" {
+		t.Fatalf("expected the first point to be named after Varna, got %q", report.KeyPoints[0].Name)
+	}
+	if report.Sanity.Checked {
+		t.Fatal("expected no sanity check for an unknown dataset")
+	}
+}
+
+func TestBuildReportMeasuresWithSelectedGeodesic(t *testing.T) {
+	coast := []geometry.LatLon{{Lat: 46.48, Lon: 30.73}, {Lat: 44.62, Lon: 33.53}, {Lat: 41.65, Lon: 41.63}}
+
+	report := BuildReport(coast, "unit.json", "unit-test", geometry.NewKarneyDistance(geometry.WGS84))
+	if report.Geodesic != geometry.DistanceKarney || report.Ellipsoid != "WGS84" {
+		t.Fatalf("unexpected geodesic %q on %q", report.Geodesic, report.Ellipsoid)
+	}
+	if report.HaversineKM != geometry.PolylineLength(coast) {
+		t.Fatalf("expected the haversine length for comparison, got %.3f", report.HaversineKM)
+	}
+	if report.LengthKM == report.HaversineKM || math.Abs(report.LengthKM-report.HaversineKM)/report.HaversineKM > 0.005 {
+		t.Fatalf("ellipsoidal length %.3f should differ slightly from %.3f", report.LengthKM, report.HaversineKM)
+	}
+}
@@ -0,0 +1,417 @@
+package coastline
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+const (
+	shpFileCode   = 9994
+	shpHeaderSize = 100
+
+	shpNull      = 0
+	shpPolyLine  = 3
+	shpPolygon   = 5
+	shpPolyLineZ = 13
+	shpPolygonZ  = 15
+	shpPolyLineM = 23
+	shpPolygonM  = 25
+
+	dbfFieldSize       = 32
+	dbfFieldTerminator = 0x0D
+	dbfDeleted         = '*'
+	dbfLanguageCP1251  = 0xC9
+)
+
+// readLocalPayload reads a local coastline file. A .shp file is read together
+// with its .dbf attributes and converted to a GeoJSON FeatureCollection, so
+// parsing, metadata and snapshots treat it like any GeoJSON source; format is
+// then FormatShapefile.
+func readLocalPayload(path string) (payload []byte, format string, err error) {
+	if strings.EqualFold(filepath.Ext(path), ".shp") {
+		payload, err = readShapefile(path)
+		return payload, FormatShapefile, err
+	}
+
+	payload, err = os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("read coastline json %q: %w", path, err)
+	}
+	return payload, "", nil
+}
+
+// readShapefile converts path and its sibling .dbf, .cpg and .prj files to
+// GeoJSON. The attribute table is optional; coordinates must be geographic.
+func readShapefile(path string) ([]byte, error) {
+	shp, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read shapefile %q: %w", path, err)
+	}
+
+	if prj, ok := readSibling(path, ".prj"); ok && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(string(prj))), "PROJCS") {
+		return nil, fmt.Errorf("shapefile %q uses projected coordinates; reproject it to geographic WGS84", path)
+	}
+
+	var rows []map[string]any
+	if dbf, ok := readSibling(path, ".dbf"); ok {
+		cpg, _ := readSibling(path, ".cpg")
+		rows, err = parseDBF(dbf, string(cpg))
+		if err != nil {
+			return nil, fmt.Errorf("parse shapefile attributes of %q: %w", path, err)
+		}
+	}
+
+	payload, err := shapefileToGeoJSON(shp, rows)
+	if err != nil {
+		return nil, fmt.Errorf("parse shapefile %q: %w", path, err)
+	}
+	return payload, nil
+}
+
+// readSibling reads the file next to path with extension ext, matching the
+// name case-insensitively as shapefiles from Windows often mix cases.
+func readSibling(path, ext string) ([]byte, bool) {
+	want := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ext
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, false
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.EqualFold(entry.Name(), want) {
+			data, err := os.ReadFile(filepath.Join(filepath.Dir(path), entry.Name()))
+			return data, err == nil
+		}
+	}
+	return nil, false
+}
+
+// shapefileToGeoJSON turns every PolyLine or Polygon record into a feature
+// with the attributes of its DBF row. A nil row slice yields features without
+// properties; records whose row is marked deleted are skipped.
+func shapefileToGeoJSON(shp []byte, rows []map[string]any) ([]byte, error) {
+	if len(shp) < shpHeaderSize || binary.BigEndian.Uint32(shp[0:4]) != shpFileCode {
+		return nil, fmt.Errorf("not a shapefile: missing file code %d", shpFileCode)
+	}
+	if length := int(binary.BigEndian.Uint32(shp[24:28])) * 2; length >= shpHeaderSize && length < len(shp) {
+		shp = shp[:length]
+	}
+
+	var features []map[string]any
+	offset := shpHeaderSize
+	for index := 0; offset < len(shp); index++ {
+		if offset+8 > len(shp) {
+			return nil, fmt.Errorf("record %d: truncated header", index+1)
+		}
+		size := int(binary.BigEndian.Uint32(shp[offset+4:offset+8])) * 2
+		start := offset + 8
+		if size < 4 || start+size > len(shp) {
+			return nil, fmt.Errorf("record %d: truncated content", index+1)
+		}
+		offset = start + size
+
+		var properties map[string]any
+		if rows != nil {
+			if index >= len(rows) {
+				return nil, fmt.Errorf("record %d has no attribute row (%d rows)", index+1, len(rows))
+			}
+			if rows[index] == nil {
+				continue
+			}
+			properties = rows[index]
+		}
+
+		geom, err := decodeShapeRecord(shp[start:offset])
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", index+1, err)
+		}
+		if geom == nil {
+			continue
+		}
+		features = append(features, geoJSONFeatureOf(properties, geom))
+	}
+
+	return featureCollectionJSON("", features)
+}
+
+// decodeShapeRecord returns the GeoJSON geometry of one record, or nil for a
+// null shape. Z and M variants share the XY layout and their extra values are
+// ignored.
+func decodeShapeRecord(content []byte) (map[string]any, error) {
+	shapeType := int(binary.LittleEndian.Uint32(content[0:4]))
+	polygon := false
+	switch shapeType {
+	case shpNull:
+		return nil, nil
+	case shpPolyLine, shpPolyLineZ, shpPolyLineM:
+	case shpPolygon, shpPolygonZ, shpPolygonM:
+		polygon = true
+	default:
+		return nil, fmt.Errorf("unsupported shape type %d (only PolyLine and Polygon)", shapeType)
+	}
+
+	// Type, bounding box, part and point counts.
+	if len(content) < 44 {
+		return nil, fmt.Errorf("truncated shape")
+	}
+	numParts := int(binary.LittleEndian.Uint32(content[36:40]))
+	numPoints := int(binary.LittleEndian.Uint32(content[40:44]))
+	pointsAt := 44 + 4*numParts
+	if numParts < 0 || numPoints < 0 || pointsAt+16*numPoints > len(content) {
+		return nil, fmt.Errorf("truncated shape with %d parts and %d points", numParts, numPoints)
+	}
+
+	paths := make([][]geometry.LatLon, 0, numParts)
+	for i := 0; i < numParts; i++ {
+		from := int(binary.LittleEndian.Uint32(content[44+4*i:]))
+		to := numPoints
+		if i+1 < numParts {
+			to = int(binary.LittleEndian.Uint32(content[48+4*i:]))
+		}
+		if from < 0 || from > to || to > numPoints {
+			return nil, fmt.Errorf("part %d has invalid point range %d..%d", i+1, from, to)
+		}
+
+		path := make([]geometry.LatLon, 0, to-from)
+		for j := from; j < to; j++ {
+			at := pointsAt + 16*j
+			path = append(path, geometry.LatLon{
+				Lon: math.Float64frombits(binary.LittleEndian.Uint64(content[at:])),
+				Lat: math.Float64frombits(binary.LittleEndian.Uint64(content[at+8:])),
+			})
+		}
+		if len(path) >= 2 {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	if !polygon {
+		if len(paths) == 1 {
+			return geoJSONGeometryOf("LineString", lonLatPath(paths[0])), nil
+		}
+		lines := make([][][2]float64, 0, len(paths))
+		for _, path := range paths {
+			lines = append(lines, lonLatPath(path))
+		}
+		return geoJSONGeometryOf("MultiLineString", lines), nil
+	}
+
+	polygons := groupShapefileRings(paths)
+	coordinates := make([][][][2]float64, 0, len(polygons))
+	for _, rings := range polygons {
+		shape := make([][][2]float64, 0, len(rings))
+		for _, ring := range rings {
+			shape = append(shape, lonLatPath(ring))
+		}
+		coordinates = append(coordinates, shape)
+	}
+	if len(coordinates) == 1 {
+		return geoJSONGeometryOf("Polygon", coordinates[0]), nil
+	}
+	return geoJSONGeometryOf("MultiPolygon", coordinates), nil
+}
+
+// groupShapefileRings splits the rings of a Polygon record into polygons.
+// Shapefile outer rings run clockwise and holes counter-clockwise; a hole
+// belongs to the last outer ring that contains it, or else to the last one. A
+// record without clockwise rings is taken to be wound the other way round.
+func groupShapefileRings(rings [][]geometry.LatLon) [][][]geometry.LatLon {
+	clockwise := make([]bool, len(rings))
+	anyOuter := false
+	for i, ring := range rings {
+		clockwise[i] = lonLatSignedArea(ring) < 0
+		anyOuter = anyOuter || clockwise[i]
+	}
+
+	var polygons [][][]geometry.LatLon
+	var holes [][]geometry.LatLon
+	for i, ring := range rings {
+		if clockwise[i] || !anyOuter {
+			polygons = append(polygons, [][]geometry.LatLon{ring})
+			continue
+		}
+		holes = append(holes, ring)
+	}
+
+	for _, hole := range holes {
+		owner := len(polygons) - 1
+		for i := len(polygons) - 1; i >= 0; i-- {
+			if containsLonLat(polygons[i][0], hole[0]) {
+				owner = i
+				break
+			}
+		}
+		polygons[owner] = append(polygons[owner], hole)
+	}
+	return polygons
+}
+
+func lonLatSignedArea(ring []geometry.LatLon) float64 {
+	sum := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		sum += a.Lon*b.Lat - b.Lon*a.Lat
+	}
+	return sum / 2
+}
+
+// containsLonLat is an even-odd ray test in plain degrees.
+func containsLonLat(ring []geometry.LatLon, p geometry.LatLon) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func lonLatPath(points []geometry.LatLon) [][2]float64 {
+	out := make([][2]float64, len(points))
+	for i, p := range points {
+		out[i] = [2]float64{p.Lon, p.Lat}
+	}
+	return out
+}
+
+type dbfField struct {
+	Name   string
+	Type   byte
+	Length int
+}
+
+// parseDBF reads a dBASE III table into one property map per record; deleted
+// records are nil. Character fields are decoded as Windows-1251 when the .cpg
+// file or the language driver says so, otherwise as UTF-8 with a Latin-1
+// fallback. Numbers become float64, logicals bool, dates YYYY-MM-DD, and
+// blank values nil.
+func parseDBF(data []byte, cpg string) ([]map[string]any, error) {
+	if len(data) < dbfFieldSize {
+		return nil, fmt.Errorf("truncated dbf header")
+	}
+	count := int(binary.LittleEndian.Uint32(data[4:8]))
+	headerSize := int(binary.LittleEndian.Uint16(data[8:10]))
+	recordSize := int(binary.LittleEndian.Uint16(data[10:12]))
+	if headerSize > len(data) || recordSize < 1 {
+		return nil, fmt.Errorf("invalid dbf header")
+	}
+
+	cp1251 := data[29] == dbfLanguageCP1251
+	if cpg = strings.ToUpper(strings.TrimSpace(cpg)); cpg != "" {
+		cp1251 = strings.Contains(cpg, "1251")
+	}
+
+	var fields []dbfField
+	width := 1
+	for at := dbfFieldSize; at+dbfFieldSize <= headerSize && data[at] != dbfFieldTerminator; at += dbfFieldSize {
+		name := data[at : at+11]
+		if end := bytes.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		field := dbfField{
+			Name:   strings.TrimSpace(decodeDBFText(name, cp1251)),
+			Type:   data[at+11],
+			Length: int(data[at+16]),
+		}
+		fields = append(fields, field)
+		width += field.Length
+	}
+	if width > recordSize {
+		return nil, fmt.Errorf("dbf fields need %d bytes, records have %d", width, recordSize)
+	}
+
+	rows := make([]map[string]any, 0, count)
+	for i := 0; i < count; i++ {
+		at := headerSize + i*recordSize
+		if at+recordSize > len(data) {
+			return nil, fmt.Errorf("dbf record %d is truncated", i+1)
+		}
+		record := data[at : at+recordSize]
+		if record[0] == dbfDeleted {
+			rows = append(rows, nil)
+			continue
+		}
+
+		row := make(map[string]any, len(fields))
+		offset := 1
+		for _, field := range fields {
+			row[field.Name] = dbfValue(field.Type, record[offset:offset+field.Length], cp1251)
+			offset += field.Length
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func dbfValue(kind byte, raw []byte, cp1251 bool) any {
+	text := strings.Trim(decodeDBFText(raw, cp1251), " \x00")
+	if text == "" {
+		return nil
+	}
+
+	switch kind {
+	case 'N', 'F':
+		if strings.HasPrefix(text, "*") {
+			return nil
+		}
+		if value, err := strconv.ParseFloat(text, 64); err == nil {
+			return value
+		}
+	case 'L':
+		switch text {
+		case "T", "t", "Y", "y":
+			return true
+		case "F", "f", "N", "n":
+			return false
+		default:
+			return nil
+		}
+	case 'D':
+		if len(text) == 8 {
+			return text[0:4] + "-" + text[4:6] + "-" + text[6:8]
+		}
+	}
+	return text
+}
+
+func decodeDBFText(raw []byte, cp1251 bool) string {
+	if !cp1251 && utf8.Valid(raw) {
+		return string(raw)
+	}
+
+	var b strings.Builder
+	for _, c := range raw {
+		switch {
+		case c < 0x80:
+			b.WriteByte(c)
+		case !cp1251:
+			b.WriteRune(rune(c))
+		case c >= 0xC0:
+			b.WriteRune(0x0410 + rune(c-0xC0))
+		default:
+			b.WriteRune(cp1251High[c-0x80])
+		}
+	}
+	return b.String()
+}
+
+// cp1251High maps Windows-1251 bytes 0x80–0xBF; 0xC0–0xFF are А–я in order.
+var cp1251High = [64]rune{
+	'Ђ', 'Ѓ', '‚', 'ѓ', '„', '…', '†', '‡', '€', '‰', 'Љ', '‹', 'Њ', 'Ќ', 'Ћ', 'Џ',
+	'ђ', '‘', '’', '“', '”', '•', '–', '—', '\ufffd', '™', 'љ', '›', 'њ', 'ќ', 'ћ', 'џ',
+	'\u00a0', 'Ў', 'ў', 'Ј', '¤', 'Ґ', '¦', '§', 'Ё', '©', 'Є', '«', '¬', '\u00ad', '®', 'Ї',
+	'°', '±', 'І', 'і', 'ґ', 'µ', '¶', '·', 'ё', '№', 'є', '»', 'ј', 'Ѕ', 'ѕ', 'ї',
+}
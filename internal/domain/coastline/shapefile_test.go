@@ -0,0 +1,248 @@
+package coastline
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+// writeTestShapefile writes one shape record per entry of records; every
+// record is a list of parts.
+func writeTestShapefile(t *testing.T, path string, shapeType int, records [][][]geometry.LatLon) {
+	t.Helper()
+
+	var body []byte
+	for i, parts := range records {
+		count := 0
+		for _, part := range parts {
+			count += len(part)
+		}
+		content := make([]byte, 44+4*len(parts)+16*count)
+		binary.LittleEndian.PutUint32(content[0:], uint32(shapeType))
+		binary.LittleEndian.PutUint32(content[36:], uint32(len(parts)))
+		binary.LittleEndian.PutUint32(content[40:], uint32(count))
+		at, start := 44+4*len(parts), 0
+		for j, part := range parts {
+			binary.LittleEndian.PutUint32(content[44+4*j:], uint32(start))
+			for _, p := range part {
+				binary.LittleEndian.PutUint64(content[at:], math.Float64bits(p.Lon))
+				binary.LittleEndian.PutUint64(content[at+8:], math.Float64bits(p.Lat))
+				at += 16
+			}
+			start += len(part)
+		}
+
+		header := make([]byte, 8)
+		binary.BigEndian.PutUint32(header[0:], uint32(i+1))
+		binary.BigEndian.PutUint32(header[4:], uint32(len(content)/2))
+		body = append(body, header...)
+		body = append(body, content...)
+	}
+
+	file := make([]byte, shpHeaderSize, shpHeaderSize+len(body))
+	binary.BigEndian.PutUint32(file[0:], shpFileCode)
+	binary.BigEndian.PutUint32(file[24:], uint32((shpHeaderSize+len(body))/2))
+	binary.LittleEndian.PutUint32(file[28:], 1000)
+	binary.LittleEndian.PutUint32(file[32:], uint32(shapeType))
+	file = append(file, body...)
+	if err := os.WriteFile(path, file, 0o644); err != nil {
+		t.Fatalf("write shapefile: %v", err)
+	}
+}
+
+// writeTestDBF writes a dBASE III table; a row starting with "*" is marked
+// deleted.
+func writeTestDBF(t *testing.T, path string, fields []dbfField, rows [][][]byte) {
+	t.Helper()
+
+	recordSize := 1
+	for _, field := range fields {
+		recordSize += field.Length
+	}
+	headerSize := dbfFieldSize*(len(fields)+1) + 1
+
+	data := make([]byte, headerSize)
+	data[0] = 0x03
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(rows)))
+	binary.LittleEndian.PutUint16(data[8:], uint16(headerSize))
+	binary.LittleEndian.PutUint16(data[10:], uint16(recordSize))
+	for i, field := range fields {
+		at := dbfFieldSize * (i + 1)
+		copy(data[at:at+11], field.Name)
+		data[at+11] = field.Type
+		data[at+16] = byte(field.Length)
+	}
+	data[headerSize-1] = dbfFieldTerminator
+
+	for _, row := range rows {
+		record := []byte{' '}
+		if len(row) > 0 && string(row[0]) == "*" {
+			record[0] = dbfDeleted
+			row = row[1:]
+		}
+		for i, field := range fields {
+			value := make([]byte, field.Length)
+			for j := range value {
+				value[j] = ' '
+			}
+			if i < len(row) {
+				copy(value, row[i])
+			}
+			record = append(record, value...)
+		}
+		data = append(data, record...)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write dbf: %v", err)
+	}
+}
+
+// encodeCP1251 covers the Cyrillic letters used in the tests.
+func encodeCP1251(value string) []byte {
+	var out []byte
+	for _, r := range value {
+		switch {
+		case r >= 'А' && r <= 'я':
+			out = append(out, byte(0xC0+r-'А'))
+		case r == 'Ё':
+			out = append(out, 0xA8)
+		case r == 'ё':
+			out = append(out, 0xB8)
+		default:
+			out = append(out, byte(r))
+		}
+	}
+	return out
+}
+
+func square(lon, lat, size float64, clockwise bool) []geometry.LatLon {
+	ring := []geometry.LatLon{
+		{Lat: lat, Lon: lon},
+		{Lat: lat + size, Lon: lon},
+		{Lat: lat + size, Lon: lon + size},
+		{Lat: lat, Lon: lon + size},
+		{Lat: lat, Lon: lon},
+	}
+	if !clockwise {
+		slices.Reverse(ring)
+	}
+	return ring
+}
+
+func writeBlackSeaShapefile(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "coast.shp")
+	writeTestShapefile(t, path, shpPolygon, [][][]geometry.LatLon{
+		{square(28, 41, 5, true), square(30, 43, 1, false), square(10, 10, 1, true)},
+		{square(36, 45, 0.5, true)},
+		{square(0, 0, 1, true)},
+	})
+	writeTestDBF(t, filepath.Join(dir, "COAST.DBF"), []dbfField{
+		{Name: "NAME", Type: 'C', Length: 24},
+		{Name: "MRGID", Type: 'N', Length: 8},
+		{Name: "SURVEYED", Type: 'D', Length: 8},
+		{Name: "CHECKED", Type: 'L', Length: 1},
+	}, [][][]byte{
+		{encodeCP1251("Чёрное море"), []byte("3319"), []byte("20190521"), []byte("T")},
+		{encodeCP1251("Азовское море"), []byte("")},
+		{[]byte("*"), []byte("deleted")},
+	})
+	if err := os.WriteFile(filepath.Join(dir, "coast.cpg"), []byte("1251\n"), 0o644); err != nil {
+		t.Fatalf("write cpg: %v", err)
+	}
+	return path
+}
+
+func TestLoadReadsShapefileWithAttributes(t *testing.T) {
+	path := writeBlackSeaShapefile(t, t.TempDir())
+
+	result, err := Load(LoadOptions{LocalPath: path})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if result.DatasetName != "Чёрное море" {
+		t.Fatalf("expected the DBF name as dataset name, got %q", result.DatasetName)
+	}
+	parts := result.Coastline.Parts
+	if len(parts) != 3 {
+		t.Fatalf("expected two polygons of the first record and one of the second, deleted record skipped; got %d parts", len(parts))
+	}
+	if parts[0].Name != "Чёрное море #1" || parts[2].Name != "Азовское море" {
+		t.Fatalf("unexpected part names %q, %q", parts[0].Name, parts[2].Name)
+	}
+	if len(parts[0].Rings) != 2 || parts[0].Rings[0].Role != RingOuter || parts[0].Rings[1].Role != RingInner {
+		t.Fatalf("expected the counter-clockwise ring to be a hole of the first polygon, got %+v", parts[0].Rings)
+	}
+
+	props := parts[0].Properties
+	if props["MRGID"] != 3319.0 || props["SURVEYED"] != "2019-05-21" || props["CHECKED"] != true {
+		t.Fatalf("unexpected attributes %v", props)
+	}
+	if value, ok := parts[2].Properties["MRGID"]; !ok || value != nil {
+		t.Fatalf("expected a blank number to be null, got %v", parts[2].Properties)
+	}
+	if !parts[0].Rings[0].Main {
+		t.Fatalf("expected the largest outer ring to be the main ring")
+	}
+}
+
+func TestInspectSourceReportsShapefileMetadata(t *testing.T) {
+	dir := t.TempDir()
+	path := writeBlackSeaShapefile(t, dir)
+
+	result, err := InspectSource(InspectOptions{LocalPath: path, SnapshotPath: filepath.Join(dir, "snapshots")})
+	if err != nil {
+		t.Fatalf("InspectSource returned error: %v", err)
+	}
+
+	meta := result.Metadata
+	if meta.Format != FormatShapefile || meta.RegionID != "3319" || meta.FeatureCount != 2 {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+	if !slices.Equal(meta.GeometryTypes, []string{"MultiPolygon", "Polygon"}) {
+		t.Fatalf("unexpected geometry types %v", meta.GeometryTypes)
+	}
+	if filepath.Ext(result.SnapshotPath) != ".geojson" {
+		t.Fatalf("expected the converted GeoJSON snapshot, got %q", result.SnapshotPath)
+	}
+}
+
+func TestLoadReadsShapefilePolyLineWithoutAttributes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "line.shp")
+	writeTestShapefile(t, path, shpPolyLine, [][][]geometry.LatLon{{
+		{{Lat: 46.48, Lon: 30.73}, {Lat: 45.33, Lon: 32.49}, {Lat: 44.94, Lon: 34.10}},
+		{{Lat: 43.70, Lon: 39.75}, {Lat: 41.65, Lon: 41.63}},
+	}})
+
+	result, err := Load(LoadOptions{LocalPath: path})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	rings := result.Coastline.Rings()
+	if len(rings) != 2 || rings[0].Role != RingLine || len(result.Points) != 3 {
+		t.Fatalf("expected two line rings with the longer one as main, got %+v", rings)
+	}
+	if result.DatasetName != "line.shp" {
+		t.Fatalf("expected the file name as dataset name, got %q", result.DatasetName)
+	}
+}
+
+func TestReadShapefileRejectsProjectedCoordinates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "utm.shp")
+	writeTestShapefile(t, path, shpPolyLine, [][][]geometry.LatLon{{{{Lat: 4_900_000, Lon: 500_000}, {Lat: 4_900_100, Lon: 500_100}}}})
+	if err := os.WriteFile(filepath.Join(dir, "utm.prj"), []byte(`PROJCS["WGS_1984_UTM_Zone_36N",GEOGCS["GCS_WGS_1984"]]`), 0o644); err != nil {
+		t.Fatalf("write prj: %v", err)
+	}
+
+	if _, err := Load(LoadOptions{LocalPath: path}); err == nil {
+		t.Fatalf("expected projected shapefile to be rejected")
+	}
+}
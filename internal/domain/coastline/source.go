@@ -36,6 +36,7 @@ type SourceMetadata struct {
 	FeatureCount        int
 	GeometryTypes       []string
 	CoastlinePointCount int
+	CoastlineRingCount  int
 	PayloadBytes        int
 	Bounds              GeoBounds
 }
@@ -51,6 +52,7 @@ type SourceInspection struct {
 
 type resolvedSourcePayload struct {
 	Payload      []byte
+	Format       string // overrides the detected metadata format of converted payloads
 	Source       string
 	CachePath    string
 	LoadWarnings []string
@@ -90,6 +92,9 @@ func InspectSource(options InspectOptions) (SourceInspection, error) {
 	if err != nil {
 		return SourceInspection{}, fmt.Errorf("inspect coastline source %q: %w", payload.Source, err)
 	}
+	if payload.Format != "" {
+		metadata.Format = payload.Format
+	}
 
 	datasetName := datasetNameFromMetadata(metadata, localPath, remoteURL)
 	snapshotPath, err := resolveSnapshotPath(options.SnapshotPath, metadata, datasetName)
@@ -118,12 +123,13 @@ func resolveSourcePayload(localPath, remoteURL, cachePath string, refresh bool,
 
 	remoteURL = strings.TrimSpace(remoteURL)
 	if remoteURL == "" {
-		payload, err := os.ReadFile(localPath)
+		payload, format, err := readLocalPayload(localPath)
 		if err != nil {
-			return resolvedSourcePayload{}, fmt.Errorf("read coastline json %q: %w", localPath, err)
+			return resolvedSourcePayload{}, err
 		}
 		return resolvedSourcePayload{
 			Payload: payload,
+			Format:  format,
 			Source:  localPath,
 		}, nil
 	}
@@ -168,13 +174,14 @@ func resolveSourcePayload(localPath, remoteURL, cachePath string, refresh bool,
 		}, nil
 	}
 
-	localPayload, localErr := os.ReadFile(localPath)
+	localPayload, localFormat, localErr := readLocalPayload(localPath)
 	if localErr != nil {
 		return resolvedSourcePayload{}, fmt.Errorf("load coastline from remote %q: %v; load cache %q: %v; load fallback %q: %w", remoteURL, remoteErr, cachePath, cacheErr, localPath, localErr)
 	}
 
 	return resolvedSourcePayload{
 		Payload: localPayload,
+		Format:  localFormat,
 		Source:  localPath,
 		LoadWarnings: []string{
 			fmt.Sprintf("remote source %q unavailable, using local fallback %q: %v", remoteURL, localPath, remoteErr),
@@ -183,45 +190,49 @@ func resolveSourcePayload(localPath, remoteURL, cachePath string, refresh bool,
 }
 
 func inspectSourceMetadata(data []byte) (SourceMetadata, error) {
-	trimmed := bytes.TrimSpace(data)
-	if len(trimmed) == 0 {
-		return SourceMetadata{}, fmt.Errorf("empty coastline payload")
+	payload, err := decodePayload(data)
+	if err != nil {
+		return SourceMetadata{}, err
 	}
 
-	points, err := parseCoastlineData(trimmed, GeoBounds{})
+	coast, err := parseCoastlineData(payload.Data, GeoBounds{})
 	if err != nil {
 		return SourceMetadata{}, err
 	}
 
+	var allPoints []geometry.LatLon
+	for _, ring := range coast.Rings() {
+		allPoints = append(allPoints, ring.Points...)
+	}
+
 	meta := SourceMetadata{
-		PayloadBytes:        len(trimmed),
-		CoastlinePointCount: len(points),
-		Bounds:              boundsFromPoints(points),
+		PayloadBytes:        len(bytes.TrimSpace(data)),
+		CoastlinePointCount: len(coast.MainPoints()),
+		CoastlineRingCount:  coast.RingCount(),
+		Bounds:              boundsFromPoints(allPoints),
 	}
 
-	switch trimmed[0] {
-	case '[':
-		meta.Format = "point-array"
+	meta.Format = payload.Format
+	if payload.Format == FormatPointArray {
 		meta.RootType = "array"
 		meta.FeatureCount = 1
 		meta.GeometryTypes = []string{"PointArray"}
 		return meta, nil
-	case '{':
-		meta.Format = "GeoJSON"
-	default:
-		return SourceMetadata{}, fmt.Errorf("unsupported coastline payload")
 	}
 
 	var envelope struct {
 		Type string `json:"type"`
 	}
-	if err := json.Unmarshal(trimmed, &envelope); err != nil {
+	if err := json.Unmarshal(payload.Data, &envelope); err != nil {
 		return SourceMetadata{}, fmt.Errorf("parse json envelope: %w", err)
 	}
 	meta.RootType = envelope.Type
+	if payload.Root != "" {
+		meta.RootType = payload.Root
+	}
 
 	var root sourceFeatureCollection
-	if err := json.Unmarshal(trimmed, &root); err != nil {
+	if err := json.Unmarshal(payload.Data, &root); err != nil {
 		return SourceMetadata{}, fmt.Errorf("parse geojson envelope: %w", err)
 	}
 
@@ -390,8 +401,15 @@ func snapshotFilename(meta SourceMetadata, datasetName string, now time.Time) st
 	}
 
 	ext := ".geojson"
-	if meta.Format == "point-array" {
+	switch meta.Format {
+	case FormatPointArray:
 		ext = ".json"
+	case FormatKML:
+		ext = ".kml"
+	case FormatGPX:
+		ext = ".gpx"
+	case FormatWKT:
+		ext = ".wkt"
 	}
 
 	return fmt.Sprintf("%s-%s%s", slug, now.Format("20060102-150405"), ext)
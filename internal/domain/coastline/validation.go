@@ -323,3 +323,65 @@ func formatIntersections(intersections []segmentIntersection) string {
 	}
 	return strings.Join(parts, ", ")
 }
+
+// validateSecondaryRings checks every ring except the main one. Problems that
+// abort loading for the main ring only drop the affected ring here, so one
+// broken islet cannot hide the rest of the dataset.
+func validateSecondaryRings(coast Coastline, report *ValidationReport) Coastline {
+	parts := make([]Part, 0, len(coast.Parts))
+	for _, part := range coast.Parts {
+		rings := make([]Ring, 0, len(part.Rings))
+		for _, ring := range part.Rings {
+			if ring.Main {
+				rings = append(rings, ring)
+				continue
+			}
+
+			validated, ok := validateSecondaryRing(ring, report)
+			if ok {
+				rings = append(rings, validated)
+			}
+		}
+		if len(rings) == 0 {
+			continue
+		}
+		part.Rings = rings
+		parts = append(parts, part)
+	}
+	coast.Parts = parts
+	return coast
+}
+
+func validateSecondaryRing(ring Ring, report *ValidationReport) (Ring, bool) {
+	points := ring.Points
+	closed := isClosedPolyline(points)
+	if closed {
+		points = points[:len(points)-1]
+	}
+
+	for i, point := range points {
+		if point.Lat < -90 || point.Lat > 90 || point.Lon < -180 || point.Lon > 180 {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("кольцо %q отброшено: некорректные координаты в точке %d", ring.Name, i))
+			return Ring{}, false
+		}
+	}
+
+	deduped, removed := removeDuplicateCoordinates(points)
+	if removed > 0 {
+		report.Fixes = append(report.Fixes, fmt.Sprintf("кольцо %q: удалены повторяющиеся координаты: %d", ring.Name, removed))
+	}
+	if len(deduped) < 2 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("кольцо %q отброшено: меньше 2 точек", ring.Name))
+		return Ring{}, false
+	}
+
+	if closed {
+		deduped = append(deduped, deduped[0])
+	}
+	if intersections := findSelfIntersections(deduped); len(intersections) > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("кольцо %q имеет self-intersection: %d пересечений", ring.Name, len(intersections)))
+	}
+
+	ring.Points = deduped
+	return ring, true
+}
@@ -0,0 +1,268 @@
+package coastline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// wktGeographicSRID is the only EWKT SRID accepted: coordinates must be
+// WGS84 longitude and latitude.
+const wktGeographicSRID = 4326
+
+var wktTypes = []string{
+	"GEOMETRYCOLLECTION", "MULTILINESTRING", "MULTIPOLYGON", "MULTIPOINT",
+	"LINESTRING", "POLYGON", "POINT",
+}
+
+// looksLikeWKT reports whether data starts with an EWKT SRID or a WKT
+// geometry keyword.
+func looksLikeWKT(data []byte) bool {
+	head := strings.ToUpper(string(data[:min(len(data), 32)]))
+	if strings.HasPrefix(head, "SRID=") {
+		return true
+	}
+	for _, kind := range wktTypes {
+		if strings.HasPrefix(head, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// wktToGeoJSON reads one or more WKT or EWKT geometries separated by
+// whitespace, newlines or semicolons, as database exports write them. Each
+// geometry with lines or polygons becomes a feature; points are dropped. Z
+// and M values are ignored. root is the type of the first geometry.
+func wktToGeoJSON(text string) (payload []byte, root string, err error) {
+	p := &wktParser{text: text}
+	var features []map[string]any
+	for {
+		p.skip(";")
+		if p.done() {
+			break
+		}
+
+		srid := 0
+		if strings.HasPrefix(strings.ToUpper(p.rest()), "SRID=") {
+			p.pos += len("SRID=")
+			value, err := p.number()
+			if err != nil {
+				return nil, "", fmt.Errorf("parse ewkt srid: %w", err)
+			}
+			srid = int(value)
+			if err := p.expect(';'); err != nil {
+				return nil, "", err
+			}
+		}
+		if srid != 0 && srid != wktGeographicSRID {
+			return nil, "", fmt.Errorf("ewkt srid %d is not geographic WGS84 (%d)", srid, wktGeographicSRID)
+		}
+
+		kind := p.word()
+		if root == "" {
+			root = kind
+		}
+		geom, err := p.geometry(kind)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse wkt %s at offset %d: %w", kind, p.pos, err)
+		}
+		if geom != nil {
+			features = append(features, geoJSONFeatureOf(nil, geom))
+		}
+	}
+
+	if len(features) == 0 {
+		return nil, "", fmt.Errorf("wkt does not contain line or polygon geometry")
+	}
+	payload, err = featureCollectionJSON("", features)
+	return payload, root, err
+}
+
+type wktParser struct {
+	text string
+	pos  int
+}
+
+func (p *wktParser) rest() string { return p.text[p.pos:] }
+
+func (p *wktParser) done() bool { return p.pos >= len(p.text) }
+
+// skip moves past whitespace and any of the extra separator bytes.
+func (p *wktParser) skip(extra string) {
+	for !p.done() {
+		c := p.text[p.pos]
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' && !strings.ContainsRune(extra, rune(c)) {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *wktParser) peek() byte {
+	p.skip("")
+	if p.done() {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *wktParser) expect(c byte) error {
+	if got := p.peek(); got != c {
+		if got == 0 {
+			return fmt.Errorf("expected %q, got end of input", c)
+		}
+		return fmt.Errorf("expected %q, got %q", c, got)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) word() string {
+	p.skip("")
+	start := p.pos
+	for !p.done() {
+		c := p.text[p.pos]
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.text[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skip("")
+	start := p.pos
+	for !p.done() && strings.IndexByte("+-.0123456789eE", p.text[p.pos]) >= 0 {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, fmt.Errorf("expected a number at offset %d", start)
+	}
+	return strconv.ParseFloat(p.text[start:p.pos], 64)
+}
+
+// geometry parses the body of a geometry of kind and returns it as GeoJSON,
+// or nil for points and empty geometries.
+func (p *wktParser) geometry(kind string) (map[string]any, error) {
+	// Dimension markers such as LINESTRING Z or POLYGON ZM only announce the
+	// extra ordinates that point skips.
+	for {
+		save := p.pos
+		marker := p.word()
+		if marker == "EMPTY" {
+			return nil, nil
+		}
+		if marker != "Z" && marker != "M" && marker != "ZM" {
+			p.pos = save
+			break
+		}
+	}
+
+	switch kind {
+	case "POINT":
+		_, err := p.pointList()
+		return nil, err
+	case "MULTIPOINT":
+		err := p.list(func() error {
+			if p.peek() == '(' {
+				_, err := p.pointList()
+				return err
+			}
+			_, err := p.point()
+			return err
+		})
+		return nil, err
+	case "LINESTRING":
+		line, err := p.pointList()
+		return geoJSONGeometryOf("LineString", line), err
+	case "POLYGON":
+		rings, err := p.ringList()
+		return geoJSONGeometryOf("Polygon", rings), err
+	case "MULTILINESTRING":
+		lines, err := p.ringList()
+		return geoJSONGeometryOf("MultiLineString", lines), err
+	case "MULTIPOLYGON":
+		var polygons [][][][2]float64
+		err := p.list(func() error {
+			rings, err := p.ringList()
+			polygons = append(polygons, rings)
+			return err
+		})
+		return geoJSONGeometryOf("MultiPolygon", polygons), err
+	case "GEOMETRYCOLLECTION":
+		var geometries []map[string]any
+		err := p.list(func() error {
+			member := p.word()
+			geom, err := p.geometry(member)
+			if geom != nil {
+				geometries = append(geometries, geom)
+			}
+			return err
+		})
+		if err != nil || len(geometries) == 0 {
+			return nil, err
+		}
+		return geoJSONGeometryOfAll(geometries), nil
+	default:
+		return nil, fmt.Errorf("unsupported wkt geometry type %q", kind)
+	}
+}
+
+// list parses "(" item {"," item} ")".
+func (p *wktParser) list(item func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	return p.expect(')')
+}
+
+// point reads "x y [z [m]]" as a lon, lat pair.
+func (p *wktParser) point() ([2]float64, error) {
+	var values []float64
+	for {
+		c := p.peek()
+		if c == ',' || c == ')' || c == 0 {
+			break
+		}
+		value, err := p.number()
+		if err != nil {
+			return [2]float64{}, err
+		}
+		values = append(values, value)
+	}
+	if len(values) < 2 {
+		return [2]float64{}, fmt.Errorf("coordinate must contain x and y")
+	}
+	return [2]float64{values[0], values[1]}, nil
+}
+
+func (p *wktParser) pointList() ([][2]float64, error) {
+	var points [][2]float64
+	err := p.list(func() error {
+		point, err := p.point()
+		points = append(points, point)
+		return err
+	})
+	return points, err
+}
+
+func (p *wktParser) ringList() ([][][2]float64, error) {
+	var rings [][][2]float64
+	err := p.list(func() error {
+		ring, err := p.pointList()
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
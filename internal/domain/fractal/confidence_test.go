@@ -0,0 +1,33 @@
+package fractal
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestBootstrapBoxCountingCoversKochTheory(t *testing.T) {
+	curve := koch.KochCurve([]geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.2}}, 5)
+	analysis := AnalyzeBoxCounting(curve)
+	theoretical := math.Log(4) / math.Log(3)
+
+	interval := BootstrapBoxCountingWith(curve, nil, analysis, 60, 7)
+	if !interval.Valid || interval.Replicates != 60 || interval.Level != 0.95 {
+		t.Fatalf("expected a valid 95%% interval from 60 replicates, got %+v", interval)
+	}
+	if interval.Lower > theoretical || interval.Upper < theoretical {
+		t.Fatalf("expected [%.4f, %.4f] to cover %.4f", interval.Lower, interval.Upper, theoretical)
+	}
+	if interval.StdError <= 0 || interval.Upper-interval.Lower > 8*interval.StdError {
+		t.Fatalf("expected a positive standard error consistent with the width, got %+v", interval)
+	}
+
+	if again := BootstrapBoxCountingWith(curve, nil, analysis, 60, 7); again != interval {
+		t.Fatalf("expected the same seed to give the same interval, got %+v and %+v", interval, again)
+	}
+	if disabled := BootstrapBoxCountingWith(curve, nil, analysis, 0, 7); disabled.Valid {
+		t.Fatal("expected no interval without replicates")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
 )
 
 const (
@@ -41,7 +42,10 @@ type BoxCountingAnalysis struct {
 	StabilitySpread    float64
 	Samples            []BoxCountingSample
 	LocalDimensions    []float64
-	Valid              bool
+	// WindowStart and WindowEnd index the Samples of the regression window.
+	WindowStart int
+	WindowEnd   int
+	Valid       bool
 }
 
 func FractalDimension(points []geometry.LatLon) float64 {
@@ -53,15 +57,25 @@ func FractalDimension(points []geometry.LatLon) float64 {
 }
 
 func AnalyzeBoxCounting(points []geometry.LatLon) BoxCountingAnalysis {
+	return AnalyzeBoxCountingWith(points, nil)
+}
+
+// AnalyzeBoxCountingWith counts boxes in the plane of proj; nil means the
+// default projection centred on the points.
+func AnalyzeBoxCountingWith(points []geometry.LatLon, proj projection.Projector) BoxCountingAnalysis {
+	return analyzeBoxCounts(points, proj, boxesCoveredMetersAverage)
+}
+
+// boxCounter returns the number of boxes of boxSize occupied by the curve,
+// averaged over the grid offsets.
+type boxCounter func(points []Point2D, boxSize, minX, minY float64, offsets [][2]float64) float64
+
+func analyzeBoxCounts(points []geometry.LatLon, proj projection.Projector, count boxCounter) BoxCountingAnalysis {
 	if len(points) < 2 {
 		return BoxCountingAnalysis{}
 	}
 
-	meters := make([]Point2D, len(points))
-	for i, p := range points {
-		meters[i] = latLonToMeters(p)
-	}
-
+	meters := projectMeters(points, proj)
 	minX, maxX, minY, maxY := bboxMeters(meters)
 	width := maxX - minX
 	height := maxY - minY
@@ -78,7 +92,7 @@ func AnalyzeBoxCounting(points []geometry.LatLon) BoxCountingAnalysis {
 		if boxSize <= 0 {
 			continue
 		}
-		boxes := boxesCoveredMetersAverage(meters, boxSize, minX, minY, gridOffsets)
+		boxes := count(meters, boxSize, minX, minY, gridOffsets)
 		if boxes <= 1 {
 			continue
 		}
@@ -129,21 +143,18 @@ func AnalyzeBoxCounting(points []geometry.LatLon) BoxCountingAnalysis {
 		StabilitySpread:    spread,
 		Samples:            samples,
 		LocalDimensions:    localDimensions,
+		WindowStart:        window.start,
+		WindowEnd:          window.end,
 		Valid:              true,
 	}
 }
 
-func latLonToMeters(p geometry.LatLon) Point2D {
-	const (
-		refLat          = 43.5
-		metersPerDegLat = 111194.9
-		metersPerDegLon = 87300.0
-	)
-
-	dLat := (p.Lat - refLat) * metersPerDegLat
-	dLon := (p.Lon - 35.0) * metersPerDegLon
-
-	return Point2D{X: dLon, Y: dLat}
+func projectMeters(points []geometry.LatLon, proj projection.Projector) []Point2D {
+	meters := make([]Point2D, len(points))
+	for i, xy := range geometry.ProjectPoints(proj, points) {
+		meters[i] = Point2D{X: xy[0], Y: xy[1]}
+	}
+	return meters
 }
 
 func bboxMeters(points []Point2D) (minX, maxX, minY, maxY float64) {
@@ -221,14 +232,23 @@ type regressionWindow struct {
 }
 
 func bestRegressionWindow(x, y []float64) *regressionWindow {
+	return bestRegressionWindowMin(x, y, minScaleSamples)
+}
+
+// bestRegressionWindowMin is bestRegressionWindow with a caller-chosen minimum
+// window length; shorter windows are never considered.
+func bestRegressionWindowMin(x, y []float64, minLength int) *regressionWindow {
 	n := len(x)
-	if n < minScaleSamples || len(y) != n {
+	if minLength < minScaleSamples {
+		minLength = minScaleSamples
+	}
+	if n < minLength || len(y) != n {
 		return nil
 	}
 
 	var best *regressionWindow
-	for start := 0; start <= n-minScaleSamples; start++ {
-		for end := start + minScaleSamples - 1; end < n; end++ {
+	for start := 0; start <= n-minLength; start++ {
+		for end := start + minLength - 1; end < n; end++ {
 			xs := x[start : end+1]
 			ys := y[start : end+1]
 			slope, intercept := linearRegression(xs, ys)
@@ -0,0 +1,250 @@
+package fractal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+// Dimension estimators accepted by EstimateDimensionWith. EstimatorBox is the
+// boundary-only box count of AnalyzeBoxCounting.
+const (
+	EstimatorBox          = "box"
+	EstimatorBoxFilled    = "box-filled"
+	EstimatorMassRadius   = "mass-radius"
+	EstimatorInformation  = "information"
+	EstimatorCorrelation  = "correlation"
+	EstimatorMultifractal = "multifractal"
+)
+
+// Estimators lists every estimator in report order.
+var Estimators = []string{
+	EstimatorBox,
+	EstimatorBoxFilled,
+	EstimatorMassRadius,
+	EstimatorInformation,
+	EstimatorCorrelation,
+	EstimatorMultifractal,
+}
+
+const (
+	minEstimatedDimension = 0.5
+	maxEstimatedDimension = 3.0
+	massRadiusCentres     = 64
+)
+
+// DimensionEstimate is one estimator's result in a form shared by all
+// estimators, so they can be compared side by side.
+type DimensionEstimate struct {
+	Estimator          string
+	Dimension          float64
+	RegressionRSquared float64
+	StableAcrossScales bool
+	StabilitySpread    float64
+	SampleCount        int
+	Valid              bool
+}
+
+// EstimateDimensionWith runs one estimator in the plane of proj; nil means the
+// default projection centred on the points. The multifractal estimator
+// reports the capacity dimension D(0) of the spectrum.
+func EstimateDimensionWith(points []geometry.LatLon, proj projection.Projector, estimator string) (DimensionEstimate, error) {
+	switch estimator {
+	case EstimatorBox:
+		return estimateFromBoxCounting(estimator, AnalyzeBoxCountingWith(points, proj)), nil
+	case EstimatorBoxFilled:
+		return estimateFromBoxCounting(estimator, AnalyzeFilledBoxCountingWith(points, proj)), nil
+	case EstimatorMassRadius:
+		analysis := AnalyzeMassRadiusWith(points, proj)
+		return DimensionEstimate{
+			Estimator:          estimator,
+			Dimension:          analysis.Dimension,
+			RegressionRSquared: analysis.RegressionRSquared,
+			StableAcrossScales: analysis.StableAcrossScales,
+			StabilitySpread:    analysis.StabilitySpread,
+			SampleCount:        len(analysis.Samples),
+			Valid:              analysis.Valid,
+		}, nil
+	case EstimatorInformation, EstimatorCorrelation, EstimatorMultifractal:
+		q := map[string]float64{EstimatorInformation: 1, EstimatorCorrelation: 2, EstimatorMultifractal: 0}[estimator]
+		analysis := AnalyzeMultifractalWith(points, proj, []float64{q})
+		estimate := DimensionEstimate{Estimator: estimator, SampleCount: analysis.SampleCount}
+		if len(analysis.Spectrum) == 1 {
+			point := analysis.Spectrum[0]
+			estimate.Dimension = point.Dq
+			estimate.RegressionRSquared = point.RegressionRSquared
+			estimate.StabilitySpread = point.StabilitySpread
+			estimate.StableAcrossScales = point.StableAcrossScales
+			estimate.Valid = analysis.Valid && point.Valid
+		}
+		return estimate, nil
+	default:
+		return DimensionEstimate{}, fmt.Errorf("unknown dimension estimator %q (want one of %s)", estimator, strings.Join(Estimators, ", "))
+	}
+}
+
+func estimateFromBoxCounting(estimator string, analysis BoxCountingAnalysis) DimensionEstimate {
+	return DimensionEstimate{
+		Estimator:          estimator,
+		Dimension:          analysis.Dimension,
+		RegressionRSquared: analysis.RegressionRSquared,
+		StableAcrossScales: analysis.StableAcrossScales,
+		StabilitySpread:    analysis.StabilitySpread,
+		SampleCount:        len(analysis.Samples),
+		Valid:              analysis.Valid,
+	}
+}
+
+// AnalyzeFilledBoxCountingWith counts the boxes touched by the outline or
+// lying inside it, the outline being closed from its last vertex back to the
+// first. A box is inside when its centre is.
+func AnalyzeFilledBoxCountingWith(points []geometry.LatLon, proj projection.Projector) BoxCountingAnalysis {
+	return analyzeBoxCounts(points, proj, boxesFilledMetersAverage)
+}
+
+func boxesFilledMetersAverage(points []Point2D, boxSize, minX, minY float64, offsets [][2]float64) float64 {
+	if len(offsets) == 0 {
+		offsets = [][2]float64{{0, 0}}
+	}
+
+	sum := 0.0
+	for _, off := range offsets {
+		covered := make(map[[2]int]struct{})
+		for i := 1; i < len(points); i++ {
+			markSegmentBoxesOffset(covered, points[i-1], points[i], boxSize, minX, minY, off[0], off[1])
+		}
+		markInteriorBoxes(covered, points, boxSize, minX-off[0]*boxSize, minY-off[1]*boxSize)
+		sum += float64(len(covered))
+	}
+	return sum / float64(len(offsets))
+}
+
+// markInteriorBoxes scans the rows of the grid anchored at (originX, originY)
+// and marks every box whose centre lies inside the closed outline (even-odd
+// rule).
+func markInteriorBoxes(covered map[[2]int]struct{}, points []Point2D, boxSize, originX, originY float64) {
+	crossings := make(map[int][]float64)
+	n := len(points)
+	for i := 0; i < n; i++ {
+		a, b := points[i], points[(i+1)%n]
+		if a.Y == b.Y {
+			continue
+		}
+		low, high := math.Min(a.Y, b.Y), math.Max(a.Y, b.Y)
+		// rows whose centre line y = origin + (row+0.5)*boxSize falls in [low, high)
+		first := int(math.Ceil((low-originY)/boxSize - 0.5))
+		last := int(math.Ceil((high-originY)/boxSize-0.5)) - 1
+		for row := first; row <= last; row++ {
+			y := originY + (float64(row)+0.5)*boxSize
+			x := a.X + (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)
+			crossings[row] = append(crossings[row], x)
+		}
+	}
+
+	for row, xs := range crossings {
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			first := int(math.Ceil((xs[i]-originX)/boxSize - 0.5))
+			last := int(math.Floor((xs[i+1]-originX)/boxSize - 0.5))
+			for col := first; col <= last; col++ {
+				covered[[2]int{row, col}] = struct{}{}
+			}
+		}
+	}
+}
+
+type MassRadiusSample struct {
+	RadiusMeters float64
+	MeanMass     float64
+	LogRadius    float64
+	LogMass      float64
+}
+
+type MassRadiusAnalysis struct {
+	Dimension          float64
+	RegressionRSquared float64
+	StableAcrossScales bool
+	StabilitySpread    float64
+	Samples            []MassRadiusSample
+	LocalDimensions    []float64
+	Valid              bool
+}
+
+// AnalyzeMassRadiusWith is the sandbox method: the curve is resampled at an
+// even step, and the mean number of samples within radius r of centres spread
+// along the curve grows as M(r) ∝ r^D.
+func AnalyzeMassRadiusWith(points []geometry.LatLon, proj projection.Projector) MassRadiusAnalysis {
+	if len(points) < 2 {
+		return MassRadiusAnalysis{}
+	}
+	meters := projectMeters(points, proj)
+	minX, maxX, minY, maxY := bboxMeters(meters)
+	bboxSize := math.Max(maxX-minX, maxY-minY)
+	if bboxSize < 1 {
+		return MassRadiusAnalysis{}
+	}
+
+	measure, step := measurePoints(meters, bboxSize)
+	radii := make([]float64, 0, len(defaultScaleFactors))
+	for i := len(defaultScaleFactors) - 1; i >= 0; i-- {
+		radius := bboxSize / defaultScaleFactors[i]
+		if radius >= 2*step {
+			radii = append(radii, radius)
+		}
+	}
+	if len(radii) < minScaleSamples {
+		return MassRadiusAnalysis{}
+	}
+
+	centres := massRadiusCentres
+	if centres > len(measure) {
+		centres = len(measure)
+	}
+	counts := make([]float64, len(radii))
+	for c := 0; c < centres; c++ {
+		centre := measure[c*len(measure)/centres]
+		for _, p := range measure {
+			d := math.Hypot(p.X-centre.X, p.Y-centre.Y)
+			if k := sort.SearchFloat64s(radii, d); k < len(radii) {
+				counts[k]++
+			}
+		}
+	}
+
+	var samples []MassRadiusSample
+	var logRadius, logMass []float64
+	mass := 0.0
+	for k, radius := range radii {
+		mass += counts[k]
+		mean := mass / float64(centres)
+		if mean <= 1 {
+			continue
+		}
+		sample := MassRadiusSample{RadiusMeters: radius, MeanMass: mean, LogRadius: math.Log(radius), LogMass: math.Log(mean)}
+		samples = append(samples, sample)
+		logRadius = append(logRadius, sample.LogRadius)
+		logMass = append(logMass, sample.LogMass)
+	}
+
+	result := MassRadiusAnalysis{Samples: samples}
+	window := bestRegressionWindow(logRadius, logMass)
+	if window == nil {
+		return result
+	}
+	result.LocalDimensions = localSlopeSeries(window.x, window.y)
+	result.StabilitySpread = valueSpread(result.LocalDimensions)
+	result.RegressionRSquared = window.rSquared
+	if window.slope < minEstimatedDimension || window.slope > maxEstimatedDimension {
+		return result
+	}
+	result.Dimension = window.slope
+	result.StableAcrossScales = len(result.LocalDimensions) >= minStableLocalSlopes &&
+		window.rSquared >= minRegressionRSquared &&
+		result.StabilitySpread <= maxLocalSlopeSpread
+	result.Valid = true
+	return result
+}
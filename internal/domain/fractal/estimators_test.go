@@ -0,0 +1,101 @@
+package fractal
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestEstimatorsAgreeOnKochCurve(t *testing.T) {
+	curve := koch.KochCurve([]geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.2}}, 5)
+	theoretical := math.Log(4) / math.Log(3)
+
+	for _, estimator := range []string{EstimatorBox, EstimatorMassRadius, EstimatorInformation, EstimatorCorrelation, EstimatorMultifractal} {
+		estimate, err := EstimateDimensionWith(curve, nil, estimator)
+		if err != nil {
+			t.Fatalf("%s: %v", estimator, err)
+		}
+		if !estimate.Valid {
+			t.Fatalf("%s: expected a valid estimate, got %+v", estimator, estimate)
+		}
+		if math.Abs(estimate.Dimension-theoretical) > 0.12 {
+			t.Fatalf("%s: expected dimension near %.5f, got %.5f", estimator, theoretical, estimate.Dimension)
+		}
+	}
+
+	if _, err := EstimateDimensionWith(curve, nil, "hausdorff"); err == nil {
+		t.Fatal("expected an error for an unknown estimator")
+	}
+}
+
+func TestFilledBoxCountingOfSquareIsTwoDimensional(t *testing.T) {
+	var square []geometry.LatLon
+	edges := [][2]geometry.LatLon{
+		{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.2}},
+		{{Lat: 0, Lon: 0.2}, {Lat: 0.2, Lon: 0.2}},
+		{{Lat: 0.2, Lon: 0.2}, {Lat: 0.2, Lon: 0}},
+		{{Lat: 0.2, Lon: 0}, {Lat: 0, Lon: 0}},
+	}
+	for _, edge := range edges {
+		for i := 0; i < 50; i++ {
+			square = append(square, interpolateLatLon(edge[0], edge[1], float64(i)/50))
+		}
+	}
+
+	boundary := AnalyzeBoxCountingWith(square, nil)
+	filled := AnalyzeFilledBoxCountingWith(square, nil)
+	if !boundary.Valid || math.Abs(boundary.Dimension-1) > 0.15 {
+		t.Fatalf("expected boundary dimension near 1, got %+v", boundary.Dimension)
+	}
+	if !filled.Valid || math.Abs(filled.Dimension-2) > 0.15 {
+		t.Fatalf("expected filled dimension near 2, got %.5f", filled.Dimension)
+	}
+}
+
+func TestMultifractalSpectrumOfKochCurveIsNarrow(t *testing.T) {
+	curve := koch.KochCurve([]geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.2}}, 5)
+	qs, err := ParseQRange(DefaultQRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis := AnalyzeMultifractalWith(curve, nil, qs)
+	if !analysis.Valid || len(analysis.Spectrum) != 11 {
+		t.Fatalf("expected an 11 point spectrum, got %+v", analysis)
+	}
+	// the length measure of the Koch curve is uniform, so D(q) barely moves
+	first, last := analysis.Spectrum[0], analysis.Spectrum[len(analysis.Spectrum)-1]
+	if first.Dq < last.Dq || first.Dq-last.Dq > 0.3 {
+		t.Fatalf("expected a nearly flat non-increasing D(q), got D(-5)=%.4f D(5)=%.4f", first.Dq, last.Dq)
+	}
+	if analysis.AlphaMax-analysis.AlphaMin > 0.3 {
+		t.Fatalf("expected a narrow f(α), got α in [%.4f, %.4f]", analysis.AlphaMin, analysis.AlphaMax)
+	}
+}
+
+func TestParseQRange(t *testing.T) {
+	qs, err := ParseQRange("-1:1:0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{-1, -0.5, 0, 0.5, 1}
+	if len(qs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, qs)
+	}
+	for i := range want {
+		if qs[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, qs)
+		}
+	}
+
+	if qs, err := ParseQRange("0, 1, 2"); err != nil || len(qs) != 3 || qs[2] != 2 {
+		t.Fatalf("expected a q list, got %v (%v)", qs, err)
+	}
+	for _, spec := range []string{"", "1:0:1", "0:1:0", "a,b"} {
+		if _, err := ParseQRange(spec); err == nil {
+			t.Fatalf("expected an error for %q", spec)
+		}
+	}
+}
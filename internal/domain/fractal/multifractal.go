@@ -0,0 +1,292 @@
+package fractal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+const (
+	// the curve is resampled 4 times finer than the smallest box
+	measureStepsPerBox = 4
+	maxMeasurePoints   = 200000
+	maxQRangeValues    = 201
+	// boxes the curve only clips hold a sliver of a straight crossing and are
+	// left out of the measure, or they dominate negative q
+	minBoxShareOfCrossing = 0.25
+)
+
+// DefaultQRange is the q-range of the spectrum when none is given.
+const DefaultQRange = "-5:5:1"
+
+type MultifractalPoint struct {
+	Q                  float64
+	Tau                float64
+	Dq                 float64
+	Alpha              float64
+	FAlpha             float64
+	RegressionRSquared float64
+	StableAcrossScales bool
+	StabilitySpread    float64
+	Valid              bool
+}
+
+type MultifractalAnalysis struct {
+	Spectrum    []MultifractalPoint
+	SampleCount int
+	WindowStart int
+	WindowEnd   int
+	// AlphaMin and AlphaMax bound the valid f(α) points; their difference is
+	// the width of the spectrum, zero for a monofractal.
+	AlphaMin float64
+	AlphaMax float64
+	Valid    bool
+}
+
+// AnalyzeMultifractalWith estimates the generalised dimensions D(q) and the
+// f(α) spectrum of the length measure of the curve: every box holds the share
+// p of the curve length inside it. For each q the partition sum
+// Z(q, ε) = Σ p^q scales as ε^τ(q) with D(q) = τ(q)/(q-1); D(1) is the
+// information dimension from Σ p ln p and α, f(α) follow Chhabra–Jensen.
+// All q share the scale window chosen by the box count D(0).
+func AnalyzeMultifractalWith(points []geometry.LatLon, proj projection.Projector, qs []float64) MultifractalAnalysis {
+	if len(points) < 2 || len(qs) == 0 {
+		return MultifractalAnalysis{}
+	}
+	meters := projectMeters(points, proj)
+	minX, maxX, minY, maxY := bboxMeters(meters)
+	bboxSize := math.Max(maxX-minX, maxY-minY)
+	if bboxSize < 1 {
+		return MultifractalAnalysis{}
+	}
+	measure, step := measurePoints(meters, bboxSize)
+
+	// per scale: log(1/ε), log N and per q the log partition sum, α and f terms
+	var logInvScale, logBoxes []float64
+	var tauY, alphaY, fY [][]float64
+	for _, factor := range defaultScaleFactors {
+		boxSize := bboxSize / factor
+		if boxSize < 2*step {
+			continue
+		}
+		boxes := 0.0
+		tau := make([]float64, len(qs))
+		alpha := make([]float64, len(qs))
+		f := make([]float64, len(qs))
+		for _, off := range gridOffsets {
+			p := boxShares(measure, step, boxSize, minX-off[0]*boxSize, minY-off[1]*boxSize)
+			boxes += float64(len(p))
+			for i, q := range qs {
+				t, a, fa := partitionTerms(p, q)
+				tau[i] += t
+				alpha[i] += a
+				f[i] += fa
+			}
+		}
+		offsets := float64(len(gridOffsets))
+		if boxes/offsets <= 1 {
+			continue
+		}
+		for i := range qs {
+			tau[i] /= offsets
+			alpha[i] /= offsets
+			f[i] /= offsets
+		}
+		logInvScale = append(logInvScale, math.Log(factor))
+		logBoxes = append(logBoxes, math.Log(boxes/offsets))
+		tauY = append(tauY, tau)
+		alphaY = append(alphaY, alpha)
+		fY = append(fY, f)
+	}
+
+	result := MultifractalAnalysis{SampleCount: len(logInvScale)}
+	window := bestRegressionWindow(logInvScale, logBoxes)
+	if window == nil || window.slope < minEstimatedDimension || window.slope > maxEstimatedDimension {
+		return result
+	}
+	result.WindowStart = window.start
+	result.WindowEnd = window.end
+	result.Valid = true
+
+	x := window.x
+	column := func(rows [][]float64, i int) []float64 {
+		values := make([]float64, 0, len(x))
+		for _, row := range rows[window.start : window.end+1] {
+			values = append(values, row[i])
+		}
+		return values
+	}
+	result.AlphaMin, result.AlphaMax = math.Inf(1), math.Inf(-1)
+	for i, q := range qs {
+		y := column(tauY, i)
+		slope, intercept := linearRegression(x, y)
+		locals := localSlopeSeries(x, y)
+		point := MultifractalPoint{Q: q, RegressionRSquared: regressionRSquared(x, y, slope, intercept)}
+		// for q = 1 the fitted term is the entropy, whose slope is D(1) itself;
+		// otherwise the slope is -τ(q) and local slopes are rescaled the same way
+		if q == 1 {
+			point.Dq = slope
+		} else {
+			point.Tau = -slope
+			point.Dq = point.Tau / (q - 1)
+			for j := range locals {
+				locals[j] = -locals[j] / (q - 1)
+			}
+		}
+		point.StabilitySpread = valueSpread(locals)
+		point.Alpha, _ = linearRegression(x, column(alphaY, i))
+		point.FAlpha, _ = linearRegression(x, column(fY, i))
+		point.StableAcrossScales = len(locals) >= minStableLocalSlopes &&
+			point.RegressionRSquared >= minRegressionRSquared &&
+			point.StabilitySpread <= maxLocalSlopeSpread
+		point.Valid = !math.IsNaN(point.Dq) && !math.IsInf(point.Dq, 0) && !math.IsNaN(point.Alpha) && !math.IsNaN(point.FAlpha)
+		if point.Valid {
+			result.AlphaMin = math.Min(result.AlphaMin, point.Alpha)
+			result.AlphaMax = math.Max(result.AlphaMax, point.Alpha)
+		}
+		result.Spectrum = append(result.Spectrum, point)
+	}
+	if math.IsInf(result.AlphaMin, 0) {
+		result.AlphaMin, result.AlphaMax = 0, 0
+	}
+	return result
+}
+
+// partitionTerms returns, for the box shares p, the quantities regressed
+// against log(1/ε): log Σ p^q (the entropy -Σ p ln p for q = 1), and the
+// Chhabra–Jensen terms -Σ μ ln p and -Σ μ ln μ with μ = p^q / Σ p^q.
+func partitionTerms(p []float64, q float64) (tau, alpha, f float64) {
+	if q == 1 {
+		entropy := 0.0
+		for _, v := range p {
+			entropy -= v * math.Log(v)
+		}
+		return entropy, entropy, entropy
+	}
+
+	// Σ p^q through the largest log term to keep negative q finite
+	logs := make([]float64, len(p))
+	maxLog := math.Inf(-1)
+	for i, v := range p {
+		logs[i] = q * math.Log(v)
+		maxLog = math.Max(maxLog, logs[i])
+	}
+	sum := 0.0
+	for _, l := range logs {
+		sum += math.Exp(l - maxLog)
+	}
+	logZ := maxLog + math.Log(sum)
+	for i, v := range p {
+		mu := math.Exp(logs[i] - logZ)
+		if mu == 0 {
+			continue
+		}
+		alpha -= mu * math.Log(v)
+		f -= mu * math.Log(mu)
+	}
+	return logZ, alpha, f
+}
+
+// boxShares is the share of measure points in every box of the grid anchored
+// at (originX, originY) that holds at least minBoxShareOfCrossing of a
+// straight crossing; step is the spacing of the measure points.
+func boxShares(points []Point2D, step, boxSize, originX, originY float64) []float64 {
+	counts := make(map[[2]int]int)
+	for _, p := range points {
+		counts[[2]int{int(math.Floor((p.Y - originY) / boxSize)), int(math.Floor((p.X - originX) / boxSize))}]++
+	}
+	minCount := int(minBoxShareOfCrossing * boxSize / step)
+	total := 0
+	for _, count := range counts {
+		if count >= minCount {
+			total += count
+		}
+	}
+	shares := make([]float64, 0, len(counts))
+	for _, count := range counts {
+		if count >= minCount {
+			shares = append(shares, float64(count)/float64(total))
+		}
+	}
+	return shares
+}
+
+// measurePoints resamples the polyline at an even step along its length, so
+// every returned point carries the same share of the length measure. The step
+// is a fraction of the smallest box, coarsened when the curve is long.
+func measurePoints(points []Point2D, bboxSize float64) ([]Point2D, float64) {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += math.Hypot(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y)
+	}
+	smallestBox := bboxSize / defaultScaleFactors[len(defaultScaleFactors)-1]
+	step := math.Max(smallestBox/measureStepsPerBox, total/maxMeasurePoints)
+	if total <= 0 {
+		return []Point2D{points[0]}, step
+	}
+
+	measure := make([]Point2D, 0, int(total/step)+1)
+	next := step / 2
+	walked := 0.0
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		for next <= walked+length && length > 0 {
+			t := (next - walked) / length
+			measure = append(measure, Point2D{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t})
+			next += step
+		}
+		walked += length
+	}
+	return measure, step
+}
+
+// ParseQRange reads "min:max:step" or a comma separated list of q values.
+func ParseQRange(spec string) ([]float64, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("q-range is empty")
+	}
+
+	if parts := strings.Split(spec, ":"); len(parts) == 3 {
+		var bounds [3]float64
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, fmt.Errorf("q-range %q: %q is not a number", spec, part)
+			}
+			bounds[i] = value
+		}
+		low, high, step := bounds[0], bounds[1], bounds[2]
+		if step <= 0 || high < low {
+			return nil, fmt.Errorf("q-range %q must be min:max:step with min <= max and step > 0", spec)
+		}
+		count := int(math.Floor((high-low)/step+1e-9)) + 1
+		if count > maxQRangeValues {
+			return nil, fmt.Errorf("q-range %q has more than %d values", spec, maxQRangeValues)
+		}
+		qs := make([]float64, count)
+		for i := range qs {
+			// round away the accumulated step error, e.g. 0.30000000000000004
+			qs[i] = math.Round((low+float64(i)*step)*1e9) / 1e9
+		}
+		return qs, nil
+	}
+
+	var qs []float64
+	for _, item := range strings.Split(spec, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("q-range %q: %q is not a number", spec, item)
+		}
+		qs = append(qs, value)
+	}
+	if len(qs) > maxQRangeValues {
+		return nil, fmt.Errorf("q-range %q has more than %d values", spec, maxQRangeValues)
+	}
+	return qs, nil
+}
@@ -0,0 +1,231 @@
+package fractal
+
+import (
+	"math"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+const (
+	minRichardsonDimension = 0.9
+	maxRichardsonDimension = 2.0
+	dividerBisectionSteps  = 40
+)
+
+// ruler lengths are taken relative to the largest bbox side, mirroring box-counting scales
+var defaultRulerFactors = []float64{4, 6, 8, 12, 16, 24, 32, 48, 64, 96, 128, 192, 256, 384, 512}
+
+// RichardsonSample is one ruler of the walk. Each direction measures
+// Steps·RulerKM + RemainderKM; LengthKM is the mean of the two.
+type RichardsonSample struct {
+	ScaleFactor         float64
+	RulerKM             float64
+	ForwardSteps        int
+	ForwardRemainderKM  float64
+	BackwardSteps       int
+	BackwardRemainderKM float64
+	LengthKM            float64
+	LogInvRuler         float64
+	LogLength           float64
+}
+
+type RichardsonAnalysis struct {
+	Dimension          float64
+	Exponent           float64
+	Prefactor          float64
+	RegressionRSquared float64
+	StableAcrossScales bool
+	StabilitySpread    float64
+	ExtentKM           float64
+	Samples            []RichardsonSample
+	LocalDimensions    []float64
+	WindowStart        int
+	WindowEnd          int
+	Valid              bool
+}
+
+// AnalyzeRichardson walks the polyline with geodesic dividers of decreasing
+// length and fits log L(ε) against log ε. The Richardson exponent is the slope
+// of that fit and the divider dimension is D = 1 - slope.
+func AnalyzeRichardson(points []geometry.LatLon) RichardsonAnalysis {
+	if len(points) < 2 {
+		return RichardsonAnalysis{}
+	}
+
+	extent := extentKM(points)
+	if extent <= 0 {
+		return RichardsonAnalysis{}
+	}
+
+	// rulers shorter than the mean segment only re-measure the straight chords
+	meanSegment := geometry.PolylineLength(points) / float64(len(points)-1)
+	reversed := reverseLatLon(points)
+
+	samples := make([]RichardsonSample, 0, len(defaultRulerFactors))
+	logInvRuler := make([]float64, 0, len(defaultRulerFactors))
+	logLength := make([]float64, 0, len(defaultRulerFactors))
+	for _, factor := range defaultRulerFactors {
+		ruler := extent / factor
+		if ruler < meanSegment {
+			continue
+		}
+
+		// average forward and backward walks so the result does not depend on the start vertex
+		forwardSteps, forwardRemainder := dividerLength(points, ruler)
+		backwardSteps, backwardRemainder := dividerLength(reversed, ruler)
+		if forwardSteps < 1 || backwardSteps < 1 {
+			continue
+		}
+
+		length := (float64(forwardSteps+backwardSteps)*ruler + forwardRemainder + backwardRemainder) / 2
+		if length <= 0 {
+			continue
+		}
+
+		sample := RichardsonSample{
+			ScaleFactor:         factor,
+			RulerKM:             ruler,
+			ForwardSteps:        forwardSteps,
+			ForwardRemainderKM:  forwardRemainder,
+			BackwardSteps:       backwardSteps,
+			BackwardRemainderKM: backwardRemainder,
+			LengthKM:            length,
+			LogInvRuler:         math.Log(1.0 / ruler),
+			LogLength:           math.Log(length),
+		}
+		samples = append(samples, sample)
+		logInvRuler = append(logInvRuler, sample.LogInvRuler)
+		logLength = append(logLength, sample.LogLength)
+	}
+
+	result := RichardsonAnalysis{ExtentKM: extent, Samples: samples}
+	if len(samples) < minScaleSamples {
+		return result
+	}
+
+	// divider lengths oscillate on self-similar curves, so short windows are not trusted
+	window := bestRegressionWindowMin(logInvRuler, logLength, (len(samples)+1)/2)
+	if window == nil || window.length < minScaleSamples {
+		return result
+	}
+
+	// fit is log L = slope*log(1/ε) + c, so the Richardson exponent on log ε is -slope
+	localDimensions := localSlopeSeries(window.x, window.y)
+	for i := range localDimensions {
+		localDimensions[i] += 1
+	}
+	spread := valueSpread(localDimensions)
+
+	result.Exponent = -window.slope
+	result.Dimension = 1 + window.slope
+	result.Prefactor = math.Exp(window.intercept)
+	result.RegressionRSquared = window.rSquared
+	result.StabilitySpread = spread
+	result.LocalDimensions = localDimensions
+	result.WindowStart = window.start
+	result.WindowEnd = window.end
+	result.StableAcrossScales = len(localDimensions) >= minStableLocalSlopes &&
+		window.rSquared >= minRegressionRSquared &&
+		spread <= maxLocalSlopeSpread
+	result.Valid = result.Dimension >= minRichardsonDimension && result.Dimension <= maxRichardsonDimension
+	return result
+}
+
+// DividerWalk returns the divider pivots produced by stepping along the
+// polyline with a fixed geodesic opening of rulerKM. The first pivot is the
+// first vertex; the walk stops when the remaining line is shorter than one ruler.
+func DividerWalk(points []geometry.LatLon, rulerKM float64) []geometry.LatLon {
+	if len(points) == 0 {
+		return nil
+	}
+
+	pivots := []geometry.LatLon{points[0]}
+	if len(points) < 2 || rulerKM <= 0 {
+		return pivots
+	}
+
+	pivot := points[0]
+	segment := 0
+	t := 0.0
+	for segment < len(points)-1 {
+		next, nextSegment, nextT, ok := nextDividerPoint(points, pivot, segment, t, rulerKM)
+		if !ok {
+			break
+		}
+		pivots = append(pivots, next)
+		pivot = next
+		segment = nextSegment
+		t = nextT
+	}
+	return pivots
+}
+
+func dividerLength(points []geometry.LatLon, rulerKM float64) (int, float64) {
+	walk := DividerWalk(points, rulerKM)
+	if len(walk) == 0 {
+		return 0, 0
+	}
+	return len(walk) - 1, geometry.Haversine(walk[len(walk)-1], points[len(points)-1])
+}
+
+// nextDividerPoint scans forward from (segment, t) for the first point on the
+// polyline whose geodesic distance from pivot reaches rulerKM.
+func nextDividerPoint(points []geometry.LatLon, pivot geometry.LatLon, segment int, t, rulerKM float64) (geometry.LatLon, int, float64, bool) {
+	for i := segment; i < len(points)-1; i++ {
+		startT := 0.0
+		if i == segment {
+			startT = t
+		}
+
+		a := points[i]
+		b := points[i+1]
+		if geometry.Haversine(pivot, b) < rulerKM {
+			continue
+		}
+
+		low := startT
+		high := 1.0
+		for step := 0; step < dividerBisectionSteps; step++ {
+			mid := (low + high) / 2
+			if geometry.Haversine(pivot, interpolateLatLon(a, b, mid)) < rulerKM {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		return interpolateLatLon(a, b, high), i, high, true
+	}
+	return geometry.LatLon{}, 0, 0, false
+}
+
+func interpolateLatLon(a, b geometry.LatLon, t float64) geometry.LatLon {
+	return geometry.LatLon{
+		Lat: a.Lat + (b.Lat-a.Lat)*t,
+		Lon: a.Lon + (b.Lon-a.Lon)*t,
+	}
+}
+
+func reverseLatLon(points []geometry.LatLon) []geometry.LatLon {
+	reversed := make([]geometry.LatLon, len(points))
+	for i, p := range points {
+		reversed[len(points)-1-i] = p
+	}
+	return reversed
+}
+
+func extentKM(points []geometry.LatLon) float64 {
+	minLat, maxLat := points[0].Lat, points[0].Lat
+	minLon, maxLon := points[0].Lon, points[0].Lon
+	for _, p := range points[1:] {
+		minLat = math.Min(minLat, p.Lat)
+		maxLat = math.Max(maxLat, p.Lat)
+		minLon = math.Min(minLon, p.Lon)
+		maxLon = math.Max(maxLon, p.Lon)
+	}
+
+	midLat := (minLat + maxLat) / 2
+	midLon := (minLon + maxLon) / 2
+	width := geometry.Haversine(geometry.LatLon{Lat: midLat, Lon: minLon}, geometry.LatLon{Lat: midLat, Lon: maxLon})
+	height := geometry.Haversine(geometry.LatLon{Lat: minLat, Lon: midLon}, geometry.LatLon{Lat: maxLat, Lon: midLon})
+	return math.Max(width, height)
+}
@@ -0,0 +1,77 @@
+package fractal
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestDividerWalkStepsAlongStraightLine(t *testing.T) {
+	line := []geometry.LatLon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 0.5},
+		{Lat: 0, Lon: 1.0},
+	}
+
+	total := geometry.PolylineLength(line)
+	ruler := total / 4
+	walk := DividerWalk(line, ruler)
+	if len(walk) != 5 {
+		t.Fatalf("expected 5 pivots for 4 rulers, got %d", len(walk))
+	}
+
+	for i := 1; i < len(walk); i++ {
+		step := geometry.Haversine(walk[i-1], walk[i])
+		if math.Abs(step-ruler) > 1e-6 {
+			t.Fatalf("expected divider opening %.6f km, got %.6f km at step %d", ruler, step, i)
+		}
+	}
+}
+
+func TestAnalyzeRichardsonStraightLine(t *testing.T) {
+	line := make([]geometry.LatLon, 0, 601)
+	for i := 0; i <= 600; i++ {
+		line = append(line, geometry.LatLon{Lat: 0, Lon: 0.15 * float64(i) / 600})
+	}
+
+	analysis := AnalyzeRichardson(line)
+	if !analysis.Valid {
+		t.Fatalf("expected valid analysis for a straight line, got %+v", analysis)
+	}
+	if math.Abs(analysis.Dimension-1.0) > 0.05 {
+		t.Fatalf("expected divider dimension near 1.0, got %.5f", analysis.Dimension)
+	}
+	if math.Abs(analysis.Exponent-(1-analysis.Dimension)) > 1e-12 {
+		t.Fatalf("expected exponent 1-D, got exponent %.5f for D %.5f", analysis.Exponent, analysis.Dimension)
+	}
+}
+
+func TestAnalyzeRichardsonKochCurveNearTheory(t *testing.T) {
+	base := []geometry.LatLon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 0.2},
+	}
+
+	curve := koch.KochCurve(base, 5)
+	analysis := AnalyzeRichardson(curve)
+	theoretical := math.Log(4) / math.Log(3)
+
+	if !analysis.Valid {
+		t.Fatal("expected valid analysis for Koch curve")
+	}
+	if math.Abs(analysis.Dimension-theoretical) > 0.10 {
+		t.Fatalf("expected divider dimension near %.5f, got %.5f", theoretical, analysis.Dimension)
+	}
+	if analysis.RegressionRSquared < 0.9 {
+		t.Fatalf("expected a good log-log fit, got R²=%.4f", analysis.RegressionRSquared)
+	}
+	for _, s := range analysis.Samples {
+		forward := float64(s.ForwardSteps)*s.RulerKM + s.ForwardRemainderKM
+		backward := float64(s.BackwardSteps)*s.RulerKM + s.BackwardRemainderKM
+		if math.Abs((forward+backward)/2-s.LengthKM) > 1e-9 || s.ForwardRemainderKM >= s.RulerKM || s.BackwardRemainderKM >= s.RulerKM {
+			t.Fatalf("ruler %.3f km: expected L = mean of the walks %.6f and %.6f with remainders below one ruler, got %+v", s.RulerKM, forward, backward, s)
+		}
+	}
+}
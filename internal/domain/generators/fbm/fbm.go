@@ -0,0 +1,155 @@
+package fbm
+
+import (
+	"math"
+	"math/rand"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+const (
+	// MaxIterations bounds the midpoint refinement: every iteration doubles
+	// the number of segments.
+	MaxIterations = 12
+	// DefaultHurst gives the target dimension 1.3, close to rocky coasts.
+	DefaultHurst = 0.7
+	// DefaultAmplitude is the standard deviation of the first midpoint
+	// displacement as a share of the base segment length.
+	DefaultAmplitude = 0.3
+)
+
+type Options struct {
+	Seed      int64
+	Hurst     float64
+	Amplitude float64
+	// Projection is the plane the normals are taken in; nil means the
+	// default projection centred on the base.
+	Projection projection.Projector
+}
+
+// TargetDimension is the box-counting dimension D = 2 − H of the graph of
+// fBm with Hurst exponent H.
+func TargetDimension(hurst float64) float64 {
+	return 2 - hurst
+}
+
+// node is a vertex of the curve: the base segment it belongs to, its
+// position t ∈ [0, 1] along the segment and the displacement h along the
+// segment normal in segment lengths.
+type node struct {
+	segment int
+	t, h    float64
+}
+
+// Curve displaces every base segment along its normal by a fractional
+// Brownian bridge built by random midpoint displacement: iteration k inserts
+// the midpoint of every segment, shifted from the mean of its ends by a
+// Gaussian with standard deviation Amplitude·2^(−(k−1)H). Base vertices stay
+// in place, so each segment becomes the graph of fBm with dimension
+// TargetDimension(H) at scales below its length. Iteration n is a refinement
+// of iteration n−1 for the same seed. iterations is clamped to
+// [0, MaxIterations].
+func Curve(base []geometry.LatLon, iterations int, opts Options) []geometry.LatLon {
+	iterations = max(0, min(iterations, MaxIterations))
+	result := make([]geometry.LatLon, len(base))
+	copy(result, base)
+	if iterations == 0 || len(base) < 2 {
+		return result
+	}
+
+	proj := opts.Projection
+	if proj == nil {
+		proj, _ = geometry.ProjectionFor(projection.Default, base)
+	}
+	hurst := opts.Hurst
+	if !(hurst > 0 && hurst < 1) {
+		hurst = DefaultHurst
+	}
+	amplitude := math.Max(opts.Amplitude, 0)
+
+	nodes := make([]node, 0, len(base))
+	for i := 0; i < len(base)-1; i++ {
+		nodes = append(nodes, node{segment: i})
+	}
+	nodes = append(nodes, node{segment: len(base) - 2, t: 1})
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	sigma := amplitude
+	for k := 0; k < iterations; k++ {
+		refined := make([]node, 0, 2*len(nodes)-1)
+		for i := 0; i+1 < len(nodes); i++ {
+			a, b := nodes[i], nodes[i+1]
+			end := b.t
+			if b.segment != a.segment {
+				end = 1
+			}
+			refined = append(refined, a, node{
+				segment: a.segment,
+				t:       (a.t + end) / 2,
+				h:       (a.h+b.h)/2 + sigma*rng.NormFloat64(),
+			})
+		}
+		nodes = append(refined, nodes[len(nodes)-1])
+		sigma *= math.Pow(2, -hurst)
+	}
+
+	plane := geometry.ProjectPoints(proj, base)
+	out := make([]geometry.LatLon, len(nodes))
+	for i, n := range nodes {
+		if n.h == 0 && (n.t == 0 || n.t == 1) {
+			out[i] = base[n.segment+int(n.t)]
+			continue
+		}
+		a, b := plane[n.segment], plane[n.segment+1]
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		lat, lon := proj.Inverse(a[0]+n.t*dx-n.h*dy, a[1]+n.t*dy+n.h*dx)
+		out[i] = geometry.LatLon{Lat: lat, Lon: lon}
+	}
+	return out
+}
+
+// Sample is one iteration of the fBm refinement. GrowthKM is measured
+// against the previous iteration and is zero at iteration 0.
+type Sample struct {
+	Iteration   int
+	PointsCount int
+	LengthKM    float64
+	GrowthKM    float64
+	RatioToBase float64
+}
+
+type Report struct {
+	Options      Options
+	BasePoints   int
+	BaseLengthKM float64
+	Samples      []Sample
+}
+
+// Analyze measures the fBm curve for every iteration up to maxIterations.
+func Analyze(base []geometry.LatLon, maxIterations int, opts Options) Report {
+	baseLength := geometry.PolylineLength(base)
+	report := Report{
+		Options:      opts,
+		BasePoints:   len(base),
+		BaseLengthKM: baseLength,
+		Samples:      make([]Sample, 0, maxIterations+1),
+	}
+
+	prevLength := baseLength
+	for iter := 0; iter <= maxIterations; iter++ {
+		curve := Curve(base, iter, opts)
+		length := geometry.PolylineLength(curve)
+
+		sample := Sample{Iteration: iter, PointsCount: len(curve), LengthKM: length, RatioToBase: 1}
+		if baseLength > 0 {
+			sample.RatioToBase = length / baseLength
+		}
+		if iter > 0 {
+			sample.GrowthKM = length - prevLength
+		}
+		report.Samples = append(report.Samples, sample)
+		prevLength = length
+	}
+	return report
+}
@@ -0,0 +1,68 @@
+package fbm
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestCurveRefinesTheBaseByMidpointDisplacement(t *testing.T) {
+	base := []geometry.LatLon{{Lat: 44, Lon: 30}, {Lat: 44, Lon: 30.5}, {Lat: 44.3, Lon: 30.8}}
+	opts := Options{Seed: 3, Hurst: 0.6, Amplitude: 0.3}
+
+	coarse := Curve(base, 3, opts)
+	fine := Curve(base, 4, opts)
+	if len(coarse) != 2*8+1 || len(fine) != 2*16+1 {
+		t.Fatalf("expected every iteration to double the segments, got %d and %d points", len(coarse), len(fine))
+	}
+	if coarse[0] != base[0] || coarse[8] != base[1] || coarse[16] != base[2] {
+		t.Fatal("expected the base vertices to stay in place")
+	}
+	for i, p := range coarse {
+		if q := fine[2*i]; math.Abs(p.Lat-q.Lat) > 1e-9 || math.Abs(p.Lon-q.Lon) > 1e-9 {
+			t.Fatalf("expected iteration 4 to refine iteration 3, point %d moved from %v to %v", i, p, q)
+		}
+	}
+	if !slices.Equal(fine, Curve(base, 4, opts)) {
+		t.Fatal("expected the same curve for the same seed")
+	}
+	if flat := Curve(base, 4, Options{Seed: 3, Hurst: 0.6}); geometry.PolylineLength(flat)-geometry.PolylineLength(base) > 1e-6 {
+		t.Fatal("expected zero amplitude to keep the base line")
+	}
+}
+
+func TestBoxCountingTracksTargetDimension(t *testing.T) {
+	line := []geometry.LatLon{{Lat: 44, Lon: 30}, {Lat: 44, Lon: 32}}
+	previous := 2.0
+	for _, hurst := range []float64{0.3, 0.5, 0.7, 0.9} {
+		analysis := fractal.AnalyzeBoxCounting(Curve(line, 12, Options{Seed: 1, Hurst: hurst, Amplitude: 1}))
+		target := TargetDimension(hurst)
+		if !analysis.Valid || math.Abs(analysis.Dimension-target) > 0.15 {
+			t.Fatalf("H=%.1f: expected D near %.2f, got %.3f (valid=%t)", hurst, target, analysis.Dimension, analysis.Valid)
+		}
+		if analysis.Dimension >= previous {
+			t.Fatalf("H=%.1f: expected D to fall as H grows, got %.3f after %.3f", hurst, analysis.Dimension, previous)
+		}
+		previous = analysis.Dimension
+	}
+}
+
+func TestAnalyzeReportsGrowth(t *testing.T) {
+	base := []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.1}}
+	opts := Options{Seed: 5, Hurst: DefaultHurst, Amplitude: DefaultAmplitude}
+
+	report := Analyze(base, 3, opts)
+	if report.BasePoints != 2 || len(report.Samples) != 4 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if first := report.Samples[0]; first.GrowthKM != 0 || first.RatioToBase != 1 {
+		t.Fatalf("expected iteration 0 to match the base, got %+v", first)
+	}
+	last := report.Samples[3]
+	if last.PointsCount != 9 || last.RatioToBase <= 1 {
+		t.Fatalf("expected 9 points longer than the base, got %+v", last)
+	}
+}
@@ -0,0 +1,203 @@
+package koch
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+// Deterministic curves accepted by NewGenerator.
+const (
+	GeneratorKoch       = "koch"
+	GeneratorQuadratic1 = "quadratic-1"
+	GeneratorQuadratic2 = "quadratic-2"
+	GeneratorMinkowski  = "minkowski"
+	GeneratorCesaro     = "cesaro"
+	GeneratorLevy       = "levy-c"
+	GeneratorGosper     = "gosper"
+)
+
+// Generators lists every generator in report order.
+var Generators = []string{
+	GeneratorKoch,
+	GeneratorQuadratic1,
+	GeneratorQuadratic2,
+	GeneratorMinkowski,
+	GeneratorCesaro,
+	GeneratorLevy,
+	GeneratorGosper,
+}
+
+// DefaultCesaroAngle is the base angle of the Cesàro spike in degrees; 60°
+// gives the triadic Koch curve and the curve fills the plane towards 90°.
+const DefaultCesaroAngle = 85.0
+
+// Generator replaces every segment of a polyline by a scaled copy of its
+// motif, iteration after iteration.
+type Generator interface {
+	Name() string
+	// Apply clamps iterations to [0, MaxIterations()].
+	Apply(base []geometry.LatLon, iterations int) []geometry.LatLon
+	// TheoreticalDimension is the similarity dimension of the limit curve.
+	TheoreticalDimension() float64
+	// TheoreticalLengthFactor is the length ratio of one iteration to the
+	// previous one.
+	TheoreticalLengthFactor() float64
+	// Segments is the number of motif segments that replace one segment.
+	Segments() int
+	// MaxIterations keeps the points grown from one base segment within
+	// those of MaxIterations triadic Koch iterations.
+	MaxIterations() int
+}
+
+type GeneratorOptions struct {
+	// CesaroAngleDeg is the spike angle of GeneratorCesaro; values outside
+	// (0, 90) fall back to DefaultCesaroAngle.
+	CesaroAngleDeg float64
+	// Projection is the plane the motifs are laid out in; nil means the
+	// default projection centred on the base.
+	Projection projection.Projector
+}
+
+// NewGenerator looks up a generator by name, ignoring case.
+func NewGenerator(name string, opts GeneratorOptions) (Generator, error) {
+	var points []complex128
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case GeneratorKoch:
+		points = cesaroMotif(60)
+	case GeneratorQuadratic1:
+		points = gridMotif(3, "F+F-F-F+F")
+	case GeneratorQuadratic2:
+		points = gridMotif(8, "-F+F-F-F+F+FF-F+F+FF+F-F-FF+FF-FF+F+F-FF-F-F+FF-F-F+F+F-F+")
+	case GeneratorMinkowski:
+		points = gridMotif(4, "F+F-F-FF+F+F-F")
+	case GeneratorCesaro:
+		angle := opts.CesaroAngleDeg
+		if !(angle > 0 && angle < 90) {
+			angle = DefaultCesaroAngle
+		}
+		points = cesaroMotif(angle)
+	case GeneratorLevy:
+		points = []complex128{0, complex(0.5, 0.5), 1}
+	case GeneratorGosper:
+		// Three segments of length 1/√7 turned by φ = atan(√3/5) so that
+		// the 120° bend lands on (1, 0).
+		step := cmplx.Rect(1/math.Sqrt(7), -math.Atan(math.Sqrt(3)/5))
+		points = []complex128{0, step, step + step*cmplx.Rect(1, math.Pi/3), 1}
+	default:
+		return nil, fmt.Errorf("unknown generator %q (known: %s)", name, strings.Join(Generators, ", "))
+	}
+	return motif{name: strings.ToLower(strings.TrimSpace(name)), points: points, proj: opts.Projection}, nil
+}
+
+// cesaroMotif is the Koch spike with base angle angleDeg: four segments of
+// length 1/(2 + 2·cos angle).
+func cesaroMotif(angleDeg float64) []complex128 {
+	angle := angleDeg * math.Pi / 180
+	r := 1 / (2 + 2*math.Cos(angle))
+	return []complex128{0, complex(r, 0), complex(0.5, r*math.Sin(angle)), complex(1-r, 0), 1}
+}
+
+// gridMotif traces a turtle path of unit steps with 90° turns (+ is left)
+// and scales it by 1/size; the path must end at (size, 0).
+func gridMotif(size int, path string) []complex128 {
+	points := []complex128{0}
+	position, heading := complex(0, 0), complex(1, 0)
+	for _, c := range path {
+		switch c {
+		case '+':
+			heading *= 1i
+		case '-':
+			heading *= -1i
+		case 'F':
+			position += heading
+			points = append(points, position/complex(float64(size), 0))
+		}
+	}
+	return points
+}
+
+// motif is a generator whose segments all have the same length; points run
+// from 0 to 1 in the complex plane with the bumps on the left of the
+// segment.
+type motif struct {
+	name   string
+	points []complex128
+	proj   projection.Projector
+}
+
+func (m motif) Name() string {
+	return m.name
+}
+
+func (m motif) Segments() int {
+	return len(m.points) - 1
+}
+
+func (m motif) TheoreticalLengthFactor() float64 {
+	return float64(m.Segments()) * cmplx.Abs(m.points[1]-m.points[0])
+}
+
+func (m motif) TheoreticalDimension() float64 {
+	return math.Log(float64(m.Segments())) / -math.Log(cmplx.Abs(m.points[1]-m.points[0]))
+}
+
+func (m motif) MaxIterations() int {
+	budget := math.Pow(4, MaxIterations)
+	n := 0
+	for math.Pow(float64(m.Segments()), float64(n+1)) <= budget {
+		n++
+	}
+	return n
+}
+
+func (m motif) Apply(base []geometry.LatLon, iterations int) []geometry.LatLon {
+	iterations = max(0, min(iterations, m.MaxIterations()))
+	result := make([]geometry.LatLon, len(base))
+	copy(result, base)
+	if iterations == 0 || len(base) < 2 {
+		return result
+	}
+
+	proj := m.proj
+	if proj == nil {
+		proj, _ = geometry.ProjectionFor(projection.Default, base)
+	}
+	points := make([]complex128, len(base))
+	for i, p := range geometry.ProjectPoints(proj, base) {
+		points[i] = complex(p[0], p[1])
+	}
+
+	for k := 0; k < iterations; k++ {
+		refined := make([]complex128, 0, (len(points)-1)*m.Segments()+1)
+		for i := 0; i+1 < len(points); i++ {
+			a, d := points[i], points[i+1]-points[i]
+			for _, p := range m.points[:m.Segments()] {
+				refined = append(refined, a+d*p)
+			}
+		}
+		points = append(refined, points[len(points)-1])
+	}
+
+	stride := int(math.Pow(float64(m.Segments()), float64(iterations)))
+	out := make([]geometry.LatLon, len(points))
+	for i, p := range points {
+		if i%stride == 0 {
+			out[i] = base[i/stride]
+			continue
+		}
+		lat, lon := proj.Inverse(real(p), imag(p))
+		out[i] = geometry.LatLon{Lat: lat, Lon: lon}
+	}
+	return out
+}
+
+// CheckGeneratorTheory compares the length of every iteration of gen with
+// L₀ × factorⁿ, as CheckTheoryConsistency does for the classic curve.
+func CheckGeneratorTheory(gen Generator, base []geometry.LatLon, maxIterations int) TheoryCheckReport {
+	return checkTheory(base, maxIterations, gen.Apply, gen.TheoreticalLengthFactor())
+}
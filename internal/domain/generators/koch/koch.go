@@ -2,13 +2,14 @@ package koch
 
 import (
 	"coastal-geometry/internal/domain/geometry"
-	"fmt"
 	"math"
-	"strings"
 )
 
 const MaxIterations = 10
-const maxTheoryErrorPct = 2.0
+
+// MaxTheoryErrorPct is the largest deviation from Lₙ = L₀ × (4/3)ⁿ that
+// CheckTheoryConsistency still accepts.
+const MaxTheoryErrorPct = 2.0
 
 type TheoryCheckSample struct {
 	Iteration        int
@@ -20,18 +21,16 @@ type TheoryCheckSample struct {
 }
 
 type TheoryCheckReport struct {
-	Samples []TheoryCheckSample
-	Valid   bool
+	BasePoints   int
+	BaseLengthKM float64
+	Samples      []TheoryCheckSample
+	Valid        bool
 }
 
+// KochCurve applies the Koch construction to every segment of base.
+// iterations is clamped to [0, MaxIterations].
 func KochCurve(base []geometry.LatLon, iterations int) []geometry.LatLon {
-	if iterations < 0 {
-		iterations = 0
-	}
-	if iterations > MaxIterations {
-		fmt.Printf("Предупреждение: слишком много итераций (%d). Ограничено до %d\n", iterations, MaxIterations)
-		iterations = MaxIterations
-	}
+	iterations = max(0, min(iterations, MaxIterations))
 
 	if iterations == 0 {
 		result := make([]geometry.LatLon, len(base))
@@ -105,16 +104,22 @@ func TheoryErrorPercent(measuredLength, theoreticalLength float64) float64 {
 }
 
 func CheckTheoryConsistency(base []geometry.LatLon, maxIterations int) TheoryCheckReport {
+	return checkTheory(base, maxIterations, KochCurve, 4.0/3.0)
+}
+
+func checkTheory(base []geometry.LatLon, maxIterations int, curveFor func([]geometry.LatLon, int) []geometry.LatLon, lengthFactor float64) TheoryCheckReport {
 	baseLength := geometry.PolylineLength(base)
 	report := TheoryCheckReport{
-		Samples: make([]TheoryCheckSample, 0, maxIterations+1),
-		Valid:   true,
+		BasePoints:   len(base),
+		BaseLengthKM: baseLength,
+		Samples:      make([]TheoryCheckSample, 0, maxIterations+1),
+		Valid:        true,
 	}
 
 	for iter := 0; iter <= maxIterations; iter++ {
-		curve := KochCurve(base, iter)
+		curve := curveFor(base, iter)
 		measuredLength := geometry.PolylineLength(curve)
-		theoreticalLength := TheoreticalLength(baseLength, iter)
+		theoreticalLength := baseLength * math.Pow(lengthFactor, float64(iter))
 		errorKM := TheoryError(measuredLength, theoreticalLength)
 		errorPct := TheoryErrorPercent(measuredLength, theoreticalLength)
 
@@ -127,46 +132,10 @@ func CheckTheoryConsistency(base []geometry.LatLon, maxIterations int) TheoryChe
 			ErrorPercent:     errorPct,
 		})
 
-		if errorPct > maxTheoryErrorPct {
+		if errorPct > MaxTheoryErrorPct {
 			report.Valid = false
 		}
 	}
 
 	return report
 }
-
-func Demonstrate(base []geometry.LatLon, maxIterations int) TheoryCheckReport {
-	baseLength := geometry.PolylineLength(base)
-	report := CheckTheoryConsistency(base, maxIterations)
-
-	fmt.Println(strings.Repeat("═", 80))
-	fmt.Println("\tФРАКТАЛЬНАЯ БЕРЕГОВАЯ ЛИНИЯ ЧЁРНОГО МОРЯ — КРИВАЯ КОХА (рекурсивная)")
-	fmt.Println(strings.Repeat("═", 90))
-
-	fmt.Printf("Исходная полилиния: %d точек, длина = %.0f км\n\n", len(base), baseLength)
-
-	fmt.Printf("%-5s %-10s %-15s %-15s %-15s %-12s\n", "Итер.", "Точек", "Измерено, км", "Теория, км", "Ошибка, км", "Ошибка, %")
-	fmt.Println(strings.Repeat("─", 96))
-
-	for _, sample := range report.Samples {
-		fmt.Printf("%-5d %-10d %-15.0f %-15.0f %-15.2f %-12.2f\n",
-			sample.Iteration,
-			sample.PointsCount,
-			sample.MeasuredLengthKM,
-			sample.TheoreticalKM,
-			sample.ErrorKM,
-			sample.ErrorPercent)
-
-		if sample.ErrorPercent > maxTheoryErrorPct {
-			fmt.Println("WARNING: Koch implementation inconsistent with theory")
-		}
-	}
-
-	fmt.Println(strings.Repeat("─", 96))
-	fmt.Printf("Математическая формула: Lₙ = L₀ × (4/3)ⁿ\n")
-	fmt.Printf("error = |L_measured - L_theory|\n")
-	fmt.Printf("Порог предупреждения: %.0f%%\n", maxTheoryErrorPct)
-	fmt.Printf("Фрактальная размерность D = log(4)/log(3) ≈ %.5f\n", math.Log(4)/math.Log(3))
-	fmt.Printf("При n→∞ длина → ∞, но кривая остаётся в ограниченной области\n")
-	return report
-}
@@ -38,7 +38,78 @@ func TestKochCurveMatchesTheoryForSingleSegment(t *testing.T) {
 	theoreticalLength := TheoreticalLength(baseLength, 1)
 	errorPct := TheoryErrorPercent(measuredLength, theoreticalLength)
 
-	if errorPct > maxTheoryErrorPct {
-		t.Fatalf("expected theory error <= %.2f%%, got %.4f%%", maxTheoryErrorPct, errorPct)
+	if errorPct > MaxTheoryErrorPct {
+		t.Fatalf("expected theory error <= %.2f%%, got %.4f%%", MaxTheoryErrorPct, errorPct)
+	}
+}
+
+func TestAnalyzeOrganicReportsGrowth(t *testing.T) {
+	base := []geometry.LatLon{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 0.1},
+	}
+	opts := OrganicOptions{Seed: 5, AngleJitterDeg: 10, HeightJitterPct: 0.2}
+
+	report := AnalyzeOrganic(base, 2, opts)
+	if report.BasePoints != 2 || len(report.Samples) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if first := report.Samples[0]; first.GrowthKM != 0 || first.RatioToBase != 1 {
+		t.Fatalf("expected iteration 0 to match the base, got %+v", first)
+	}
+	last := report.Samples[2]
+	if last.PointsCount != len(OrganicKochCurve(base, 2, opts)) {
+		t.Fatalf("expected %d points, got %d", len(OrganicKochCurve(base, 2, opts)), last.PointsCount)
+	}
+	if math.Abs(last.LengthKM-report.Samples[1].LengthKM-last.GrowthKM) > 1e-9 {
+		t.Fatalf("expected growth against the previous iteration, got %+v", last)
+	}
+}
+
+func TestGeneratorsMatchTheory(t *testing.T) {
+	base := []geometry.LatLon{{Lat: 44, Lon: 30}, {Lat: 44.5, Lon: 31}, {Lat: 43.5, Lon: 33}}
+	want := map[string]struct{ dimension, factor float64 }{
+		GeneratorKoch:       {math.Log(4) / math.Log(3), 4.0 / 3.0},
+		GeneratorQuadratic1: {math.Log(5) / math.Log(3), 5.0 / 3.0},
+		GeneratorQuadratic2: {5.0 / 3.0, 4},
+		GeneratorMinkowski:  {1.5, 2},
+		GeneratorCesaro:     {math.Log(4) / math.Log(2+2*math.Cos(DefaultCesaroAngle*math.Pi/180)), 4 / (2 + 2*math.Cos(DefaultCesaroAngle*math.Pi/180))},
+		GeneratorLevy:       {2, math.Sqrt2},
+		GeneratorGosper:     {math.Log(3) / math.Log(math.Sqrt(7)), 3 / math.Sqrt(7)},
+	}
+
+	for _, name := range Generators {
+		gen, err := NewGenerator(name, GeneratorOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := want[name]; math.Abs(gen.TheoreticalDimension()-w.dimension) > 1e-9 || math.Abs(gen.TheoreticalLengthFactor()-w.factor) > 1e-9 {
+			t.Fatalf("%s: expected D=%.4f and factor %.4f, got %.4f and %.4f", name, w.dimension, w.factor, gen.TheoreticalDimension(), gen.TheoreticalLengthFactor())
+		}
+
+		iterations := min(gen.MaxIterations(), 3)
+		report := CheckGeneratorTheory(gen, base, iterations)
+		if !report.Valid {
+			t.Fatalf("%s: expected lengths within %.0f%% of theory, got %+v", name, MaxTheoryErrorPct, report.Samples)
+		}
+		curve := gen.Apply(base, iterations)
+		stride := (len(curve) - 1) / (len(base) - 1)
+		if curve[0] != base[0] || curve[stride] != base[1] || curve[2*stride] != base[2] {
+			t.Fatalf("%s: expected the base vertices to stay in place", name)
+		}
+	}
+}
+
+func TestNewGeneratorOptions(t *testing.T) {
+	if _, err := NewGenerator("dragon", GeneratorOptions{}); err == nil {
+		t.Fatal("expected an unknown generator to be rejected")
+	}
+	cesaro, _ := NewGenerator("Cesaro", GeneratorOptions{CesaroAngleDeg: 60})
+	classic, _ := NewGenerator(GeneratorKoch, GeneratorOptions{})
+	if math.Abs(cesaro.TheoreticalDimension()-classic.TheoreticalDimension()) > 1e-9 {
+		t.Fatalf("expected the 60° Cesàro curve to be the Koch curve, got D=%.4f", cesaro.TheoreticalDimension())
+	}
+	if gen, _ := NewGenerator(GeneratorQuadratic2, GeneratorOptions{}); gen.MaxIterations() != 4 {
+		t.Fatalf("expected 32 segments to allow 4 iterations, got %d", gen.MaxIterations())
 	}
 }
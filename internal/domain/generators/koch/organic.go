@@ -2,10 +2,8 @@ package koch
 
 import (
 	"coastal-geometry/internal/domain/geometry"
-	"fmt"
 	"math"
 	"math/rand"
-	"strings"
 )
 
 type OrganicOptions struct {
@@ -14,14 +12,10 @@ type OrganicOptions struct {
 	HeightJitterPct float64
 }
 
+// OrganicKochCurve is KochCurve with seeded jitter of the peak angle and
+// height. iterations is clamped to [0, MaxIterations].
 func OrganicKochCurve(base []geometry.LatLon, iterations int, opts OrganicOptions) []geometry.LatLon {
-	if iterations < 0 {
-		iterations = 0
-	}
-	if iterations > MaxIterations {
-		fmt.Printf("Предупреждение: слишком много итераций (%d). Ограничено до %d\n", iterations, MaxIterations)
-		iterations = MaxIterations
-	}
+	iterations = max(0, min(iterations, MaxIterations))
 
 	result := make([]geometry.LatLon, len(base))
 	copy(result, base)
@@ -84,41 +78,48 @@ func randomSigned(rng *rand.Rand, amplitude float64) float64 {
 	return (rng.Float64()*2 - 1) * amplitude
 }
 
-func DemonstrateOrganic(base []geometry.LatLon, maxIterations int, opts OrganicOptions) {
-	baseLength := geometry.PolylineLength(base)
-
-	fmt.Println(strings.Repeat("═", 80))
-	fmt.Println("\tОРГАНИЧЕСКАЯ ФРАКТАЛЬНАЯ БЕРЕГОВАЯ ЛИНИЯ — KOCH ORGANIC")
-	fmt.Println(strings.Repeat("═", 90))
+// OrganicSample is one iteration of the organic Koch growth. GrowthKM is
+// measured against the previous iteration and is zero at iteration 0.
+type OrganicSample struct {
+	Iteration   int
+	PointsCount int
+	LengthKM    float64
+	GrowthKM    float64
+	RatioToBase float64
+}
 
-	fmt.Printf("Исходная полилиния: %d точек, длина = %.0f км\n", len(base), baseLength)
-	fmt.Printf("Seed=%d, angle jitter=±%.1f°, height jitter=±%.0f%%\n\n",
-		opts.Seed, opts.AngleJitterDeg, opts.HeightJitterPct*100)
+type OrganicReport struct {
+	Options      OrganicOptions
+	BasePoints   int
+	BaseLengthKM float64
+	Samples      []OrganicSample
+}
 
-	fmt.Printf("%-5s %-10s %-15s %-15s %-12s\n", "Итер.", "Точек", "Длина, км", "Прирост", "× от исходной")
-	fmt.Println(strings.Repeat("─", 80))
+// AnalyzeOrganic measures the organic curve for every iteration up to
+// maxIterations.
+func AnalyzeOrganic(base []geometry.LatLon, maxIterations int, opts OrganicOptions) OrganicReport {
+	baseLength := geometry.PolylineLength(base)
+	report := OrganicReport{
+		Options:      opts,
+		BasePoints:   len(base),
+		BaseLengthKM: baseLength,
+		Samples:      make([]OrganicSample, 0, maxIterations+1),
+	}
 
 	prevLength := baseLength
 	for iter := 0; iter <= maxIterations; iter++ {
 		curve := OrganicKochCurve(base, iter, opts)
 		length := geometry.PolylineLength(curve)
-		pointsCount := len(curve)
 
-		growth := ""
-		multiplier := ""
+		sample := OrganicSample{Iteration: iter, PointsCount: len(curve), LengthKM: length, RatioToBase: 1}
+		if baseLength > 0 {
+			sample.RatioToBase = length / baseLength
+		}
 		if iter > 0 {
-			growth = fmt.Sprintf("+%.0f км", length-prevLength)
-			multiplier = fmt.Sprintf("%.3f×", length/baseLength)
-		} else {
-			multiplier = "1.000×"
+			sample.GrowthKM = length - prevLength
 		}
-
-		fmt.Printf("%-5d %-10d %-15.0f %-15s %-12s\n",
-			iter, pointsCount, length, growth, multiplier)
-
+		report.Samples = append(report.Samples, sample)
 		prevLength = length
 	}
-
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Println("Organic Koch нарушает идеальную самоподобность, поэтому выглядит ближе к природной береговой линии.")
+	return report
 }
@@ -60,3 +60,12 @@ func projectToMetersLocal(points []LatLon) []pointXY {
 
 	return projected
 }
+
+// PolygonArea returns the area of the outer ring minus its holes in square kilometers.
+func PolygonArea(outer []LatLon, holes [][]LatLon) float64 {
+	area := Area(outer)
+	for _, hole := range holes {
+		area -= Area(hole)
+	}
+	return math.Max(area, 0)
+}
@@ -0,0 +1,128 @@
+package geometry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DistanceHaversine = "haversine"
+	DistanceVincenty  = "vincenty"
+	DistanceKarney    = "karney"
+)
+
+// Distance measures the length of the shortest path between two points in
+// kilometers.
+type Distance interface {
+	Distance(a, b LatLon) float64
+	Name() string
+}
+
+// Ellipsoid is a reference ellipsoid of revolution. A is the equatorial
+// radius in meters and F the flattening; only oblate ellipsoids (0 <= F < 1)
+// are supported.
+type Ellipsoid struct {
+	Name string
+	A    float64
+	F    float64
+}
+
+var (
+	WGS84          = Ellipsoid{Name: "WGS84", A: 6378137, F: 1 / 298.257223563}
+	GRS80          = Ellipsoid{Name: "GRS80", A: 6378137, F: 1 / 298.257222101}
+	Krassovsky1940 = Ellipsoid{Name: "Krassovsky1940", A: 6378245, F: 1 / 298.3}
+)
+
+var ellipsoids = []Ellipsoid{WGS84, GRS80, Krassovsky1940}
+
+// EllipsoidByName looks up one of the predefined ellipsoids, ignoring case.
+func EllipsoidByName(name string) (Ellipsoid, error) {
+	for _, e := range ellipsoids {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	names := make([]string, len(ellipsoids))
+	for i, e := range ellipsoids {
+		names[i] = e.Name
+	}
+	return Ellipsoid{}, fmt.Errorf("unknown ellipsoid %q (known: %s)", name, strings.Join(names, ", "))
+}
+
+func (e Ellipsoid) Validate() error {
+	if !(e.A > 0) {
+		return fmt.Errorf("ellipsoid %s: equatorial radius must be positive", e.Name)
+	}
+	if !(e.F >= 0 && e.F < 1) {
+		return fmt.Errorf("ellipsoid %s: flattening must be in [0, 1)", e.Name)
+	}
+	return nil
+}
+
+// orDefault lets zero-value strategies such as VincentyDistance{} use WGS84.
+func (e Ellipsoid) orDefault() Ellipsoid {
+	if e.A == 0 {
+		return WGS84
+	}
+	return e
+}
+
+// HaversineDistance is the great-circle distance on a sphere of RadiusKM,
+// EarthRadiusKM when zero.
+type HaversineDistance struct {
+	RadiusKM float64
+}
+
+func (d HaversineDistance) Distance(a, b LatLon) float64 {
+	if d.RadiusKM == 0 {
+		return Haversine(a, b)
+	}
+	return Haversine(a, b) / EarthRadiusKM * d.RadiusKM
+}
+
+func (HaversineDistance) Name() string { return DistanceHaversine }
+
+// NewDistance builds the strategy selected by name. The ellipsoid is
+// ignored by haversine.
+func NewDistance(name string, ellipsoid Ellipsoid) (Distance, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", DistanceHaversine:
+		return HaversineDistance{}, nil
+	case DistanceVincenty:
+		if err := ellipsoid.orDefault().Validate(); err != nil {
+			return nil, err
+		}
+		return VincentyDistance{Ellipsoid: ellipsoid}, nil
+	case DistanceKarney:
+		if err := ellipsoid.orDefault().Validate(); err != nil {
+			return nil, err
+		}
+		return NewKarneyDistance(ellipsoid), nil
+	default:
+		return nil, fmt.Errorf("unknown geodesic %q (known: %s, %s, %s)", name, DistanceHaversine, DistanceVincenty, DistanceKarney)
+	}
+}
+
+// PolylineLengthWith is PolylineLength measured with d; nil means haversine.
+func PolylineLengthWith(points []LatLon, d Distance) float64 {
+	if d == nil {
+		return PolylineLength(points)
+	}
+	var total float64
+	for i := 1; i < len(points); i++ {
+		total += d.Distance(points[i-1], points[i])
+	}
+	return total
+}
+
+// DistanceEllipsoid reports the ellipsoid an ellipsoidal strategy measures on.
+func DistanceEllipsoid(d Distance) (Ellipsoid, bool) {
+	switch v := d.(type) {
+	case VincentyDistance:
+		return v.Ellipsoid.orDefault(), true
+	case KarneyDistance:
+		return v.Ellipsoid.orDefault(), true
+	default:
+		return Ellipsoid{}, false
+	}
+}
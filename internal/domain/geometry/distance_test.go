@@ -0,0 +1,77 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVincentyMatchesFlindersPeakExample(t *testing.T) {
+	// Geoscience Australia's reference pair: Flinders Peak to Buninyong is
+	// 54 972.271 m on GRS80/WGS84.
+	p := LatLon{Lat: -(37 + 57/60.0 + 3.72030/3600), Lon: 144 + 25/60.0 + 29.52440/3600}
+	q := LatLon{Lat: -(37 + 39/60.0 + 10.15610/3600), Lon: 143 + 55/60.0 + 35.38390/3600}
+
+	for _, d := range []Distance{VincentyDistance{}, NewKarneyDistance(WGS84)} {
+		if got := d.Distance(p, q) * 1000; math.Abs(got-54972.271) > 0.001 {
+			t.Fatalf("%s distance = %.4f m, want 54972.271", d.Name(), got)
+		}
+	}
+}
+
+func TestKarneyConvergesForNearlyAntipodalPoints(t *testing.T) {
+	wellington := LatLon{Lat: -41.32, Lon: 174.81}
+	salamanca := LatLon{Lat: 40.96, Lon: -5.50}
+	if got := NewKarneyDistance(WGS84).Distance(wellington, salamanca) * 1000; math.Abs(got-19959679.267) > 0.001 {
+		t.Fatalf("karney distance = %.4f m, want 19959679.267 (GeographicLib)", got)
+	}
+
+	// Vincenty does not converge here and must fall back to Karney.
+	p, q := LatLon{Lat: 0, Lon: 0}, LatLon{Lat: 0.5, Lon: 179.7}
+	if _, ok := vincentyInverse(WGS84, p, q); ok {
+		t.Fatalf("expected vincenty to fail for nearly antipodal points")
+	}
+	if got, want := (VincentyDistance{}).Distance(p, q), NewKarneyDistance(WGS84).Distance(p, q); got != want {
+		t.Fatalf("vincenty fallback = %.9f km, want karney %.9f", got, want)
+	}
+}
+
+func TestKarneyQuarterMeridianAndEquator(t *testing.T) {
+	k := NewKarneyDistance(WGS84)
+	if got := k.Distance(LatLon{Lat: -90}, LatLon{Lat: 90}) * 1000; math.Abs(got-20003931.4586) > 0.001 {
+		t.Fatalf("pole to pole = %.4f m, want 20003931.4586", got)
+	}
+	if got, want := k.Distance(LatLon{}, LatLon{Lon: 90})*1000, WGS84.A*math.Pi/2; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("quarter equator = %.6f m, want %.6f", got, want)
+	}
+}
+
+func TestEllipsoidalStrategiesAgreeAndDifferFromSphere(t *testing.T) {
+	coast := []LatLon{{Lat: 46.48, Lon: 30.73}, {Lat: 45.33, Lon: 32.49}, {Lat: 44.62, Lon: 33.53}, {Lat: 43.58, Lon: 39.72}, {Lat: 41.65, Lon: 41.63}, {Lat: 41.01, Lon: 28.97}}
+
+	haversine := PolylineLengthWith(coast, nil)
+	vincenty := PolylineLengthWith(coast, VincentyDistance{Ellipsoid: WGS84})
+	karney := PolylineLengthWith(coast, NewKarneyDistance(WGS84))
+	if math.Abs(vincenty-karney) > 1e-6 {
+		t.Fatalf("vincenty %.9f km and karney %.9f km disagree", vincenty, karney)
+	}
+	if diff := math.Abs(karney-haversine) / karney; diff < 1e-4 || diff > 5e-3 {
+		t.Fatalf("sphere bias %.5f outside expected 0.01%%..0.5%%", diff)
+	}
+
+	sphere := NewKarneyDistance(Ellipsoid{Name: "sphere", A: EarthRadiusKM * 1000})
+	if got := PolylineLengthWith(coast, sphere); math.Abs(got-haversine) > 1e-6 {
+		t.Fatalf("karney on a sphere = %.9f km, want haversine %.9f", got, haversine)
+	}
+}
+
+func TestNewDistanceRejectsUnknownNames(t *testing.T) {
+	if _, err := NewDistance("euclid", WGS84); err == nil {
+		t.Fatalf("expected error for unknown geodesic")
+	}
+	if _, err := NewDistance(DistanceKarney, Ellipsoid{Name: "bad", A: 1, F: 1.5}); err == nil {
+		t.Fatalf("expected error for invalid flattening")
+	}
+	if _, err := EllipsoidByName("krassovsky1940"); err != nil {
+		t.Fatalf("EllipsoidByName: %v", err)
+	}
+}
@@ -1,10 +1,11 @@
 package geometry
 
 import (
-	"math"
 	"math/rand"
 	"sync"
 	"time"
+
+	"coastal-geometry/internal/domain/projection"
 )
 
 const (
@@ -16,15 +17,21 @@ const (
 // strength is the standard deviation of the displacement in meters; zero or
 // negative values return a clone of the input without changes.
 func Erode(points []LatLon, strength float64) []LatLon {
-	return erodeWithRand(points, strength, rand.New(rand.NewSource(time.Now().UnixNano())))
+	return erodeWithRand(points, strength, rand.New(rand.NewSource(time.Now().UnixNano())), nil)
 }
 
 // ErodeWithSeed mirrors Erode but allows a fixed seed for reproducible output.
 func ErodeWithSeed(points []LatLon, strength float64, seed int64) []LatLon {
+	return ErodeProjected(points, strength, seed, nil)
+}
+
+// ErodeProjected mirrors ErodeWithSeed with displacements applied in the
+// plane of proj; nil means the default projection centred on the points.
+func ErodeProjected(points []LatLon, strength float64, seed int64, proj projection.Projector) []LatLon {
 	if seed == 0 {
 		seed = time.Now().UnixNano()
 	}
-	return erodeWithRand(points, strength, rand.New(rand.NewSource(seed)))
+	return erodeWithRand(points, strength, rand.New(rand.NewSource(seed)), proj)
 }
 
 // SimulateErosion runs multiple erosion steps and returns snapshot after each step,
@@ -45,32 +52,30 @@ func SimulateErosionWithSeed(points []LatLon, steps int, strength float64, seed
 	snapshots[0] = current
 
 	for i := 1; i <= steps; i++ {
-		current = erodeParallel(current, strength, seed, i)
+		current = erodeParallel(current, strength, nil, seed, i, nil)
 		snapshots[i] = current
 	}
 	return snapshots
 }
 
-func erodeWithRand(points []LatLon, strength float64, rng *rand.Rand) []LatLon {
+// ErosionStep applies step number step of SimulateErosionWithSeed to points,
+// so callers can run their own processing between steps and still get the
+// same noise for the same seed. weights scale the displacement of every
+// point, e.g. 1/resistance of the rock at that point; missing weights
+// default to 1. Displacements are applied in the plane of proj; nil means
+// the default projection centred on the points.
+func ErosionStep(points []LatLon, strength float64, seed int64, step int, weights []float64, proj projection.Projector) []LatLon {
+	return erodeParallel(points, strength, weights, seed, step, proj)
+}
+
+func erodeWithRand(points []LatLon, strength float64, rng *rand.Rand, proj projection.Projector) []LatLon {
 	if len(points) == 0 {
 		return nil
 	}
 	if strength <= 0 || rng == nil {
 		return clonePoints(points)
 	}
-
-	// Use mean latitude to approximate meters-to-degrees conversion.
-	refLat := 0.0
-	for _, p := range points {
-		refLat += p.Lat
-	}
-	refLat /= float64(len(points))
-
-	metersPerDegLat := 111194.9
-	metersPerDegLon := metersPerDegLat * math.Cos(refLat*math.Pi/180)
-	if math.Abs(metersPerDegLon) < 1e-9 {
-		metersPerDegLon = metersPerDegLat
-	}
+	proj = projectorOr(proj, points)
 
 	eroded := make([]LatLon, len(points))
 	firstShiftLat := 0.0
@@ -92,31 +97,19 @@ func erodeWithRand(points []LatLon, strength float64, rng *rand.Rand) []LatLon {
 			}
 		}
 
-		eroded[i] = LatLon{
-			Lat: p.Lat + dy/metersPerDegLat,
-			Lon: p.Lon + dx/metersPerDegLon,
-		}
+		eroded[i] = shiftMeters(proj, p, dx, dy)
 	}
 
 	return eroded
 }
 
-func erodeParallel(points []LatLon, strength float64, seed int64, step int) []LatLon {
+func erodeParallel(points []LatLon, strength float64, weights []float64, seed int64, step int, proj projection.Projector) []LatLon {
 	if len(points) == 0 || strength <= 0 {
 		return clonePoints(points)
 	}
 
 	closed := isClosedPolyline(points)
-	refLat := 0.0
-	for _, p := range points {
-		refLat += p.Lat
-	}
-	refLat /= float64(len(points))
-
-	metersPerDegLon := metersPerDegLat * math.Cos(refLat*math.Pi/180)
-	if math.Abs(metersPerDegLon) < 1e-9 {
-		metersPerDegLon = metersPerDegLat
-	}
+	proj = projectorOr(proj, points)
 
 	out := make([]LatLon, len(points))
 
@@ -139,10 +132,13 @@ func erodeParallel(points []LatLon, strength float64, seed int64, step int) []La
 			defer wg.Done()
 			for i := startIdx; i < endIdx; i++ {
 				p := points[i]
-				localSeed := seed + int64(step)*10_000 + int64(i)
-				rng := rand.New(rand.NewSource(localSeed))
-				dx := rng.NormFloat64() * strength
-				dy := rng.NormFloat64() * strength
+				noise := NewVertexNoise(seed, step, i)
+				scale := strength
+				if i < len(weights) {
+					scale *= weights[i]
+				}
+				dx := noise.NormFloat64() * scale
+				dy := noise.NormFloat64() * scale
 
 				if closed && i == 0 {
 					mu.Lock()
@@ -151,10 +147,7 @@ func erodeParallel(points []LatLon, strength float64, seed int64, step int) []La
 					mu.Unlock()
 				}
 
-				out[i] = LatLon{
-					Lat: p.Lat + dy/metersPerDegLat,
-					Lon: p.Lon + dx/metersPerDegLon,
-				}
+				out[i] = shiftMeters(proj, p, dx, dy)
 			}
 		}()
 	}
@@ -163,10 +156,7 @@ func erodeParallel(points []LatLon, strength float64, seed int64, step int) []La
 	if closed && len(out) > 1 {
 		last := len(out) - 1
 		p := points[last]
-		out[last] = LatLon{
-			Lat: p.Lat + firstShiftLat/metersPerDegLat,
-			Lon: p.Lon + firstShiftLon/metersPerDegLon,
-		}
+		out[last] = shiftMeters(proj, p, firstShiftLon, firstShiftLat)
 	}
 
 	return out
@@ -0,0 +1,63 @@
+package geometry
+
+import (
+	"testing"
+
+	"coastal-geometry/internal/domain/projection"
+)
+
+func TestErosionStepScalesDisplacement(t *testing.T) {
+	points := make([]LatLon, 200)
+	for i := range points {
+		points[i] = LatLon{Lat: 44, Lon: 30 + float64(i)*0.01}
+	}
+	weights := make([]float64, len(points))
+	for i := range weights {
+		weights[i] = 1
+		if i >= len(points)/2 {
+			weights[i] = 0.25
+		}
+	}
+	proj, err := ProjectionFor(projection.UTM, points)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	weighted := ErosionStep(points, 100, 7, 1, weights, proj)
+	plain := ErosionStep(points, 100, 7, 1, nil, proj)
+
+	soft, hard := 0.0, 0.0
+	for i := range points {
+		shift := Haversine(points[i], weighted[i])
+		if i < len(points)/2 {
+			soft += shift
+			if weighted[i] != plain[i] {
+				t.Fatalf("point %d with weight 1 should match unweighted erosion", i)
+			}
+		} else {
+			hard += shift
+		}
+	}
+	if ratio := hard / soft; ratio < 0.15 || ratio > 0.35 {
+		t.Fatalf("expected weight 0.25 to shrink displacement about four times, got ratio %.3f", ratio)
+	}
+}
+
+func TestErosionStepReproducesSimulateErosionWithSeed(t *testing.T) {
+	points := make([]LatLon, 50)
+	for i := range points {
+		points[i] = LatLon{Lat: 44 + float64(i%7)*0.01, Lon: 30 + float64(i)*0.01}
+	}
+	points = append(points, points[0])
+
+	snapshots := SimulateErosionWithSeed(points, 3, 100, 7)
+	current := points
+	for step := 1; step <= 3; step++ {
+		current = ErosionStep(current, 100, 7, step, nil, nil)
+		for i := range current {
+			if current[i] != snapshots[step][i] {
+				t.Fatalf("step %d, point %d: expected %v, got %v", step, i, snapshots[step][i], current[i])
+			}
+		}
+	}
+}
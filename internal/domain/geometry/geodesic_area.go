@@ -0,0 +1,168 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	AreaPlanar      = "planar"
+	AreaSpherical   = "spherical"
+	AreaEllipsoidal = "ellipsoidal"
+)
+
+// AreaMeasure computes the area enclosed by a ring in square kilometers. An
+// open ring is closed by connecting the last point to the first. Winding
+// order does not matter: the smaller of the two regions the ring splits the
+// globe into is measured.
+type AreaMeasure interface {
+	Area(ring []LatLon) float64
+	Name() string
+}
+
+// PlanarArea is Area: the shoelace formula on a local equirectangular grid.
+// Cheap, but distorts basins spanning several degrees of longitude.
+type PlanarArea struct{}
+
+func (PlanarArea) Area(ring []LatLon) float64 { return Area(ring) }
+
+func (PlanarArea) Name() string { return AreaPlanar }
+
+// SphericalArea sums the spherical excess of the ring's edges on a sphere of
+// RadiusKM, EarthRadiusKM when zero. Edges are great circles.
+type SphericalArea struct {
+	RadiusKM float64
+}
+
+func (s SphericalArea) Area(ring []LatLon) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+	radius := s.RadiusKM
+	if radius == 0 {
+		radius = EarthRadiusKM
+	}
+
+	// Each edge contributes the signed excess of the quadrilateral it forms
+	// with the equator, in tangent half-angle form.
+	var excess float64
+	crossings := 0
+	for i := range ring {
+		p, q := ring[i], ring[(i+1)%len(ring)]
+		dlon, _ := angDiff(p.Lon, q.Lon)
+		t1 := math.Tan(p.Lat * math.Pi / 360)
+		t2 := math.Tan(q.Lat * math.Pi / 360)
+		excess += 2 * math.Atan2(math.Tan(dlon*math.Pi/360)*(t1+t2), 1+t1*t2)
+		crossings += transit(p.Lon, q.Lon)
+	}
+	return reduceRingArea(excess, crossings, 4*math.Pi) * radius * radius
+}
+
+func (SphericalArea) Name() string { return AreaSpherical }
+
+// EllipsoidalArea is the area bounded by geodesics on the ellipsoid, summed
+// edge by edge with Karney's series (Algorithms for geodesics, §6).
+type EllipsoidalArea struct {
+	Ellipsoid Ellipsoid
+}
+
+func (e EllipsoidalArea) Area(ring []LatLon) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+	g := NewKarneyDistance(e.Ellipsoid)
+
+	var sum float64
+	crossings := 0
+	for i := range ring {
+		p, q := ring[i], ring[(i+1)%len(ring)]
+		_, s12 := g.inverse(p.Lat, p.Lon, q.Lat, q.Lon, true)
+		sum += s12
+		crossings += transit(p.Lon, q.Lon)
+	}
+	return reduceRingArea(sum, crossings, 4*math.Pi*g.c2) / 1_000_000
+}
+
+func (EllipsoidalArea) Name() string { return AreaEllipsoidal }
+
+// NewAreaMeasure builds the measure selected by name. The ellipsoid is used
+// only by the ellipsoidal measure.
+func NewAreaMeasure(name string, ellipsoid Ellipsoid) (AreaMeasure, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case AreaPlanar:
+		return PlanarArea{}, nil
+	case AreaSpherical:
+		return SphericalArea{}, nil
+	case "", AreaEllipsoidal:
+		ellipsoid = ellipsoid.orDefault()
+		if err := ellipsoid.Validate(); err != nil {
+			return nil, err
+		}
+		return EllipsoidalArea{Ellipsoid: ellipsoid}, nil
+	default:
+		return nil, fmt.Errorf("unknown area method %q (known: %s, %s, %s)", name, AreaPlanar, AreaSpherical, AreaEllipsoidal)
+	}
+}
+
+// PolygonAreaWith is PolygonArea measured with m; nil means planar.
+func PolygonAreaWith(outer []LatLon, holes [][]LatLon, m AreaMeasure) float64 {
+	if m == nil {
+		return PolygonArea(outer, holes)
+	}
+	area := m.Area(outer)
+	for _, hole := range holes {
+		area -= m.Area(hole)
+	}
+	return math.Max(area, 0)
+}
+
+// AreaEllipsoid reports the ellipsoid an ellipsoidal measure works on.
+func AreaEllipsoid(m AreaMeasure) (Ellipsoid, bool) {
+	if v, ok := m.(EllipsoidalArea); ok {
+		return v.Ellipsoid.orDefault(), true
+	}
+	return Ellipsoid{}, false
+}
+
+// openRing drops the closing vertex so every edge is visited once.
+func openRing(ring []LatLon) []LatLon {
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		return ring[:n-1]
+	}
+	return ring
+}
+
+// transit counts the crossings of the prime meridian by the edge lon1→lon2:
+// +1 eastwards, -1 westwards.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// reduceRingArea turns the signed sum of edge areas into the area of the
+// smaller region bounded by the ring. A ring encircling a pole crosses the
+// prime meridian an odd number of times and its sum is off by half the
+// total area.
+func reduceRingArea(sum float64, crossings int, total float64) float64 {
+	if crossings&1 != 0 {
+		if sum < 0 {
+			sum += total / 2
+		} else {
+			sum -= total / 2
+		}
+	}
+	sum = math.Remainder(sum, total)
+	return math.Abs(sum)
+}
@@ -0,0 +1,92 @@
+package geometry
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEllipsoidalAreaOfOctantIsEighthOfEllipsoid(t *testing.T) {
+	octant := []LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 90}, {Lat: 90, Lon: 0}}
+
+	a, f := WGS84.A, WGS84.F
+	b := a * (1 - f)
+	e := math.Sqrt(f * (2 - f))
+	total := 2*math.Pi*a*a + math.Pi*b*b/e*math.Log((1+e)/(1-e))
+	want := total / 8 / 1_000_000
+
+	if got := (EllipsoidalArea{Ellipsoid: WGS84}).Area(octant); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("octant area = %.6f km², want %.6f", got, want)
+	}
+}
+
+func TestEllipsoidalAreaOfRingAroundPole(t *testing.T) {
+	// GeographicLib's Antarctica example: 13 662 703 680 020.1 m² on WGS84.
+	antarctica := []LatLon{
+		{-63.1, -58}, {-72.9, -74}, {-71.9, -102}, {-74.9, -102}, {-74.3, -131},
+		{-77.5, -163}, {-77.4, 163}, {-71.7, 172}, {-65.9, 140}, {-65.7, 113},
+		{-66.6, 88}, {-66.9, 59}, {-69.8, 25}, {-70.0, -4}, {-71.0, -14},
+		{-77.3, -33}, {-77.9, -46}, {-74.7, -61},
+	}
+	if got := (EllipsoidalArea{}).Area(antarctica) * 1_000_000; math.Abs(got-13662703680020.1) > 1 {
+		t.Fatalf("antarctica area = %.1f m², want 13662703680020.1", got)
+	}
+}
+
+func TestAreaMeasuresIgnoreWindingAndClosure(t *testing.T) {
+	basin := []LatLon{{Lat: 41, Lon: 28}, {Lat: 41, Lon: 41}, {Lat: 46.5, Lon: 41}, {Lat: 46.5, Lon: 28}}
+	reversed := slices.Clone(basin)
+	slices.Reverse(reversed)
+	closed := append(slices.Clone(basin), basin[0])
+
+	for _, m := range []AreaMeasure{PlanarArea{}, SphericalArea{}, EllipsoidalArea{}} {
+		want := m.Area(basin)
+		if want <= 0 {
+			t.Fatalf("%s area = %f, want positive", m.Name(), want)
+		}
+		if got := m.Area(reversed); math.Abs(got-want) > 1e-6*want {
+			t.Fatalf("%s reversed area = %f, want %f", m.Name(), got, want)
+		}
+	}
+	// Planar area takes its grid origin from the mean of all vertices, so
+	// only the geodesic measures are exactly closure-independent.
+	for _, m := range []AreaMeasure{SphericalArea{}, EllipsoidalArea{}} {
+		if got, want := m.Area(closed), m.Area(basin); math.Abs(got-want) > 1e-6*want {
+			t.Fatalf("%s closed area = %f, want %f", m.Name(), got, want)
+		}
+	}
+}
+
+func TestSphericalAreaMatchesSphereAndEllipsoid(t *testing.T) {
+	// A polar cap bounded by a parallel has area 2πR²(1 − sin φ); with many
+	// vertices the geodesic edges hug the parallel closely.
+	var cap []LatLon
+	for lon := -180.0; lon < 180; lon += 0.5 {
+		cap = append(cap, LatLon{Lat: 80, Lon: lon})
+	}
+	want := 2 * math.Pi * EarthRadiusKM * EarthRadiusKM * (1 - math.Sin(80*math.Pi/180))
+	if got := (SphericalArea{}).Area(cap); math.Abs(got-want)/want > 1e-4 {
+		t.Fatalf("cap area = %.0f km², want %.0f", got, want)
+	}
+
+	sphere := Ellipsoid{Name: "sphere", A: EarthRadiusKM * 1000}
+	basin := []LatLon{{Lat: 41, Lon: 28}, {Lat: 41.5, Lon: 41.7}, {Lat: 46.6, Lon: 38}, {Lat: 45, Lon: 29.7}}
+	spherical := (SphericalArea{}).Area(basin)
+	if got := (EllipsoidalArea{Ellipsoid: sphere}).Area(basin); math.Abs(got-spherical) > 1e-6*spherical {
+		t.Fatalf("ellipsoidal area with f=0 = %.6f km², want spherical %.6f", got, spherical)
+	}
+}
+
+func TestPolygonAreaWithSubtractsHoles(t *testing.T) {
+	outer := []LatLon{{Lat: 40, Lon: 30}, {Lat: 40, Lon: 40}, {Lat: 45, Lon: 40}, {Lat: 45, Lon: 30}}
+	hole := []LatLon{{Lat: 42, Lon: 33}, {Lat: 43, Lon: 33}, {Lat: 43, Lon: 35}, {Lat: 42, Lon: 35}}
+
+	m := EllipsoidalArea{}
+	want := m.Area(outer) - m.Area(hole)
+	if got := PolygonAreaWith(outer, [][]LatLon{hole}, m); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("polygon area = %f, want %f", got, want)
+	}
+	if _, err := NewAreaMeasure("conical", WGS84); err == nil {
+		t.Fatalf("expected error for unknown area method")
+	}
+}
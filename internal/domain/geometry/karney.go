@@ -0,0 +1,705 @@
+package geometry
+
+import "math"
+
+// Karney's solution of the inverse geodesic problem ("Algorithms for
+// geodesics", J. Geod. 87, 2013), ported from GeographicLib with series to
+// sixth order in the third flattening. It converges for every pair of
+// points, including nearly antipodal ones, and is accurate to about 15 nm.
+
+const (
+	karneyOrder     = 6
+	karneyC3Count   = 15
+	karneyC4Count   = 21
+	karneyMaxIter1  = 20
+	karneyMaxIter2  = karneyMaxIter1 + 53 + 10
+	karneyDigitsEps = 0x1p-52
+)
+
+var (
+	karneyTiny    = math.Sqrt(math.SmallestNonzeroFloat64 * 0x1p52)
+	karneyTol0    = karneyDigitsEps
+	karneyTol1    = 200 * karneyTol0
+	karneyTol2    = math.Sqrt(karneyTol0)
+	karneyTolB    = karneyTol0 * karneyTol2
+	karneyXThresh = 1000 * karneyTol2
+)
+
+// KarneyDistance is the ellipsoidal geodesic distance. Build it with
+// NewKarneyDistance; the zero value measures on WGS84 but recomputes the
+// series coefficients on every call.
+type KarneyDistance struct {
+	Ellipsoid Ellipsoid
+
+	ready        bool
+	a, f, f1, e2 float64
+	ep2, n, b    float64
+	etol2, c2    float64
+	a3x          [karneyOrder]float64
+	c3x          [karneyC3Count]float64
+	c4x          [karneyC4Count]float64
+}
+
+func NewKarneyDistance(e Ellipsoid) KarneyDistance {
+	e = e.orDefault()
+	g := KarneyDistance{Ellipsoid: e, ready: true, a: e.A, f: e.F}
+	g.f1 = 1 - g.f
+	g.e2 = g.f * (2 - g.f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = g.f / (2 - g.f)
+	g.b = g.a * g.f1
+	g.etol2 = 0.1 * karneyTol2 / math.Sqrt(math.Max(0.001, math.Abs(g.f))*math.Min(1, 1-g.f/2)/2)
+	// c2 is the authalic radius squared: 4πc2 is the ellipsoid's area.
+	switch {
+	case g.e2 == 0:
+		g.c2 = g.a * g.a
+	case g.e2 > 0:
+		g.c2 = (g.a*g.a + g.b*g.b*math.Atanh(math.Sqrt(g.e2))/math.Sqrt(g.e2)) / 2
+	default:
+		g.c2 = (g.a*g.a + g.b*g.b*math.Atan(math.Sqrt(-g.e2))/math.Sqrt(-g.e2)) / 2
+	}
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+func (KarneyDistance) Name() string { return DistanceKarney }
+
+func (g KarneyDistance) Distance(p, q LatLon) float64 {
+	if !g.ready {
+		g = NewKarneyDistance(g.Ellipsoid)
+	}
+	s12, _ := g.inverse(p.Lat, p.Lon, q.Lat, q.Lon, false)
+	return s12 / 1000
+}
+
+// inverse returns the geodesic distance in meters and, when area is set,
+// the area in square meters between the geodesic and the equator (S12 in
+// Karney's notation).
+func (g KarneyDistance) inverse(lat1, lon1, lat2, lon2 float64, area bool) (s12, areaS12 float64) {
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if lon12 < 0 || (lon12 == 0 && math.Signbit(lon12)) {
+		lonsign = -1
+	}
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * math.Pi / 180
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapp = -1
+		lonsign = -lonsign
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.0
+	if lat1 < 0 {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(karneyTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(karneyTiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	var c1a, c2a [karneyOrder + 1]float64
+	var c3a [karneyOrder]float64
+	var s12x, sig12 float64
+	var salp1, calp1, salp2, calp2 float64
+	// somg12 = 2 marks that omg12 is not known (meridional geodesics).
+	somg12, comg12 := 2.0, 0.0
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12b, m12b, _ := g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, &c1a, &c2a)
+		if sig12 < 1 || m12b >= 0 {
+			if sig12 < 3*karneyTiny || (sig12 < karneyTol0 && (s12b < 0 || m12b < 0)) {
+				s12b = 0
+			}
+			s12x = s12b * g.b
+		} else {
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// Equatorial geodesic.
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		somg12, comg12 = math.Sincos(lam12 / g.f1)
+	} else if !meridian {
+		var sig12Start, dnm float64
+		sig12Start, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12)
+		if sig12Start >= 0 {
+			s12x = sig12Start * g.b * dnm
+			somg12, comg12 = math.Sincos(lam12 / (g.f1 * dnm))
+		} else {
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+			tripn, tripb := false, false
+			salp1a, calp1a := karneyTiny, 1.0
+			salp1b, calp1b := karneyTiny, -1.0
+			for numit := 0; numit < karneyMaxIter2; {
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+					salp1, calp1, slam12, clam12, numit < karneyMaxIter1, &c1a, &c2a, &c3a)
+				limit := 1.0
+				if tripn {
+					limit = 8
+				}
+				if tripb || !(math.Abs(v) >= limit*karneyTol0) {
+					break
+				}
+				if v > 0 && (numit > karneyMaxIter1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > karneyMaxIter1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				numit++
+				if numit < karneyMaxIter1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sincos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							tripn = math.Abs(v) <= 16*karneyTol0
+							continue
+						}
+					}
+				}
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < karneyTolB ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < karneyTolB
+			}
+			s12b, _, _ := g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, &c1a, &c2a)
+			s12x = s12b * g.b
+			sdomg12, cdomg12 := math.Sincos(domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+	if !area {
+		return 0 + s12x, 0
+	}
+
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+	if calp0 != 0 && salp0 != 0 {
+		ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+		ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+		k2 := calp0 * calp0 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		a4 := g.a * g.a * calp0 * salp0 * g.e2
+		var c4a [karneyOrder]float64
+		g.c4f(eps, &c4a)
+		areaS12 = a4 * (sinCosSeries(false, ssig2, csig2, c4a[:]) - sinCosSeries(false, ssig1, csig1, c4a[:]))
+	}
+
+	var alp12 float64
+	if !meridian && somg12 != 2 && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		domg12 := 1 + comg12
+		dbet1 := 1 + cbet1
+		dbet2 := 1 + cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		salp12 := salp2*calp1 - calp2*salp1
+		calp12 := calp2*calp1 + salp2*salp1
+		if salp12 == 0 && calp12 < 0 {
+			salp12 = karneyTiny * calp1
+			calp12 = -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+	areaS12 += g.c2 * alp12
+	areaS12 *= swapp * lonsign * latsign
+	return 0 + s12x, areaS12 + 0
+}
+
+// lengths returns the distance and the reduced length scaled by b, and m0.
+func (g KarneyDistance) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64,
+	c1a, c2a *[karneyOrder + 1]float64) (s12b, m12b, m0 float64) {
+	a1 := a1m1f(eps)
+	c1f(eps, c1a)
+	a2 := a2m1f(eps)
+	c2f(eps, c2a)
+	m0 = a1 - a2
+	a1++
+	a2++
+	b1 := sinCosSeries(true, ssig2, csig2, c1a[:]) - sinCosSeries(true, ssig1, csig1, c1a[:])
+	s12b = a1 * (sig12 + b1)
+	b2 := sinCosSeries(true, ssig2, csig2, c2a[:]) - sinCosSeries(true, ssig1, csig1, c2a[:])
+	j12 := m0*sig12 + (a1*b1 - a2*b2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b, m0
+}
+
+func (g KarneyDistance) inverseStart(sbet1, cbet1, sbet2, cbet2, lam12, slam12, clam12 float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < g.etol2:
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*somg12*somg12/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(g.n) >= 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1:
+		// Zeroth order spherical approximation is good enough.
+	default:
+		// Nearly antipodal points: start from the astroid solution.
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sbet1 * sbet1 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+		if y > -karneyTol1 && x > -1-karneyXThresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+func (g KarneyDistance) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool,
+	c1a, c2a *[karneyOrder + 1]float64, c3a *[karneyOrder]float64) (lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		calp1 = -karneyTiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(calp1*cbet1*calp1*cbet1+d) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := calp0 * calp0 * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, c3a)
+	b312 := sinCosSeries(true, ssig2, csig2, c3a[:]) - sinCosSeries(true, ssig1, csig1, c3a[:])
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, m12b, _ := g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, c1a, c2a)
+			dlam12 = m12b * g.f1 / (calp2 * cbet2)
+		}
+	} else {
+		dlam12 = math.NaN()
+	}
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12
+}
+
+func (g KarneyDistance) a3f(eps float64) float64 {
+	return polyval(karneyOrder-1, g.a3x[:], eps)
+}
+
+func (g KarneyDistance) c3f(eps float64, c *[karneyOrder]float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < karneyOrder; l++ {
+		m := karneyOrder - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func (g KarneyDistance) c4f(eps float64, c *[karneyOrder]float64) {
+	mult := 1.0
+	o := 0
+	for l := 0; l < karneyOrder; l++ {
+		m := karneyOrder - l - 1
+		c[l] = mult * polyval(m, g.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+func (g *KarneyDistance) a3coeff() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := karneyOrder - 1; j >= 0; j-- {
+		m := min(karneyOrder-j-1, j)
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+func (g *KarneyDistance) c3coeff() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < karneyOrder; l++ {
+		for j := karneyOrder - 1; j >= l; j-- {
+			m := min(karneyOrder-j-1, j)
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *KarneyDistance) c4coeff() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < karneyOrder; l++ {
+		for j := karneyOrder - 1; j >= l; j-- {
+			m := karneyOrder - j - 1
+			g.c4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func a1m1f(eps float64) float64 {
+	coeff := []float64{1, 4, 64, 0, 256}
+	m := karneyOrder / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+func a2m1f(eps float64) float64 {
+	coeff := []float64{-11, -28, -192, 0, 256}
+	m := karneyOrder / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+func c1f(eps float64, c *[karneyOrder + 1]float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	seriesCoefficients(eps, coeff, c)
+}
+
+func c2f(eps float64, c *[karneyOrder + 1]float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	seriesCoefficients(eps, coeff, c)
+}
+
+func seriesCoefficients(eps float64, coeff []float64, c *[karneyOrder + 1]float64) {
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= karneyOrder; l++ {
+		m := (karneyOrder - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// sinCosSeries evaluates sum(c[l] * sin(2*l*x)) by Clenshaw summation;
+// c[0] is unused.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k
+	if sinp {
+		n--
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+func norm2(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
+
+// angRound snaps tiny angles to zero so that lat = ±epsilon behaves as the
+// equator.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	return math.Copysign(y, x)
+}
+
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+// angDiff returns lon2 - lon1 reduced to [-180, 180] together with its
+// rounding error.
+func angDiff(x, y float64) (float64, float64) {
+	d, t := twoSum(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	return twoSum(d, t)
+}
+
+func twoSum(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	return s, -(up + vpp)
+}
+
+// sincosd is sin and cos of x degrees, exact at multiples of 90°.
+func sincosd(x float64) (float64, float64) {
+	r := math.Mod(x, 360)
+	q := math.Round(r / 90)
+	r -= 90 * q
+	s, c := math.Sincos(r * math.Pi / 180)
+	switch int(q) & 3 {
+	case 0:
+		return s, c
+	case 1:
+		return c, -s
+	case 2:
+		return -s, -c
+	default:
+		return -c, s
+	}
+}
@@ -10,3 +10,12 @@ func PolylineLength(points []LatLon) float64 {
 	}
 	return total
 }
+
+// MultiPolylineLength sums the geodesic lengths of independent polylines.
+func MultiPolylineLength(lines [][]LatLon) float64 {
+	var total float64
+	for _, line := range lines {
+		total += PolylineLength(line)
+	}
+	return total
+}
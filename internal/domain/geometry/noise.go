@@ -0,0 +1,39 @@
+package geometry
+
+import "math"
+
+// VertexNoise is a splitmix64 stream keyed by (seed, step, vertex). Every
+// vertex of every step gets its own stream without a shared source, so
+// parallel workers draw the same noise in any order and no two keys
+// overlap however long the line is.
+type VertexNoise struct {
+	state uint64
+}
+
+// NewVertexNoise hashes the key into the starting state of the stream.
+func NewVertexNoise(seed int64, step, vertex int) VertexNoise {
+	h := splitmix64(uint64(seed))
+	h = splitmix64(h ^ uint64(step))
+	h = splitmix64(h ^ uint64(vertex))
+	return VertexNoise{state: h}
+}
+
+// Float64 returns a uniform value in [0, 1).
+func (n *VertexNoise) Float64() float64 {
+	n.state += 0x9e3779b97f4a7c15
+	return float64(splitmix64(n.state)>>11) / (1 << 53)
+}
+
+// NormFloat64 returns a standard normal value (Box–Muller).
+func (n *VertexNoise) NormFloat64() float64 {
+	u := 1 - n.Float64()
+	v := n.Float64()
+	return math.Sqrt(-2*math.Log(u)) * math.Cos(2*math.Pi*v)
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
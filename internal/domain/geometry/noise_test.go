@@ -0,0 +1,37 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVertexNoiseKeysDoNotOverlapAcrossSteps(t *testing.T) {
+	// seed + step*10000 + i gave vertex i+10000 of step s the noise of vertex
+	// i of step s+1.
+	a := NewVertexNoise(7, 1, 10_003)
+	b := NewVertexNoise(7, 2, 3)
+	if a.NormFloat64() == b.NormFloat64() {
+		t.Fatal("expected different streams for different (step, vertex) keys")
+	}
+	c := NewVertexNoise(7, 2, 3)
+	d := NewVertexNoise(7, 2, 3)
+	if c.NormFloat64() != d.NormFloat64() || c.NormFloat64() != d.NormFloat64() {
+		t.Fatal("expected equal keys to give equal streams")
+	}
+}
+
+func TestVertexNoiseIsStandardNormal(t *testing.T) {
+	const n = 40_000
+	sum, sumSq := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		noise := NewVertexNoise(42, 1, i)
+		x := noise.NormFloat64()
+		sum += x
+		sumSq += x * x
+	}
+	mean := sum / n
+	variance := sumSq/n - mean*mean
+	if math.Abs(mean) > 0.03 || math.Abs(variance-1) > 0.03 {
+		t.Fatalf("expected mean 0 and variance 1 over vertices, got %.4f and %.4f", mean, variance)
+	}
+}
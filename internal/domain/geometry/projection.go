@@ -0,0 +1,62 @@
+package geometry
+
+import (
+	"math"
+
+	"coastal-geometry/internal/domain/projection"
+)
+
+// ProjectionFor builds the named projection centred on the bounding box of
+// the point sets, so one run can share it between every subsystem.
+func ProjectionFor(name string, sets ...[]LatLon) (projection.Projector, error) {
+	lat, lon := boundsCenter(sets...)
+	return projection.New(name, lat, lon)
+}
+
+// projectorOr returns p, or the default projection centred on the points
+// when p is nil.
+func projectorOr(p projection.Projector, sets ...[]LatLon) projection.Projector {
+	if p != nil {
+		return p
+	}
+	lat, lon := boundsCenter(sets...)
+	p, _ = projection.New(projection.Default, lat, lon)
+	return p
+}
+
+// ProjectPoints maps points to plane meters with p; nil means the default
+// projection centred on the points.
+func ProjectPoints(p projection.Projector, points []LatLon) [][2]float64 {
+	p = projectorOr(p, points)
+	out := make([][2]float64, len(points))
+	for i, pt := range points {
+		out[i][0], out[i][1] = p.Forward(pt.Lat, pt.Lon)
+	}
+	return out
+}
+
+// shiftMeters moves p by dx meters east and dy meters north on the ground.
+// Each component is mapped through the local ground axes of the projection,
+// so the displacement does not depend on how much the projection stretches
+// the map there, nor on the stretch differing between parallel and meridian.
+func shiftMeters(proj projection.Projector, p LatLon, dx, dy float64) LatLon {
+	east, north := projection.GroundAxes(proj, p.Lat, p.Lon)
+	x, y := proj.Forward(p.Lat, p.Lon)
+	lat, lon := proj.Inverse(x+dx*east[0]+dy*north[0], y+dx*east[1]+dy*north[1])
+	return LatLon{Lat: lat, Lon: lon}
+}
+
+func boundsCenter(sets ...[]LatLon) (lat, lon float64) {
+	minLat, maxLat := math.Inf(1), math.Inf(-1)
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	for _, points := range sets {
+		for _, p := range points {
+			minLat, maxLat = math.Min(minLat, p.Lat), math.Max(maxLat, p.Lat)
+			minLon, maxLon = math.Min(minLon, p.Lon), math.Max(maxLon, p.Lon)
+		}
+	}
+	if math.IsInf(minLat, 1) {
+		return 0, 0
+	}
+	return (minLat + maxLat) / 2, (minLon + maxLon) / 2
+}
@@ -0,0 +1,35 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/projection"
+)
+
+func TestShiftMetersMovesGroundMetersOffLAEAOrigin(t *testing.T) {
+	// 6° from the origin LAEA stretches parallels and shrinks meridians by
+	// about 0.25% each; one shared factor put a north shift of 1 km 5 m short.
+	proj := projection.NewLAEA(43.4, 34.6)
+	p := LatLon{Lat: 46.6, Lon: 41.7}
+
+	const a, f = 6378137.0, 1 / 298.257223563
+	e2 := f * (2 - f)
+	s := math.Sin(p.Lat * math.Pi / 180)
+	w := 1 - e2*s*s
+	meridian := a * (1 - e2) / (w * math.Sqrt(w))
+	parallel := a / math.Sqrt(w) * math.Cos(p.Lat*math.Pi/180)
+
+	north := shiftMeters(proj, p, 0, 1000)
+	if got := (north.Lat - p.Lat) * math.Pi / 180 * meridian; math.Abs(got-1000) > 0.05 {
+		t.Fatalf("north shift of 1000 m moved %.3f m along the meridian", got)
+	}
+	if drift := (north.Lon - p.Lon) * math.Pi / 180 * parallel; math.Abs(drift) > 0.05 {
+		t.Fatalf("north shift drifted %.3f m east", drift)
+	}
+
+	east := shiftMeters(proj, p, 1000, 0)
+	if got := (east.Lon - p.Lon) * math.Pi / 180 * parallel; math.Abs(got-1000) > 0.05 {
+		t.Fatalf("east shift of 1000 m moved %.3f m along the parallel", got)
+	}
+}
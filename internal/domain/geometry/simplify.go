@@ -1,9 +1,17 @@
 package geometry
 
-import "math"
+import (
+	"math"
 
+	"coastal-geometry/internal/domain/projection"
+)
+
+// SimplifyOptions configures SimplifyPolyline. Tolerances are measured in
+// the plane of Projection; nil means the default projection centred on the
+// points.
 type SimplifyOptions struct {
-	MaxPoints int
+	MaxPoints  int
+	Projection projection.Projector
 }
 
 type SimplifyResult struct {
@@ -59,7 +67,7 @@ func SimplifyPolyline(points []LatLon, options SimplifyOptions) SimplifyResult {
 		return result
 	}
 
-	projected := projectToMeters(working)
+	projected := projectToMeters(working, options.Projection)
 	diagonal := projectedDiagonal(projected)
 	if diagonal <= 0 {
 		result.SimplifiedClosed = closed
@@ -176,32 +184,14 @@ func squaredDistance(a, b pointXY) float64 {
 	return dx*dx + dy*dy
 }
 
-func projectToMeters(points []LatLon) []pointXY {
+func projectToMeters(points []LatLon, proj projection.Projector) []pointXY {
 	if len(points) == 0 {
 		return nil
 	}
 
-	refLat := 0.0
-	refLon := 0.0
-	for _, point := range points {
-		refLat += point.Lat
-		refLon += point.Lon
-	}
-	refLat /= float64(len(points))
-	refLon /= float64(len(points))
-
-	metersPerDegLat := 111194.9
-	metersPerDegLon := metersPerDegLat * math.Cos(refLat*math.Pi/180)
-	if math.Abs(metersPerDegLon) < 1e-9 {
-		metersPerDegLon = metersPerDegLat
-	}
-
 	projected := make([]pointXY, len(points))
-	for i, point := range points {
-		projected[i] = pointXY{
-			X: (point.Lon - refLon) * metersPerDegLon,
-			Y: (point.Lat - refLat) * metersPerDegLat,
-		}
+	for i, xy := range ProjectPoints(proj, points) {
+		projected[i] = pointXY{X: xy[0], Y: xy[1]}
 	}
 	return projected
 }
@@ -0,0 +1,78 @@
+package geometry
+
+import "math"
+
+const (
+	vincentyMaxIterations = 200
+	vincentyTolerance     = 1e-12
+)
+
+// VincentyDistance solves the inverse geodesic problem with Vincenty's
+// iteration (1975). It is accurate to fractions of a millimeter but does not
+// converge for nearly antipodal points; those fall back to Karney's method.
+// The zero value uses WGS84.
+type VincentyDistance struct {
+	Ellipsoid Ellipsoid
+}
+
+func (VincentyDistance) Name() string { return DistanceVincenty }
+
+func (d VincentyDistance) Distance(p, q LatLon) float64 {
+	e := d.Ellipsoid.orDefault()
+	meters, ok := vincentyInverse(e, p, q)
+	if !ok {
+		meters, _ = NewKarneyDistance(e).inverse(p.Lat, p.Lon, q.Lat, q.Lon, false)
+	}
+	return meters / 1000
+}
+
+func vincentyInverse(e Ellipsoid, p, q LatLon) (float64, bool) {
+	a, f := e.A, e.F
+	b := a * (1 - f)
+
+	lonDiff := math.Remainder(q.Lon-p.Lon, 360) * math.Pi / 180
+	u1 := math.Atan((1 - f) * math.Tan(p.Lat*math.Pi/180))
+	u2 := math.Atan((1 - f) * math.Tan(q.Lat*math.Pi/180))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := lonDiff
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	converged := false
+	for i := 0; i < vincentyMaxIterations; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, true
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		previous := lambda
+		lambda = lonDiff + (1-c)*f*sinAlpha*
+			(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda) > math.Pi {
+			return 0, false
+		}
+		if math.Abs(lambda-previous) < vincentyTolerance {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return 0, false
+	}
+
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := bigB * sinSigma * (cos2SigmaM + bigB/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return b * bigA * (sigma - deltaSigma), true
+}
@@ -0,0 +1,316 @@
+package lithology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+const (
+	RockClay      = "clay"
+	RockLimestone = "limestone"
+	RockGranite   = "granite"
+	RockPebble    = "pebble"
+)
+
+// Rock is a rock type with its resistance to erosion. Erosion displacement
+// scales with 1/Resistance, so 1 leaves the base rate unchanged.
+type Rock struct {
+	Name       string  `json:"-"`
+	Label      string  `json:"label,omitempty"`
+	Resistance float64 `json:"resistance"`
+	Color      string  `json:"color,omitempty"`
+}
+
+// BuiltinRocks are the rock types available without declaring them in the
+// profile file. Resistances are relative to limestone.
+var BuiltinRocks = map[string]Rock{
+	RockClay:      {Name: RockClay, Label: "глинистые обрывы", Resistance: 0.5, Color: "#b5651d"},
+	RockLimestone: {Name: RockLimestone, Label: "известняк", Resistance: 1, Color: "#c9b458"},
+	RockPebble:    {Name: RockPebble, Label: "галечные пляжи", Resistance: 1.5, Color: "#7f8c8d"},
+	RockGranite:   {Name: RockGranite, Label: "гранит", Resistance: 4, Color: "#8e3b46"},
+}
+
+// unassigned is used for vertices no rule covers when the profile has no
+// default rock.
+var unassigned = Rock{Name: "unassigned", Label: "не задано", Resistance: 1, Color: "#1f6f8b"}
+
+// Rule maps a part of the coastline to a rock type, either by an inclusive
+// range of vertex indexes of the main ring or by a lat/lon polygon.
+// Resistance overrides the rock resistance for this rule only.
+type Rule struct {
+	Rock       string            `json:"rock"`
+	Resistance float64           `json:"resistance,omitempty"`
+	From       *int              `json:"from,omitempty"`
+	To         *int              `json:"to,omitempty"`
+	Polygon    []geometry.LatLon `json:"polygon,omitempty"`
+}
+
+// Profile is a lithology input file. Rules are applied in order, so a later
+// rule overrides an earlier one where they overlap.
+type Profile struct {
+	Name        string          `json:"name"`
+	DefaultRock string          `json:"default_rock,omitempty"`
+	Rocks       map[string]Rock `json:"rocks,omitempty"`
+	Rules       []Rule          `json:"segments"`
+	Source      string          `json:"-"`
+}
+
+// Assignment holds the rock of every vertex of the polyline it was resolved for.
+type Assignment []Rock
+
+type RockSummary struct {
+	Rock       Rock
+	Points     int
+	Segments   int
+	LengthKM   float64
+	ShareOfLen float64
+}
+
+// Run is a maximal stretch of consecutive segments of the same rock.
+type Run struct {
+	Rock   Rock
+	Points []geometry.LatLon
+}
+
+func Load(path string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("read lithology json %q: %w", path, err)
+	}
+	profile, err := Parse(data)
+	if err != nil {
+		return Profile{}, fmt.Errorf("parse lithology json %q: %w", path, err)
+	}
+	profile.Source = path
+	return profile, nil
+}
+
+func Parse(data []byte) (Profile, error) {
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return Profile{}, err
+	}
+
+	rocks := make(map[string]Rock, len(BuiltinRocks)+len(profile.Rocks))
+	for name, rock := range BuiltinRocks {
+		rocks[name] = rock
+	}
+	for name, rock := range profile.Rocks {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return Profile{}, fmt.Errorf("rock type with empty name")
+		}
+		if rock.Resistance <= 0 {
+			return Profile{}, fmt.Errorf("rock %q must have a positive resistance", name)
+		}
+		base := rocks[name]
+		rock.Name = name
+		if rock.Label == "" {
+			rock.Label = base.Label
+		}
+		if rock.Label == "" {
+			rock.Label = name
+		}
+		if rock.Color == "" {
+			rock.Color = base.Color
+		}
+		if rock.Color == "" {
+			rock.Color = unassigned.Color
+		}
+		rocks[name] = rock
+	}
+	profile.Rocks = rocks
+
+	if profile.DefaultRock != "" {
+		if _, ok := rocks[profile.DefaultRock]; !ok {
+			return Profile{}, fmt.Errorf("unknown default rock %q", profile.DefaultRock)
+		}
+	}
+	if len(profile.Rules) == 0 {
+		return Profile{}, fmt.Errorf("lithology profile has no segments")
+	}
+	for i, rule := range profile.Rules {
+		if err := validateRule(rule, rocks); err != nil {
+			return Profile{}, fmt.Errorf("segment %d: %w", i, err)
+		}
+	}
+	return profile, nil
+}
+
+func validateRule(rule Rule, rocks map[string]Rock) error {
+	if _, ok := rocks[rule.Rock]; !ok {
+		return fmt.Errorf("unknown rock %q", rule.Rock)
+	}
+	if rule.Resistance < 0 {
+		return fmt.Errorf("resistance must be positive")
+	}
+
+	hasRange := rule.From != nil || rule.To != nil
+	hasPolygon := len(rule.Polygon) > 0
+	switch {
+	case hasRange && hasPolygon:
+		return fmt.Errorf("use either from/to or polygon, not both")
+	case hasRange:
+		if rule.From == nil || rule.To == nil {
+			return fmt.Errorf("index range needs both from and to")
+		}
+		if *rule.From < 0 || *rule.To < *rule.From {
+			return fmt.Errorf("invalid index range %d..%d", *rule.From, *rule.To)
+		}
+	case hasPolygon:
+		if len(rule.Polygon) < 3 {
+			return fmt.Errorf("polygon needs at least 3 points")
+		}
+	default:
+		return fmt.Errorf("segment needs from/to or polygon")
+	}
+	return nil
+}
+
+// Resolve assigns a rock to every vertex of points. Index ranges that reach
+// past the polyline are clipped and reported as warnings.
+func (p Profile) Resolve(points []geometry.LatLon) (Assignment, []string) {
+	base := unassigned
+	if rock, ok := p.Rocks[p.DefaultRock]; ok && p.DefaultRock != "" {
+		base = rock
+	}
+
+	assignment := make(Assignment, len(points))
+	for i := range assignment {
+		assignment[i] = base
+	}
+
+	var warnings []string
+	for idx, rule := range p.Rules {
+		rock := p.Rocks[rule.Rock]
+		if rule.Resistance > 0 {
+			rock.Resistance = rule.Resistance
+		}
+
+		if rule.From != nil {
+			from, to := *rule.From, *rule.To
+			if from >= len(points) {
+				warnings = append(warnings, fmt.Sprintf("lithology segment %d (%s) starts at index %d beyond the %d loaded points; skipped", idx, rule.Rock, from, len(points)))
+				continue
+			}
+			if to >= len(points) {
+				warnings = append(warnings, fmt.Sprintf("lithology segment %d (%s) clipped from index %d to %d", idx, rule.Rock, to, len(points)-1))
+				to = len(points) - 1
+			}
+			for i := from; i <= to; i++ {
+				assignment[i] = rock
+			}
+			continue
+		}
+
+		matched := 0
+		for i, point := range points {
+			if pointInPolygon(point, rule.Polygon) {
+				assignment[i] = rock
+				matched++
+			}
+		}
+		if matched == 0 {
+			warnings = append(warnings, fmt.Sprintf("lithology segment %d (%s) polygon contains no coastline points", idx, rule.Rock))
+		}
+	}
+	return assignment, warnings
+}
+
+// Weights returns the per-vertex erosion multipliers 1/resistance.
+func (a Assignment) Weights() []float64 {
+	weights := make([]float64, len(a))
+	for i, rock := range a {
+		weights[i] = 1 / rock.Resistance
+	}
+	return weights
+}
+
+// Resistances returns the per-vertex resistance coefficients.
+func (a Assignment) Resistances() []float64 {
+	resistances := make([]float64, len(a))
+	for i, rock := range a {
+		resistances[i] = rock.Resistance
+	}
+	return resistances
+}
+
+// Runs splits points into stretches of the same rock. A segment takes the
+// rock of its first vertex; neighbouring runs share their boundary point.
+func (a Assignment) Runs(points []geometry.LatLon) []Run {
+	if len(points) < 2 || len(a) != len(points) {
+		return nil
+	}
+
+	var runs []Run
+	start := 0
+	for i := 1; i < len(points)-1; i++ {
+		if a[i] != a[start] {
+			runs = append(runs, Run{Rock: a[start], Points: points[start : i+1]})
+			start = i
+		}
+	}
+	runs = append(runs, Run{Rock: a[start], Points: points[start:]})
+	return runs
+}
+
+// Summaries aggregates points, segments and length by rock, longest first.
+// Rules that override the resistance of a rock are summarized separately.
+func (a Assignment) Summaries(points []geometry.LatLon) []RockSummary {
+	if len(a) != len(points) {
+		return nil
+	}
+
+	byRock := map[Rock]*RockSummary{}
+	var order []Rock
+	total := 0.0
+	for i, rock := range a {
+		summary, ok := byRock[rock]
+		if !ok {
+			summary = &RockSummary{Rock: rock}
+			byRock[rock] = summary
+			order = append(order, rock)
+		}
+		summary.Points++
+		if i+1 < len(points) {
+			length := geometry.Haversine(points[i], points[i+1])
+			summary.Segments++
+			summary.LengthKM += length
+			total += length
+		}
+	}
+
+	summaries := make([]RockSummary, 0, len(order))
+	for _, rock := range order {
+		summary := *byRock[rock]
+		if total > 0 {
+			summary.ShareOfLen = summary.LengthKM / total
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].LengthKM > summaries[j].LengthKM
+	})
+	return summaries
+}
+
+func pointInPolygon(point geometry.LatLon, polygon []geometry.LatLon) bool {
+	inside := false
+	j := len(polygon) - 1
+	for i := range polygon {
+		pi, pj := polygon[i], polygon[j]
+		if (pi.Lat > point.Lat) != (pj.Lat > point.Lat) {
+			lon := pj.Lon + (point.Lat-pj.Lat)*(pi.Lon-pj.Lon)/(pi.Lat-pj.Lat)
+			if point.Lon < lon {
+				inside = !inside
+			}
+		}
+		j = i
+	}
+	return inside
+}
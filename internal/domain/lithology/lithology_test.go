@@ -0,0 +1,84 @@
+package lithology
+
+import (
+	"strings"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func linePoints(n int) []geometry.LatLon {
+	points := make([]geometry.LatLon, n)
+	for i := range points {
+		points[i] = geometry.LatLon{Lat: 44, Lon: 30 + float64(i)*0.1}
+	}
+	return points
+}
+
+func TestResolveAppliesRangesAndPolygonsInOrder(t *testing.T) {
+	profile, err := Parse([]byte(`{
+		"name": "test",
+		"default_rock": "limestone",
+		"rocks": {"sand": {"resistance": 0.7}},
+		"segments": [
+			{"rock": "clay", "from": 0, "to": 3},
+			{"rock": "granite", "polygon": [{"lat": 43, "lon": 30.25}, {"lat": 43, "lon": 30.45}, {"lat": 45, "lon": 30.45}, {"lat": 45, "lon": 30.25}]},
+			{"rock": "sand", "resistance": 0.2, "from": 8, "to": 20}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	points := linePoints(10)
+	rocks, warnings := profile.Resolve(points)
+
+	want := []string{RockClay, RockClay, RockClay, RockGranite, RockGranite, RockLimestone, RockLimestone, RockLimestone, "sand", "sand"}
+	for i, name := range want {
+		if rocks[i].Name != name {
+			t.Fatalf("point %d: expected %q, got %q", i, name, rocks[i].Name)
+		}
+	}
+	if rocks[8].Resistance != 0.2 {
+		t.Fatalf("expected rule resistance override, got %v", rocks[8].Resistance)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "clipped") {
+		t.Fatalf("expected one clipping warning, got %v", warnings)
+	}
+
+	weights := rocks.Weights()
+	if weights[0] != 2 || weights[3] != 0.25 || weights[5] != 1 {
+		t.Fatalf("unexpected weights %v", weights)
+	}
+
+	runs := rocks.Runs(points)
+	if len(runs) != 4 {
+		t.Fatalf("expected 4 runs, got %d", len(runs))
+	}
+	if runs[1].Rock.Name != RockGranite || len(runs[1].Points) != 3 {
+		t.Fatalf("expected granite run of points 3..5, got %s with %d points", runs[1].Rock.Name, len(runs[1].Points))
+	}
+}
+
+func TestParseRejectsInvalidProfiles(t *testing.T) {
+	tests := map[string]string{
+		"unknown rock":      `{"segments": [{"rock": "basalt", "from": 0, "to": 1}]}`,
+		"no selector":       `{"segments": [{"rock": "clay"}]}`,
+		"both selectors":    `{"segments": [{"rock": "clay", "from": 0, "to": 1, "polygon": [{"lat": 0, "lon": 0}, {"lat": 1, "lon": 0}, {"lat": 1, "lon": 1}]}]}`,
+		"reversed range":    `{"segments": [{"rock": "clay", "from": 5, "to": 1}]}`,
+		"zero resistance":   `{"rocks": {"sand": {"resistance": 0}}, "segments": [{"rock": "sand", "from": 0, "to": 1}]}`,
+		"short polygon":     `{"segments": [{"rock": "clay", "polygon": [{"lat": 0, "lon": 0}, {"lat": 1, "lon": 1}]}]}`,
+		"empty segments":    `{"segments": []}`,
+		"unknown default":   `{"default_rock": "basalt", "segments": [{"rock": "clay", "from": 0, "to": 1}]}`,
+		"half open range":   `{"segments": [{"rock": "clay", "from": 0}]}`,
+		"malformed payload": `{"segments": [`,
+	}
+
+	for name, payload := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(payload)); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
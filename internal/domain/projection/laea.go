@@ -0,0 +1,82 @@
+package projection
+
+import (
+	"fmt"
+	"math"
+)
+
+// LambertAzimuthal is the oblique Lambert azimuthal equal-area projection on
+// WGS84 (Snyder, Map Projections — A Working Manual, §24). Areas are exact
+// everywhere; shapes are true at the origin and shear slowly away from it.
+type LambertAzimuthal struct {
+	Lat0, Lon0 float64
+
+	e, qp, rq    float64
+	sinB1, cosB1 float64
+	d            float64
+}
+
+func NewLAEA(lat0, lon0 float64) LambertAzimuthal {
+	e := math.Sqrt(wgs84F * (2 - wgs84F))
+	l := LambertAzimuthal{Lat0: lat0, Lon0: lon0, e: e}
+	l.qp = l.q(1)
+	l.rq = wgs84A * math.Sqrt(l.qp/2)
+
+	sinPhi1 := math.Sin(lat0 * math.Pi / 180)
+	b1 := math.Asin(l.q(sinPhi1) / l.qp)
+	l.sinB1, l.cosB1 = math.Sincos(b1)
+	m1 := math.Cos(lat0*math.Pi/180) / math.Sqrt(1-e*e*sinPhi1*sinPhi1)
+	if l.cosB1 == 0 {
+		l.d = 1
+	} else {
+		l.d = wgs84A * m1 / (l.rq * l.cosB1)
+	}
+	return l
+}
+
+// q is Snyder's q(φ) for sin φ; the authalic latitude is asin(q/qp).
+func (l LambertAzimuthal) q(sinPhi float64) float64 {
+	e := l.e
+	es := e * sinPhi
+	return (1 - e*e) * (sinPhi/(1-es*es) - math.Log((1-es)/(1+es))/(2*e))
+}
+
+func (l LambertAzimuthal) Forward(lat, lon float64) (float64, float64) {
+	sinB := math.Max(-1, math.Min(1, l.q(math.Sin(lat*math.Pi/180))/l.qp))
+	cosB := math.Sqrt(1 - sinB*sinB)
+	sinL, cosL := math.Sincos(math.Remainder(lon-l.Lon0, 360) * math.Pi / 180)
+
+	denom := 1 + l.sinB1*sinB + l.cosB1*cosB*cosL
+	if denom <= 0 {
+		// The antipode of the origin maps to a circle; pin it to one point.
+		return 0, -2 * l.rq / l.d
+	}
+	b := l.rq * math.Sqrt(2/denom)
+	return b * l.d * cosB * sinL, b / l.d * (l.cosB1*sinB - l.sinB1*cosB*cosL)
+}
+
+func (l LambertAzimuthal) Inverse(x, y float64) (float64, float64) {
+	rho := math.Hypot(x/l.d, l.d*y)
+	if rho == 0 {
+		return l.Lat0, l.Lon0
+	}
+	ce := 2 * math.Asin(math.Min(1, rho/(2*l.rq)))
+	sinCe, cosCe := math.Sincos(ce)
+	sinB := math.Max(-1, math.Min(1, cosCe*l.sinB1+l.d*y*sinCe*l.cosB1/rho))
+	beta := math.Asin(sinB)
+
+	e2 := l.e * l.e
+	e4, e6 := e2*e2, e2*e2*e2
+	phi := beta +
+		(e2/3+31*e4/180+517*e6/5040)*math.Sin(2*beta) +
+		(23*e4/360+251*e6/3780)*math.Sin(4*beta) +
+		(761*e6/45360)*math.Sin(6*beta)
+	lon := l.Lon0 + math.Atan2(x*sinCe, l.d*rho*l.cosB1*cosCe-l.d*l.d*y*l.sinB1*sinCe)*180/math.Pi
+	return phi * 180 / math.Pi, math.Remainder(lon, 360)
+}
+
+func (LambertAzimuthal) Name() string { return LAEA }
+
+func (l LambertAzimuthal) String() string {
+	return fmt.Sprintf("LAEA %.2f°, %.2f°", l.Lat0, l.Lon0)
+}
@@ -0,0 +1,27 @@
+package projection
+
+import "math"
+
+// webMercatorMaxLat keeps the projected world square, as web map tiles do.
+const webMercatorMaxLat = 85.05112877980659
+
+// Mercator is Web Mercator (EPSG:3857): the spherical Mercator formulas
+// applied to WGS84 coordinates on a sphere of the WGS84 equatorial radius.
+// It matches web map tiles but is neither conformal on the ellipsoid nor
+// equal-area: plane distances exceed ground distances by 1/cos φ.
+type Mercator struct{}
+
+func (Mercator) Forward(lat, lon float64) (float64, float64) {
+	lat = math.Max(-webMercatorMaxLat, math.Min(webMercatorMaxLat, lat))
+	phi := lat * math.Pi / 180
+	return wgs84A * math.Remainder(lon, 360) * math.Pi / 180, wgs84A * math.Log(math.Tan(math.Pi/4+phi/2))
+}
+
+func (Mercator) Inverse(x, y float64) (float64, float64) {
+	lat := (2*math.Atan(math.Exp(y/wgs84A)) - math.Pi/2) * 180 / math.Pi
+	return lat, x / wgs84A * 180 / math.Pi
+}
+
+func (Mercator) Name() string { return WebMercator }
+
+func (Mercator) String() string { return "Web Mercator" }
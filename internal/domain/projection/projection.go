@@ -0,0 +1,107 @@
+package projection
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Map projections shared by analysis and rendering. Every projection maps
+// geographic degrees to plane meters on WGS84 and back, so box counting,
+// simplification, erosion and SVG work in one coordinate system per run.
+
+const (
+	UTM         = "utm"
+	LAEA        = "laea"
+	WebMercator = "webmercator"
+
+	// Default is equal-area and centred on the data, so extended basins keep
+	// their shape and area better than in a single UTM zone.
+	Default = LAEA
+)
+
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+)
+
+// Projector converts between geographic coordinates in degrees and plane
+// coordinates in meters: X grows eastwards, Y northwards. String describes
+// the projection with its parameters for reports.
+type Projector interface {
+	Forward(lat, lon float64) (x, y float64)
+	Inverse(x, y float64) (lat, lon float64)
+	Name() string
+	String() string
+}
+
+// New builds the projection selected by name for data centred at
+// (centerLat, centerLon): UTM picks the zone and hemisphere of the centre,
+// LAEA uses it as the projection origin, Web Mercator ignores it.
+func New(name string, centerLat, centerLon float64) (Projector, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", LAEA:
+		return NewLAEA(centerLat, centerLon), nil
+	case UTM:
+		return NewUTM(UTMZone(centerLon), centerLat < 0), nil
+	case WebMercator:
+		return Mercator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown projection %q (known: %s, %s, %s)", name, UTM, LAEA, WebMercator)
+	}
+}
+
+// Names lists the projections accepted by New.
+func Names() []string {
+	return []string{UTM, LAEA, WebMercator}
+}
+
+// ScaleFactor is the ratio of plane to ground distance along the parallel
+// through a point, measured over a short step on WGS84. Conformal
+// projections have the same factor in every direction.
+func ScaleFactor(p Projector, lat, lon float64) float64 {
+	const step = 1e-4
+	x1, y1 := p.Forward(lat, lon-step/2)
+	x2, y2 := p.Forward(lat, lon+step/2)
+	ground := step * math.Pi / 180 * primeVerticalRadius(lat) * math.Cos(lat*math.Pi/180)
+	if ground == 0 {
+		return 1
+	}
+	return math.Hypot(x2-x1, y2-y1) / ground
+}
+
+// GroundAxes returns the plane offsets of one ground meter east and one
+// ground meter north of a point, measured over short steps on WGS84. Off the
+// centre of LAEA the two have different lengths, the meridian scale being
+// 1/k of the parallel scale, and both turn with the meridian convergence.
+func GroundAxes(p Projector, lat, lon float64) (east, north [2]float64) {
+	const step = 1e-4
+	rad := step * math.Pi / 180
+
+	x1, y1 := p.Forward(lat-step/2, lon)
+	x2, y2 := p.Forward(lat+step/2, lon)
+	ground := rad * meridianRadius(lat)
+	north = [2]float64{(x2 - x1) / ground, (y2 - y1) / ground}
+
+	x1, y1 = p.Forward(lat, lon-step/2)
+	x2, y2 = p.Forward(lat, lon+step/2)
+	ground = rad * primeVerticalRadius(lat) * math.Cos(lat*math.Pi/180)
+	if ground == 0 {
+		// At the pole east is north turned clockwise.
+		return [2]float64{north[1], -north[0]}, north
+	}
+	east = [2]float64{(x2 - x1) / ground, (y2 - y1) / ground}
+	return east, north
+}
+
+func meridianRadius(lat float64) float64 {
+	e2 := wgs84F * (2 - wgs84F)
+	s := math.Sin(lat * math.Pi / 180)
+	return wgs84A * (1 - e2) / math.Pow(1-e2*s*s, 1.5)
+}
+
+func primeVerticalRadius(lat float64) float64 {
+	e2 := wgs84F * (2 - wgs84F)
+	s := math.Sin(lat * math.Pi / 180)
+	return wgs84A / math.Sqrt(1-e2*s*s)
+}
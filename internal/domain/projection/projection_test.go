@@ -0,0 +1,113 @@
+package projection
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUTMMeridianArcAndZones(t *testing.T) {
+	// On the central meridian northing is k0 times the meridian arc, 4 984
+	// 944.378 m from the equator to 45° on WGS84.
+	utm := NewUTM(32, false)
+	x, y := utm.Forward(45, 9)
+	if math.Abs(x-500000) > 1e-6 || math.Abs(y-0.9996*4984944.378) > 0.001 {
+		t.Fatalf("utm(45, 9) = (%.3f, %.3f), want (500000, %.3f)", x, y, 0.9996*4984944.378)
+	}
+	if k := ScaleFactor(utm, 45, 9); math.Abs(k-0.9996) > 1e-6 {
+		t.Fatalf("scale on central meridian = %.7f, want 0.9996", k)
+	}
+
+	for _, c := range []struct {
+		lon  float64
+		zone int
+	}{{-180, 1}, {-177.1, 1}, {0, 31}, {33.5, 36}, {179.9, 60}, {180, 1}} {
+		if got := UTMZone(c.lon); got != c.zone {
+			t.Fatalf("UTMZone(%v) = %d, want %d", c.lon, got, c.zone)
+		}
+	}
+	if _, y := NewUTM(36, true).Forward(-0.000001, 33); y < 9_999_999 {
+		t.Fatalf("southern hemisphere northing = %.1f, want false northing 10 000 km", y)
+	}
+}
+
+func TestLAEAMatchesEPSGExample(t *testing.T) {
+	// EPSG Guidance Note 7-2: ETRS89-LAEA (52°N, 10°E, false origin
+	// 4 321 000 / 3 210 000) maps 50°N 5°E to 3 962 799.45 / 2 999 718.85.
+	laea := NewLAEA(52, 10)
+	x, y := laea.Forward(50, 5)
+	if math.Abs(x+4321000-3962799.45) > 0.01 || math.Abs(y+3210000-2999718.85) > 0.01 {
+		t.Fatalf("laea(50, 5) = (%.2f, %.2f), want (3962799.45, 2999718.85) with false origin", x+4321000, y+3210000)
+	}
+}
+
+func TestWebMercatorSquareWorld(t *testing.T) {
+	x, y := Mercator{}.Forward(90, 180)
+	if math.Abs(x-20037508.342789) > 1e-3 || math.Abs(y-20037508.342789) > 1e-3 {
+		t.Fatalf("web mercator corner = (%.6f, %.6f), want 20037508.342789", x, y)
+	}
+}
+
+func TestProjectionsRoundTrip(t *testing.T) {
+	points := [][2]float64{{41.0, 28.0}, {46.6, 38.0}, {43.4, 34.6}, {41.6, 41.7}, {45.2, 29.7}}
+	for _, name := range Names() {
+		p, err := New(name, 43.4, 34.6)
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", name, err)
+		}
+		for _, pt := range points {
+			x, y := p.Forward(pt[0], pt[1])
+			lat, lon := p.Inverse(x, y)
+			if math.Abs(lat-pt[0]) > 1e-8 || math.Abs(lon-pt[1]) > 1e-8 {
+				t.Fatalf("%s round trip of %v = (%.10f, %.10f)", p, pt, lat, lon)
+			}
+		}
+	}
+	if _, err := New("plate-carree", 0, 0); err == nil {
+		t.Fatal("expected unknown projection to be rejected")
+	}
+}
+
+func TestLAEAPreservesAreaAwayFromOrigin(t *testing.T) {
+	// A small cell's plane area equals its ellipsoidal area: the meridian
+	// scale is the inverse of the parallel scale.
+	laea := NewLAEA(43.4, 34.6)
+	for _, pt := range [][2]float64{{43.4, 34.6}, {41, 28}, {46.6, 41.7}} {
+		const d = 1e-4
+		x0, y0 := laea.Forward(pt[0], pt[1])
+		x1, y1 := laea.Forward(pt[0], pt[1]+d)
+		x2, y2 := laea.Forward(pt[0]+d, pt[1])
+		plane := math.Abs((x1-x0)*(y2-y0) - (y1-y0)*(x2-x0))
+
+		e2 := wgs84F * (2 - wgs84F)
+		s := math.Sin(pt[0] * math.Pi / 180)
+		w := 1 - e2*s*s
+		meridian := wgs84A * (1 - e2) / (w * math.Sqrt(w))
+		parallel := wgs84A / math.Sqrt(w) * math.Cos(pt[0]*math.Pi/180)
+		ground := meridian * parallel * (d * math.Pi / 180) * (d * math.Pi / 180)
+		if math.Abs(plane/ground-1) > 1e-5 {
+			t.Fatalf("laea area ratio at %v = %.7f, want 1", pt, plane/ground)
+		}
+	}
+}
+
+func TestGroundAxesFollowMeridianAndParallelScales(t *testing.T) {
+	east, north := GroundAxes(NewUTM(32, false), 45, 9)
+	if math.Abs(east[0]-0.9996) > 1e-6 || math.Abs(north[1]-0.9996) > 1e-6 || math.Abs(east[1]) > 1e-6 || math.Abs(north[0]) > 1e-6 {
+		t.Fatalf("utm axes on central meridian = %v, %v, want 0.9996 along x and y", east, north)
+	}
+
+	// Off the LAEA origin the parallel scale k and the meridian scale 1/k
+	// differ, while the cell they span keeps its area.
+	east, north = GroundAxes(NewLAEA(43.4, 34.6), 46.6, 41.7)
+	k := math.Hypot(east[0], east[1])
+	h := math.Hypot(north[0], north[1])
+	if math.Abs(k-h) < 1e-3 {
+		t.Fatalf("expected different parallel and meridian scales off the origin, got %.6f and %.6f", k, h)
+	}
+	if area := east[0]*north[1] - east[1]*north[0]; math.Abs(area-1) > 1e-5 {
+		t.Fatalf("laea ground cell area = %.7f, want 1", area)
+	}
+	if k := ScaleFactor(NewLAEA(43.4, 34.6), 46.6, 41.7); math.Abs(k-math.Hypot(east[0], east[1])) > 1e-9 {
+		t.Fatalf("east axis length %.9f differs from ScaleFactor %.9f", math.Hypot(east[0], east[1]), k)
+	}
+}
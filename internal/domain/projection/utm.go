@@ -0,0 +1,105 @@
+package projection
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500_000.0
+	utmFalseNorthing = 10_000_000.0
+)
+
+// TransverseMercator is UTM on WGS84: Krüger's series to third order in the
+// third flattening, accurate to about a millimetre within a zone and to
+// centimetres several zones away from the central meridian.
+type TransverseMercator struct {
+	Zone  int
+	South bool
+
+	lon0     float64
+	a        float64
+	e        float64
+	alpha    [3]float64
+	beta     [3]float64
+	delta    [3]float64
+	northing float64
+}
+
+// UTMZone is the standard 6° zone of a longitude, without the Norway and
+// Svalbard exceptions.
+func UTMZone(lon float64) int {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return min(int(lon/6)+1, 60)
+}
+
+func NewUTM(zone int, south bool) TransverseMercator {
+	zone = min(max(zone, 1), 60)
+	n := wgs84F / (2 - wgs84F)
+	n2, n3 := n*n, n*n*n
+	t := TransverseMercator{
+		Zone:  zone,
+		South: south,
+		lon0:  float64(zone)*6 - 183,
+		a:     wgs84A / (1 + n) * (1 + n2/4 + n2*n2/64),
+		e:     2 * math.Sqrt(n) / (1 + n),
+		alpha: [3]float64{n/2 - 2*n2/3 + 5*n3/16, 13*n2/48 - 3*n3/5, 61 * n3 / 240},
+		beta:  [3]float64{n/2 - 2*n2/3 + 37*n3/96, n2/48 + n3/15, 17 * n3 / 480},
+		delta: [3]float64{2*n - 2*n2/3 - 2*n3, 7*n2/3 - 8*n3/5, 56 * n3 / 15},
+	}
+	if south {
+		t.northing = utmFalseNorthing
+	}
+	return t
+}
+
+func (t TransverseMercator) Forward(lat, lon float64) (float64, float64) {
+	phi := lat * math.Pi / 180
+	dlon := math.Remainder(lon-t.lon0, 360) * math.Pi / 180
+
+	sinPhi := math.Sin(phi)
+	tau := math.Sinh(math.Atanh(sinPhi) - t.e*math.Atanh(t.e*sinPhi))
+	xi := math.Atan2(tau, math.Cos(dlon))
+	eta := math.Atanh(math.Sin(dlon) / math.Sqrt(1+tau*tau))
+
+	x, y := eta, xi
+	for j, alpha := range t.alpha {
+		k := 2 * float64(j+1)
+		x += alpha * math.Cos(k*xi) * math.Sinh(k*eta)
+		y += alpha * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+	return utmFalseEasting + utmScale*t.a*x, t.northing + utmScale*t.a*y
+}
+
+func (t TransverseMercator) Inverse(x, y float64) (float64, float64) {
+	xi := (y - t.northing) / (utmScale * t.a)
+	eta := (x - utmFalseEasting) / (utmScale * t.a)
+
+	xiP, etaP := xi, eta
+	for j, beta := range t.beta {
+		k := 2 * float64(j+1)
+		xiP -= beta * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaP -= beta * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	chi := math.Asin(math.Sin(xiP) / math.Cosh(etaP))
+	phi := chi
+	for j, delta := range t.delta {
+		phi += delta * math.Sin(2*float64(j+1)*chi)
+	}
+	lon := t.lon0 + math.Atan2(math.Sinh(etaP), math.Cos(xiP))*180/math.Pi
+	return phi * 180 / math.Pi, math.Remainder(lon, 360)
+}
+
+func (TransverseMercator) Name() string { return UTM }
+
+func (t TransverseMercator) String() string {
+	hemisphere := "N"
+	if t.South {
+		hemisphere = "S"
+	}
+	return fmt.Sprintf("UTM %d%s", t.Zone, hemisphere)
+}
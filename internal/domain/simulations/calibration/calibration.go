@@ -0,0 +1,473 @@
+package calibration
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/generators/fbm"
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+	"coastal-geometry/internal/domain/simulations/ensemble"
+)
+
+const (
+	// DefaultMaxPoints keeps the deepest reference curve within the triadic
+	// Koch curve at iteration 7.
+	DefaultMaxPoints = 20000
+	// DefaultRotations is the number of grid orientations per curve.
+	DefaultRotations = 4
+	// MaxRotations bounds the orientations so one run stays interactive.
+	MaxRotations = 16
+	// DefaultTolerance is the largest |bias| at which an estimator counts as
+	// converged, the theory tolerance of model dimension.
+	DefaultTolerance = 0.05
+	// SettleDelta is the largest change of the mean estimate between
+	// consecutive iterations at which an estimator counts as settled.
+	SettleDelta = 0.03
+)
+
+// DefaultHursts spans rough to smooth fBm coasts, D from 1.7 down to 1.1.
+var DefaultHursts = []float64{0.3, 0.5, 0.7, 0.9}
+
+// referenceBase is the segment every reference curve grows from, about
+// 160 km along a parallel in the Black Sea box.
+var referenceBase = []geometry.LatLon{{Lat: 44, Lon: 30}, {Lat: 44, Lon: 32}}
+
+const (
+	FamilyGenerator = "generator"
+	FamilyFBM       = "fbm"
+)
+
+type Options struct {
+	// Generators are koch.Generators names; nil means all of them.
+	Generators []string
+	// Hursts are fBm exponents in (0, 1); nil means DefaultHursts.
+	Hursts []float64
+	// Estimators are fractal.Estimators names; nil means all of them.
+	Estimators []string
+	// Rotations turns the plane in steps of 90°/Rotations before estimating,
+	// which moves the box grid relative to the curve.
+	Rotations int
+	// MaxPoints is the largest curve an iteration may produce.
+	MaxPoints int
+	Tolerance float64
+	Seed      int64
+	// Workers <= 0 means GOMAXPROCS.
+	Workers int
+	// Progress, when set, is called after every reference iteration from
+	// the worker that finished it.
+	Progress func(done, total int, reference string, iteration int)
+}
+
+// Normalize fills the defaults and rejects unknown names.
+func (o Options) Normalize() (Options, error) {
+	if o.Generators == nil {
+		o.Generators = koch.Generators
+	}
+	o.Generators = append([]string(nil), o.Generators...)
+	for i, name := range o.Generators {
+		if _, err := koch.NewGenerator(name, koch.GeneratorOptions{}); err != nil {
+			return o, err
+		}
+		o.Generators[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	if o.Hursts == nil {
+		o.Hursts = append([]float64(nil), DefaultHursts...)
+	}
+	for _, h := range o.Hursts {
+		if !(h > 0 && h < 1) {
+			return o, fmt.Errorf("hurst %g must be in (0, 1)", h)
+		}
+	}
+	if o.Estimators == nil {
+		o.Estimators = append([]string(nil), fractal.Estimators...)
+	}
+	for _, name := range o.Estimators {
+		if !slices.Contains(fractal.Estimators, name) {
+			return o, fmt.Errorf("unknown dimension estimator %q (want one of %s)", name, strings.Join(fractal.Estimators, ", "))
+		}
+	}
+	if len(o.Generators)+len(o.Hursts) == 0 {
+		return o, fmt.Errorf("calibration needs at least one generator or hurst exponent")
+	}
+	if len(o.Estimators) == 0 {
+		return o, fmt.Errorf("calibration needs at least one estimator")
+	}
+	if o.Rotations == 0 {
+		o.Rotations = DefaultRotations
+	}
+	if o.Rotations < 1 || o.Rotations > MaxRotations {
+		return o, fmt.Errorf("rotations must be between 1 and %d", MaxRotations)
+	}
+	if o.MaxPoints == 0 {
+		o.MaxPoints = DefaultMaxPoints
+	}
+	if o.MaxPoints < 16 {
+		return o, fmt.Errorf("max points must be at least 16")
+	}
+	if o.Tolerance == 0 {
+		o.Tolerance = DefaultTolerance
+	}
+	if !(o.Tolerance > 0) {
+		return o, fmt.Errorf("tolerance must be positive")
+	}
+	return o, nil
+}
+
+// Reference is a curve of known dimension, refined up to MaxIteration.
+type Reference struct {
+	Name          string
+	Family        string
+	TrueDimension float64
+	MaxIteration  int
+	curve         func(iterations int) []geometry.LatLon
+}
+
+// Curve returns the reference at the given iteration.
+func (r Reference) Curve(iterations int) []geometry.LatLon {
+	return r.curve(iterations)
+}
+
+// References builds the generators first and the fBm curves after them, in
+// option order. Every reference stops at the last iteration whose curve has
+// at most MaxPoints points.
+func References(opts Options) ([]Reference, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	proj, err := geometry.ProjectionFor(projection.Default, referenceBase)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []Reference
+	for _, name := range opts.Generators {
+		gen, err := koch.NewGenerator(name, koch.GeneratorOptions{Projection: proj})
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, Reference{
+			Name:          gen.Name(),
+			Family:        FamilyGenerator,
+			TrueDimension: gen.TheoreticalDimension(),
+			MaxIteration:  deepestIteration(gen.Segments(), gen.MaxIterations(), opts.MaxPoints),
+			curve: func(iterations int) []geometry.LatLon {
+				return gen.Apply(referenceBase, iterations)
+			},
+		})
+	}
+	for _, h := range opts.Hursts {
+		fbmOpts := fbm.Options{Seed: opts.Seed, Hurst: h, Amplitude: 1, Projection: proj}
+		refs = append(refs, Reference{
+			Name:          fmt.Sprintf("fbm-h%.2f", h),
+			Family:        FamilyFBM,
+			TrueDimension: fbm.TargetDimension(h),
+			MaxIteration:  deepestIteration(2, fbm.MaxIterations, opts.MaxPoints),
+			curve: func(iterations int) []geometry.LatLon {
+				return fbm.Curve(referenceBase, iterations, fbmOpts)
+			},
+		})
+	}
+	return refs, nil
+}
+
+// deepestIteration is the last iteration whose single-segment curve of
+// segments^n + 1 points fits in maxPoints.
+func deepestIteration(segments, limit, maxPoints int) int {
+	n, points := 0, 1
+	for n < limit && points*segments+1 <= maxPoints {
+		points *= segments
+		n++
+	}
+	return n
+}
+
+// Estimate is one estimator on one reference iteration seen at one grid
+// orientation. Error is Dimension − TrueDimension and NaN when the estimate
+// is invalid.
+type Estimate struct {
+	Reference     string
+	Family        string
+	Estimator     string
+	TrueDimension float64
+	Iteration     int
+	Points        int
+	RotationDeg   float64
+	Dimension     float64
+	RSquared      float64
+	Valid         bool
+	Error         float64
+}
+
+// Level pools the orientations of one iteration. Mean, StdDev, Bias and
+// RMSE are NaN when no orientation gave a valid estimate.
+type Level struct {
+	Iteration int
+	Points    int
+	Valid     int
+	Total     int
+	Mean      float64
+	StdDev    float64
+	Bias      float64
+	RMSE      float64
+}
+
+// Summary follows one estimator on one reference through the iterations.
+// Bias and RMSE are those of the deepest level. ConvergedAt is the first
+// iteration from which |bias| stays within the tolerance; SettledAt is the
+// first from which the mean moves by at most SettleDelta per iteration.
+// Zero means the estimator never did.
+type Summary struct {
+	Reference     string
+	Family        string
+	Estimator     string
+	TrueDimension float64
+	Levels        []Level
+	Bias          float64
+	RMSE          float64
+	ConvergedAt   int
+	SettledAt     int
+}
+
+// EstimatorSummary ranks an estimator over every reference at its deepest
+// level: MeanAbsBias and RMSE skip references without a valid estimate.
+type EstimatorSummary struct {
+	Estimator   string
+	References  int
+	Valid       int
+	MeanBias    float64
+	MeanAbsBias float64
+	RMSE        float64
+	Converged   int
+}
+
+type Result struct {
+	Options    Options
+	References []Reference
+	Estimates  []Estimate
+	Summaries  []Summary
+	Estimators []EstimatorSummary
+}
+
+// Run estimates the dimension of every reference iteration with every
+// estimator at every orientation. Estimates are ordered by reference,
+// iteration, orientation and estimator whatever the number of workers.
+func Run(opts Options) (Result, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return Result{}, err
+	}
+	refs, err := References(opts)
+	if err != nil {
+		return Result{}, err
+	}
+	base, err := geometry.ProjectionFor(projection.Default, referenceBase)
+	if err != nil {
+		return Result{}, err
+	}
+	projectors := make([]rotatedProjector, opts.Rotations)
+	for k := range projectors {
+		projectors[k] = newRotatedProjector(base, 90*float64(k)/float64(opts.Rotations))
+	}
+
+	type job struct{ ref, iteration int }
+	var jobs []job
+	for r, ref := range refs {
+		for it := 1; it <= ref.MaxIteration; it++ {
+			jobs = append(jobs, job{ref: r, iteration: it})
+		}
+	}
+
+	var done atomic.Int32
+	batches := ensemble.Run(len(jobs), opts.Workers, func(i int) []Estimate {
+		ref := refs[jobs[i].ref]
+		curve := ref.Curve(jobs[i].iteration)
+		out := make([]Estimate, 0, len(projectors)*len(opts.Estimators))
+		for _, proj := range projectors {
+			for _, name := range opts.Estimators {
+				est, _ := fractal.EstimateDimensionWith(curve, proj, name)
+				e := Estimate{
+					Reference:     ref.Name,
+					Family:        ref.Family,
+					Estimator:     name,
+					TrueDimension: ref.TrueDimension,
+					Iteration:     jobs[i].iteration,
+					Points:        len(curve),
+					RotationDeg:   proj.degrees,
+					Dimension:     est.Dimension,
+					RSquared:      est.RegressionRSquared,
+					Valid:         est.Valid,
+					Error:         math.NaN(),
+				}
+				if e.Valid {
+					e.Error = e.Dimension - e.TrueDimension
+				}
+				out = append(out, e)
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(int(done.Add(1)), len(jobs), ref.Name, jobs[i].iteration)
+		}
+		return out
+	})
+
+	result := Result{Options: opts, References: refs}
+	for _, batch := range batches {
+		result.Estimates = append(result.Estimates, batch...)
+	}
+	result.Summaries = summarize(refs, opts, result.Estimates)
+	result.Estimators = rankEstimators(opts, result.Summaries)
+	return result, nil
+}
+
+func summarize(refs []Reference, opts Options, estimates []Estimate) []Summary {
+	type key struct {
+		ref, estimator string
+		iteration      int
+	}
+	pooled := map[key][]Estimate{}
+	for _, e := range estimates {
+		k := key{e.Reference, e.Estimator, e.Iteration}
+		pooled[k] = append(pooled[k], e)
+	}
+
+	var summaries []Summary
+	for _, ref := range refs {
+		for _, name := range opts.Estimators {
+			s := Summary{
+				Reference:     ref.Name,
+				Family:        ref.Family,
+				Estimator:     name,
+				TrueDimension: ref.TrueDimension,
+				Bias:          math.NaN(),
+				RMSE:          math.NaN(),
+			}
+			for it := 1; it <= ref.MaxIteration; it++ {
+				s.Levels = append(s.Levels, pool(it, ref.TrueDimension, pooled[key{ref.Name, name, it}]))
+			}
+			if n := len(s.Levels); n > 0 {
+				s.Bias, s.RMSE = s.Levels[n-1].Bias, s.Levels[n-1].RMSE
+			}
+			s.ConvergedAt = firstFrom(s.Levels, func(i int) bool {
+				return math.Abs(s.Levels[i].Bias) <= opts.Tolerance
+			})
+			s.SettledAt = firstFrom(s.Levels, func(i int) bool {
+				return i > 0 && math.Abs(s.Levels[i].Mean-s.Levels[i-1].Mean) <= SettleDelta
+			})
+			if s.SettledAt > 0 {
+				// the run starts at the level the first small change is
+				// measured from
+				s.SettledAt--
+			}
+			summaries = append(summaries, s)
+		}
+	}
+	return summaries
+}
+
+func pool(iteration int, trueDimension float64, estimates []Estimate) Level {
+	level := Level{Iteration: iteration, Total: len(estimates), Mean: math.NaN(), StdDev: math.NaN(), Bias: math.NaN(), RMSE: math.NaN()}
+	var sum, squaredError float64
+	for _, e := range estimates {
+		level.Points = e.Points
+		if !e.Valid {
+			continue
+		}
+		level.Valid++
+		sum += e.Dimension
+		squaredError += e.Error * e.Error
+	}
+	if level.Valid == 0 {
+		return level
+	}
+	n := float64(level.Valid)
+	level.Mean = sum / n
+	level.Bias = level.Mean - trueDimension
+	level.RMSE = math.Sqrt(squaredError / n)
+	var spread float64
+	for _, e := range estimates {
+		if e.Valid {
+			spread += (e.Dimension - level.Mean) * (e.Dimension - level.Mean)
+		}
+	}
+	level.StdDev = 0
+	if level.Valid > 1 {
+		level.StdDev = math.Sqrt(spread / (n - 1))
+	}
+	return level
+}
+
+// firstFrom returns the iteration of the first level from which ok holds at
+// every deeper level, or 0. NaN comparisons are false, so an invalid
+// deepest level means no convergence.
+func firstFrom(levels []Level, ok func(i int) bool) int {
+	first := 0
+	for i := len(levels) - 1; i >= 0; i-- {
+		if !ok(i) {
+			break
+		}
+		first = levels[i].Iteration
+	}
+	return first
+}
+
+func rankEstimators(opts Options, summaries []Summary) []EstimatorSummary {
+	ranks := make([]EstimatorSummary, 0, len(opts.Estimators))
+	for _, name := range opts.Estimators {
+		rank := EstimatorSummary{Estimator: name, MeanBias: math.NaN(), MeanAbsBias: math.NaN(), RMSE: math.NaN()}
+		var bias, absBias, squared float64
+		for _, s := range summaries {
+			if s.Estimator != name {
+				continue
+			}
+			rank.References++
+			if s.ConvergedAt > 0 {
+				rank.Converged++
+			}
+			if math.IsNaN(s.Bias) {
+				continue
+			}
+			rank.Valid++
+			bias += s.Bias
+			absBias += math.Abs(s.Bias)
+			squared += s.RMSE * s.RMSE
+		}
+		if rank.Valid > 0 {
+			n := float64(rank.Valid)
+			rank.MeanBias = bias / n
+			rank.MeanAbsBias = absBias / n
+			rank.RMSE = math.Sqrt(squared / n)
+		}
+		ranks = append(ranks, rank)
+	}
+	return ranks
+}
+
+// rotatedProjector turns the plane of base by degrees around its origin.
+// Box counting anchors its grid on the bounding box of the projected curve,
+// so a turn shows the estimator another grid.
+type rotatedProjector struct {
+	projection.Projector
+	degrees  float64
+	cos, sin float64
+}
+
+func newRotatedProjector(base projection.Projector, degrees float64) rotatedProjector {
+	angle := degrees * math.Pi / 180
+	return rotatedProjector{Projector: base, degrees: degrees, cos: math.Cos(angle), sin: math.Sin(angle)}
+}
+
+func (p rotatedProjector) Forward(lat, lon float64) (float64, float64) {
+	x, y := p.Projector.Forward(lat, lon)
+	return x*p.cos - y*p.sin, x*p.sin + y*p.cos
+}
+
+func (p rotatedProjector) Inverse(x, y float64) (float64, float64) {
+	return p.Projector.Inverse(x*p.cos+y*p.sin, -x*p.sin+y*p.cos)
+}
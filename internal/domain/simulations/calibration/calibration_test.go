@@ -0,0 +1,164 @@
+package calibration
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+func TestNormalizeFillsDefaultsAndRejectsUnknownNames(t *testing.T) {
+	opts, err := Options{}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(opts.Generators) != len(koch.Generators) || len(opts.Hursts) != len(DefaultHursts) || len(opts.Estimators) != len(fractal.Estimators) {
+		t.Fatalf("expected every reference and estimator by default, got %+v", opts)
+	}
+	if opts.Rotations != DefaultRotations || opts.MaxPoints != DefaultMaxPoints || opts.Tolerance != DefaultTolerance {
+		t.Fatalf("expected default grid, size and tolerance, got %+v", opts)
+	}
+
+	bad := []Options{
+		{Generators: []string{"dragon"}},
+		{Hursts: []float64{1}},
+		{Estimators: []string{"ruler"}},
+		{Generators: []string{}, Hursts: []float64{}},
+		{Rotations: MaxRotations + 1},
+		{MaxPoints: 8},
+		{Tolerance: -0.1},
+	}
+	for _, o := range bad {
+		if _, err := o.Normalize(); err == nil {
+			t.Fatalf("expected %+v to be rejected", o)
+		}
+	}
+}
+
+func TestNormalizeLeavesCallerGeneratorsUntouched(t *testing.T) {
+	generators := []string{" Koch", "MINKOWSKI"}
+	opts, err := Options{Generators: generators}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(opts.Generators, []string{koch.GeneratorKoch, koch.GeneratorMinkowski}) {
+		t.Fatalf("expected normalised generator names, got %q", opts.Generators)
+	}
+	if !reflect.DeepEqual(generators, []string{" Koch", "MINKOWSKI"}) {
+		t.Fatalf("expected the caller's slice to stay unchanged, got %q", generators)
+	}
+}
+
+func TestReferencesStopWithinMaxPoints(t *testing.T) {
+	refs, err := References(Options{Generators: []string{"Koch", koch.GeneratorQuadratic2}, Hursts: []float64{0.5}, MaxPoints: 1100})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := []struct {
+		name      string
+		dimension float64
+		deepest   int
+	}{
+		{name: koch.GeneratorKoch, dimension: math.Log(4) / math.Log(3), deepest: 5},
+		{name: koch.GeneratorQuadratic2, dimension: 5.0 / 3.0, deepest: 2},
+		{name: "fbm-h0.50", dimension: 1.5, deepest: 10},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %d references, got %d", len(want), len(refs))
+	}
+	for i, w := range want {
+		ref := refs[i]
+		if ref.Name != w.name || math.Abs(ref.TrueDimension-w.dimension) > 1e-9 || ref.MaxIteration != w.deepest {
+			t.Fatalf("reference %d: expected %s D=%.4f up to %d, got %s D=%.4f up to %d", i, w.name, w.dimension, w.deepest, ref.Name, ref.TrueDimension, ref.MaxIteration)
+		}
+		if points := len(ref.Curve(ref.MaxIteration)); points > 1100 {
+			t.Fatalf("%s: deepest curve has %d points", ref.Name, points)
+		}
+	}
+}
+
+func TestRunMeasuresKochBiasAndConvergence(t *testing.T) {
+	opts := Options{
+		Generators: []string{koch.GeneratorKoch},
+		Hursts:     []float64{},
+		Estimators: []string{fractal.EstimatorBox},
+		Rotations:  2,
+		MaxPoints:  1100,
+		Workers:    1,
+	}
+	result, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result.Estimates) != 5*2 {
+		t.Fatalf("expected 5 iterations × 2 orientations, got %d estimates", len(result.Estimates))
+	}
+	if result.Estimates[0].RotationDeg != 0 || result.Estimates[1].RotationDeg != 45 || result.Estimates[2].Iteration != 2 {
+		t.Fatalf("expected estimates ordered by iteration then orientation, got %+v", result.Estimates[:3])
+	}
+
+	if len(result.Summaries) != 1 {
+		t.Fatalf("expected one summary, got %d", len(result.Summaries))
+	}
+	s := result.Summaries[0]
+	if math.Abs(s.Bias) > DefaultTolerance || s.RMSE < math.Abs(s.Bias) {
+		t.Fatalf("expected box counting within %.2f of log 4 / log 3 at iteration 5, got bias %.4f RMSE %.4f", DefaultTolerance, s.Bias, s.RMSE)
+	}
+	if s.ConvergedAt == 0 || s.ConvergedAt > 5 || s.Levels[0].Bias > -DefaultTolerance {
+		t.Fatalf("expected a coarse first iteration and convergence later, got %+v", s)
+	}
+	if rank := result.Estimators[0]; rank.References != 1 || rank.Valid != 1 || rank.Converged != 1 {
+		t.Fatalf("expected the estimator ranked over one reference, got %+v", rank)
+	}
+
+	opts.Workers = 3
+	parallel, err := Run(opts)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(parallel.Estimates, result.Estimates) {
+		t.Fatal("expected the same estimates whatever the number of workers")
+	}
+}
+
+func TestConvergenceNeedsEveryDeeperLevel(t *testing.T) {
+	levels := []Level{
+		{Iteration: 1, Mean: 1.0, Bias: -0.3},
+		{Iteration: 2, Mean: 1.2, Bias: -0.01},
+		{Iteration: 3, Mean: 1.1, Bias: -0.2},
+		{Iteration: 4, Mean: 1.28, Bias: -0.02},
+		{Iteration: 5, Mean: 1.3, Bias: 0},
+	}
+	within := func(i int) bool { return math.Abs(levels[i].Bias) <= DefaultTolerance }
+	if got := firstFrom(levels, within); got != 4 {
+		t.Fatalf("expected convergence from iteration 4, got %d", got)
+	}
+	levels[4].Bias = math.NaN()
+	if got := firstFrom(levels, within); got != 0 {
+		t.Fatalf("expected an invalid deepest level to rule out convergence, got %d", got)
+	}
+}
+
+func TestRotatedProjectorRoundTrips(t *testing.T) {
+	base, err := geometry.ProjectionFor(projection.Default, referenceBase)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	rotated := newRotatedProjector(base, 30)
+	x0, y0 := base.Forward(44.5, 31)
+	x, y := rotated.Forward(44.5, 31)
+	if math.Abs(math.Hypot(x, y)-math.Hypot(x0, y0)) > 1e-6 || math.Abs(x-x0) < 1 {
+		t.Fatalf("expected a turn around the origin, got (%.1f, %.1f) from (%.1f, %.1f)", x, y, x0, y0)
+	}
+	lat, lon := rotated.Inverse(x, y)
+	if math.Abs(lat-44.5) > 1e-7 || math.Abs(lon-31) > 1e-7 {
+		t.Fatalf("expected the inverse to undo the turn, got %.8f, %.8f", lat, lon)
+	}
+	if rotated.Name() != base.Name() {
+		t.Fatalf("expected the projection name %q, got %q", base.Name(), rotated.Name())
+	}
+}
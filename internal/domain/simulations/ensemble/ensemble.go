@@ -0,0 +1,139 @@
+package ensemble
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// MinMembers is the smallest ensemble that has a spread.
+const MinMembers = 2
+
+// Seeds returns n consecutive seeds starting at base; member i runs with
+// Seeds(base, n)[i], so member 0 repeats the single-seed run.
+func Seeds(base int64, n int) []int64 {
+	seeds := make([]int64, max(n, 0))
+	for i := range seeds {
+		seeds[i] = base + int64(i)
+	}
+	return seeds
+}
+
+// Run calls member for every index in [0, n) on at most workers goroutines
+// and returns the results in member order. workers <= 0 means GOMAXPROCS.
+// member must be safe to call concurrently.
+func Run[T any](n, workers int, member func(i int) T) []T {
+	results := make([]T, max(n, 0))
+	if n <= 0 {
+		return results
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = member(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// Summary describes the distribution of one quantity across the members.
+// Percentiles interpolate between order statistics; StdDev is the sample
+// standard deviation. Count is the number of finite values, and a zero
+// Count leaves every other field zero.
+type Summary struct {
+	Count  int
+	Mean   float64
+	StdDev float64
+	Min    float64
+	P5     float64
+	P25    float64
+	Median float64
+	P75    float64
+	P95    float64
+	Max    float64
+}
+
+// Summarize skips NaN and infinite values, which mark members whose
+// estimate failed.
+func Summarize(values []float64) Summary {
+	finite := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			finite = append(finite, v)
+		}
+	}
+	if len(finite) == 0 {
+		return Summary{}
+	}
+	sort.Float64s(finite)
+
+	mean := 0.0
+	for _, v := range finite {
+		mean += v
+	}
+	mean /= float64(len(finite))
+	variance := 0.0
+	for _, v := range finite {
+		variance += (v - mean) * (v - mean)
+	}
+	if len(finite) > 1 {
+		variance /= float64(len(finite) - 1)
+	}
+
+	return Summary{
+		Count:  len(finite),
+		Mean:   mean,
+		StdDev: math.Sqrt(variance),
+		Min:    finite[0],
+		P5:     percentile(finite, 0.05),
+		P25:    percentile(finite, 0.25),
+		Median: percentile(finite, 0.5),
+		P75:    percentile(finite, 0.75),
+		P95:    percentile(finite, 0.95),
+		Max:    finite[len(finite)-1],
+	}
+}
+
+// Column summarizes values[member][step] step by step; members with fewer
+// steps count as missing at the steps they lack.
+func Column(values [][]float64) []Summary {
+	steps := 0
+	for _, row := range values {
+		steps = max(steps, len(row))
+	}
+	summaries := make([]Summary, steps)
+	column := make([]float64, 0, len(values))
+	for step := range summaries {
+		column = column[:0]
+		for _, row := range values {
+			if step < len(row) {
+				column = append(column, row[step])
+			}
+		}
+		summaries[step] = Summarize(column)
+	}
+	return summaries
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	low := int(math.Floor(position))
+	high := int(math.Ceil(position))
+	return sorted[low] + (sorted[high]-sorted[low])*(position-float64(low))
+}
@@ -0,0 +1,68 @@
+package ensemble
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunKeepsMemberOrderAndBoundsWorkers(t *testing.T) {
+	var running, peak atomic.Int32
+	results := Run(40, 3, func(i int) int {
+		now := running.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		defer running.Add(-1)
+		return i * i
+	})
+
+	if len(results) != 40 {
+		t.Fatalf("expected 40 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r != i*i {
+			t.Fatalf("expected result %d at %d, got %d", i*i, i, r)
+		}
+	}
+	if peak.Load() > 3 {
+		t.Fatalf("expected at most 3 members at once, got %d", peak.Load())
+	}
+}
+
+func TestSummarizeSkipsMissingValues(t *testing.T) {
+	s := Summarize([]float64{4, math.NaN(), 1, 3, 2, 5})
+	if s.Count != 5 || s.Min != 1 || s.Max != 5 || s.Median != 3 || s.Mean != 3 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if s.P25 != 2 || s.P75 != 4 || math.Abs(s.P5-1.2) > 1e-12 || math.Abs(s.P95-4.8) > 1e-12 {
+		t.Fatalf("unexpected percentiles %+v", s)
+	}
+	if math.Abs(s.StdDev-math.Sqrt(2.5)) > 1e-12 {
+		t.Fatalf("expected sample standard deviation %.4f, got %.4f", math.Sqrt(2.5), s.StdDev)
+	}
+
+	if empty := Summarize([]float64{math.NaN()}); empty != (Summary{}) {
+		t.Fatalf("expected an empty summary, got %+v", empty)
+	}
+}
+
+func TestColumnSummarizesEveryStep(t *testing.T) {
+	summaries := Column([][]float64{{1, 10}, {3, 30}, {2}})
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(summaries))
+	}
+	if summaries[0].Count != 3 || summaries[0].Median != 2 {
+		t.Fatalf("unexpected first step %+v", summaries[0])
+	}
+	if summaries[1].Count != 2 || summaries[1].Mean != 20 {
+		t.Fatalf("unexpected second step %+v", summaries[1])
+	}
+
+	if seeds := Seeds(42, 3); len(seeds) != 3 || seeds[0] != 42 || seeds[2] != 44 {
+		t.Fatalf("unexpected seeds %v", seeds)
+	}
+}
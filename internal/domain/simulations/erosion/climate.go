@@ -0,0 +1,86 @@
+package erosion
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultWaveClimate is a coarse Black Sea storm climate: dominant north-east
+// and south-west waves with weaker westerly and northerly components.
+const DefaultWaveClimate = "0:0.10,45:0.25,90:0.10,135:0.05,180:0.05,225:0.20,270:0.15,315:0.10"
+
+// WaveDirection is one sector of the wave climate. FromDeg is the compass
+// bearing waves come from; Weight is the relative share of wave energy.
+type WaveDirection struct {
+	FromDeg float64
+	Weight  float64
+}
+
+type WaveClimate []WaveDirection
+
+// ParseWaveClimate reads a "bearing:weight,bearing:weight" list. Weights are
+// normalized to sum to one.
+func ParseWaveClimate(spec string) (WaveClimate, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("wave climate is empty")
+	}
+
+	var climate WaveClimate
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		bearingText, weightText, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("wave climate entry %q must be bearing:weight", item)
+		}
+		bearing, err := strconv.ParseFloat(strings.TrimSpace(bearingText), 64)
+		if err != nil || math.IsNaN(bearing) || math.IsInf(bearing, 0) {
+			return nil, fmt.Errorf("wave climate entry %q has invalid bearing", item)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+		if err != nil || math.IsNaN(weight) || math.IsInf(weight, 0) || weight <= 0 {
+			return nil, fmt.Errorf("wave climate entry %q must have a positive weight", item)
+		}
+		climate = append(climate, WaveDirection{FromDeg: normalizeBearing(bearing), Weight: weight})
+	}
+	if len(climate) == 0 {
+		return nil, fmt.Errorf("wave climate is empty")
+	}
+	return climate.normalized(), nil
+}
+
+func (c WaveClimate) String() string {
+	parts := make([]string, 0, len(c))
+	for _, dir := range c {
+		parts = append(parts, fmt.Sprintf("%g:%.2f", dir.FromDeg, dir.Weight))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (c WaveClimate) normalized() WaveClimate {
+	total := 0.0
+	for _, dir := range c {
+		total += dir.Weight
+	}
+	out := make(WaveClimate, 0, len(c))
+	for _, dir := range c {
+		if dir.Weight <= 0 || total <= 0 {
+			continue
+		}
+		out = append(out, WaveDirection{FromDeg: normalizeBearing(dir.FromDeg), Weight: dir.Weight / total})
+	}
+	return out
+}
+
+func normalizeBearing(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,244 @@
+package erosion
+
+import (
+	"math"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+type vec struct {
+	X, Y float64
+}
+
+func (a vec) add(b vec) vec       { return vec{a.X + b.X, a.Y + b.Y} }
+func (a vec) sub(b vec) vec       { return vec{a.X - b.X, a.Y - b.Y} }
+func (a vec) scale(k float64) vec { return vec{a.X * k, a.Y * k} }
+func (a vec) dot(b vec) float64   { return a.X*b.X + a.Y*b.Y }
+func (a vec) cross(b vec) float64 { return a.X*b.Y - a.Y*b.X }
+func (a vec) norm() float64       { return math.Hypot(a.X, a.Y) }
+
+// plane is the run's map projection rescaled to ground meters at the centre
+// of the data. It is fixed for the whole simulation so that retreat distances
+// stay comparable between steps.
+type plane struct {
+	proj  projection.Projector
+	scale float64
+}
+
+func newPlane(proj projection.Projector, sets ...[]geometry.LatLon) plane {
+	if proj == nil {
+		proj, _ = geometry.ProjectionFor(projection.Default, sets...)
+	}
+	lat, lon := center(sets...)
+	scale := projection.ScaleFactor(proj, lat, lon)
+	if !(scale > 0) {
+		scale = 1
+	}
+	return plane{proj: proj, scale: scale}
+}
+
+func center(sets ...[]geometry.LatLon) (lat, lon float64) {
+	count := 0
+	for _, points := range sets {
+		for _, p := range points {
+			lat += p.Lat
+			lon += p.Lon
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return lat / float64(count), lon / float64(count)
+}
+
+func (p plane) forward(points []geometry.LatLon) []vec {
+	out := make([]vec, len(points))
+	for i, pt := range points {
+		x, y := p.proj.Forward(pt.Lat, pt.Lon)
+		out[i] = vec{X: x / p.scale, Y: y / p.scale}
+	}
+	return out
+}
+
+func (p plane) inverse(v vec) geometry.LatLon {
+	lat, lon := p.proj.Inverse(v.X*p.scale, v.Y*p.scale)
+	return geometry.LatLon{Lat: lat, Lon: lon}
+}
+
+type segment struct {
+	a, b vec
+	// owner is the shore vertex index the segment starts at, or -1 for
+	// obstacle segments that never belong to the eroding ring.
+	owner int
+}
+
+// segmentGrid is a uniform bucket index over shore and obstacle segments so
+// that a ray only tests the segments of the cells it crosses.
+type segmentGrid struct {
+	minX, minY float64
+	cellSize   float64
+	cols, rows int
+	cells      [][]int32
+	segments   []segment
+}
+
+func newSegmentGrid(segments []segment) *segmentGrid {
+	grid := &segmentGrid{segments: segments}
+	if len(segments) == 0 {
+		return grid
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, s := range segments {
+		minX = math.Min(minX, math.Min(s.a.X, s.b.X))
+		minY = math.Min(minY, math.Min(s.a.Y, s.b.Y))
+		maxX = math.Max(maxX, math.Max(s.a.X, s.b.X))
+		maxY = math.Max(maxY, math.Max(s.a.Y, s.b.Y))
+	}
+
+	width := math.Max(maxX-minX, 1)
+	height := math.Max(maxY-minY, 1)
+	// Aim for a few segments per cell on average.
+	cellSize := math.Sqrt(width * height / math.Max(float64(len(segments))/2, 1))
+	cellSize = math.Max(cellSize, math.Max(width, height)/2048)
+
+	grid.minX, grid.minY = minX, minY
+	grid.cellSize = cellSize
+	grid.cols = int(width/cellSize) + 1
+	grid.rows = int(height/cellSize) + 1
+	grid.cells = make([][]int32, grid.cols*grid.rows)
+
+	for i, s := range segments {
+		c0, r0 := grid.cellOf(vec{math.Min(s.a.X, s.b.X), math.Min(s.a.Y, s.b.Y)})
+		c1, r1 := grid.cellOf(vec{math.Max(s.a.X, s.b.X), math.Max(s.a.Y, s.b.Y)})
+		for r := r0; r <= r1; r++ {
+			for c := c0; c <= c1; c++ {
+				idx := r*grid.cols + c
+				grid.cells[idx] = append(grid.cells[idx], int32(i))
+			}
+		}
+	}
+	return grid
+}
+
+func (g *segmentGrid) cellOf(p vec) (int, int) {
+	c := int((p.X - g.minX) / g.cellSize)
+	r := int((p.Y - g.minY) / g.cellSize)
+	return clampInt(c, 0, g.cols-1), clampInt(r, 0, g.rows-1)
+}
+
+// castRay returns the distance from origin along dir (unit vector) to the
+// nearest segment, skipping the segments owned by skipA and skipB. The second
+// result is false when the ray leaves the indexed area without a hit.
+func (g *segmentGrid) castRay(origin, dir vec, skipA, skipB int) (float64, bool) {
+	if len(g.segments) == 0 {
+		return 0, false
+	}
+
+	col, row := g.cellOf(origin)
+	stepC, stepR := 1, 1
+	nextX := g.minX + float64(col+1)*g.cellSize
+	nextY := g.minY + float64(row+1)*g.cellSize
+	if dir.X < 0 {
+		stepC = -1
+		nextX = g.minX + float64(col)*g.cellSize
+	}
+	if dir.Y < 0 {
+		stepR = -1
+		nextY = g.minY + float64(row)*g.cellSize
+	}
+
+	tMaxX, tDeltaX := math.Inf(1), math.Inf(1)
+	if math.Abs(dir.X) > 1e-12 {
+		tMaxX = (nextX - origin.X) / dir.X
+		tDeltaX = g.cellSize / math.Abs(dir.X)
+	}
+	tMaxY, tDeltaY := math.Inf(1), math.Inf(1)
+	if math.Abs(dir.Y) > 1e-12 {
+		tMaxY = (nextY - origin.Y) / dir.Y
+		tDeltaY = g.cellSize / math.Abs(dir.Y)
+	}
+
+	minHit := g.cellSize * 1e-6
+	best := math.Inf(1)
+	for col >= 0 && col < g.cols && row >= 0 && row < g.rows {
+		for _, idx := range g.cells[row*g.cols+col] {
+			s := g.segments[idx]
+			if s.owner >= 0 && (s.owner == skipA || s.owner == skipB) {
+				continue
+			}
+			if t, ok := raySegment(origin, dir, s.a, s.b); ok && t > minHit && t < best {
+				best = t
+			}
+		}
+
+		cellExit := math.Min(tMaxX, tMaxY)
+		if best <= cellExit {
+			return best, true
+		}
+		if tMaxX < tMaxY {
+			col += stepC
+			tMaxX += tDeltaX
+		} else {
+			row += stepR
+			tMaxY += tDeltaY
+		}
+	}
+
+	if math.IsInf(best, 1) {
+		return 0, false
+	}
+	return best, true
+}
+
+func raySegment(origin, dir, a, b vec) (float64, bool) {
+	edge := b.sub(a)
+	denom := dir.cross(edge)
+	if math.Abs(denom) < 1e-12 {
+		return 0, false
+	}
+	diff := a.sub(origin)
+	t := diff.cross(edge) / denom
+	u := diff.cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// inSeaWedge reports whether dir points into the sea between the two edges
+// meeting at a vertex. toNext and toPrev point from the vertex to its
+// neighbours; the sea lies counter-clockwise from toNext when ccw is true.
+func inSeaWedge(dir, toNext, toPrev vec, ccw bool) bool {
+	from, to := toNext, toPrev
+	if !ccw {
+		from, to = toPrev, toNext
+	}
+	return ccwAngle(from, dir) < ccwAngle(from, to)
+}
+
+func ccwAngle(from, to vec) float64 {
+	angle := math.Atan2(from.cross(to), from.dot(to))
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
+func bearingVector(deg float64) vec {
+	rad := deg * math.Pi / 180
+	return vec{X: math.Sin(rad), Y: math.Cos(rad)}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,248 @@
+package erosion
+
+import (
+	"math"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+const (
+	// DefaultSedimentRateM3 is the longshore transport per step of waves
+	// breaking at 45° on a fully exposed shore, m³.
+	DefaultSedimentRateM3 = 50_000
+	// DefaultClosureDepthM is the height of the active beach profile that
+	// turns a volume change of a cell into a shoreline shift.
+	DefaultClosureDepthM = 8
+
+	// stabilityFactor caps the flux through a cell boundary at
+	// stabilityFactor·D·L² so that the explicit one-line scheme stays stable
+	// on short segments.
+	stabilityFactor = 0.25
+	shiftEpsilonM   = 1e-9
+)
+
+// SedimentOptions configures longshore transport. RateM3 is the transport of
+// waves at 45° to a fully exposed shore; ClosureDepthM converts volumes into
+// shoreline shifts. An empty climate uses DefaultWaveClimate.
+type SedimentOptions struct {
+	RateM3        float64
+	ClosureDepthM float64
+	Climate       WaveClimate
+	// Projection is used by TransportSediment; SimulateWave passes its own.
+	Projection projection.Projector
+}
+
+// SedimentBudget is the volume balance of one transport step. Released is the
+// material the erosion step removed from the shore; Eroded adds the cells
+// drift scoured on top of it. Net = Deposited − Eroded equals −Exported: the
+// only way volume leaves the system is through the ends of an open polyline.
+type SedimentBudget struct {
+	ReleasedM3       float64
+	ErodedM3         float64
+	DepositedM3      float64
+	ExportedM3       float64
+	NetM3            float64
+	GrossDriftM3     float64
+	NetDriftM3       float64
+	AccretingPoints  int
+	RetreatingPoints int
+}
+
+// TransportSediment redistributes the material eroded between before and
+// after along the shore and returns the corrected after state.
+//
+// Every vertex owns a cell of half of its adjacent segments. Longshore drift
+// through the boundary between two cells follows the CERC form
+// Q ∝ Σ w_d·sin 2α_d, where α_d is the angle between the waves of direction d
+// and the shore normal; its divergence scours or feeds cells. Material the
+// erosion step released from a cell (landward shift × cell length × closure
+// depth) is carried into the downdrift neighbour. Volume changes become
+// seaward or landward shifts along the normal, so the shore can accrete as
+// well as retreat. Vertex order and the sea side follow SimulateWave.
+func TransportSediment(before, after []geometry.LatLon, opts SedimentOptions) ([]geometry.LatLon, SedimentBudget) {
+	return transportSediment(before, after, newPlane(opts.Projection, before), nil, opts)
+}
+
+func transportSediment(before, after []geometry.LatLon, proj plane, exposure []float64, opts SedimentOptions) ([]geometry.LatLon, SedimentBudget) {
+	out := append([]geometry.LatLon(nil), after...)
+	if len(before) != len(after) || opts.RateM3 < 0 {
+		return out, SedimentBudget{}
+	}
+	closed := len(before) > 1 && before[0] == before[len(before)-1]
+	ring := before
+	if closed {
+		ring = before[:len(before)-1]
+	}
+	n := len(ring)
+	if n < 3 {
+		return out, SedimentBudget{}
+	}
+
+	depth := opts.ClosureDepthM
+	if depth <= 0 {
+		depth = DefaultClosureDepthM
+	}
+	climate := opts.Climate.normalized()
+	if len(climate) == 0 {
+		climate, _ = ParseWaveClimate(DefaultWaveClimate)
+	}
+
+	xy := proj.forward(ring)
+	moved := proj.forward(after[:n])
+	ccw := signedArea(xy) > 0
+	span := max(1, n/shapeScale)
+
+	index := func(i int) int {
+		if closed {
+			return (i%n + n) % n
+		}
+		return clampInt(i, 0, n-1)
+	}
+	seaward := func(tangent vec) vec {
+		if tangent.norm() == 0 {
+			return vec{}
+		}
+		tangent = tangent.scale(1 / tangent.norm())
+		normal := vec{X: -tangent.Y, Y: tangent.X}
+		if !ccw {
+			normal = normal.scale(-1)
+		}
+		return normal
+	}
+
+	cellLen := make([]float64, n)
+	normals := make([]vec, n)
+	for i := range xy {
+		if closed || i+1 < n {
+			half := xy[index(i+1)].sub(xy[i]).norm() / 2
+			cellLen[i] += half
+			cellLen[index(i+1)] += half
+		}
+		normals[i] = seaward(xy[index(i+span)].sub(xy[index(i-span)]))
+	}
+
+	// flux[b] is the drift from cell b to cell b+1, positive along the vertex
+	// order. An open polyline has no boundary after its last cell.
+	boundaries := n
+	if !closed {
+		boundaries = n - 1
+	}
+	directions := make([]vec, len(climate))
+	for d, dir := range climate {
+		directions[d] = bearingVector(dir.FromDeg)
+	}
+	flux := make([]float64, boundaries)
+	budget := SedimentBudget{}
+	for b := range flux {
+		next := index(b + 1)
+		tangent := xy[index(b+span)].sub(xy[index(b+1-span)])
+		if tangent.norm() == 0 {
+			continue
+		}
+		tangent = tangent.scale(1 / tangent.norm())
+		normal := seaward(tangent)
+
+		drive := 0.0
+		for d, dir := range directions {
+			incidence := dir.dot(normal)
+			if incidence <= 0 {
+				continue
+			}
+			// Waves arrive from dir and travel along −dir, so drift runs
+			// against the tangential component of dir.
+			drive -= climate[d].Weight * 2 * incidence * dir.dot(tangent)
+		}
+		if len(exposure) == n {
+			drive *= (exposure[b] + exposure[next]) / 2
+		}
+
+		limit := stabilityFactor * depth * math.Pow(math.Min(cellLen[b], cellLen[next]), 2)
+		flux[b] = math.Max(-limit, math.Min(limit, opts.RateM3*drive))
+		budget.GrossDriftM3 += math.Abs(flux[b])
+		budget.NetDriftM3 += flux[b]
+	}
+
+	inflow := func(i int) float64 {
+		if closed {
+			return flux[index(i-1)]
+		}
+		if i == 0 {
+			// Only outflow through the start: nothing feeds the polyline
+			// from beyond its ends.
+			if boundaries > 0 {
+				return math.Min(flux[0], 0)
+			}
+			return 0
+		}
+		return flux[i-1]
+	}
+	outflow := func(i int) float64 {
+		if closed || i < n-1 {
+			return flux[i]
+		}
+		return math.Max(flux[boundaries-1], 0)
+	}
+	if !closed {
+		budget.ExportedM3 += outflow(n-1) - inflow(0)
+	}
+
+	cross := make([]float64, n)
+	change := make([]float64, n)
+	for i := range xy {
+		change[i] += inflow(i) - outflow(i)
+
+		cross[i] = moved[i].sub(xy[i]).dot(normals[i])
+		if cross[i] >= 0 || cellLen[i] == 0 {
+			continue
+		}
+		released := -cross[i] * cellLen[i] * depth
+		budget.ReleasedM3 += released
+
+		target := i
+		switch local := inflow(i) + outflow(i); {
+		case local > 0:
+			target = i + 1
+		case local < 0:
+			target = i - 1
+		}
+		if !closed && (target < 0 || target >= n) {
+			budget.ExportedM3 += released
+			continue
+		}
+		target = index(target)
+		if cellLen[target] == 0 {
+			target = i
+		}
+		change[target] += released
+	}
+
+	budget.ErodedM3 = budget.ReleasedM3
+	for i := range xy {
+		if change[i] > 0 {
+			budget.DepositedM3 += change[i]
+		} else {
+			budget.ErodedM3 -= change[i]
+		}
+
+		shift := 0.0
+		if cellLen[i] > 0 {
+			shift = change[i] / (cellLen[i] * depth)
+		}
+		if shift != 0 {
+			out[i] = proj.inverse(moved[i].add(normals[i].scale(shift)))
+		}
+
+		switch total := cross[i] + shift; {
+		case total > shiftEpsilonM:
+			budget.AccretingPoints++
+		case total < -shiftEpsilonM:
+			budget.RetreatingPoints++
+		}
+	}
+	if closed {
+		out[n] = out[0]
+	}
+	budget.NetM3 = budget.DepositedM3 - budget.ErodedM3
+	return out, budget
+}
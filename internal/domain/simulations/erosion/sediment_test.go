@@ -0,0 +1,110 @@
+package erosion
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+// shiftNorth moves p by meters northwards in the plane TransportSediment
+// builds for points.
+func shiftNorth(points []geometry.LatLon, p geometry.LatLon, meters float64) geometry.LatLon {
+	proj := newPlane(nil, points)
+	return proj.inverse(proj.forward([]geometry.LatLon{p})[0].add(vec{Y: meters}))
+}
+
+// straightBeach returns an open west-east polyline along the equator; with
+// collinear vertices the sea lies south of it.
+func straightBeach(count int, stepDeg float64) []geometry.LatLon {
+	points := make([]geometry.LatLon, count)
+	for i := range points {
+		points[i] = geometry.LatLon{Lat: 0, Lon: float64(i) * stepDeg}
+	}
+	return points
+}
+
+func TestTransportSedimentConservesVolumeOnClosedSea(t *testing.T) {
+	climate, err := ParseWaveClimate("20:0.5,200:0.3,290:0.2")
+	if err != nil {
+		t.Fatalf("unexpected climate error: %v", err)
+	}
+	result := SimulateWave(squareSea(1, 25), WaveOptions{
+		Steps:     3,
+		StrengthM: 200,
+		Seed:      11,
+		Climate:   climate,
+		Jitter:    DefaultJitter,
+		Sediment:  &SedimentOptions{RateM3: DefaultSedimentRateM3},
+	})
+
+	if len(result.Sediment) != 3 {
+		t.Fatalf("expected a budget per step, got %d", len(result.Sediment))
+	}
+	for i, budget := range result.Sediment {
+		if budget.ReleasedM3 <= 0 || budget.DepositedM3 <= 0 || budget.GrossDriftM3 <= 0 {
+			t.Fatalf("step %d: expected eroded material to be moved, got %+v", i+1, budget)
+		}
+		if budget.ExportedM3 != 0 {
+			t.Fatalf("step %d: a closed sea cannot export sediment, got %+v", i+1, budget)
+		}
+		if math.Abs(budget.NetM3) > 1e-6*budget.ErodedM3 {
+			t.Fatalf("step %d: expected zero net budget, got %+v", i+1, budget)
+		}
+		if budget.AccretingPoints == 0 {
+			t.Fatalf("step %d: expected some of the shore to accrete, got %+v", i+1, budget)
+		}
+	}
+}
+
+func TestTransportSedimentBalancesExportOnOpenPolyline(t *testing.T) {
+	before := straightBeach(21, 0.01)
+	after := append([]geometry.LatLon(nil), before...)
+	for i := 5; i < 15; i++ {
+		// Shift part of the beach 20 m north, i.e. landward.
+		after[i] = shiftNorth(before, before[i], 20)
+	}
+
+	// Waves from the south-west drive the drift east, out of the last cell.
+	_, budget := TransportSediment(before, after, SedimentOptions{
+		RateM3:  DefaultSedimentRateM3,
+		Climate: WaveClimate{{FromDeg: 225, Weight: 1}},
+	})
+	if budget.ExportedM3 <= 0 {
+		t.Fatalf("expected drift to leave through the downdrift end, got %+v", budget)
+	}
+	if got := budget.DepositedM3 + budget.ExportedM3; math.Abs(got-budget.ErodedM3) > 1e-6*budget.ErodedM3 {
+		t.Fatalf("expected eroded = deposited + exported, got %+v", budget)
+	}
+	if math.Abs(budget.NetM3+budget.ExportedM3) > 1e-6*budget.ErodedM3 {
+		t.Fatalf("expected net budget to equal the export, got %+v", budget)
+	}
+}
+
+func TestTransportSedimentDepositsDowndrift(t *testing.T) {
+	before := straightBeach(11, 0.01)
+	after := append([]geometry.LatLon(nil), before...)
+	after[5] = shiftNorth(before, before[5], 10)
+
+	out, budget := TransportSediment(before, after, SedimentOptions{
+		RateM3:  DefaultSedimentRateM3,
+		Climate: WaveClimate{{FromDeg: 225, Weight: 1}},
+	})
+
+	proj := newPlane(nil, before)
+	xy := proj.forward(before)
+	cell := xy[1].sub(xy[0]).norm()
+	released := 10 * cell * DefaultClosureDepthM
+	if math.Abs(budget.ReleasedM3-released) > 1e-6*released {
+		t.Fatalf("expected %.1f m³ released, got %+v", released, budget)
+	}
+	// Uniform drift along a straight beach neither scours nor feeds interior
+	// cells, so the released volume lands in the eastern neighbour.
+	shift := xy[6].Y - proj.forward(out)[6].Y
+	if math.Abs(shift-10) > 1e-6 {
+		t.Fatalf("expected the downdrift vertex to advance 10 m seaward, got %.6f m", shift)
+	}
+	if geometry.Haversine(out[4], before[4]) > 1e-9 || geometry.Haversine(out[5], after[5]) > 1e-9 {
+		t.Fatalf("expected updrift vertices to keep their position, got %+v and %+v", out[4], out[5])
+	}
+}
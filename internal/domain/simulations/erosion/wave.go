@@ -0,0 +1,376 @@
+package erosion
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+const (
+	// DefaultJitter is the relative standard deviation of the per-vertex
+	// retreat noise.
+	DefaultJitter = 0.2
+
+	waveChunkSize    = 512
+	maxRetreatFactor = 3
+	// shapeScale sets the neighbourhood used for normals, headland/bay
+	// classification and exposure smoothing as a fraction of the ring size.
+	shapeScale       = 1000
+	exposedThreshold = 0.5
+)
+
+// WaveOptions configures the fetch-driven erosion model. StrengthM is the
+// retreat in meters of the most exposed vertex per step; MaxFetchKM caps the
+// fetch of a single ray (0 keeps the full distance across the sea).
+type WaveOptions struct {
+	Steps      int
+	StrengthM  float64
+	Seed       int64
+	Climate    WaveClimate
+	Obstacles  [][]geometry.LatLon
+	Jitter     float64
+	MaxFetchKM float64
+	// Resistance holds per-vertex rock resistance; retreat is divided by it.
+	// Missing or non-positive values count as 1.
+	Resistance []float64
+	// StepStrengthM overrides StrengthM step by step, e.g. with the forcing of
+	// a time-calibrated scenario.
+	StepStrengthM []float64
+	// MeanRetreat makes the strength the mean retreat of the shore rather
+	// than the retreat of the most exposed vertex.
+	MeanRetreat bool
+	// Sediment enables longshore transport of the eroded material after
+	// every step; its drift is scaled by the exposure of the step.
+	Sediment *SedimentOptions
+	// Projection is the plane fetch and retreat are computed in; nil means
+	// the default projection centred on the shore and obstacles.
+	Projection projection.Projector
+}
+
+// WaveStepStats describes the exposure field that drove one erosion step.
+type WaveStepStats struct {
+	Step             int
+	MeanFetchKM      float64
+	MaxFetchKM       float64
+	MeanExposure     float64
+	P90Exposure      float64
+	ExposedShare     float64
+	MeanRetreatM     float64
+	MaxRetreatM      float64
+	HeadlandRetreatM float64
+	BayRetreatM      float64
+	Headlands        int
+	Bays             int
+}
+
+// WaveResult holds snapshots including the initial state at index 0 and the
+// stats of every step, so Steps[i] produced Snapshots[i+1]. Sediment is
+// filled only when WaveOptions.Sediment is set and follows Steps.
+type WaveResult struct {
+	Snapshots [][]geometry.LatLon
+	Steps     []WaveStepStats
+	Sediment  []SedimentBudget
+}
+
+// SimulateWave erodes the shore treating the polyline as the outline of a
+// closed sea: an open polyline is closed by the chord between its ends and
+// obstacles (islands, other rings) block waves but are not eroded.
+//
+// Every step casts a ray from each vertex towards every wave direction of the
+// climate; the ray length until it meets the shore is the fetch. Exposure is
+// the climate-weighted fetch times the cosine between the wave direction and
+// the seaward normal, normalized to the most exposed vertex. Vertices retreat
+// landward proportionally to exposure with seeded multiplicative noise, so
+// open headlands retreat faster than sheltered bays and a fixed seed always
+// gives the same result.
+func SimulateWave(points []geometry.LatLon, opts WaveOptions) WaveResult {
+	steps := max(opts.Steps, 0)
+	climate := opts.Climate.normalized()
+	if len(climate) == 0 {
+		climate, _ = ParseWaveClimate(DefaultWaveClimate)
+	}
+
+	proj := newPlane(opts.Projection, append([][]geometry.LatLon{points}, opts.Obstacles...)...)
+	obstacles := obstacleSegments(proj, opts.Obstacles)
+
+	result := WaveResult{
+		Snapshots: make([][]geometry.LatLon, steps+1),
+		Steps:     make([]WaveStepStats, 0, steps),
+	}
+	current := append([]geometry.LatLon(nil), points...)
+	result.Snapshots[0] = current
+
+	var sediment SedimentOptions
+	if opts.Sediment != nil {
+		sediment = *opts.Sediment
+		if len(sediment.Climate) == 0 {
+			sediment.Climate = climate
+		}
+	}
+
+	for step := 1; step <= steps; step++ {
+		stepOpts := opts
+		if step <= len(opts.StepStrengthM) {
+			stepOpts.StrengthM = opts.StepStrengthM[step-1]
+		}
+		next, stats, exposure := waveStep(current, proj, obstacles, climate, stepOpts, step)
+		stats.Step = step
+		if opts.Sediment != nil {
+			var budget SedimentBudget
+			next, budget = transportSediment(current, next, proj, exposure, sediment)
+			result.Sediment = append(result.Sediment, budget)
+		}
+		result.Snapshots[step] = next
+		result.Steps = append(result.Steps, stats)
+		current = next
+	}
+	return result
+}
+
+type vertexExposure struct {
+	energy         float64
+	weightedFetchM float64
+	maxFetchM      float64
+	normal         vec
+	headland       bool
+	bay            bool
+}
+
+func waveStep(points []geometry.LatLon, proj plane, obstacles []segment, climate WaveClimate, opts WaveOptions, step int) ([]geometry.LatLon, WaveStepStats, []float64) {
+	closed := len(points) > 1 && points[0] == points[len(points)-1]
+	ring := points
+	if closed {
+		ring = points[:len(points)-1]
+	}
+	if len(ring) < 3 {
+		return append([]geometry.LatLon(nil), points...), WaveStepStats{}, nil
+	}
+
+	xy := proj.forward(ring)
+	ccw := signedArea(xy) > 0
+
+	segments := make([]segment, 0, len(xy)+len(obstacles))
+	for i := range xy {
+		segments = append(segments, segment{a: xy[i], b: xy[(i+1)%len(xy)], owner: i})
+	}
+	segments = append(segments, obstacles...)
+	grid := newSegmentGrid(segments)
+
+	span := max(1, len(xy)/shapeScale)
+	exposures := computeExposures(xy, ccw, span, grid, climate, opts.MaxFetchKM*1000)
+
+	energies := make([]float64, len(xy))
+	for i, e := range exposures {
+		energies[i] = e.energy
+	}
+	for pass := 0; pass < span; pass++ {
+		energies = smoothCyclic(energies)
+	}
+	maxEnergy := 0.0
+	for _, e := range energies {
+		maxEnergy = math.Max(maxEnergy, e)
+	}
+
+	n := len(xy)
+	exposure := make([]float64, n)
+	retreat := make([]float64, n)
+	moved := make([]geometry.LatLon, n, len(points))
+	meanExposure := 0.0
+	for i := range xy {
+		if maxEnergy > 0 {
+			exposure[i] = energies[i] / maxEnergy
+		}
+		meanExposure += exposure[i] / float64(n)
+	}
+	level := opts.StrengthM
+	if opts.MeanRetreat && meanExposure > 0 {
+		level /= meanExposure
+	}
+	for i := range xy {
+		if level > 0 {
+			rng := geometry.NewVertexNoise(opts.Seed, step, i)
+			noise := 1 + opts.Jitter*rng.NormFloat64()
+			retreat[i] = math.Min(math.Max(level*exposure[i]*noise, 0), maxRetreatFactor*level)
+		}
+	}
+	// Neighbouring vertices share the noise so that it varies the retreat
+	// along the shore instead of roughening the line vertex by vertex.
+	for pass := 0; pass < span; pass++ {
+		retreat = smoothCyclic(retreat)
+	}
+	for i := range retreat {
+		if i < len(opts.Resistance) && opts.Resistance[i] > 0 {
+			retreat[i] /= opts.Resistance[i]
+		}
+	}
+	for i := range xy {
+		// Sheltered vertices keep their exact coordinates instead of taking
+		// a round trip through the projection.
+		moved[i] = ring[i]
+		if retreat[i] != 0 {
+			moved[i] = proj.inverse(xy[i].sub(exposures[i].normal.scale(retreat[i])))
+		}
+	}
+	if closed {
+		moved = append(moved, moved[0])
+	}
+
+	return moved, summarizeStep(exposures, exposure, retreat), exposure
+}
+
+func computeExposures(xy []vec, ccw bool, span int, grid *segmentGrid, climate WaveClimate, maxFetchM float64) []vertexExposure {
+	n := len(xy)
+	out := make([]vertexExposure, n)
+	directions := make([]vec, len(climate))
+	for i, dir := range climate {
+		directions[i] = bearingVector(dir.FromDeg)
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += waveChunkSize {
+		end := min(start+waveChunkSize, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				out[i] = vertexExposureAt(xy, i, ccw, span, grid, climate, directions, maxFetchM)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return out
+}
+
+func vertexExposureAt(xy []vec, i int, ccw bool, span int, grid *segmentGrid, climate WaveClimate, directions []vec, maxFetchM float64) vertexExposure {
+	n := len(xy)
+	prev := (i - 1 + n) % n
+	next := (i + 1) % n
+	toPrev := xy[prev].sub(xy[i])
+	toNext := xy[next].sub(xy[i])
+	// The normal and the headland/bay turn are taken over span vertices so
+	// that digitizing noise does not flip them from vertex to vertex.
+	farPrev := xy[(i-span%n+n)%n].sub(xy[i])
+	farNext := xy[(i+span)%n].sub(xy[i])
+	tangent := farNext.sub(farPrev)
+	if toPrev.norm() == 0 || toNext.norm() == 0 || tangent.norm() == 0 {
+		return vertexExposure{}
+	}
+
+	tangent = tangent.scale(1 / tangent.norm())
+	normal := vec{X: -tangent.Y, Y: tangent.X}
+	if !ccw {
+		normal = normal.scale(-1)
+	}
+
+	e := vertexExposure{normal: normal}
+	for d, dir := range directions {
+		incidence := dir.dot(normal)
+		if incidence <= 0 || !inSeaWedge(dir, toNext, toPrev, ccw) {
+			continue
+		}
+		fetch, ok := grid.castRay(xy[i], dir, prev, i)
+		if !ok {
+			continue
+		}
+		if maxFetchM > 0 {
+			fetch = math.Min(fetch, maxFetchM)
+		}
+		e.energy += climate[d].Weight * fetch * incidence
+		e.weightedFetchM += climate[d].Weight * fetch
+		e.maxFetchM = math.Max(e.maxFetchM, fetch)
+	}
+
+	// A vertex where the sea wraps around the land is a headland; one where
+	// the land wraps around the sea is a bay.
+	turn := farPrev.cross(farNext)
+	if ccw {
+		turn = -turn
+	}
+	e.headland = turn < 0
+	e.bay = turn > 0
+	return e
+}
+
+func summarizeStep(exposures []vertexExposure, exposure, retreat []float64) WaveStepStats {
+	n := len(exposures)
+	stats := WaveStepStats{}
+	if n == 0 {
+		return stats
+	}
+
+	headlandSum, baySum := 0.0, 0.0
+	exposed := 0
+	for i, e := range exposures {
+		stats.MeanFetchKM += e.weightedFetchM / 1000
+		stats.MaxFetchKM = math.Max(stats.MaxFetchKM, e.maxFetchM/1000)
+		stats.MeanExposure += exposure[i]
+		stats.MeanRetreatM += retreat[i]
+		stats.MaxRetreatM = math.Max(stats.MaxRetreatM, retreat[i])
+		if exposure[i] >= exposedThreshold {
+			exposed++
+		}
+		if e.headland {
+			stats.Headlands++
+			headlandSum += retreat[i]
+		}
+		if e.bay {
+			stats.Bays++
+			baySum += retreat[i]
+		}
+	}
+
+	stats.MeanFetchKM /= float64(n)
+	stats.MeanExposure /= float64(n)
+	stats.MeanRetreatM /= float64(n)
+	stats.ExposedShare = float64(exposed) / float64(n)
+	if stats.Headlands > 0 {
+		stats.HeadlandRetreatM = headlandSum / float64(stats.Headlands)
+	}
+	if stats.Bays > 0 {
+		stats.BayRetreatM = baySum / float64(stats.Bays)
+	}
+
+	sorted := append([]float64(nil), exposure...)
+	sort.Float64s(sorted)
+	stats.P90Exposure = sorted[int(math.Ceil(0.9*float64(n)))-1]
+	return stats
+}
+
+func obstacleSegments(proj plane, rings [][]geometry.LatLon) []segment {
+	var segments []segment
+	for _, ring := range rings {
+		xy := proj.forward(ring)
+		for i := 1; i < len(xy); i++ {
+			if xy[i] == xy[i-1] {
+				continue
+			}
+			segments = append(segments, segment{a: xy[i-1], b: xy[i], owner: -1})
+		}
+	}
+	return segments
+}
+
+func signedArea(xy []vec) float64 {
+	area := 0.0
+	for i := range xy {
+		area += xy[i].cross(xy[(i+1)%len(xy)])
+	}
+	return area / 2
+}
+
+// smoothCyclic applies one 1-2-1 pass so that a single noisy vertex does not
+// turn into a spike that keeps attracting erosion.
+func smoothCyclic(values []float64) []float64 {
+	n := len(values)
+	if n < 3 {
+		return values
+	}
+	out := make([]float64, n)
+	for i := range values {
+		out[i] = (values[(i-1+n)%n] + 2*values[i] + values[(i+1)%n]) / 4
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package erosion
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+// squareSea returns a closed counter-clockwise sea outline of size deg×deg
+// with every side split into segments.
+func squareSea(size float64, perSide int) []geometry.LatLon {
+	corners := []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: size}, {Lat: size, Lon: size}, {Lat: size, Lon: 0}}
+	var points []geometry.LatLon
+	for c := range corners {
+		a, b := corners[c], corners[(c+1)%len(corners)]
+		for i := 0; i < perSide; i++ {
+			t := float64(i) / float64(perSide)
+			points = append(points, geometry.LatLon{Lat: a.Lat + (b.Lat-a.Lat)*t, Lon: a.Lon + (b.Lon-a.Lon)*t})
+		}
+	}
+	return append(points, points[0])
+}
+
+func TestSimulateWaveFetchSpansClosedSea(t *testing.T) {
+	sea := squareSea(1, 20)
+	result := SimulateWave(sea, WaveOptions{
+		Steps:     1,
+		StrengthM: 100,
+		Seed:      7,
+		Climate:   WaveClimate{{FromDeg: 0, Weight: 1}},
+	})
+
+	width := geometry.Haversine(geometry.LatLon{Lat: 0, Lon: 0.5}, geometry.LatLon{Lat: 1, Lon: 0.5})
+	if got := result.Steps[0].MaxFetchKM; math.Abs(got-width)/width > 0.01 {
+		t.Fatalf("expected max fetch close to %.1f km, got %.1f km", width, got)
+	}
+
+	// The northern shore faces south and is sheltered from northern waves.
+	before, after := result.Snapshots[0], result.Snapshots[1]
+	for i, p := range before {
+		if p.Lat == 1 && after[i] != p {
+			t.Fatalf("sheltered vertex %d moved from %+v to %+v", i, p, after[i])
+		}
+	}
+	// The southern shore retreats landward, i.e. southwards.
+	if after[10].Lat >= before[10].Lat {
+		t.Fatalf("expected exposed vertex to retreat south, got %+v -> %+v", before[10], after[10])
+	}
+}
+
+func TestSimulateWaveRetreatsHeadlandFasterThanBay(t *testing.T) {
+	// A square sea with a headland pushing north from the southern shore and
+	// a narrow bay cutting north into the land from the northern shore.
+	sea := []geometry.LatLon{
+		{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.45}, {Lat: 0.4, Lon: 0.5}, {Lat: 0, Lon: 0.55},
+		{Lat: 0, Lon: 1}, {Lat: 1, Lon: 1}, {Lat: 1, Lon: 0.52}, {Lat: 1.4, Lon: 0.51},
+		{Lat: 1.4, Lon: 0.49}, {Lat: 1, Lon: 0.48}, {Lat: 1, Lon: 0}, {Lat: 0, Lon: 0},
+	}
+	climate, err := ParseWaveClimate("0:1,45:1,90:1,135:1,180:1,225:1,270:1,315:1")
+	if err != nil {
+		t.Fatalf("unexpected climate error: %v", err)
+	}
+
+	result := SimulateWave(sea, WaveOptions{Steps: 1, StrengthM: 100, Seed: 1, Climate: climate})
+	headland := geometry.Haversine(result.Snapshots[0][2], result.Snapshots[1][2])
+	bayHead := geometry.Haversine(result.Snapshots[0][7], result.Snapshots[1][7])
+	if headland <= bayHead {
+		t.Fatalf("expected headland retreat %.4f km to exceed bay head retreat %.4f km", headland, bayHead)
+	}
+	if stats := result.Steps[0]; stats.Headlands == 0 || stats.Bays == 0 {
+		t.Fatalf("expected both headland and bay vertices, got %+v", stats)
+	}
+}
+
+func TestSimulateWaveIsDeterministicForSeed(t *testing.T) {
+	sea := squareSea(1, 30)
+	opts := WaveOptions{Steps: 3, StrengthM: 200, Seed: 42, Jitter: DefaultJitter}
+
+	first := SimulateWave(sea, opts)
+	second := SimulateWave(sea, opts)
+	for step := range first.Snapshots {
+		for i := range first.Snapshots[step] {
+			if first.Snapshots[step][i] != second.Snapshots[step][i] {
+				t.Fatalf("step %d point %d differs between runs with the same seed", step, i)
+			}
+		}
+	}
+
+	opts.Seed = 43
+	other := SimulateWave(sea, opts)
+	if other.Snapshots[3][5] == first.Snapshots[3][5] {
+		t.Fatalf("expected a different seed to change the retreat noise")
+	}
+}
+
+func TestParseWaveClimateRejectsBadEntries(t *testing.T) {
+	climate, err := ParseWaveClimate("45:3, 405:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(climate) != 2 || climate[0].Weight != 0.75 || climate[1].FromDeg != 45 {
+		t.Fatalf("unexpected climate %+v", climate)
+	}
+
+	for _, spec := range []string{"", "45", "x:1", "45:0", "45:-1"} {
+		if _, err := ParseWaveClimate(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestSimulateWaveDividesRetreatByResistance(t *testing.T) {
+	sea := squareSea(1, 20)
+	resistance := make([]float64, len(sea)-1)
+	for i := range resistance {
+		resistance[i] = 1
+	}
+	resistance[10] = 4
+
+	opts := WaveOptions{Steps: 1, StrengthM: 100, Seed: 7, Climate: WaveClimate{{FromDeg: 0, Weight: 1}}}
+	plain := SimulateWave(sea, opts)
+	opts.Resistance = resistance
+	weighted := SimulateWave(sea, opts)
+
+	plainShift := geometry.Haversine(sea[10], plain.Snapshots[1][10])
+	weightedShift := geometry.Haversine(sea[10], weighted.Snapshots[1][10])
+	if math.Abs(weightedShift*4-plainShift) > 1e-6 {
+		t.Fatalf("expected resistance 4 to quarter the retreat, got %.6f vs %.6f km", weightedShift, plainShift)
+	}
+	if weighted.Snapshots[1][9] != plain.Snapshots[1][9] {
+		t.Fatal("expected vertices with resistance 1 to keep their retreat")
+	}
+}
+
+func TestSimulateWaveMeanRetreatFollowsStepStrength(t *testing.T) {
+	result := SimulateWave(squareSea(1, 20), WaveOptions{
+		Steps:         2,
+		StrengthM:     500,
+		StepStrengthM: []float64{0, 40},
+		MeanRetreat:   true,
+		Seed:          3,
+		Climate:       WaveClimate{{FromDeg: 0, Weight: 1}, {FromDeg: 90, Weight: 1}},
+	})
+
+	if got := result.Steps[0].MeanRetreatM; got != 0 {
+		t.Fatalf("expected a zero-strength step to keep the shore, got mean retreat %.3f m", got)
+	}
+	if got := result.Steps[1].MeanRetreatM; math.Abs(got-40) > 1e-6 {
+		t.Fatalf("expected mean retreat of 40 m, got %.6f m", got)
+	}
+}
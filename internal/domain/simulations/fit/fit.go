@@ -0,0 +1,500 @@
+package fit
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"coastal-geometry/internal/domain/simulations/ensemble"
+)
+
+const (
+	// MethodGrid evaluates a coarse grid with half of the budget and refines
+	// its best cells by Nelder–Mead.
+	MethodGrid = "grid"
+	// MethodNelderMead starts Nelder–Mead from the middle of the bounds.
+	MethodNelderMead = "nelder-mead"
+
+	DefaultEvaluations = 60
+	DefaultSeeds       = 3
+	// DefaultSinuosityWindows cuts the Black Sea ring into arcs of about
+	// 200 km.
+	DefaultSinuosityWindows = 32
+	// MinEvaluations leaves a four-parameter search at least its first
+	// simplex.
+	MinEvaluations = 5
+
+	// refineStarts is the number of best grid cells MethodGrid refines.
+	refineStarts = 3
+	// simplexTolerance stops Nelder–Mead once every vertex is this close to
+	// the best one in the unit cube of the bounds.
+	simplexTolerance = 5e-3
+	// maxCallsPerEvaluation bounds the steps spent on cached points, which
+	// integer parameters revisit.
+	maxCallsPerEvaluation = 10
+)
+
+var Methods = []string{MethodGrid, MethodNelderMead}
+
+// Parameter is one model parameter searched within [Min, Max]; Min == Max
+// fixes it. Integer parameters are rounded before the model runs.
+type Parameter struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Integer bool
+}
+
+// Free reports whether the search moves the parameter at all.
+func (p Parameter) Free() bool { return p.Max > p.Min }
+
+func (p Parameter) value(u float64) float64 {
+	v := p.Min + u*(p.Max-p.Min)
+	if p.Integer {
+		v = math.Round(v)
+	}
+	return v
+}
+
+// ParseParameter reads bounds as a single value or min:max.
+func ParseParameter(name, spec string, integer bool) (Parameter, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) > 2 {
+		return Parameter{}, fmt.Errorf("%s %q must be a value or min:max", name, spec)
+	}
+	var bounds []float64
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return Parameter{}, fmt.Errorf("%s %q: %q is not a number", name, spec, part)
+		}
+		if integer && value != math.Trunc(value) {
+			return Parameter{}, fmt.Errorf("%s %q: %q is not a whole number", name, spec, part)
+		}
+		bounds = append(bounds, value)
+	}
+	p := Parameter{Name: name, Min: bounds[0], Max: bounds[len(bounds)-1], Integer: integer}
+	if p.Max < p.Min {
+		return Parameter{}, fmt.Errorf("%s %q must have min <= max", name, spec)
+	}
+	return p, nil
+}
+
+type Options struct {
+	Parameters []Parameter
+	Method     string
+	// Evaluations is the number of model runs per seed.
+	Evaluations int
+	// Seeds fits the model independently for Seeds consecutive seeds from
+	// Seed; the spread of their best parameters is the uncertainty.
+	Seeds   int
+	Seed    int64
+	Weights Weights
+	// Workers <= 0 means GOMAXPROCS.
+	Workers int
+	// Progress, when set, is called from the worker of a seed whenever the
+	// seed finds a better fit.
+	Progress func(best Evaluation)
+}
+
+// Normalize fills the zero values with the defaults and rejects the rest.
+func (o Options) Normalize() (Options, error) {
+	if len(o.Parameters) == 0 {
+		return o, fmt.Errorf("fit needs at least one parameter")
+	}
+	if o.Method == "" {
+		o.Method = MethodGrid
+	}
+	if !slices.Contains(Methods, o.Method) {
+		return o, fmt.Errorf("method must be one of %s", strings.Join(Methods, ", "))
+	}
+	if o.Evaluations == 0 {
+		o.Evaluations = DefaultEvaluations
+	}
+	if o.Evaluations < MinEvaluations {
+		return o, fmt.Errorf("evaluations must be at least %d", MinEvaluations)
+	}
+	if o.Seeds == 0 {
+		o.Seeds = DefaultSeeds
+	}
+	if o.Seeds < 1 {
+		return o, fmt.Errorf("seeds must be positive")
+	}
+	if o.Weights == (Weights{}) {
+		o.Weights = DefaultWeights
+	}
+	if o.Weights.Dimension < 0 || o.Weights.Richardson < 0 || o.Weights.Sinuosity < 0 {
+		return o, fmt.Errorf("weights must be non-negative")
+	}
+	return o, nil
+}
+
+// Evaluation is one model run. Index counts the runs of its seed from 1.
+type Evaluation struct {
+	Seed      int64
+	Index     int
+	Values    []float64
+	Signature Signature
+	Misfit    Misfit
+}
+
+// SeedFit is the search of one seed, its runs in the order they were made.
+type SeedFit struct {
+	Seed        int64
+	Best        Evaluation
+	Evaluations []Evaluation
+}
+
+// Estimate is the fitted value of one parameter: Best from the best fit of
+// every seed, Spread over the best values of the seeds.
+type Estimate struct {
+	Parameter Parameter
+	Best      float64
+	Spread    ensemble.Summary
+}
+
+type Result struct {
+	Options   Options
+	Target    Signature
+	Fits      []SeedFit
+	Best      Evaluation
+	Estimates []Estimate
+}
+
+// Model runs the model for a seed and parameter values in Parameters order
+// and measures it.
+type Model func(seed int64, values []float64) Signature
+
+// Run fits the model to the target for every seed. Seeds run in parallel;
+// the search of a seed is sequential, so the result does not depend on the
+// number of workers.
+func Run(opts Options, target Signature, model Model) (Result, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := checkTarget(target, opts.Weights); err != nil {
+		return Result{}, err
+	}
+
+	seeds := ensemble.Seeds(opts.Seed, opts.Seeds)
+	fits := ensemble.Run(len(seeds), opts.Workers, func(i int) SeedFit {
+		s := newSearch(opts, seeds[i], func(values []float64) (Signature, Misfit) {
+			sig := model(seeds[i], values)
+			return sig, Compare(sig, target, opts.Weights)
+		})
+		s.run()
+		return SeedFit{Seed: seeds[i], Best: s.evaluations[s.best], Evaluations: s.evaluations}
+	})
+
+	result := Result{Options: opts, Target: target, Fits: fits, Best: fits[0].Best}
+	for _, f := range fits[1:] {
+		if f.Best.Misfit.Total < result.Best.Misfit.Total {
+			result.Best = f.Best
+		}
+	}
+	if math.IsInf(result.Best.Misfit.Total, 1) {
+		return Result{}, fmt.Errorf("no model run could be compared with the target: every run has an invalid weighted term")
+	}
+	for j, p := range opts.Parameters {
+		values := make([]float64, len(fits))
+		for i, f := range fits {
+			values[i] = f.Best.Values[j]
+		}
+		result.Estimates = append(result.Estimates, Estimate{Parameter: p, Best: result.Best.Values[j], Spread: ensemble.Summarize(values)})
+	}
+	return result, nil
+}
+
+func checkTarget(target Signature, w Weights) error {
+	switch {
+	case w.Dimension > 0 && math.IsNaN(target.Dimension):
+		return fmt.Errorf("target box-counting dimension is invalid; set its weight to 0")
+	case w.Richardson > 0 && len(target.Richardson) < MinCommonRulers:
+		return fmt.Errorf("target Richardson curve has %d rulers, at least %d are needed; set its weight to 0", len(target.Richardson), MinCommonRulers)
+	case w.Sinuosity > 0 && math.IsNaN(target.Sinuosity):
+		return fmt.Errorf("target sinuosity is invalid; set its weight to 0")
+	}
+	return nil
+}
+
+// search minimises the misfit over the unit cube of the free parameters.
+type search struct {
+	params      []Parameter
+	free        []int
+	method      string
+	budget      int
+	limit       int
+	calls       int
+	seed        int64
+	progress    func(Evaluation)
+	measure     func(values []float64) (Signature, Misfit)
+	cache       map[string]int
+	evaluations []Evaluation
+	best        int
+	bestU       []float64
+}
+
+func newSearch(opts Options, seed int64, measure func(values []float64) (Signature, Misfit)) *search {
+	s := &search{
+		params:   opts.Parameters,
+		seed:     seed,
+		method:   opts.Method,
+		budget:   opts.Evaluations,
+		progress: opts.Progress,
+		measure:  measure,
+		cache:    map[string]int{},
+	}
+	for i, p := range opts.Parameters {
+		if p.Free() {
+			s.free = append(s.free, i)
+		}
+	}
+	return s
+}
+
+func (s *search) run() {
+	k := len(s.free)
+	if k == 0 {
+		s.limit = 1
+		s.objective(nil)
+		return
+	}
+
+	center := make([]float64, k)
+	for i := range center {
+		center[i] = 0.5
+	}
+	starts, step := [][]float64{center}, 0.25
+	if s.method == MethodGrid {
+		m := gridPointsPerAxis(k, s.budget/2)
+		type cell struct {
+			u     []float64
+			value float64
+		}
+		var cells []cell
+		s.limit = s.budget
+		for _, c := range gridCells(k, m) {
+			u := make([]float64, k)
+			for i, index := range c {
+				u[i] = (float64(index) + 0.5) / float64(m)
+			}
+			value, ok := s.objective(u)
+			if !ok {
+				return
+			}
+			cells = append(cells, cell{u: u, value: value})
+		}
+		slices.SortStableFunc(cells, func(a, b cell) int { return compareMisfit(a.value, b.value) })
+		starts, step = nil, 0.5/float64(m)
+		for _, c := range cells[:min(refineStarts, len(cells))] {
+			starts = append(starts, c.u)
+		}
+	}
+
+	// every start gets an equal share of what is left of the budget
+	for i, start := range starts {
+		s.limit = len(s.evaluations) + (s.budget-len(s.evaluations))/(len(starts)-i)
+		s.refine(start, step)
+	}
+}
+
+// refine runs Nelder–Mead from start. A collapsed simplex may sit on a
+// plateau of an integer parameter, so it restarts around the best point
+// while restarts improve it.
+func (s *search) refine(start []float64, step float64) {
+	bestU := slices.Clone(start)
+	for restart := false; ; restart = true {
+		previous := s.best
+		if !s.nelderMead(bestU, step) || (restart && s.best == previous) {
+			return
+		}
+		bestU, step = slices.Clone(s.bestU), 0.25
+	}
+}
+
+// gridPointsPerAxis is the largest m with m^k <= budget, at least 2.
+func gridPointsPerAxis(k, budget int) int {
+	m := 2
+	for math.Pow(float64(m+1), float64(k)) <= float64(budget) {
+		m++
+	}
+	return m
+}
+
+// gridCells lists every cell of an m^k grid; the last axis varies fastest.
+func gridCells(k, m int) [][]int {
+	cells := [][]int{{}}
+	for range k {
+		var next [][]int
+		for _, cell := range cells {
+			for c := range m {
+				next = append(next, append(slices.Clone(cell), c))
+			}
+		}
+		cells = next
+	}
+	return cells
+}
+
+// objective returns the misfit at u and false once the budget is spent.
+// Points that round to values already run reuse that run.
+func (s *search) objective(u []float64) (float64, bool) {
+	if s.calls >= s.budget*maxCallsPerEvaluation {
+		return math.Inf(1), false
+	}
+	s.calls++
+
+	values := make([]float64, len(s.params))
+	for i, p := range s.params {
+		values[i] = p.Min
+	}
+	for i, j := range s.free {
+		values[j] = s.params[j].value(u[i])
+	}
+	key := fmt.Sprint(values)
+	if i, ok := s.cache[key]; ok {
+		return s.evaluations[i].Misfit.Total, true
+	}
+	if len(s.evaluations) >= s.limit {
+		return math.Inf(1), false
+	}
+
+	sig, misfit := s.measure(values)
+	e := Evaluation{Seed: s.seed, Index: len(s.evaluations) + 1, Values: values, Signature: sig, Misfit: misfit}
+	s.evaluations = append(s.evaluations, e)
+	s.cache[key] = len(s.evaluations) - 1
+	if len(s.evaluations) == 1 || misfit.Total < s.evaluations[s.best].Misfit.Total {
+		s.best = len(s.evaluations) - 1
+		s.bestU = slices.Clone(u)
+		if s.progress != nil {
+			s.progress(e)
+		}
+	}
+	return misfit.Total, true
+}
+
+// nelderMead runs the downhill simplex in the unit cube from start with an
+// initial edge of step; points are clamped to the cube.
+func (s *search) nelderMead(start []float64, step float64) bool {
+	k := len(start)
+	simplex := make([][]float64, k+1)
+	values := make([]float64, k+1)
+	simplex[0] = start
+	for i := range k {
+		v := slices.Clone(start)
+		if v[i]+step <= 1 {
+			v[i] += step
+		} else {
+			v[i] -= step
+		}
+		simplex[i+1] = v
+	}
+	for i, v := range simplex {
+		f, ok := s.objective(v)
+		if !ok {
+			return false
+		}
+		values[i] = f
+	}
+
+	// along returns c + t·(w − c): t = −1 reflects, −2 expands, ±0.5 contracts
+	along := func(c, w []float64, t float64) []float64 {
+		out := make([]float64, k)
+		for i := range out {
+			out[i] = math.Max(0, math.Min(1, c[i]+t*(w[i]-c[i])))
+		}
+		return out
+	}
+
+	for {
+		order := make([]int, k+1)
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return compareMisfit(values[a], values[b]) })
+		sorted, sortedValues := make([][]float64, k+1), make([]float64, k+1)
+		for i, j := range order {
+			sorted[i], sortedValues[i] = simplex[j], values[j]
+		}
+		simplex, values = sorted, sortedValues
+
+		if simplexSize(simplex) < simplexTolerance {
+			return true
+		}
+		centroid := make([]float64, k)
+		for _, v := range simplex[:k] {
+			for i := range centroid {
+				centroid[i] += v[i] / float64(k)
+			}
+		}
+		worst := simplex[k]
+
+		reflected := along(centroid, worst, -1)
+		fr, ok := s.objective(reflected)
+		if !ok {
+			return false
+		}
+		switch {
+		case fr < values[0]:
+			expanded := along(centroid, worst, -2)
+			fe, ok := s.objective(expanded)
+			if !ok {
+				return false
+			}
+			if fe < fr {
+				simplex[k], values[k] = expanded, fe
+			} else {
+				simplex[k], values[k] = reflected, fr
+			}
+		case fr < values[k-1]:
+			simplex[k], values[k] = reflected, fr
+		default:
+			t := 0.5
+			if fr < values[k] {
+				t = -0.5
+			}
+			contracted := along(centroid, worst, t)
+			fc, ok := s.objective(contracted)
+			if !ok {
+				return false
+			}
+			if fc < math.Min(fr, values[k]) {
+				simplex[k], values[k] = contracted, fc
+				continue
+			}
+			for i := 1; i <= k; i++ {
+				simplex[i] = along(simplex[0], simplex[i], 0.5)
+				if values[i], ok = s.objective(simplex[i]); !ok {
+					return false
+				}
+			}
+		}
+	}
+}
+
+func compareMisfit(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// simplexSize is the largest distance of a vertex from the first one.
+func simplexSize(simplex [][]float64) float64 {
+	var size float64
+	for _, v := range simplex[1:] {
+		var d float64
+		for i := range v {
+			d = math.Max(d, math.Abs(v[i]-simplex[0][i]))
+		}
+		size = math.Max(size, d)
+	}
+	return size
+}
@@ -0,0 +1,128 @@
+package fit
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// syntheticModel has a signature that pins every parameter: the Richardson
+// slope gives angle, its level iterations, the sinuosity height. Seeds
+// nudge D, so the seeds disagree slightly.
+func syntheticModel(seed int64, values []float64) Signature {
+	iterations, angle, height := values[0], values[1], values[2]
+	sig := Signature{
+		Dimension: 1 + 0.05*iterations + 0.004*angle + 0.0005*float64(seed-42),
+		Sinuosity: 1 + height + 0.01*iterations,
+	}
+	for _, factor := range []float64{4, 8, 16, 32, 64} {
+		sig.Richardson = append(sig.Richardson, RulerLength{
+			ScaleFactor: factor,
+			LengthKM:    100 * math.Exp(0.1*iterations) * math.Pow(factor, 0.01*angle),
+		})
+	}
+	return sig
+}
+
+func syntheticParameters() []Parameter {
+	return []Parameter{
+		{Name: "iterations", Min: 1, Max: 5, Integer: true},
+		{Name: "angle-jitter", Min: 0, Max: 30},
+		{Name: "height-jitter", Min: 0, Max: 0.5},
+	}
+}
+
+func TestRunRecoversSyntheticParameters(t *testing.T) {
+	target := syntheticModel(42, []float64{3, 12, 0.2})
+	for _, method := range Methods {
+		result, err := Run(Options{Parameters: syntheticParameters(), Method: method, Evaluations: 120, Seeds: 2, Seed: 42, Workers: 1}, target, syntheticModel)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", method, err)
+		}
+		best := result.Best.Values
+		if best[0] != 3 || math.Abs(best[1]-12) > 1 || math.Abs(best[2]-0.2) > 0.02 {
+			t.Fatalf("%s: expected iterations 3, angle 12, height 0.2, got %v (misfit %.4f)", method, best, result.Best.Misfit.Total)
+		}
+		for _, f := range result.Fits {
+			if len(f.Evaluations) > 120 || f.Evaluations[f.Best.Index-1].Misfit.Total != f.Best.Misfit.Total {
+				t.Fatalf("%s: seed %d: expected at most 120 runs and Best among them, got %d", method, f.Seed, len(f.Evaluations))
+			}
+		}
+		if e := result.Estimates[0]; e.Parameter.Name != "iterations" || e.Spread.Count != 2 || e.Best != 3 {
+			t.Fatalf("%s: expected an estimate per parameter over both seeds, got %+v", method, e)
+		}
+	}
+}
+
+func TestRunIsIndependentOfWorkersAndSkipsFixedParameters(t *testing.T) {
+	params := syntheticParameters()
+	params[0] = Parameter{Name: "iterations", Min: 3, Max: 3, Integer: true}
+	opts := Options{Parameters: params, Evaluations: 40, Seeds: 3, Seed: 7, Workers: 1}
+	target := syntheticModel(7, []float64{3, 20, 0.1})
+
+	serial, err := Run(opts, target, syntheticModel)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	opts.Workers = 3
+	parallel, err := Run(opts, target, syntheticModel)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(serial.Fits, parallel.Fits) {
+		t.Fatal("expected the same fits whatever the number of workers")
+	}
+	for _, f := range serial.Fits {
+		for _, e := range f.Evaluations {
+			if e.Values[0] != 3 {
+				t.Fatalf("expected the fixed parameter to stay at 3, got %v", e.Values)
+			}
+		}
+	}
+	if s := serial.Estimates[0].Spread; s.StdDev != 0 || s.Mean != 3 {
+		t.Fatalf("expected no spread of a fixed parameter, got %+v", s)
+	}
+}
+
+func TestRunRejectsUnmeasurableTarget(t *testing.T) {
+	target := syntheticModel(42, []float64{3, 12, 0.2})
+	target.Dimension = math.NaN()
+	if _, err := Run(Options{Parameters: syntheticParameters()}, target, syntheticModel); err == nil {
+		t.Fatal("expected an invalid target dimension to be rejected")
+	}
+	if _, err := Run(Options{Parameters: syntheticParameters(), Weights: Weights{Richardson: 1, Sinuosity: 1}, Evaluations: 10, Seeds: 1}, target, syntheticModel); err != nil {
+		t.Fatalf("expected the dimension to be left out at weight 0, got %v", err)
+	}
+}
+
+func TestParseParameterReadsValueOrBounds(t *testing.T) {
+	p, err := ParseParameter("angle-jitter", "5:25", false)
+	if err != nil || p.Min != 5 || p.Max != 25 || !p.Free() {
+		t.Fatalf("expected bounds 5..25, got %+v (%v)", p, err)
+	}
+	p, err = ParseParameter("iterations", "3", true)
+	if err != nil || p.Free() || p.Min != 3 {
+		t.Fatalf("expected a fixed 3, got %+v (%v)", p, err)
+	}
+	for _, spec := range []string{"", "1:2:3", "5:1", "x"} {
+		if _, err := ParseParameter("angle-jitter", spec, false); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+	if _, err := ParseParameter("iterations", "1:2.5", true); err == nil {
+		t.Fatal("expected a fractional bound of an integer parameter to be rejected")
+	}
+}
+
+func TestGridCellsCoverEveryCombination(t *testing.T) {
+	cells := gridCells(2, 3)
+	if len(cells) != 9 || !reflect.DeepEqual(cells[1], []int{0, 1}) || !reflect.DeepEqual(cells[8], []int{2, 2}) {
+		t.Fatalf("expected 9 cells with the last axis fastest, got %v", cells)
+	}
+	if m := gridPointsPerAxis(3, 30); m != 3 {
+		t.Fatalf("expected 3 points per axis within 30 runs, got %d", m)
+	}
+	if m := gridPointsPerAxis(4, 10); m != 2 {
+		t.Fatalf("expected at least 2 points per axis, got %d", m)
+	}
+}
@@ -0,0 +1,168 @@
+package fit
+
+import (
+	"math"
+	"sort"
+
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+// MinCommonRulers is the fewest divider lengths two Richardson curves must
+// share to be compared.
+const MinCommonRulers = 3
+
+// Signature holds the shape statistics a model is fitted to.
+type Signature struct {
+	Points   int
+	LengthKM float64
+	// Dimension is the box-counting D, NaN when the estimate is invalid.
+	Dimension float64
+	// Richardson is the divider length at every ruler the walk reached;
+	// RichardsonDimension is NaN when the fit is invalid.
+	Richardson          []RulerLength
+	RichardsonDimension float64
+	// Sinuosity is NaN for a line shorter than two points.
+	Sinuosity float64
+}
+
+// RulerLength is one point of the Richardson curve. ScaleFactor is the
+// extent of the line divided by the ruler, so lines of similar extent share
+// scale factors.
+type RulerLength struct {
+	ScaleFactor float64
+	RulerKM     float64
+	LengthKM    float64
+}
+
+// Measure computes the signature of a line; windows is the number of arcs
+// of the sinuosity.
+func Measure(points []geometry.LatLon, proj projection.Projector, windows int) Signature {
+	sig := Signature{
+		Points:              len(points),
+		LengthKM:            geometry.PolylineLength(points),
+		Dimension:           math.NaN(),
+		RichardsonDimension: math.NaN(),
+		Sinuosity:           Sinuosity(points, windows),
+	}
+	if box := fractal.AnalyzeBoxCountingWith(points, proj); box.Valid {
+		sig.Dimension = box.Dimension
+	}
+	richardson := fractal.AnalyzeRichardson(points)
+	if richardson.Valid {
+		sig.RichardsonDimension = richardson.Dimension
+	}
+	for _, s := range richardson.Samples {
+		sig.Richardson = append(sig.Richardson, RulerLength{ScaleFactor: s.ScaleFactor, RulerKM: s.RulerKM, LengthKM: s.LengthKM})
+	}
+	return sig
+}
+
+// Sinuosity cuts the line into windows arcs of equal length and averages
+// arc length over chord. Windows of a given count cover about the same
+// stretch of coast on a line and on a more detailed model of it, whatever
+// their lengths, so the value compares the two at one scale.
+func Sinuosity(points []geometry.LatLon, windows int) float64 {
+	if len(points) < 2 || windows < 1 {
+		return math.NaN()
+	}
+	cumulative := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		cumulative[i] = cumulative[i-1] + geometry.Haversine(points[i-1], points[i])
+	}
+	total := cumulative[len(cumulative)-1]
+	if total <= 0 {
+		return math.NaN()
+	}
+
+	arc := total / float64(windows)
+	at := func(s float64) geometry.LatLon {
+		i := max(1, min(len(points)-1, sort.SearchFloat64s(cumulative, s)))
+		span := cumulative[i] - cumulative[i-1]
+		if span <= 0 {
+			return points[i]
+		}
+		t := (s - cumulative[i-1]) / span
+		a, b := points[i-1], points[i]
+		return geometry.LatLon{Lat: a.Lat + (b.Lat-a.Lat)*t, Lon: a.Lon + (b.Lon-a.Lon)*t}
+	}
+
+	var sum float64
+	var counted int
+	start := points[0]
+	for k := 1; k <= windows; k++ {
+		end := at(arc * float64(k))
+		// a closed ring seen as one window has no chord
+		if chord := geometry.Haversine(start, end); chord > 0 {
+			sum += arc / chord
+			counted++
+		}
+		start = end
+	}
+	if counted == 0 {
+		return math.NaN()
+	}
+	return sum / float64(counted)
+}
+
+// Weights scale the terms of the misfit; a zero weight leaves a term out.
+type Weights struct {
+	Dimension  float64
+	Richardson float64
+	Sinuosity  float64
+}
+
+var DefaultWeights = Weights{Dimension: 1, Richardson: 1, Sinuosity: 1}
+
+// Misfit is the distance of a model from the target. Dimension is |ΔD|,
+// Richardson the RMS of log(L_model/L_target) over the shared rulers and
+// Sinuosity |log(S_model/S_target)|; a term is NaN when it cannot be
+// measured. Total is the weighted sum and +Inf when a weighted term is NaN.
+type Misfit struct {
+	Dimension  float64
+	Richardson float64
+	Rulers     int
+	Sinuosity  float64
+	Total      float64
+}
+
+func Compare(model, target Signature, w Weights) Misfit {
+	m := Misfit{
+		Dimension:  math.Abs(model.Dimension - target.Dimension),
+		Richardson: math.NaN(),
+		Sinuosity:  math.Abs(math.Log(model.Sinuosity / target.Sinuosity)),
+	}
+
+	lengths := make(map[float64]float64, len(target.Richardson))
+	for _, r := range target.Richardson {
+		lengths[r.ScaleFactor] = r.LengthKM
+	}
+	var squared float64
+	for _, r := range model.Richardson {
+		if length, ok := lengths[r.ScaleFactor]; ok && length > 0 && r.LengthKM > 0 {
+			d := math.Log(r.LengthKM / length)
+			squared += d * d
+			m.Rulers++
+		}
+	}
+	if m.Rulers >= MinCommonRulers {
+		m.Richardson = math.Sqrt(squared / float64(m.Rulers))
+	}
+
+	for _, term := range []struct{ weight, value float64 }{
+		{w.Dimension, m.Dimension},
+		{w.Richardson, m.Richardson},
+		{w.Sinuosity, m.Sinuosity},
+	} {
+		if term.weight <= 0 {
+			continue
+		}
+		if math.IsNaN(term.value) {
+			m.Total = math.Inf(1)
+			return m
+		}
+		m.Total += term.weight * term.value
+	}
+	return m
+}
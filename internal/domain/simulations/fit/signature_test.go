@@ -0,0 +1,70 @@
+package fit
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestSinuosityOfStraightAndZigzagLines(t *testing.T) {
+	straight := []geometry.LatLon{{Lat: 44, Lon: 30}, {Lat: 44.5, Lon: 30}, {Lat: 45, Lon: 30}}
+	if s := Sinuosity(straight, 4); math.Abs(s-1) > 1e-6 {
+		t.Fatalf("expected a straight line to have sinuosity 1, got %.6f", s)
+	}
+
+	// teeth of 45° along a parallel: every tooth is √2 times its base
+	var zigzag []geometry.LatLon
+	for i := 0; i <= 16; i++ {
+		lat := 44.0
+		if i%2 == 1 {
+			lat += 0.05
+		}
+		zigzag = append(zigzag, geometry.LatLon{Lat: lat, Lon: 30 + 0.05*float64(i)/math.Cos(44*math.Pi/180)})
+	}
+	if s := Sinuosity(zigzag, 4); math.Abs(s-math.Sqrt2) > 0.01 {
+		t.Fatalf("expected the zigzag to have sinuosity √2, got %.4f", s)
+	}
+
+	if s := Sinuosity(straight[:1], 4); !math.IsNaN(s) {
+		t.Fatalf("expected NaN for a single point, got %.4f", s)
+	}
+}
+
+func TestCompareWeighsSharedRulersOnly(t *testing.T) {
+	target := Signature{
+		Dimension: 1.1,
+		Sinuosity: 1.2,
+		Richardson: []RulerLength{
+			{ScaleFactor: 4, LengthKM: 100}, {ScaleFactor: 8, LengthKM: 120},
+			{ScaleFactor: 16, LengthKM: 140}, {ScaleFactor: 32, LengthKM: 160},
+		},
+	}
+	if m := Compare(target, target, DefaultWeights); m.Total != 0 || m.Rulers != 4 {
+		t.Fatalf("expected a zero misfit against itself, got %+v", m)
+	}
+
+	model := Signature{
+		Dimension: 1.2,
+		Sinuosity: 1.2 * math.E,
+		Richardson: []RulerLength{
+			{ScaleFactor: 8, LengthKM: 120 * math.E}, {ScaleFactor: 16, LengthKM: 140 * math.E},
+			{ScaleFactor: 32, LengthKM: 160 * math.E}, {ScaleFactor: 64, LengthKM: 500},
+		},
+	}
+	m := Compare(model, target, Weights{Dimension: 2, Richardson: 1, Sinuosity: 0.5})
+	if m.Rulers != 3 || math.Abs(m.Richardson-1) > 1e-9 || math.Abs(m.Sinuosity-1) > 1e-9 || math.Abs(m.Dimension-0.1) > 1e-9 {
+		t.Fatalf("expected terms 0.1, 1 over 3 rulers and 1, got %+v", m)
+	}
+	if math.Abs(m.Total-(2*0.1+1+0.5)) > 1e-9 {
+		t.Fatalf("expected the weighted sum 1.7, got %.6f", m.Total)
+	}
+
+	model.Dimension = math.NaN()
+	if m := Compare(model, target, DefaultWeights); !math.IsInf(m.Total, 1) {
+		t.Fatalf("expected an invalid weighted term to give +Inf, got %.4f", m.Total)
+	}
+	if m := Compare(model, target, Weights{Richardson: 1}); math.IsInf(m.Total, 1) {
+		t.Fatalf("expected an unweighted invalid term to be ignored, got %+v", m)
+	}
+}
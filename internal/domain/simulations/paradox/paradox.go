@@ -1,52 +1,67 @@
 package paradox
 
 import (
-	"fmt"
-	"strings"
 	"time"
 
 	"coastal-geometry/internal/domain/generators/koch"
 	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
 )
 
-func Demonstrate(base []geometry.LatLon, maxIterations int, erosionStrength float64, seed int64) {
-	fmt.Println("\n" + strings.Repeat("=", 80))
-	fmt.Println("\tПАРАДОКС БЕРЕГОВОЙ ЛИНИИ")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("Демонстрация использует изменение масштаба измерения и добавление новых")
-	fmt.Println("геометрических деталей через кривую Коха. Простое деление сегментов без")
-	fmt.Println("изменения формы здесь не используется.")
-	fmt.Println(strings.Repeat("-", 80))
+// Level is one row of the paradox table.
+type Level struct {
+	Level      int
+	Points     int
+	Segments   int
+	MeanStepKM float64
+	LengthKM   float64
+	// GrowthKM and GrowthRatio compare with the previous level and are zero
+	// at level 0.
+	GrowthKM    float64
+	GrowthRatio float64
+}
 
-	fmt.Printf("%-8s %-12s %-16s %-18s %-24s\n", "Уровень", "Точек", "Сегментов", "Средний шаг, км", "Длина, км")
-	fmt.Println(strings.Repeat("-", 80))
+// Report holds the paradox table. Seed is the seed actually used for
+// erosion, which differs from the requested one when that was 0.
+type Report struct {
+	ErosionStrength float64
+	Seed            int64
+	Levels          []Level
+}
 
+// Analyze measures the Koch curve of base at every detail level up to
+// maxIterations, optionally eroded with the given strength in meters in the
+// plane of proj (nil for the default projection). A zero seed is replaced by
+// the current time.
+func Analyze(base []geometry.LatLon, maxIterations int, erosionStrength float64, seed int64, proj projection.Projector) Report {
+	report := Report{ErosionStrength: erosionStrength, Seed: seed, Levels: make([]Level, 0, maxIterations+1)}
 	prevLength := 0.0
 	for level := 0; level <= maxIterations; level++ {
-		curve := koch.KochCurve(base, level)
-		if erosionStrength > 0 {
-			if seed == 0 {
-				seed = time.Now().UnixNano()
-			}
-			curve = geometry.ErodeWithSeed(curve, erosionStrength, seed+int64(level))
+		if erosionStrength > 0 && report.Seed == 0 {
+			report.Seed = time.Now().UnixNano()
 		}
+		curve := Curve(base, level, erosionStrength, report.Seed, proj)
 		length := geometry.PolylineLength(curve)
-		segments := max(len(curve)-1, 0)
-		avgStep := 0.0
-		if segments > 0 {
-			avgStep = length / float64(segments)
+		row := Level{Level: level, Points: len(curve), Segments: max(len(curve)-1, 0), LengthKM: length}
+		if row.Segments > 0 {
+			row.MeanStepKM = length / float64(row.Segments)
 		}
-
-		growth := " | —"
 		if level > 0 {
-			growth = fmt.Sprintf(" | +%.0f км (%.3fx)", length-prevLength, length/prevLength)
+			row.GrowthKM = length - prevLength
+			row.GrowthRatio = length / prevLength
 		}
-
-		fmt.Printf("%-8d %-12d %-16d %-18.2f %-24s\n", level, len(curve), segments, avgStep, fmt.Sprintf("%.0f%s", length, growth))
+		report.Levels = append(report.Levels, row)
 		prevLength = length
 	}
+	return report
+}
 
-	fmt.Println(strings.Repeat("-", 80))
-	fmt.Println("Вывод: длина растёт при уменьшении шага измерения, потому что на каждом")
-	fmt.Println("уровне появляются новые геометрические детали, а не только дополнительные точки.")
+// Curve is the Koch curve of base at one detail level, eroded with seed+level
+// when erosionStrength is positive; Analyze measures exactly these curves.
+func Curve(base []geometry.LatLon, level int, erosionStrength float64, seed int64, proj projection.Projector) []geometry.LatLon {
+	curve := koch.KochCurve(base, level)
+	if erosionStrength > 0 {
+		curve = geometry.ErodeProjected(curve, erosionStrength, seed+int64(level), proj)
+	}
+	return curve
 }
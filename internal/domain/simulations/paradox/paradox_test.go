@@ -0,0 +1,41 @@
+package paradox
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func TestAnalyzeGrowsByKochFactor(t *testing.T) {
+	base := []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.1}}
+
+	report := Analyze(base, 3, 0, 0, nil)
+	if len(report.Levels) != 4 {
+		t.Fatalf("expected 4 levels, got %d", len(report.Levels))
+	}
+	if report.Seed != 0 {
+		t.Fatalf("expected the seed to stay unused without erosion, got %d", report.Seed)
+	}
+	for _, level := range report.Levels[1:] {
+		if math.Abs(level.GrowthRatio-4.0/3.0) > 0.01 {
+			t.Fatalf("level %d: expected growth close to 4/3, got %.4f", level.Level, level.GrowthRatio)
+		}
+		if level.Segments != level.Points-1 || math.Abs(level.MeanStepKM*float64(level.Segments)-level.LengthKM) > 1e-9 {
+			t.Fatalf("level %d: inconsistent segments %+v", level.Level, level)
+		}
+	}
+}
+
+func TestAnalyzeRecordsGeneratedSeed(t *testing.T) {
+	base := []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 0.1}}
+
+	report := Analyze(base, 1, 50, 0, nil)
+	if report.Seed == 0 {
+		t.Fatal("expected the time-based erosion seed to be reported")
+	}
+	again := Analyze(base, 1, 50, report.Seed, nil)
+	if again.Levels[1].LengthKM != report.Levels[1].LengthKM {
+		t.Fatal("expected the reported seed to reproduce the run")
+	}
+}
@@ -0,0 +1,92 @@
+package percolation
+
+// longestContour traces the boundary between sea and land cells by marching
+// squares over the cell centres and returns the longest ring in cell units,
+// closed and without collinear points. Segments keep the sea on their left
+// and saddles join the land, so the sea is 4-connected like the erosion and
+// a one-cell inlet stays part of the coast. Cells on the grid border must be
+// land, which keeps every contour closed.
+func longestContour(cols, rows int, sea func(x, y int) bool) [][2]float64 {
+	next := make(map[int]int)
+	var starts []int
+	for y := 0; y+1 < rows; y++ {
+		for x := 0; x+1 < cols; x++ {
+			corners := [4]bool{sea(x, y), sea(x+1, y), sea(x+1, y+1), sea(x, y+1)}
+			edges := [4]int{
+				edgeKey(cols, x, y, false),
+				edgeKey(cols, x+1, y, true),
+				edgeKey(cols, x, y+1, false),
+				edgeKey(cols, x, y, true),
+			}
+			// Edge k runs from corner k to corner k+1 counter-clockwise. A
+			// segment leaves through a sea-to-land edge and enters through
+			// the nearest land-to-sea edge behind it.
+			for k := 0; k < 4; k++ {
+				if !corners[k] || corners[(k+1)%4] {
+					continue
+				}
+				m := (k + 3) % 4
+				for corners[m] {
+					m = (m + 3) % 4
+				}
+				next[edges[k]] = edges[m]
+				starts = append(starts, edges[k])
+			}
+		}
+	}
+
+	var longest []int
+	visited := make(map[int]bool, len(next))
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		var ring []int
+		for key := start; !visited[key]; key = next[key] {
+			visited[key] = true
+			ring = append(ring, key)
+		}
+		if len(ring) > len(longest) {
+			longest = ring
+		}
+	}
+	if len(longest) == 0 {
+		return nil
+	}
+
+	points := make([][2]float64, len(longest))
+	for i, key := range longest {
+		points[i] = edgePoint(cols, key)
+	}
+	out := make([][2]float64, 0, len(points)+1)
+	for i, p := range points {
+		prev, following := points[(i+len(points)-1)%len(points)], points[(i+1)%len(points)]
+		if (p[0]-prev[0])*(following[1]-p[1])-(p[1]-prev[1])*(following[0]-p[0]) != 0 {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return append(out, out[0])
+}
+
+// edgeKey numbers the edge from corner (x, y) to (x+1, y), or to (x, y+1)
+// when vertical.
+func edgeKey(cols, x, y int, vertical bool) int {
+	key := 2 * (y*cols + x)
+	if vertical {
+		key++
+	}
+	return key
+}
+
+// edgePoint is the midpoint of an edge in cell units.
+func edgePoint(cols, key int) [2]float64 {
+	i := key / 2
+	x, y := float64(i%cols), float64(i/cols)
+	if key%2 == 1 {
+		return [2]float64{x, y + 0.5}
+	}
+	return [2]float64{x + 0.5, y}
+}
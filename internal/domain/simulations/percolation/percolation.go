@@ -0,0 +1,312 @@
+package percolation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+const (
+	// DefaultCells is the grid resolution along the longer side.
+	DefaultCells = 600
+	// DefaultForce is the initial sea force. It is above the site percolation
+	// threshold of the square lattice (≈0.593), so the weak rock first
+	// percolates and the sea invades it until damping stops the erosion.
+	DefaultForce = 0.65
+	// DefaultDamping is the gain g of the sea force damping by coast length.
+	DefaultDamping = 0.25
+
+	// HullDimension is the dimension of the accessible percolation hull the
+	// stable coast converges to.
+	HullDimension = 4.0 / 3.0
+
+	// marginShare is the land added around the sea as a share of the longer
+	// side of its bounding box, so the sea has rock to erode on every side.
+	marginShare = 0.25
+	minCells    = 16
+)
+
+// Options configures the rocky-coast erosion model. Force and Damping are
+// f0 and g of the sea force f = f0 / (1 + g·(P/P0 − 1)), where P is the
+// number of coast cells and P0 its initial value.
+type Options struct {
+	Steps   int
+	Seed    int64
+	Cells   int
+	Force   float64
+	Damping float64
+	// Projection is the plane the grid is laid out in; nil means the default
+	// projection centred on the shore.
+	Projection projection.Projector
+}
+
+// StepStats describes the grid after the sweeps of one snapshot step.
+type StepStats struct {
+	Step int
+	// Sweeps counts erosion sweeps since the start.
+	Sweeps int
+	// Force is the sea force at the end of the step.
+	Force       float64
+	ErodedCells int
+	CoastCells  int
+	// Stable marks the step that reached the stable coast.
+	Stable bool
+}
+
+// Result holds snapshots including the initial shore on the grid at index 0,
+// so Steps[i] produced Snapshots[i+1]. Sweeps is the number of sweeps until
+// the coast stopped changing.
+type Result struct {
+	Snapshots [][]geometry.LatLon
+	Steps     []StepStats
+	Columns   int
+	Rows      int
+	CellM     float64
+	Sweeps    int
+}
+
+// Simulate erodes the rock around a closed sea in the style of Sapoval,
+// Baldassarri and Gabrielli (2004). The polyline is the outline of the sea,
+// an open line is closed by the chord between its ends. The sea and a land
+// margin are rasterized on a square grid and every land cell gets a random
+// resistance r in [0, 1); the outermost cells never erode.
+//
+// One sweep erodes every coast cell (land with a sea 4-neighbour) whose
+// resistance is below the sea force, then the force is damped by the new
+// coast length. A rougher coast dissipates more wave energy, so the force
+// falls below the percolation threshold and erosion stops by itself on a
+// coast whose dimension approaches HullDimension. The run continues until a
+// sweep erodes nothing; the snapshots split those sweeps into Steps equal
+// parts and are extracted from the grid by marching squares.
+func Simulate(points []geometry.LatLon, opts Options) Result {
+	steps := max(opts.Steps, 0)
+	proj := opts.Projection
+	if proj == nil {
+		proj, _ = geometry.ProjectionFor(projection.Default, points)
+	}
+	g := newGrid(points, max(opts.Cells, minCells), proj)
+	force := opts.Force
+	if !(force > 0) {
+		force = DefaultForce
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	resistance := make([]float64, len(g.sea))
+	for i := range resistance {
+		resistance[i] = rng.Float64()
+		x, y := i%g.cols, i/g.cols
+		if x == 0 || y == 0 || x == g.cols-1 || y == g.rows-1 {
+			resistance[i] = math.Inf(1)
+		}
+	}
+
+	history := g.erode(resistance, force, math.Max(opts.Damping, 0))
+	sweeps := len(history) - 1
+
+	result := Result{
+		Snapshots: make([][]geometry.LatLon, steps+1),
+		Steps:     make([]StepStats, 0, steps),
+		Columns:   g.cols,
+		Rows:      g.rows,
+		CellM:     g.cell / g.scale,
+		Sweeps:    sweeps,
+	}
+	result.Snapshots[0] = g.coast(0)
+	for step := 1; step <= steps; step++ {
+		t := (step*sweeps + steps - 1) / steps
+		result.Snapshots[step] = g.coast(t)
+		result.Steps = append(result.Steps, StepStats{
+			Step:        step,
+			Sweeps:      t,
+			Force:       history[t].force,
+			ErodedCells: history[t].eroded,
+			CoastCells:  history[t].coast,
+			Stable:      t == sweeps,
+		})
+	}
+	return result
+}
+
+type sweepState struct {
+	force  float64
+	eroded int
+	coast  int
+}
+
+// grid is the sea mask in plane coordinates: cell (x, y) has its centre at
+// origin + (x+0.5, y+0.5)·cell. erodedAt holds the sweep that turned a land
+// cell into sea, 0 for the initial sea and -1 for land that never eroded.
+type grid struct {
+	cols, rows int
+	cell       float64
+	scale      float64
+	originX    float64
+	originY    float64
+	sea        []bool
+	erodedAt   []int
+	proj       projection.Projector
+}
+
+func newGrid(points []geometry.LatLon, cells int, proj projection.Projector) *grid {
+	ring := geometry.ProjectPoints(proj, points)
+	if n := len(ring); n > 1 && ring[0] != ring[n-1] {
+		ring = append(ring, ring[0])
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	var sumLat, sumLon float64
+	for i, p := range ring {
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+		if i < len(points) {
+			sumLat += points[i].Lat
+			sumLon += points[i].Lon
+		}
+	}
+	span := math.Max(maxX-minX, maxY-minY)
+	if !(span > 0) || math.IsInf(span, 0) {
+		span = 1
+	}
+	margin := span * marginShare
+	g := &grid{
+		cell:    (span + 2*margin) / float64(cells),
+		originX: minX - margin,
+		originY: minY - margin,
+		proj:    proj,
+		scale:   1,
+	}
+	if len(points) > 0 {
+		if scale := projection.ScaleFactor(proj, sumLat/float64(len(points)), sumLon/float64(len(points))); scale > 0 {
+			g.scale = scale
+		}
+	}
+	g.cols = int(math.Ceil((maxX-minX+2*margin)/g.cell)) + 1
+	g.rows = int(math.Ceil((maxY-minY+2*margin)/g.cell)) + 1
+	g.sea = make([]bool, g.cols*g.rows)
+	g.erodedAt = make([]int, len(g.sea))
+
+	// Even-odd scanline fill of the ring at the cell centres.
+	var crossings []float64
+	for y := 0; y < g.rows; y++ {
+		cy := g.originY + (float64(y)+0.5)*g.cell
+		crossings = crossings[:0]
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (a[1] > cy) == (b[1] > cy) {
+				continue
+			}
+			crossings = append(crossings, a[0]+(cy-a[1])/(b[1]-a[1])*(b[0]-a[0]))
+		}
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			from := int(math.Ceil((crossings[i]-g.originX)/g.cell - 0.5))
+			to := int(math.Floor((crossings[i+1]-g.originX)/g.cell - 0.5))
+			for x := max(from, 1); x <= min(to, g.cols-2); x++ {
+				g.sea[y*g.cols+x] = true
+			}
+		}
+	}
+	for i, sea := range g.sea {
+		if !sea {
+			g.erodedAt[i] = -1
+		}
+	}
+	return g
+}
+
+// erode runs sweeps until the coast is stable and returns the state after
+// every sweep, history[0] being the initial one.
+func (g *grid) erode(resistance []float64, f0, damping float64) []sweepState {
+	stamp := make([]int, len(g.sea))
+	var coast []int
+	addCoast := func(i, sweep int) {
+		if g.sea[i] || stamp[i] == sweep+1 {
+			return
+		}
+		for _, j := range g.neighbours(i) {
+			if g.sea[j] {
+				stamp[i] = sweep + 1
+				coast = append(coast, i)
+				return
+			}
+		}
+	}
+	for i := range g.sea {
+		addCoast(i, 0)
+	}
+
+	p0 := float64(max(len(coast), 1))
+	forceFor := func(coastCells int) float64 {
+		return f0 / (1 + damping*(float64(coastCells)/p0-1))
+	}
+	history := []sweepState{{force: forceFor(len(coast)), coast: len(coast)}}
+	eroded := 0
+	for sweep := 1; ; sweep++ {
+		force := history[sweep-1].force
+		var hit []int
+		for _, i := range coast {
+			if resistance[i] < force {
+				hit = append(hit, i)
+			}
+		}
+		if len(hit) == 0 {
+			return history
+		}
+		for _, i := range hit {
+			g.sea[i] = true
+			g.erodedAt[i] = sweep
+		}
+		eroded += len(hit)
+
+		previous := coast
+		coast = nil
+		for _, i := range previous {
+			addCoast(i, sweep)
+		}
+		for _, i := range hit {
+			for _, j := range g.neighbours(i) {
+				addCoast(j, sweep)
+			}
+		}
+		history = append(history, sweepState{force: forceFor(len(coast)), eroded: eroded, coast: len(coast)})
+	}
+}
+
+func (g *grid) neighbours(i int) []int {
+	x, y := i%g.cols, i/g.cols
+	out := make([]int, 0, 4)
+	if x > 0 {
+		out = append(out, i-1)
+	}
+	if x < g.cols-1 {
+		out = append(out, i+1)
+	}
+	if y > 0 {
+		out = append(out, i-g.cols)
+	}
+	if y < g.rows-1 {
+		out = append(out, i+g.cols)
+	}
+	return out
+}
+
+// seaAt reports whether cell i was sea after the given sweep.
+func (g *grid) seaAt(i, sweep int) bool {
+	return g.erodedAt[i] >= 0 && g.erodedAt[i] <= sweep
+}
+
+// coast is the longest contour of the sea after the given sweep, mapped
+// back to geographic coordinates.
+func (g *grid) coast(sweep int) []geometry.LatLon {
+	ring := longestContour(g.cols, g.rows, func(x, y int) bool { return g.seaAt(y*g.cols+x, sweep) })
+	out := make([]geometry.LatLon, len(ring))
+	for i, p := range ring {
+		lat, lon := g.proj.Inverse(g.originX+(p[0]+0.5)*g.cell, g.originY+(p[1]+0.5)*g.cell)
+		out[i] = geometry.LatLon{Lat: lat, Lon: lon}
+	}
+	return out
+}
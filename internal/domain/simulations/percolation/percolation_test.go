@@ -0,0 +1,69 @@
+package percolation
+
+import (
+	"math"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/pkg/fraes"
+)
+
+func TestLongestContourKeepsTheSeaOnTheLeft(t *testing.T) {
+	block := func(x, y int) bool { return x >= 2 && x <= 3 && y >= 2 && y <= 3 }
+	ring := longestContour(6, 6, block)
+	if len(ring) != 9 || ring[0] != ring[8] {
+		t.Fatalf("expected a closed octagon around a 2x2 sea, got %v", ring)
+	}
+	if area := signedArea(ring); math.Abs(area-3.5) > 1e-12 {
+		t.Fatalf("expected a counter-clockwise ring of area 3.5, got %v", area)
+	}
+
+	// Diagonal sea cells touch only at a corner: the land between them stays
+	// connected and each cell gets its own diamond.
+	diagonal := func(x, y int) bool { return (x == 2 && y == 2) || (x == 3 && y == 3) }
+	if ring := longestContour(6, 6, diagonal); len(ring) != 5 || math.Abs(signedArea(ring)-0.5) > 1e-12 {
+		t.Fatalf("expected a diamond around one diagonal cell, got %v", ring)
+	}
+}
+
+func TestSimulateRoughensTheCoastUntilItIsStable(t *testing.T) {
+	sea := []geometry.LatLon{
+		{Lat: 44, Lon: 30}, {Lat: 44, Lon: 31}, {Lat: 45, Lon: 31}, {Lat: 45, Lon: 30}, {Lat: 44, Lon: 30},
+	}
+	opts := Options{Steps: 4, Seed: 7, Cells: 120, Force: DefaultForce, Damping: DefaultDamping}
+	result := Simulate(sea, opts)
+
+	if len(result.Snapshots) != 5 || len(result.Steps) != 4 || result.Sweeps == 0 {
+		t.Fatalf("expected 4 steps over a run of sweeps, got %d snapshots, %d sweeps", len(result.Snapshots), result.Sweeps)
+	}
+	last := result.Steps[3]
+	if !last.Stable || last.Sweeps != result.Sweeps || last.Force >= DefaultForce {
+		t.Fatalf("expected the last step to end on the stable coast with a damped force, got %+v", last)
+	}
+	for i := 1; i < len(result.Steps); i++ {
+		if result.Steps[i].Sweeps <= result.Steps[i-1].Sweeps || result.Steps[i].ErodedCells < result.Steps[i-1].ErodedCells {
+			t.Fatalf("expected steps to advance through the sweeps, got %+v", result.Steps)
+		}
+	}
+
+	initial, final := fraes.PolylineLength(result.Snapshots[0]), fraes.PolylineLength(result.Snapshots[4])
+	if math.Abs(initial-fraes.PolylineLength(sea))/fraes.PolylineLength(sea) > 0.1 {
+		t.Fatalf("expected the rasterized shore to keep the length of the square, got %.0f km", initial)
+	}
+	if final < 1.5*initial {
+		t.Fatalf("expected erosion to roughen the coast, got %.0f -> %.0f km", initial, final)
+	}
+
+	again := Simulate(sea, opts)
+	if again.Sweeps != result.Sweeps || len(again.Snapshots[4]) != len(result.Snapshots[4]) {
+		t.Fatal("expected the same seed to give the same coast")
+	}
+}
+
+func signedArea(ring [][2]float64) float64 {
+	var sum float64
+	for i := 1; i < len(ring); i++ {
+		sum += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+	}
+	return sum / 2
+}
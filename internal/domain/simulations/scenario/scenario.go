@@ -0,0 +1,143 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	SeaLevelNone   = ""
+	SeaLevelLinear = "linear"
+	SeaLevelTable  = "table"
+
+	// DefaultBruunSlope is the slope of the active beach profile, 1:50.
+	DefaultBruunSlope = 0.02
+	// DefaultStormVariability is the log-normal sigma of storm retreat.
+	DefaultStormVariability = 0.5
+
+	maxScenarioYears = 1000
+)
+
+// Scenario is a time-calibrated erosion forcing read from JSON. Every model
+// step covers StepYears calendar years starting at StartYear.
+type Scenario struct {
+	Name               string   `json:"name"`
+	StartYear          int      `json:"start_year"`
+	EndYear            int      `json:"end_year"`
+	StepYears          int      `json:"step_years,omitempty"`
+	BackgroundRetreatM float64  `json:"background_retreat_m_per_year"`
+	Storms             Storms   `json:"storms"`
+	SeaLevel           SeaLevel `json:"sea_level"`
+	Source             string   `json:"-"`
+}
+
+// Storms are Poisson events with the given return period. RetreatM is the
+// mean retreat of one storm; Variability is the sigma of its log-normal spread.
+type Storms struct {
+	ReturnPeriodYears float64 `json:"return_period_years,omitempty"`
+	RetreatM          float64 `json:"retreat_m,omitempty"`
+	Variability       float64 `json:"variability"`
+}
+
+// SeaLevel is a sea-level-rise curve relative to StartYear, either a linear
+// rate or an RCP-style table, converted to retreat by the Bruun rule
+// R = ΔS / slope.
+type SeaLevel struct {
+	Type          string          `json:"type,omitempty"`
+	RateMMPerYear float64         `json:"rate_mm_per_year,omitempty"`
+	Table         []SeaLevelPoint `json:"table,omitempty"`
+	BruunSlope    float64         `json:"bruun_slope,omitempty"`
+}
+
+type SeaLevelPoint struct {
+	Year  int     `json:"year"`
+	RiseM float64 `json:"rise_m"`
+}
+
+func Load(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("read scenario json %q: %w", path, err)
+	}
+	scenario, err := Parse(data)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("parse scenario json %q: %w", path, err)
+	}
+	scenario.Source = path
+	return scenario, nil
+}
+
+// Parse reads a scenario from JSON. Defaults fill only the fields missing
+// from data, so an explicit "variability": 0 gives storms of exactly the
+// mean retreat.
+func Parse(data []byte) (Scenario, error) {
+	scenario := Scenario{
+		StepYears: 1,
+		Storms:    Storms{Variability: DefaultStormVariability},
+		SeaLevel:  SeaLevel{BruunSlope: DefaultBruunSlope},
+	}
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return Scenario{}, err
+	}
+	sort.SliceStable(scenario.SeaLevel.Table, func(i, j int) bool {
+		return scenario.SeaLevel.Table[i].Year < scenario.SeaLevel.Table[j].Year
+	})
+
+	if err := scenario.validate(); err != nil {
+		return Scenario{}, err
+	}
+	return scenario, nil
+}
+
+func (s Scenario) validate() error {
+	switch {
+	case s.EndYear <= s.StartYear:
+		return fmt.Errorf("end_year %d must be after start_year %d", s.EndYear, s.StartYear)
+	case s.EndYear-s.StartYear > maxScenarioYears:
+		return fmt.Errorf("scenario spans %d years, at most %d are supported", s.EndYear-s.StartYear, maxScenarioYears)
+	case s.StepYears <= 0:
+		return fmt.Errorf("step_years must be positive")
+	case s.BackgroundRetreatM < 0:
+		return fmt.Errorf("background_retreat_m_per_year must be non-negative")
+	case s.Storms.ReturnPeriodYears < 0 || s.Storms.RetreatM < 0 || s.Storms.Variability < 0:
+		return fmt.Errorf("storms: return_period_years, retreat_m and variability must be non-negative")
+	case s.Storms.ReturnPeriodYears == 0 && s.Storms.RetreatM > 0:
+		return fmt.Errorf("storms: retreat_m needs return_period_years")
+	case s.SeaLevel.BruunSlope <= 0:
+		return fmt.Errorf("sea_level: bruun_slope must be positive")
+	}
+
+	switch s.SeaLevel.Type {
+	case SeaLevelNone:
+	case SeaLevelLinear:
+		if s.SeaLevel.RateMMPerYear < 0 {
+			return fmt.Errorf("sea_level: rate_mm_per_year must be non-negative")
+		}
+	case SeaLevelTable:
+		table := s.SeaLevel.Table
+		if len(table) < 2 {
+			return fmt.Errorf("sea_level: table needs at least 2 points")
+		}
+		for i := 1; i < len(table); i++ {
+			if table[i].Year == table[i-1].Year {
+				return fmt.Errorf("sea_level: duplicate table year %d", table[i].Year)
+			}
+		}
+		if table[0].Year > s.StartYear || table[len(table)-1].Year < s.EndYear {
+			return fmt.Errorf("sea_level: table covers %d..%d, scenario needs %d..%d",
+				table[0].Year, table[len(table)-1].Year, s.StartYear, s.EndYear)
+		}
+	default:
+		return fmt.Errorf("sea_level: unknown type %q (use %q or %q)", s.SeaLevel.Type, SeaLevelLinear, SeaLevelTable)
+	}
+	return nil
+}
+
+// Steps returns the number of model steps, the last one possibly shorter
+// than StepYears.
+func (s Scenario) Steps() int {
+	years := s.EndYear - s.StartYear
+	return (years + s.StepYears - 1) / s.StepYears
+}
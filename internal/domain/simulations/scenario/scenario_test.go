@@ -0,0 +1,130 @@
+package scenario
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTimelineCombinesBackgroundAndBruunRetreat(t *testing.T) {
+	scenario, err := Parse([]byte(`{
+		"name": "linear",
+		"start_year": 2025,
+		"end_year": 2035,
+		"step_years": 4,
+		"background_retreat_m_per_year": 0.5,
+		"sea_level": {"type": "linear", "rate_mm_per_year": 4, "bruun_slope": 0.02}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	steps := scenario.Timeline(1)
+	if len(steps) != 3 || scenario.Steps() != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+	last := steps[2]
+	if last.FromYear != 2033 || last.ToYear != 2035 {
+		t.Fatalf("expected the last step to cover 2033..2035, got %d..%d", last.FromYear, last.ToYear)
+	}
+	// Two years: 2 × 0.5 m background and 8 mm of rise over a 1:50 profile.
+	if math.Abs(last.BackgroundM-1) > 1e-9 || math.Abs(last.BruunM-0.4) > 1e-9 || math.Abs(last.TotalM-1.4) > 1e-9 {
+		t.Fatalf("unexpected forcing %+v", last)
+	}
+	if math.Abs(last.SeaLevelM-0.04) > 1e-9 {
+		t.Fatalf("expected 4 cm of rise by 2035, got %.4f m", last.SeaLevelM)
+	}
+}
+
+func TestSeaLevelTableIsInterpolatedFromStartYear(t *testing.T) {
+	scenario, err := Parse([]byte(`{
+		"start_year": 2030,
+		"end_year": 2100,
+		"sea_level": {"type": "table", "table": [{"year": 2100, "rise_m": 0.6}, {"year": 2020, "rise_m": 0}, {"year": 2050, "rise_m": 0.15}]}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	if got := scenario.SeaLevelAt(2030); got != 0 {
+		t.Fatalf("expected zero rise at the start year, got %.4f", got)
+	}
+	// 2030 lies at 0.05 m and 2075 at 0.15 + 0.45·25/50 = 0.375 m.
+	if got := scenario.SeaLevelAt(2075); math.Abs(got-0.325) > 1e-9 {
+		t.Fatalf("expected 0.325 m at 2075, got %.4f", got)
+	}
+}
+
+func TestTimelineStormsFollowReturnPeriod(t *testing.T) {
+	scenario, err := Parse([]byte(`{
+		"start_year": 0,
+		"end_year": 1000,
+		"step_years": 10,
+		"storms": {"return_period_years": 5, "retreat_m": 4}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	first := scenario.Timeline(42)
+	storms, retreat := 0, 0.0
+	for _, step := range first {
+		storms += step.Storms
+		retreat += step.StormM
+	}
+	if storms < 160 || storms > 240 {
+		t.Fatalf("expected about 200 storms in 1000 years, got %d", storms)
+	}
+	if mean := retreat / float64(storms); mean < 3.5 || mean > 4.5 {
+		t.Fatalf("expected mean storm retreat close to 4 m, got %.2f", mean)
+	}
+
+	second := scenario.Timeline(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("step %d differs between runs with the same seed", i)
+		}
+	}
+}
+
+func TestParseDefaultsOnlyMissingFields(t *testing.T) {
+	scenario, err := Parse([]byte(`{"start_year": 2030, "end_year": 2040, "storms": {"return_period_years": 2, "retreat_m": 3}}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if scenario.StepYears != 1 || scenario.Storms.Variability != DefaultStormVariability || scenario.SeaLevel.BruunSlope != DefaultBruunSlope {
+		t.Fatalf("expected defaults for missing fields, got %+v", scenario)
+	}
+
+	scenario, err = Parse([]byte(`{"start_year": 2030, "end_year": 2040, "storms": {"return_period_years": 2, "retreat_m": 3, "variability": 0}}`))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if scenario.Storms.Variability != 0 {
+		t.Fatalf("expected explicit variability 0 to be kept, got %v", scenario.Storms.Variability)
+	}
+	for _, step := range scenario.Timeline(7) {
+		if step.StormM != float64(step.Storms)*3 {
+			t.Fatalf("expected every storm to retreat exactly 3 m without variability, got %v for %d storms", step.StormM, step.Storms)
+		}
+	}
+}
+
+func TestParseRejectsInvalidScenarios(t *testing.T) {
+	cases := map[string]string{
+		`{"start_year": 2030, "end_year": 2030}`:                                                                                            "end_year",
+		`{"start_year": 2030, "end_year": 2040, "storms": {"retreat_m": 3}}`:                                                                "return_period_years",
+		`{"start_year": 2030, "end_year": 2040, "sea_level": {"type": "rcp"}}`:                                                              "unknown type",
+		`{"start_year": 2030, "end_year": 2040, "sea_level": {"type": "table", "table": [{"year": 2030}, {"year": 2035}]}}`:                 "covers",
+		`{"start_year": 2030, "end_year": 2040, "background_retreat_m_per_year": -1}`:                                                       "background",
+		`{"start_year": 2030, "end_year": 2040, "step_years": 0}`:                                                                           "step_years",
+		`{"start_year": 2030, "end_year": 2040, "sea_level": {"type": "linear", "bruun_slope": 0}}`:                                         "bruun_slope",
+		`{"start_year": 2030, "end_year": 2040, "sea_level": {"type": "table", "table": [{"year": 2030}, {"year": 2030}, {"year": 2040}]}}`: "duplicate",
+	}
+	for data, want := range cases {
+		_, err := Parse([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q for %s, got %v", want, data, err)
+		}
+	}
+}
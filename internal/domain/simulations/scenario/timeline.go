@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Step is the forcing of one model step covering the years (FromYear, ToYear].
+// TotalM is the mean shoreline retreat the step should produce.
+type Step struct {
+	Index         int
+	FromYear      int
+	ToYear        int
+	BackgroundM   float64
+	Storms        int
+	StormM        float64
+	MaxStormM     float64
+	SeaLevelM     float64
+	SeaLevelRiseM float64
+	BruunM        float64
+	TotalM        float64
+}
+
+// Timeline combines background retreat, storms and the Bruun response to sea
+// level rise into per-step retreat. Storms are drawn year by year from one
+// generator, so a fixed seed always gives the same storm history.
+func (s Scenario) Timeline(seed int64) []Step {
+	rng := rand.New(rand.NewSource(seed))
+	steps := make([]Step, 0, s.Steps())
+	for from := s.StartYear; from < s.EndYear; from += s.StepYears {
+		to := min(from+s.StepYears, s.EndYear)
+		step := Step{Index: len(steps) + 1, FromYear: from, ToYear: to}
+		for year := from; year < to; year++ {
+			step.BackgroundM += s.BackgroundRetreatM
+			for range s.stormCount(rng) {
+				retreat := s.stormRetreat(rng)
+				step.Storms++
+				step.StormM += retreat
+				step.MaxStormM = math.Max(step.MaxStormM, retreat)
+			}
+		}
+		step.SeaLevelM = s.SeaLevelAt(to)
+		step.SeaLevelRiseM = step.SeaLevelM - s.SeaLevelAt(from)
+		if s.SeaLevel.BruunSlope > 0 {
+			step.BruunM = step.SeaLevelRiseM / s.SeaLevel.BruunSlope
+		}
+		step.TotalM = step.BackgroundM + step.StormM + step.BruunM
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// SeaLevelAt returns the sea level rise in meters at year relative to
+// StartYear. Tables are interpolated linearly between their points.
+func (s Scenario) SeaLevelAt(year int) float64 {
+	switch s.SeaLevel.Type {
+	case SeaLevelLinear:
+		return s.SeaLevel.RateMMPerYear / 1000 * float64(year-s.StartYear)
+	case SeaLevelTable:
+		return interpolate(s.SeaLevel.Table, year) - interpolate(s.SeaLevel.Table, s.StartYear)
+	default:
+		return 0
+	}
+}
+
+func interpolate(table []SeaLevelPoint, year int) float64 {
+	if len(table) == 0 {
+		return 0
+	}
+	if year <= table[0].Year {
+		return table[0].RiseM
+	}
+	for i := 1; i < len(table); i++ {
+		a, b := table[i-1], table[i]
+		if year <= b.Year {
+			t := float64(year-a.Year) / float64(b.Year-a.Year)
+			return a.RiseM + (b.RiseM-a.RiseM)*t
+		}
+	}
+	return table[len(table)-1].RiseM
+}
+
+// stormCount draws the number of storms in one year from a Poisson
+// distribution with rate 1/ReturnPeriodYears.
+func (s Scenario) stormCount(rng *rand.Rand) int {
+	if s.Storms.ReturnPeriodYears <= 0 || s.Storms.RetreatM <= 0 {
+		return 0
+	}
+	limit := math.Exp(-1 / s.Storms.ReturnPeriodYears)
+	count := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		count++
+	}
+	return count
+}
+
+// stormRetreat draws a log-normal retreat whose mean is Storms.RetreatM.
+func (s Scenario) stormRetreat(rng *rand.Rand) float64 {
+	sigma := s.Storms.Variability
+	return s.Storms.RetreatM * math.Exp(sigma*rng.NormFloat64()-sigma*sigma/2)
+}
@@ -0,0 +1,146 @@
+package sweep
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// ErrJournalMismatch means the journal on disk belongs to another sweep.
+var ErrJournalMismatch = errors.New("sweep journal belongs to another sweep")
+
+// Header identifies a sweep: a journal only resumes the sweep it was
+// started for. Settings holds the non-swept model settings.
+type Header struct {
+	Pipeline   string            `json:"pipeline"`
+	Sampling   string            `json:"sampling"`
+	Samples    int               `json:"samples,omitempty"`
+	Seed       int64             `json:"seed"`
+	Parameters []Parameter       `json:"parameters"`
+	Settings   map[string]string `json:"settings,omitempty"`
+}
+
+// Result is one finished run; Values follow Header.Parameters.
+type Result struct {
+	Index          int       `json:"index"`
+	Values         []float64 `json:"values"`
+	LengthKM       float64   `json:"length_km"`
+	LengthGrowth   float64   `json:"length_growth"`
+	AreaKM2        float64   `json:"area_km2"`
+	Dimension      float64   `json:"dimension"`
+	DimensionValid bool      `json:"dimension_valid"`
+}
+
+// Journal appends finished runs as JSON lines after a header line, so an
+// interrupted sweep resumes where it stopped. Append is safe for concurrent
+// use.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenJournal opens or creates the journal at path and returns the runs it
+// already holds, ordered by index. fresh discards an existing journal. A
+// torn last line from an interrupted write is dropped.
+func OpenJournal(path string, header Header, fresh bool) (*Journal, []Result, error) {
+	want, err := json.Marshal(header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode sweep header: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("read sweep journal %q: %w", path, err)
+	}
+	if fresh {
+		data = nil
+	}
+
+	var results []Result
+	keep := 0
+	if len(data) > 0 {
+		if results, keep, err = readJournal(data, want); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if keep == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open sweep journal %q: %w", path, err)
+	}
+	if keep == 0 {
+		_, err = file.Write(append(want, '\n'))
+	} else if err = file.Truncate(int64(keep)); err == nil {
+		_, err = file.Seek(int64(keep), 0)
+	}
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("prepare sweep journal %q: %w", path, err)
+	}
+	return &Journal{file: file}, results, nil
+}
+
+// readJournal checks the header line and parses the runs after it; keep is
+// the length of the intact prefix.
+func readJournal(data, header []byte) ([]Result, int, error) {
+	line, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found {
+		// Not even the header made it to disk.
+		return nil, 0, nil
+	}
+	var got Header
+	if err := json.Unmarshal(line, &got); err != nil {
+		return nil, 0, fmt.Errorf("sweep journal header: %w", err)
+	}
+	if normalized, _ := json.Marshal(got); !bytes.Equal(normalized, header) {
+		return nil, 0, ErrJournalMismatch
+	}
+
+	keep := len(line) + 1
+	byIndex := make(map[int]Result)
+	for number := 2; len(rest) > 0; number++ {
+		line, rest, found = bytes.Cut(rest, []byte("\n"))
+		var result Result
+		if err := json.Unmarshal(line, &result); err != nil || !found {
+			if len(rest) == 0 {
+				break
+			}
+			return nil, 0, fmt.Errorf("sweep journal line %d: %w", number, err)
+		}
+		byIndex[result.Index] = result
+		keep += len(line) + 1
+	}
+
+	results := make([]Result, 0, len(byIndex))
+	for _, result := range byIndex {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	return results, keep, nil
+}
+
+// Append writes one finished run and syncs it to disk.
+func (j *Journal) Append(result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encode sweep result %d: %w", result.Index, err)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write sweep journal: %w", err)
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
@@ -0,0 +1,53 @@
+package sweep
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalResumesAfterInterruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sweep.jsonl")
+	header := Header{Pipeline: "organic", Sampling: SamplingGrid, Seed: 42, Parameters: []Parameter{{Name: "angle-jitter", Values: []float64{0, 10}}}}
+
+	journal, done, err := OpenJournal(path, header, false)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("expected an empty new journal, got %v (%v)", done, err)
+	}
+	for _, index := range []int{1, 0} {
+		if err := journal.Append(Result{Index: index, Values: []float64{float64(index * 10)}, LengthKM: 100, Dimension: 1.2, DimensionValid: true}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	journal.Close()
+
+	// A run killed mid-write leaves a torn line behind.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	file.WriteString(`{"index":2,"val`)
+	file.Close()
+
+	journal, done, err = OpenJournal(path, header, false)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if len(done) != 2 || done[0].Index != 0 || done[1].Index != 1 || !done[1].DimensionValid {
+		t.Fatalf("expected runs 0 and 1 in order, got %+v", done)
+	}
+	if err := journal.Append(Result{Index: 2}); err != nil {
+		t.Fatalf("append after resume: %v", err)
+	}
+	journal.Close()
+	if _, done, err = OpenJournal(path, header, false); err != nil || len(done) != 3 {
+		t.Fatalf("expected the torn line to be replaced by run 2, got %+v (%v)", done, err)
+	}
+
+	other := header
+	other.Seed = 7
+	if _, _, err := OpenJournal(path, other, false); !errors.Is(err, ErrJournalMismatch) {
+		t.Fatalf("expected a mismatch for another sweep, got %v", err)
+	}
+	if _, done, err = OpenJournal(path, other, true); err != nil || len(done) != 0 {
+		t.Fatalf("expected fresh to discard the old journal, got %+v (%v)", done, err)
+	}
+}
@@ -0,0 +1,122 @@
+package sweep
+
+import (
+	"math"
+	"slices"
+)
+
+// MaxPlaneCells bounds the heatmap cells along one axis. Axes with more
+// distinct values, such as Latin-hypercube samples, are binned into equal
+// intervals.
+const MaxPlaneCells = 12
+
+// Plane is a heatmap of one result value over two parameters:
+// Cells[row][col] averages the runs whose X falls in column col and whose Y
+// falls in row row, other parameters pooled. NaN marks an empty cell.
+// A negative Y gives a single row.
+type Plane struct {
+	X, Y     int
+	XCenters []float64
+	YCenters []float64
+	Cells    [][]float64
+	Counts   [][]int
+}
+
+// NewPlane bins points[i] (the parameter values of run i) by parameters x
+// and y and averages values[i] per cell; NaN values are skipped.
+func NewPlane(points [][]float64, values []float64, x, y int) Plane {
+	plane := Plane{X: x, Y: y}
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, point := range points {
+		xs[i] = point[x]
+		if y >= 0 {
+			ys[i] = point[y]
+		}
+	}
+	var columnOf, rowOf func(float64) int
+	plane.XCenters, columnOf = axisBins(xs)
+	plane.YCenters, rowOf = axisBins(ys)
+	if y < 0 {
+		plane.YCenters = []float64{0}
+	}
+
+	sums := make([][]float64, len(plane.YCenters))
+	plane.Counts = make([][]int, len(plane.YCenters))
+	for row := range sums {
+		sums[row] = make([]float64, len(plane.XCenters))
+		plane.Counts[row] = make([]int, len(plane.XCenters))
+	}
+	for i := range points {
+		if math.IsNaN(values[i]) || math.IsInf(values[i], 0) {
+			continue
+		}
+		row, col := rowOf(ys[i]), columnOf(xs[i])
+		sums[row][col] += values[i]
+		plane.Counts[row][col]++
+	}
+
+	plane.Cells = make([][]float64, len(sums))
+	for row := range sums {
+		plane.Cells[row] = make([]float64, len(sums[row]))
+		for col, sum := range sums[row] {
+			if plane.Counts[row][col] == 0 {
+				plane.Cells[row][col] = math.NaN()
+				continue
+			}
+			plane.Cells[row][col] = sum / float64(plane.Counts[row][col])
+		}
+	}
+	return plane
+}
+
+// axisBins keeps the distinct values as cells when there are few of them
+// and otherwise cuts [min, max] into about sqrt(n) equal intervals.
+func axisBins(values []float64) ([]float64, func(float64) int) {
+	distinct := slices.Clone(values)
+	slices.Sort(distinct)
+	distinct = slices.Compact(distinct)
+	if len(distinct) <= MaxPlaneCells {
+		return distinct, func(v float64) int {
+			i, _ := slices.BinarySearch(distinct, v)
+			return i
+		}
+	}
+
+	low, high := distinct[0], distinct[len(distinct)-1]
+	bins := min(MaxPlaneCells, max(2, int(math.Ceil(math.Sqrt(float64(len(values)))))))
+	width := (high - low) / float64(bins)
+	centers := make([]float64, bins)
+	for i := range centers {
+		centers[i] = low + (float64(i)+0.5)*width
+	}
+	return centers, func(v float64) int {
+		return min(bins-1, max(0, int((v-low)/width)))
+	}
+}
+
+// Sensitivity fits values against parameter j by least squares and returns
+// the fitted change across the parameter's sampled range; NaN values are
+// skipped and a parameter that does not vary gives 0.
+func Sensitivity(points [][]float64, values []float64, j int) float64 {
+	var n, sumX, sumY, sumXX, sumXY float64
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, point := range points {
+		if math.IsNaN(values[i]) || math.IsInf(values[i], 0) {
+			continue
+		}
+		x := point[j]
+		n++
+		sumX += x
+		sumY += values[i]
+		sumXX += x * x
+		sumXY += x * values[i]
+		low, high = math.Min(low, x), math.Max(high, x)
+	}
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return 0
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	return slope * (high - low)
+}
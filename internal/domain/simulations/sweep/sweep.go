@@ -0,0 +1,147 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	SamplingGrid = "grid"
+	SamplingLHS  = "lhs"
+
+	// MaxPoints bounds a sweep so a mistyped step cannot queue millions of
+	// model runs.
+	MaxPoints = 10000
+)
+
+// Parameter is one swept model parameter. Grid sampling runs every value;
+// Latin-hypercube sampling only uses the smallest and largest one.
+type Parameter struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+}
+
+func (p Parameter) Min() float64 { return slices.Min(p.Values) }
+func (p Parameter) Max() float64 { return slices.Max(p.Values) }
+
+// Varies reports whether the sweep moves the parameter at all.
+func (p Parameter) Varies() bool { return p.Min() != p.Max() }
+
+// ParseParameter reads a range as a single value, min:max (the two ends),
+// min:max:step or a comma separated list.
+func ParseParameter(name, spec string) (Parameter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Parameter{}, fmt.Errorf("%s: range is empty", name)
+	}
+
+	number := func(part string) (float64, error) {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return 0, fmt.Errorf("%s %q: %q is not a number", name, spec, part)
+		}
+		return value, nil
+	}
+
+	var values []float64
+	if parts := strings.Split(spec, ":"); len(parts) > 1 {
+		if len(parts) > 3 {
+			return Parameter{}, fmt.Errorf("%s %q must be min:max or min:max:step", name, spec)
+		}
+		var bounds [3]float64
+		for i, part := range parts {
+			value, err := number(part)
+			if err != nil {
+				return Parameter{}, err
+			}
+			bounds[i] = value
+		}
+		low, high := bounds[0], bounds[1]
+		if high < low {
+			return Parameter{}, fmt.Errorf("%s %q must have min <= max", name, spec)
+		}
+		if len(parts) == 2 {
+			values = []float64{low, high}
+		} else {
+			step := bounds[2]
+			if step <= 0 {
+				return Parameter{}, fmt.Errorf("%s %q must have step > 0", name, spec)
+			}
+			count := int(math.Floor((high-low)/step+1e-9)) + 1
+			if count > MaxPoints {
+				return Parameter{}, fmt.Errorf("%s %q has more than %d values", name, spec, MaxPoints)
+			}
+			values = make([]float64, count)
+			for i := range values {
+				// round away the accumulated step error, e.g. 0.30000000000000004
+				values[i] = math.Round((low+float64(i)*step)*1e9) / 1e9
+			}
+		}
+	} else {
+		for _, part := range strings.Split(spec, ",") {
+			value, err := number(part)
+			if err != nil {
+				return Parameter{}, err
+			}
+			values = append(values, value)
+		}
+	}
+
+	slices.Sort(values)
+	return Parameter{Name: name, Values: slices.Compact(values)}, nil
+}
+
+// Grid returns every combination of the parameter values; the last
+// parameter varies fastest.
+func Grid(params []Parameter) ([][]float64, error) {
+	total := 1
+	for _, p := range params {
+		total *= len(p.Values)
+		if total > MaxPoints {
+			return nil, fmt.Errorf("grid has more than %d points", MaxPoints)
+		}
+	}
+
+	points := make([][]float64, total)
+	for i := range points {
+		point := make([]float64, len(params))
+		rest := i
+		for j := len(params) - 1; j >= 0; j-- {
+			n := len(params[j].Values)
+			point[j] = params[j].Values[rest%n]
+			rest /= n
+		}
+		points[i] = point
+	}
+	return points, nil
+}
+
+// LatinHypercube draws n points so that each parameter's [min, max] is cut
+// into n equal strata and every stratum holds exactly one point. The same
+// seed gives the same points.
+func LatinHypercube(params []Parameter, n int, seed int64) ([][]float64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("latin hypercube needs at least 1 sample")
+	}
+	if n > MaxPoints {
+		return nil, fmt.Errorf("latin hypercube has more than %d points", MaxPoints)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = make([]float64, len(params))
+	}
+	for j, p := range params {
+		low, high := p.Min(), p.Max()
+		strata := rng.Perm(n)
+		for i, stratum := range strata {
+			points[i][j] = low + (float64(stratum)+rng.Float64())/float64(n)*(high-low)
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,125 @@
+package sweep
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParseParameterAcceptsValuesRangesAndLists(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []float64
+	}{
+		{spec: "5", want: []float64{5}},
+		{spec: "0:30", want: []float64{0, 30}},
+		{spec: "0:0.3:0.1", want: []float64{0, 0.1, 0.2, 0.3}},
+		{spec: "20, 10,10,0", want: []float64{0, 10, 20}},
+	}
+	for _, test := range tests {
+		p, err := ParseParameter("angle-jitter", test.spec)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", test.spec, err)
+		}
+		if !slices.Equal(p.Values, test.want) {
+			t.Fatalf("%q: expected %v, got %v", test.spec, test.want, p.Values)
+		}
+	}
+
+	for _, spec := range []string{"", "a:b", "3:1", "0:1:0", "1:2:3:4", "0:1e9:1"} {
+		if _, err := ParseParameter("angle-jitter", spec); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestGridVisitsEveryCombination(t *testing.T) {
+	points, err := Grid([]Parameter{
+		{Name: "a", Values: []float64{1, 2}},
+		{Name: "b", Values: []float64{10, 20, 30}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(points) != 6 {
+		t.Fatalf("expected 6 points, got %d", len(points))
+	}
+	if !slices.Equal(points[0], []float64{1, 10}) || !slices.Equal(points[1], []float64{1, 20}) || !slices.Equal(points[5], []float64{2, 30}) {
+		t.Fatalf("expected the last parameter to vary fastest, got %v", points)
+	}
+
+	huge := Parameter{Name: "x", Values: make([]float64, 200)}
+	if _, err := Grid([]Parameter{huge, huge}); err == nil {
+		t.Fatal("expected a grid over MaxPoints to be rejected")
+	}
+}
+
+func TestLatinHypercubeFillsEveryStratumOnce(t *testing.T) {
+	params := []Parameter{
+		{Name: "a", Values: []float64{0, 10}},
+		{Name: "b", Values: []float64{-1, 1}},
+		{Name: "fixed", Values: []float64{7}},
+	}
+	points, err := LatinHypercube(params, 8, 42)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for j, p := range params[:2] {
+		seen := make([]bool, 8)
+		for _, point := range points {
+			stratum := int((point[j] - p.Min()) / (p.Max() - p.Min()) * 8)
+			if stratum < 0 || stratum >= 8 || seen[stratum] {
+				t.Fatalf("parameter %s: value %v breaks the stratification", p.Name, point[j])
+			}
+			seen[stratum] = true
+		}
+	}
+	for _, point := range points {
+		if point[2] != 7 {
+			t.Fatalf("expected a fixed parameter to stay at 7, got %v", point[2])
+		}
+	}
+
+	again, _ := LatinHypercube(params, 8, 42)
+	for i := range points {
+		if !slices.Equal(points[i], again[i]) {
+			t.Fatal("expected the same seed to give the same samples")
+		}
+	}
+}
+
+func TestNewPlaneAveragesRunsPerCell(t *testing.T) {
+	points := [][]float64{{0, 1, 5}, {0, 1, 7}, {10, 1, 5}, {0, 2, 5}}
+	values := []float64{1.2, 1.4, 1.5, math.NaN()}
+	plane := NewPlane(points, values, 0, 1)
+
+	if !slices.Equal(plane.XCenters, []float64{0, 10}) || !slices.Equal(plane.YCenters, []float64{1, 2}) {
+		t.Fatalf("unexpected axes %v / %v", plane.XCenters, plane.YCenters)
+	}
+	if math.Abs(plane.Cells[0][0]-1.3) > 1e-12 || plane.Counts[0][0] != 2 {
+		t.Fatalf("expected the third parameter to be pooled, got %v (%d runs)", plane.Cells[0][0], plane.Counts[0][0])
+	}
+	if plane.Cells[0][1] != 1.5 || !math.IsNaN(plane.Cells[1][0]) || !math.IsNaN(plane.Cells[1][1]) {
+		t.Fatalf("unexpected cells %v", plane.Cells)
+	}
+
+	scattered := make([][]float64, 100)
+	for i := range scattered {
+		scattered[i] = []float64{float64(i), 0}
+	}
+	binned := NewPlane(scattered, make([]float64, 100), 0, -1)
+	if len(binned.XCenters) != 10 || len(binned.Cells) != 1 || binned.Counts[0][0] != 10 {
+		t.Fatalf("expected 100 distinct values in 10 bins of one row, got %d columns, %d rows", len(binned.XCenters), len(binned.Cells))
+	}
+}
+
+func TestSensitivityIsTheFittedChangeOverTheRange(t *testing.T) {
+	points := [][]float64{{0, 1}, {10, 1}, {20, 2}, {30, 2}}
+	values := []float64{1.1, 1.2, 1.3, math.NaN()}
+	if got := Sensitivity(points, values, 0); math.Abs(got-0.2) > 1e-12 {
+		t.Fatalf("expected D to rise by 0.2 over 0..20, got %v", got)
+	}
+	if got := Sensitivity([][]float64{{5}, {5}}, []float64{1, 2}, 0); got != 0 {
+		t.Fatalf("expected no sensitivity for a fixed parameter, got %v", got)
+	}
+}
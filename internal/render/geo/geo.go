@@ -0,0 +1,136 @@
+// Package geo writes model polylines as GIS data: GeoJSON feature
+// collections and GeoPackage files that QGIS or GDAL open directly.
+package geo
+
+import (
+	"bytes"
+	"coastal-geometry/internal/domain/geometry"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Property is one attribute of a feature. Value is nil, an integer, a
+// float64 or a string; NaN and infinite floats are written as null.
+type Property struct {
+	Name  string
+	Value any
+}
+
+// Feature is one polyline in WGS84 with its attributes in column order.
+type Feature struct {
+	Points     []geometry.LatLon
+	Properties []Property
+}
+
+// Layer is a named series of features, e.g. all iterations of one model.
+type Layer struct {
+	Name        string
+	Description string
+	Features    []Feature
+}
+
+// WriteGeoJSON saves layer as one FeatureCollection of LineStrings. The
+// layer name becomes the collection "name" member that GDAL and QGIS use
+// as the layer name.
+func WriteGeoJSON(filename string, layer Layer) error {
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":"FeatureCollection"`)
+	if layer.Name != "" {
+		buf.WriteString(`,"name":`)
+		writeJSONValue(&buf, layer.Name)
+	}
+	buf.WriteString(`,"features":[`)
+	for i, feature := range layer.Features {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("\n")
+		writeGeoJSONFeature(&buf, feature)
+	}
+	buf.WriteString("\n]}\n")
+
+	if err := os.WriteFile(filename, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write geojson %q: %w", filename, err)
+	}
+	return nil
+}
+
+func writeGeoJSONFeature(buf *bytes.Buffer, feature Feature) {
+	buf.WriteString(`{"type":"Feature","properties":{`)
+	for i, property := range feature.Properties {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONValue(buf, property.Name)
+		buf.WriteByte(':')
+		writeJSONValue(buf, normalizeValue(property.Value))
+	}
+	buf.WriteString(`},"geometry":{"type":"LineString","coordinates":[`)
+	for i, p := range feature.Points {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "[%s,%s]", formatCoordinate(p.Lon), formatCoordinate(p.Lat))
+	}
+	buf.WriteString("]}}")
+}
+
+func writeJSONValue(buf *bytes.Buffer, value any) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		buf.WriteString("null")
+		return
+	}
+	buf.Write(encoded)
+}
+
+// formatCoordinate keeps 1e-9 degree, well below a millimetre.
+func formatCoordinate(value float64) string {
+	return fmt.Sprint(math.Round(value*1e9) / 1e9)
+}
+
+// normalizeValue maps every supported value to nil, int64, float64 or
+// string.
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float32:
+		return normalizeValue(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return v
+	case string:
+		return v
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// bounds is the lon/lat envelope of points.
+func bounds(points []geometry.LatLon) (minLon, minLat, maxLon, maxLat float64) {
+	minLon, minLat = math.Inf(1), math.Inf(1)
+	maxLon, maxLat = math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minLon = math.Min(minLon, p.Lon)
+		maxLon = math.Max(maxLon, p.Lon)
+		minLat = math.Min(minLat, p.Lat)
+		maxLat = math.Max(maxLat, p.Lat)
+	}
+	return minLon, minLat, maxLon, maxLat
+}
@@ -0,0 +1,222 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func zigzag(n int) []geometry.LatLon {
+	points := make([]geometry.LatLon, n)
+	for i := range points {
+		points[i] = geometry.LatLon{Lat: 44 + 0.01*float64(i%2), Lon: 30 + 0.001*float64(i)}
+	}
+	return points
+}
+
+func TestWriteGeoJSONKeepsPropertyOrderAndLonLat(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "koch_iter_1.geojson")
+	err := WriteGeoJSON(filename, Layer{Name: "koch", Features: []Feature{{
+		Points:     []geometry.LatLon{{Lat: 44.5, Lon: 33.25}, {Lat: 45, Lon: 34}},
+		Properties: []Property{{"iteration", 1}, {"length_km", 91.5}, {"dimension", math.NaN()}},
+	}}})
+	if err != nil {
+		t.Fatalf("WriteGeoJSON returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var collection struct {
+		Type     string
+		Name     string
+		Features []struct {
+			Properties map[string]any
+			Geometry   struct {
+				Type        string
+				Coordinates [][2]float64
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatalf("invalid GeoJSON: %v\n%s", err, data)
+	}
+	if collection.Type != "FeatureCollection" || collection.Name != "koch" || len(collection.Features) != 1 {
+		t.Fatalf("unexpected collection %+v", collection)
+	}
+	feature := collection.Features[0]
+	if feature.Geometry.Type != "LineString" || feature.Geometry.Coordinates[0] != [2]float64{33.25, 44.5} {
+		t.Fatalf("expected a lon/lat LineString, got %+v", feature.Geometry)
+	}
+	if value, ok := feature.Properties["dimension"]; !ok || value != nil {
+		t.Fatalf("expected NaN dimension as null, got %v", feature.Properties)
+	}
+	if feature.Properties["iteration"] != 1.0 || feature.Properties["length_km"] != 91.5 {
+		t.Fatalf("unexpected properties %v", feature.Properties)
+	}
+}
+
+func TestWriteGeoPackageWritesOneTablePerLayer(t *testing.T) {
+	// 300 features with a few long ones need overflow pages and interior
+	// b-tree pages.
+	var features []Feature
+	for i := 0; i < 300; i++ {
+		points := zigzag(2 + i%50)
+		if i%100 == 0 {
+			points = zigzag(5000)
+		}
+		features = append(features, Feature{Points: points, Properties: []Property{{"iteration", i}, {"seed", int64(42)}, {"length_km", float64(i) / 2}}})
+	}
+	filename := filepath.Join(t.TempDir(), "koch-organic.gpkg")
+	err := WriteGeoPackage(filename, []Layer{
+		{Name: "koch-organic", Description: "Органическая кривая Коха", Features: features},
+		{Name: "dimension-organic", Features: features[:2]},
+	})
+	if err != nil {
+		t.Fatalf("WriteGeoPackage returned error: %v", err)
+	}
+
+	db, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(db[:16]) != "SQLite format 3\x00" || binary.BigEndian.Uint32(db[68:]) != gpkgApplicationID || binary.BigEndian.Uint32(db[60:]) != gpkgUserVersion {
+		t.Fatalf("expected a GeoPackage 1.2 SQLite header, got % x", db[:100])
+	}
+	if pages := binary.BigEndian.Uint32(db[28:]); int(pages)*sqlitePageSize != len(db) {
+		t.Fatalf("header says %d pages, file has %d bytes", pages, len(db))
+	}
+
+	roots := map[string]int{}
+	for _, entry := range readTestTable(t, db, 1) {
+		roots[entry[1].(string)] = int(entry[3].(int64))
+	}
+	for _, name := range []string{"gpkg_spatial_ref_sys", "gpkg_contents", "gpkg_geometry_columns", "koch_organic", "dimension_organic", "sqlite_sequence", "sqlite_autoindex_gpkg_contents_1"} {
+		if roots[name] == 0 {
+			t.Fatalf("schema has no %s: %v", name, roots)
+		}
+	}
+
+	contents := readTestTable(t, db, roots["gpkg_contents"])
+	if len(contents) != 2 || contents[0][0] != "koch_organic" || contents[0][2] != "koch-organic" || contents[0][9] != int64(gpkgSRSID) {
+		t.Fatalf("unexpected gpkg_contents %v", contents)
+	}
+
+	rows := readTestTable(t, db, roots["koch_organic"])
+	if len(rows) != len(features) {
+		t.Fatalf("expected %d features, got %d", len(features), len(rows))
+	}
+	blob := rows[200][1].([]byte)
+	if string(blob[:2]) != "GP" || binary.LittleEndian.Uint32(blob[4:]) != gpkgSRSID {
+		t.Fatalf("expected a GeoPackage geometry blob, got % x", blob[:8])
+	}
+	wkb := blob[8+32:]
+	if binary.LittleEndian.Uint32(wkb[1:]) != wkbLineString || binary.LittleEndian.Uint32(wkb[5:]) != 5000 {
+		t.Fatalf("expected a 5000 point WKB LineString read across overflow pages")
+	}
+	if rows[7][2] != int64(7) || rows[7][3] != int64(42) || rows[7][4] != 3.5 {
+		t.Fatalf("unexpected attributes %v", rows[7][2:])
+	}
+}
+
+// readTestTable walks a table b-tree and decodes every record.
+func readTestTable(t *testing.T, db []byte, root int) [][]any {
+	t.Helper()
+	pageAt := func(n int) []byte { return db[(n-1)*sqlitePageSize : n*sqlitePageSize] }
+
+	var rows [][]any
+	var walk func(n int)
+	walk = func(n int) {
+		page := pageAt(n)
+		offset := 0
+		if n == 1 {
+			offset = sqliteHeaderSize
+		}
+		kind := page[offset]
+		cells := int(binary.BigEndian.Uint16(page[offset+3:]))
+		header := 8
+		if kind == pageTableInterior {
+			header = 12
+		}
+		for i := 0; i < cells; i++ {
+			at := int(binary.BigEndian.Uint16(page[offset+header+2*i:]))
+			if kind == pageTableInterior {
+				walk(int(binary.BigEndian.Uint32(page[at:])))
+				continue
+			}
+			size, k := testVarint(page[at:])
+			_, r := testVarint(page[at+k:])
+			at += k + r
+			local := localPayloadSize(int(size), sqlitePageSize-35)
+			payload := append([]byte(nil), page[at:at+local]...)
+			next := 0
+			if local < int(size) {
+				next = int(binary.BigEndian.Uint32(page[at+local:]))
+			}
+			for next != 0 {
+				overflow := pageAt(next)
+				payload = append(payload, overflow[4:4+min(int(size)-len(payload), sqlitePageSize-4)]...)
+				next = int(binary.BigEndian.Uint32(overflow))
+			}
+			rows = append(rows, testRecord(payload))
+		}
+		if kind == pageTableInterior {
+			walk(int(binary.BigEndian.Uint32(page[offset+8:])))
+		}
+	}
+	walk(root)
+	return rows
+}
+
+func testVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return v<<8 | uint64(b[8]), 9
+}
+
+func testRecord(payload []byte) []any {
+	headerSize, n := testVarint(payload)
+	body := int(headerSize)
+	var values []any
+	for at := n; at < int(headerSize); {
+		serial, k := testVarint(payload[at:])
+		at += k
+		switch {
+		case serial == 0:
+			values = append(values, nil)
+		case serial == 8 || serial == 9:
+			values = append(values, int64(serial-8))
+		case serial <= 6:
+			size := []int{0, 1, 2, 3, 4, 6, 8}[serial]
+			v := int64(int8(payload[body]))
+			for _, b := range payload[body+1 : body+size] {
+				v = v<<8 | int64(b)
+			}
+			values = append(values, v)
+			body += size
+		case serial == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(payload[body:])))
+			body += 8
+		default:
+			size := int(serial-12) / 2
+			if serial%2 == 0 {
+				values = append(values, append([]byte(nil), payload[body:body+size]...))
+			} else {
+				values = append(values, string(payload[body:body+size]))
+			}
+			body += size
+		}
+	}
+	return values
+}
@@ -0,0 +1,226 @@
+package geo
+
+import (
+	"coastal-geometry/internal/domain/geometry"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GeoPackage 1.2: application_id "GPKG" and user_version 10200.
+const (
+	gpkgApplicationID = 0x47504B47
+	gpkgUserVersion   = 10200
+	gpkgSRSID         = 4326
+	gpkgGeometryCol   = "geom"
+	wkbLineString     = 2
+)
+
+const wgs84Definition = `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`
+
+// Table definitions as given in the GeoPackage specification.
+const (
+	sqlSpatialRefSys = `CREATE TABLE gpkg_spatial_ref_sys (srs_name TEXT NOT NULL, srs_id INTEGER NOT NULL PRIMARY KEY, organization TEXT NOT NULL, organization_coordsys_id INTEGER NOT NULL, definition TEXT NOT NULL, description TEXT)`
+	sqlContents      = `CREATE TABLE gpkg_contents (table_name TEXT NOT NULL PRIMARY KEY, data_type TEXT NOT NULL, identifier TEXT UNIQUE, description TEXT DEFAULT '', last_change DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')), min_x DOUBLE, min_y DOUBLE, max_x DOUBLE, max_y DOUBLE, srs_id INTEGER, CONSTRAINT fk_gc_r_srs_id FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys(srs_id))`
+	sqlGeometryCols  = `CREATE TABLE gpkg_geometry_columns (table_name TEXT NOT NULL, column_name TEXT NOT NULL, geometry_type_name TEXT NOT NULL, srs_id INTEGER NOT NULL, z TINYINT NOT NULL, m TINYINT NOT NULL, CONSTRAINT pk_geom_cols PRIMARY KEY (table_name, column_name), CONSTRAINT uk_gc_table_name UNIQUE (table_name), CONSTRAINT fk_gc_tn FOREIGN KEY (table_name) REFERENCES gpkg_contents(table_name), CONSTRAINT fk_gc_srs FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys (srs_id))`
+	sqlSequence      = `CREATE TABLE sqlite_sequence(name,seq)`
+)
+
+// WriteGeoPackage saves every layer as a LineString feature table in one
+// GeoPackage. Layer names become table names, reduced to lower case
+// letters, digits and underscores; attribute columns follow the property
+// order of the first feature that has them.
+func WriteGeoPackage(filename string, layers []Layer) error {
+	if len(layers) == 0 {
+		return fmt.Errorf("geopackage %q: no layers", filename)
+	}
+	db := newSQLiteDB()
+	db.applicationID = gpkgApplicationID
+	db.userVersion = gpkgUserVersion
+
+	db.createTable("gpkg_spatial_ref_sys", sqlSpatialRefSys, []sqliteRow{
+		{RowID: -1, Values: []any{"Undefined cartesian SRS", nil, "NONE", int64(-1), "undefined", "undefined cartesian coordinate reference system"}},
+		{RowID: 0, Values: []any{"Undefined geographic SRS", nil, "NONE", int64(0), "undefined", "undefined geographic coordinate reference system"}},
+		{RowID: gpkgSRSID, Values: []any{"WGS 84 geodetic", nil, "EPSG", int64(gpkgSRSID), wgs84Definition, "longitude/latitude coordinates in decimal degrees on the WGS 84 spheroid"}},
+	})
+
+	tables := make([]string, len(layers))
+	seen := map[string]bool{}
+	for i, layer := range layers {
+		tables[i] = tableName(layer.Name, i)
+		if seen[tables[i]] {
+			return fmt.Errorf("geopackage %q: duplicate layer %q", filename, tables[i])
+		}
+		seen[tables[i]] = true
+	}
+
+	lastChange := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	contents := make([]sqliteRow, len(layers))
+	columns := make([]sqliteRow, len(layers))
+	rowIDs := make([]int64, len(layers))
+	var contentKeys, identifierKeys, columnKeys, columnTableKeys [][]any
+	for i, layer := range layers {
+		var all []geometry.LatLon
+		for _, feature := range layer.Features {
+			all = append(all, feature.Points...)
+		}
+		var minX, minY, maxX, maxY any
+		if len(all) > 0 {
+			x0, y0, x1, y1 := bounds(all)
+			minX, minY, maxX, maxY = x0, y0, x1, y1
+		}
+		rowIDs[i] = int64(i + 1)
+		contents[i] = sqliteRow{RowID: rowIDs[i], Values: []any{tables[i], "features", layer.Name, layer.Description, lastChange, minX, minY, maxX, maxY, int64(gpkgSRSID)}}
+		columns[i] = sqliteRow{RowID: rowIDs[i], Values: []any{tables[i], gpkgGeometryCol, "LINESTRING", int64(gpkgSRSID), int64(0), int64(0)}}
+		contentKeys = append(contentKeys, []any{tables[i]})
+		identifierKeys = append(identifierKeys, []any{layer.Name})
+		columnKeys = append(columnKeys, []any{tables[i], gpkgGeometryCol})
+		columnTableKeys = append(columnTableKeys, []any{tables[i]})
+	}
+
+	db.createTable("gpkg_contents", sqlContents, contents)
+	if err := db.createAutoIndex("sqlite_autoindex_gpkg_contents_1", "gpkg_contents", rowIDs, contentKeys); err != nil {
+		return err
+	}
+	if err := db.createAutoIndex("sqlite_autoindex_gpkg_contents_2", "gpkg_contents", rowIDs, identifierKeys); err != nil {
+		return err
+	}
+	db.createTable("gpkg_geometry_columns", sqlGeometryCols, columns)
+	if err := db.createAutoIndex("sqlite_autoindex_gpkg_geometry_columns_1", "gpkg_geometry_columns", rowIDs, columnKeys); err != nil {
+		return err
+	}
+	if err := db.createAutoIndex("sqlite_autoindex_gpkg_geometry_columns_2", "gpkg_geometry_columns", rowIDs, columnTableKeys); err != nil {
+		return err
+	}
+
+	var sequence []sqliteRow
+	for i, layer := range layers {
+		names, types := layerColumns(layer)
+		rows := make([]sqliteRow, len(layer.Features))
+		for j, feature := range layer.Features {
+			values := []any{nil, geometryBlob(feature.Points)}
+			for _, name := range names {
+				values = append(values, featureValue(feature, name))
+			}
+			rows[j] = sqliteRow{RowID: int64(j + 1), Values: values}
+		}
+		db.createTable(tables[i], featureTableSQL(tables[i], names, types), rows)
+		sequence = append(sequence, sqliteRow{RowID: int64(i + 1), Values: []any{tables[i], int64(len(rows))}})
+	}
+	// AUTOINCREMENT keeps the last fid of every table in sqlite_sequence.
+	db.createTable("sqlite_sequence", sqlSequence, sequence)
+
+	if err := db.save(filename); err != nil {
+		return fmt.Errorf("write geopackage: %w", err)
+	}
+	return nil
+}
+
+// layerColumns lists the attribute columns of layer with their SQL types.
+func layerColumns(layer Layer) ([]string, []string) {
+	var names, types []string
+	index := map[string]int{}
+	for _, feature := range layer.Features {
+		for _, property := range feature.Properties {
+			value := normalizeValue(property.Value)
+			i, ok := index[property.Name]
+			if !ok {
+				i = len(names)
+				index[property.Name] = i
+				names = append(names, property.Name)
+				types = append(types, "")
+			}
+			if types[i] == "" {
+				types[i] = sqlType(value)
+			}
+		}
+	}
+	for i := range types {
+		if types[i] == "" {
+			types[i] = "TEXT"
+		}
+	}
+	return names, types
+}
+
+func sqlType(value any) string {
+	switch value.(type) {
+	case int64:
+		return "INTEGER"
+	case float64:
+		return "REAL"
+	case string:
+		return "TEXT"
+	default:
+		return ""
+	}
+}
+
+func featureValue(feature Feature, name string) any {
+	for _, property := range feature.Properties {
+		if property.Name == name {
+			return normalizeValue(property.Value)
+		}
+	}
+	return nil
+}
+
+func featureTableSQL(table string, names, types []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `CREATE TABLE "%s" ("fid" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL, "%s" LINESTRING`, table, gpkgGeometryCol)
+	for i, name := range names {
+		fmt.Fprintf(&b, `, "%s" %s`, strings.ReplaceAll(name, `"`, `""`), types[i])
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// tableName turns a layer name into a plain SQL identifier.
+func tableName(name string, index int) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	table := strings.TrimSuffix(b.String(), "_")
+	if table == "" || strings.HasPrefix(table, "gpkg_") || strings.HasPrefix(table, "sqlite_") {
+		table = fmt.Sprintf("layer_%d_%s", index+1, table)
+		table = strings.TrimSuffix(table, "_")
+	} else if table[0] >= '0' && table[0] <= '9' {
+		table = "layer_" + table
+	}
+	return table
+}
+
+// geometryBlob encodes a LineString as GeoPackage binary: the GP header
+// with SRS id and XY envelope, then little-endian WKB.
+func geometryBlob(points []geometry.LatLon) []byte {
+	blob := []byte{'G', 'P', 0, 0x01}
+	if len(points) == 0 {
+		blob[3] |= 0x10 // empty geometry, no envelope
+		blob = binary.LittleEndian.AppendUint32(blob, gpkgSRSID)
+	} else {
+		blob[3] |= 0x02 // envelope [minx, maxx, miny, maxy]
+		blob = binary.LittleEndian.AppendUint32(blob, gpkgSRSID)
+		minLon, minLat, maxLon, maxLat := bounds(points)
+		for _, v := range []float64{minLon, maxLon, minLat, maxLat} {
+			blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(v))
+		}
+	}
+
+	blob = append(blob, 1) // WKB little endian
+	blob = binary.LittleEndian.AppendUint32(blob, wkbLineString)
+	blob = binary.LittleEndian.AppendUint32(blob, uint32(len(points)))
+	for _, p := range points {
+		blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(p.Lon))
+		blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(p.Lat))
+	}
+	return blob
+}
@@ -0,0 +1,443 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// The GeoPackage writer builds the SQLite file directly, page by page, so
+// the module keeps no dependencies. It only ever writes a fresh database:
+// tables are rowid b-trees with overflow pages for long geometries, and the
+// few automatic indexes the GeoPackage schema needs fit on one page.
+//
+// File format: https://www.sqlite.org/fileformat2.html
+
+const (
+	sqlitePageSize      = 4096
+	sqliteHeaderSize    = 100
+	sqliteVersionNumber = 3045000
+
+	pageIndexInterior = 0x02
+	pageTableInterior = 0x05
+	pageIndexLeaf     = 0x0A
+	pageTableLeaf     = 0x0D
+)
+
+// sqliteDB collects pages of a database that is written once. Page 1 holds
+// the file header and the root of sqlite_schema and is filled last.
+type sqliteDB struct {
+	pages         [][]byte
+	schema        [][]any
+	applicationID uint32
+	userVersion   uint32
+}
+
+func newSQLiteDB() *sqliteDB {
+	db := &sqliteDB{}
+	db.alloc() // page 1
+	return db
+}
+
+func (db *sqliteDB) alloc() int {
+	db.pages = append(db.pages, make([]byte, sqlitePageSize))
+	return len(db.pages)
+}
+
+func (db *sqliteDB) page(n int) []byte { return db.pages[n-1] }
+
+// sqliteRow is a table row: its rowid and the column values, with nil for
+// an INTEGER PRIMARY KEY column because SQLite keeps it in the rowid.
+type sqliteRow struct {
+	RowID  int64
+	Values []any
+}
+
+// createTable writes rows as a table b-tree and registers sql in the schema.
+func (db *sqliteDB) createTable(name, sql string, rows []sqliteRow) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].RowID < rows[j].RowID })
+	cells := make([]sqliteCell, len(rows))
+	for i, row := range rows {
+		cells[i] = db.tableLeafCell(row.RowID, encodeRecord(row.Values))
+	}
+	root := db.writeTableTree(cells, 0)
+	db.schema = append(db.schema, []any{"table", name, name, int64(root), sql})
+}
+
+// createAutoIndex writes the index SQLite creates for a PRIMARY KEY or
+// UNIQUE constraint. keys holds the indexed column values of every row.
+func (db *sqliteDB) createAutoIndex(name, table string, rowIDs []int64, keys [][]any) error {
+	records := make([][]any, len(keys))
+	for i, key := range keys {
+		records[i] = append(append([]any(nil), key...), rowIDs[i])
+	}
+	sort.SliceStable(records, func(i, j int) bool { return compareRecords(records[i], records[j]) < 0 })
+
+	root := db.alloc()
+	var cells [][]byte
+	size := 8
+	for _, record := range records {
+		payload := encodeRecord(record)
+		if len(payload) > indexMaxLocal() {
+			return fmt.Errorf("sqlite index %s: key of %d bytes does not fit a page", name, len(payload))
+		}
+		cell := append(putVarint(nil, uint64(len(payload))), payload...)
+		size += len(cell) + 2
+		cells = append(cells, cell)
+	}
+	if size > sqlitePageSize {
+		return fmt.Errorf("sqlite index %s: %d keys do not fit one page", name, len(records))
+	}
+	writeBTreePage(db.page(root), 0, pageIndexLeaf, cells, 0)
+	db.schema = append(db.schema, []any{"index", name, table, int64(root), nil})
+	return nil
+}
+
+// sqliteCell is a b-tree cell with the rowid it is ordered by.
+type sqliteCell struct {
+	Key  int64
+	Data []byte
+}
+
+// tableLeafCell encodes a table leaf cell, moving the payload tail to a
+// chain of overflow pages when it does not fit the page.
+func (db *sqliteDB) tableLeafCell(rowID int64, payload []byte) sqliteCell {
+	cell := putVarint(nil, uint64(len(payload)))
+	cell = putVarint(cell, uint64(rowID))
+	local := localPayloadSize(len(payload), sqlitePageSize-35)
+	cell = append(cell, payload[:local]...)
+	if local < len(payload) {
+		cell = binary.BigEndian.AppendUint32(cell, uint32(db.writeOverflow(payload[local:])))
+	}
+	return sqliteCell{Key: rowID, Data: cell}
+}
+
+func (db *sqliteDB) writeOverflow(data []byte) int {
+	first := 0
+	var prev []byte
+	for len(data) > 0 {
+		n := db.alloc()
+		page := db.page(n)
+		if prev == nil {
+			first = n
+		} else {
+			binary.BigEndian.PutUint32(prev, uint32(n))
+		}
+		chunk := min(len(data), sqlitePageSize-4)
+		copy(page[4:], data[:chunk])
+		data = data[chunk:]
+		prev = page
+	}
+	return first
+}
+
+// localPayloadSize is the part of a payload of size p kept in the cell.
+func localPayloadSize(p, maxLocal int) int {
+	if p <= maxLocal {
+		return p
+	}
+	minLocal := (sqlitePageSize-12)*32/255 - 23
+	local := minLocal + (p-minLocal)%(sqlitePageSize-4)
+	if local <= maxLocal {
+		return local
+	}
+	return minLocal
+}
+
+func indexMaxLocal() int { return (sqlitePageSize-12)*64/255 - 23 }
+
+// writeTableTree packs cells into leaves and builds interior levels above
+// them until one page remains. It returns the root page, which is page 1
+// when root is 1 and a new page otherwise.
+func (db *sqliteDB) writeTableTree(cells []sqliteCell, root int) int {
+	capacity := sqlitePageSize
+	if root == 1 {
+		// Every node is packed for the smaller page 1, so whichever ends up
+		// as the root fits there.
+		capacity -= sqliteHeaderSize
+	}
+
+	level := []treeNode{{}}
+	size := 8
+	for _, cell := range cells {
+		last := &level[len(level)-1]
+		if size+len(cell.Data)+2 > capacity && len(last.cells) > 0 {
+			level = append(level, treeNode{})
+			last = &level[len(level)-1]
+			size = 8
+		}
+		last.cells = append(last.cells, cell)
+		last.maxKey = cell.Key
+		size += len(cell.Data) + 2
+	}
+	pageType := byte(pageTableLeaf)
+
+	// An interior cell is a child page number and a varint key of at most
+	// nine bytes. Children are spread evenly, so every interior node has
+	// at least one cell besides its right pointer.
+	perNode := (capacity-12)/(4+9+2) + 1
+	for len(level) > 1 {
+		groups := (len(level) + perNode - 1) / perNode
+		parent := make([]treeNode, 0, groups)
+		for g, next := 0, 0; g < groups; g++ {
+			count := (len(level) - next) / (groups - g)
+			var node treeNode
+			for _, child := range level[next : next+count] {
+				n := db.alloc()
+				writeTreeNode(db.page(n), 0, pageType, child)
+				if node.right != 0 {
+					cell := binary.BigEndian.AppendUint32(nil, uint32(node.right))
+					node.cells = append(node.cells, sqliteCell{Key: node.maxKey, Data: putVarint(cell, uint64(node.maxKey))})
+				}
+				node.right = n
+				node.maxKey = child.maxKey
+			}
+			parent = append(parent, node)
+			next += count
+		}
+		level = parent
+		pageType = pageTableInterior
+	}
+
+	offset := 0
+	if root == 0 {
+		root = db.alloc()
+	} else if root == 1 {
+		offset = sqliteHeaderSize
+	}
+	writeTreeNode(db.page(root), offset, pageType, level[0])
+	return root
+}
+
+// treeNode is a b-tree page before it is written: its cells, the right
+// pointer of an interior page and the largest rowid below it.
+type treeNode struct {
+	cells  []sqliteCell
+	right  int
+	maxKey int64
+}
+
+func writeTreeNode(page []byte, offset int, pageType byte, node treeNode) {
+	data := make([][]byte, len(node.cells))
+	for i, cell := range node.cells {
+		data[i] = cell.Data
+	}
+	writeBTreePage(page, offset, pageType, data, node.right)
+}
+
+// writeBTreePage lays out a b-tree page: the header at offset, the cell
+// pointer array after it and the cells packed at the end of the page.
+func writeBTreePage(page []byte, offset int, pageType byte, cells [][]byte, right int) {
+	header := 8
+	if pageType == pageTableInterior || pageType == pageIndexInterior {
+		header = 12
+	}
+	content := sqlitePageSize
+	pointers := offset + header
+	for i, cell := range cells {
+		content -= len(cell)
+		copy(page[content:], cell)
+		binary.BigEndian.PutUint16(page[pointers+2*i:], uint16(content))
+	}
+
+	page[offset] = pageType
+	binary.BigEndian.PutUint16(page[offset+1:], 0)
+	binary.BigEndian.PutUint16(page[offset+3:], uint16(len(cells)))
+	binary.BigEndian.PutUint16(page[offset+5:], uint16(content%65536))
+	page[offset+7] = 0
+	if header == 12 {
+		binary.BigEndian.PutUint32(page[offset+8:], uint32(right))
+	}
+}
+
+// save writes sqlite_schema to page 1, fills the file header and writes the
+// database to filename.
+func (db *sqliteDB) save(filename string) error {
+	rows := make([]sqliteRow, len(db.schema))
+	cells := make([]sqliteCell, len(db.schema))
+	for i, entry := range db.schema {
+		rows[i] = sqliteRow{RowID: int64(i + 1), Values: entry}
+		cells[i] = db.tableLeafCell(rows[i].RowID, encodeRecord(entry))
+	}
+	db.writeTableTree(cells, 1)
+
+	header := db.page(1)
+	copy(header, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(header[16:], sqlitePageSize)
+	header[18], header[19] = 1, 1 // rollback journal
+	header[20] = 0                // reserved bytes per page
+	header[21], header[22], header[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(header[24:], 1) // file change counter
+	binary.BigEndian.PutUint32(header[28:], uint32(len(db.pages)))
+	binary.BigEndian.PutUint32(header[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(header[44:], 4) // schema format
+	binary.BigEndian.PutUint32(header[56:], 1) // UTF-8
+	binary.BigEndian.PutUint32(header[60:], db.userVersion)
+	binary.BigEndian.PutUint32(header[68:], db.applicationID)
+	binary.BigEndian.PutUint32(header[92:], 1)
+	binary.BigEndian.PutUint32(header[96:], sqliteVersionNumber)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create sqlite %q: %w", filename, err)
+	}
+	for _, page := range db.pages {
+		if _, err := file.Write(page); err != nil {
+			file.Close()
+			return fmt.Errorf("write sqlite %q: %w", filename, err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write sqlite %q: %w", filename, err)
+	}
+	return nil
+}
+
+// encodeRecord serializes values in the SQLite record format. Values are
+// nil, int64, float64, string or []byte.
+func encodeRecord(values []any) []byte {
+	var types, body []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			types = putVarint(types, 0)
+		case int64:
+			serial, size := integerSerialType(v)
+			types = putVarint(types, serial)
+			for i := size - 1; i >= 0; i-- {
+				body = append(body, byte(v>>(8*i)))
+			}
+		case float64:
+			types = putVarint(types, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		case string:
+			types = putVarint(types, uint64(len(v))*2+13)
+			body = append(body, v...)
+		case []byte:
+			types = putVarint(types, uint64(len(v))*2+12)
+			body = append(body, v...)
+		default:
+			panic(fmt.Sprintf("sqlite record: unsupported value %T", value))
+		}
+	}
+
+	// The header size counts its own varint.
+	headerSize := len(types) + 1
+	if len(putVarint(nil, uint64(headerSize))) > 1 {
+		headerSize = len(types) + len(putVarint(nil, uint64(len(types)+2)))
+	}
+	record := putVarint(nil, uint64(headerSize))
+	record = append(record, types...)
+	return append(record, body...)
+}
+
+// integerSerialType is the smallest integer serial type holding v and the
+// number of body bytes it takes.
+func integerSerialType(v int64) (uint64, int) {
+	switch {
+	case v == 0:
+		return 8, 0
+	case v == 1:
+		return 9, 0
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return 1, 1
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return 2, 2
+	case v >= -1<<23 && v < 1<<23:
+		return 3, 3
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return 4, 4
+	case v >= -1<<47 && v < 1<<47:
+		return 5, 6
+	default:
+		return 6, 8
+	}
+}
+
+// putVarint appends v as a SQLite varint: big-endian groups of seven bits,
+// with all eight bits of the ninth byte used.
+func putVarint(buf []byte, v uint64) []byte {
+	if v > 1<<56-1 {
+		var out [9]byte
+		out[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			out[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(buf, out[:]...)
+	}
+	var out [8]byte
+	n := 0
+	for {
+		out[n] = byte(v & 0x7f)
+		n++
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		b := out[i]
+		if i > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+	}
+	return buf
+}
+
+// compareRecords orders index records with the BINARY collation: NULL,
+// numbers, text, blobs.
+func compareRecords(a, b []any) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareValues(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+func compareValues(a, b any) int {
+	rank := func(v any) int {
+		switch v.(type) {
+		case nil:
+			return 0
+		case int64, float64:
+			return 1
+		case string:
+			return 2
+		default:
+			return 3
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	switch av := a.(type) {
+	case nil:
+		return 0
+	case string:
+		return bytes.Compare([]byte(av), []byte(b.(string)))
+	case []byte:
+		return bytes.Compare(av, b.([]byte))
+	}
+	x, y := toFloat(a), toFloat(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v any) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
@@ -0,0 +1,127 @@
+package svg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+)
+
+const (
+	defaultFrameDelayCS = 80
+	maxPaletteColors    = 256
+	maxRampLevels       = 32
+)
+
+// AnimationOptions configures DrawAnimation. DelayCS is the delay between
+// frames in hundredths of a second; the last frame stays HoldLastCS.
+type AnimationOptions struct {
+	RasterOptions
+	DelayCS    int
+	HoldLastCS int
+}
+
+// DrawAnimation rasterizes docs as frames of one looping GIF. All frames
+// share one view fitted to the layers of every document, and a progress bar
+// marks the current frame.
+func DrawAnimation(docs []Document, filename string, opts AnimationOptions) error {
+	if len(docs) == 0 {
+		return fmt.Errorf("need at least 1 frame to draw animation")
+	}
+	view, err := newRasterView(docs, opts.RasterOptions, true)
+	if err != nil {
+		return err
+	}
+
+	delay := opts.DelayCS
+	if delay <= 0 {
+		delay = defaultFrameDelayCS
+	}
+	hold := opts.HoldLastCS
+	if hold <= 0 {
+		hold = 3 * delay
+	}
+
+	palette := animationPalette(docs)
+	anim := &gif.GIF{LoopCount: 0}
+	for i, doc := range docs {
+		frame := renderFrame(doc, view, i, len(docs))
+		anim.Image = append(anim.Image, quantize(frame, palette))
+		if i == len(docs)-1 {
+			anim.Delay = append(anim.Delay, hold)
+		} else {
+			anim.Delay = append(anim.Delay, delay)
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create gif %q: %w", filename, err)
+	}
+	if err := gif.EncodeAll(file, anim); err != nil {
+		file.Close()
+		return fmt.Errorf("encode gif %q: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write gif %q: %w", filename, err)
+	}
+	return nil
+}
+
+// animationPalette holds the background, the progress bar and a ramp from
+// the background to every stroke color, so anti-aliased edges keep their
+// shade after quantization.
+func animationPalette(docs []Document) color.Palette {
+	background := parseColor(rasterBackground)
+	palette := color.Palette{background, parseColor(progressTrack)}
+
+	seen := map[color.RGBA]bool{}
+	var strokes []color.RGBA
+	addStroke := func(col color.RGBA) {
+		if !seen[col] {
+			seen[col] = true
+			strokes = append(strokes, col)
+		}
+	}
+	addStroke(parseColor(defaultStroke))
+	for _, doc := range docs {
+		for _, layer := range doc.Layers {
+			addStroke(parseColor(layerStroke(layer)))
+		}
+		for _, highlight := range doc.Highlights {
+			addStroke(parseColor(highlightStroke(highlight.Stroke)))
+		}
+	}
+
+	levels := (maxPaletteColors - len(palette)) / len(strokes)
+	levels = max(1, min(levels, maxRampLevels))
+	for _, stroke := range strokes {
+		for level := 1; level <= levels; level++ {
+			if len(palette) == maxPaletteColors {
+				return palette
+			}
+			palette = append(palette, blend(background, stroke, float64(level)/float64(levels)))
+		}
+	}
+	return palette
+}
+
+// quantize maps every pixel to the nearest palette color. Frames are mostly
+// background, so the lookup is cached per color.
+func quantize(img *image.RGBA, palette color.Palette) *image.Paletted {
+	out := image.NewPaletted(img.Rect, palette)
+	cache := map[color.RGBA]uint8{}
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			col := img.RGBAAt(x, y)
+			idx, ok := cache[col]
+			if !ok {
+				idx = uint8(palette.Index(col))
+				cache[col] = idx
+			}
+			out.SetColorIndex(x, y, idx)
+		}
+	}
+	return out
+}
@@ -0,0 +1,228 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const heatmapHeaderNote = "Цвет клетки — среднее значение по прогонам, попавшим в клетку; пустые клетки не посчитаны."
+
+// heatmapPalette runs from low to high values (viridis stops).
+var heatmapPalette = [][3]float64{
+	{68, 1, 84},
+	{59, 82, 139},
+	{33, 145, 140},
+	{94, 201, 98},
+	{253, 231, 37},
+}
+
+// Heatmap colours Values[row][col] over two parameters; row 0 is drawn at
+// the bottom, NaN leaves a cell empty.
+type Heatmap struct {
+	Title    string
+	Subtitle string
+	XLabel   string
+	YLabel   string
+	// XTicks and YTicks label the columns and rows.
+	XTicks     []string
+	YTicks     []string
+	Values     [][]float64
+	ValueLabel string
+	// ValueFormat prints cell values, e.g. "%.3f"; empty means "%.2f".
+	ValueFormat string
+	StatCards   []StatCard
+	Meta        []string
+}
+
+func DrawHeatmap(h Heatmap, filename string) error {
+	if len(h.Values) == 0 || len(h.Values[0]) == 0 {
+		return fmt.Errorf("need at least 1 cell to draw heatmap")
+	}
+	rows, cols := len(h.Values), len(h.Values[0])
+	low, high, ok := heatmapBounds(h.Values)
+	if !ok {
+		return fmt.Errorf("heatmap %q has no values", h.Title)
+	}
+	format := h.ValueFormat
+	if format == "" {
+		format = "%.2f"
+	}
+
+	plotWidth := float64(canvasWidth) - sidebarWidth - 2*padding
+	header, headerBottom := buildHeaderWithNote(h.Title, h.Subtitle, heatmapHeaderNote, padding, plotWidth)
+	gridX := padding + 84
+	gridY := headerBottom + 36
+	gridWidth := plotWidth - 96
+	gridHeight := float64(canvasHeight) - gridY - padding - 64
+	cellWidth := gridWidth / float64(cols)
+	cellHeight := gridHeight / float64(rows)
+
+	var cells strings.Builder
+	for row, values := range h.Values {
+		y := gridY + float64(rows-1-row)*cellHeight
+		for col, value := range values {
+			x := gridX + float64(col)*cellWidth
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				cells.WriteString(fmt.Sprintf(
+					`    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#ece7db" stroke="#fcfbf7" stroke-width="1.5"/>`+"\n",
+					x, y, cellWidth, cellHeight,
+				))
+				continue
+			}
+			t := heatmapPosition(value, low, high)
+			cells.WriteString(fmt.Sprintf(
+				`    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" stroke="#fcfbf7" stroke-width="1.5"/>`+"\n",
+				x, y, cellWidth, cellHeight, heatmapColor(t),
+			))
+			if cellWidth >= 48 && cellHeight >= 22 {
+				textColor := "#fcfbf7"
+				if t > 0.6 {
+					textColor = "#16324f"
+				}
+				cells.WriteString(fmt.Sprintf(
+					`    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="%s">%s</text>`+"\n",
+					x+cellWidth/2, y+cellHeight/2+4, textColor, escapeText(fmt.Sprintf(format, value)),
+				))
+			}
+		}
+	}
+
+	var axes strings.Builder
+	for col, tick := range h.XTicks {
+		if col >= cols {
+			break
+		}
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="#4f6d7a">%s</text>`+"\n",
+			gridX+(float64(col)+0.5)*cellWidth, gridY+gridHeight+20, escapeText(tick),
+		))
+	}
+	for row, tick := range h.YTicks {
+		if row >= rows {
+			break
+		}
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="end" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="#4f6d7a">%s</text>`+"\n",
+			gridX-10, gridY+(float64(rows-1-row)+0.5)*cellHeight+4, escapeText(tick),
+		))
+	}
+	axes.WriteString(fmt.Sprintf(
+		`    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">%s</text>`+"\n",
+		gridX+gridWidth/2, gridY+gridHeight+48, escapeText(h.XLabel),
+	))
+	if h.YLabel != "" {
+		labelX, labelY := padding+14, gridY+gridHeight/2
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="middle" transform="rotate(-90 %.2f %.2f)" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">%s</text>`+"\n",
+			labelX, labelY, labelX, labelY, escapeText(h.YLabel),
+		))
+	}
+
+	sidebarX := padding + plotWidth + 28
+	colorBar, colorBarBottom := buildColorBar(h.ValueLabel, low, high, format, sidebarX, headerBottom+34, sidebarWidth-56)
+	statCards, statCardsBottom := buildStatCards(h.StatCards, sidebarX, colorBarBottom+20, sidebarWidth-56)
+	meta, metaBottom := buildMetaCard(h.Meta, sidebarX, statCardsBottom+20, sidebarWidth-56)
+
+	documentHeight := max(canvasHeight, int(math.Ceil(metaBottom+padding)))
+
+	svg := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
+  <rect width="100%%" height="100%%" fill="#f7f4ea"/>
+  <rect x="20" y="20" width="%d" height="%d" rx="28" fill="#fcfbf7" stroke="#d6d0c4"/>
+  <rect x="%.0f" y="20" width="%.0f" height="%d" rx="24" fill="#f0ece2" stroke="#d6d0c4"/>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+</svg>
+`, canvasWidth, documentHeight, canvasWidth, documentHeight,
+		canvasWidth-40, documentHeight-40,
+		padding+plotWidth+8, sidebarWidth-16, documentHeight-40,
+		header,
+		cells.String(),
+		axes.String(),
+		colorBar,
+		statCards,
+		meta,
+	)
+
+	if err := os.WriteFile(filename, []byte(svg), 0o644); err != nil {
+		return fmt.Errorf("write svg %q: %w", filename, err)
+	}
+	return nil
+}
+
+func buildColorBar(label string, low, high float64, format string, x, y, width float64) (string, float64) {
+	const height = 92.0
+	barX, barY, barWidth := x+14, y+36, width-28
+	steps := 24
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf(
+		`    <rect x="%.0f" y="%.0f" width="%.0f" height="%.0f" rx="18" fill="#fcfbf7" stroke="#d6d0c4"/>`+"\n",
+		x, y, width, height,
+	))
+	out.WriteString(fmt.Sprintf(
+		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">%s</text>`+"\n",
+		x+14, y+20, escapeText(label),
+	))
+	for i := 0; i < steps; i++ {
+		out.WriteString(fmt.Sprintf(
+			`    <rect x="%.2f" y="%.0f" width="%.2f" height="16" fill="%s"/>`+"\n",
+			barX+float64(i)*barWidth/float64(steps), barY, barWidth/float64(steps)+0.5, heatmapColor((float64(i)+0.5)/float64(steps)),
+		))
+	}
+	out.WriteString(fmt.Sprintf(
+		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%s</text>`+"\n",
+		barX, barY+32, escapeText(fmt.Sprintf(format, low)),
+	))
+	out.WriteString(fmt.Sprintf(
+		`    <text x="%.0f" y="%.0f" text-anchor="end" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%s</text>`+"\n",
+		barX+barWidth, barY+32, escapeText(fmt.Sprintf(format, high)),
+	))
+	return out.String(), y + height
+}
+
+func heatmapBounds(values [][]float64) (low, high float64, ok bool) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, row := range values {
+		for _, value := range row {
+			if !isFinite(value) {
+				continue
+			}
+			low, high = math.Min(low, value), math.Max(high, value)
+			ok = true
+		}
+	}
+	return low, high, ok
+}
+
+func heatmapPosition(value, low, high float64) float64 {
+	if high == low {
+		return 0.5
+	}
+	return (value - low) / (high - low)
+}
+
+func heatmapColor(t float64) string {
+	t = math.Max(0, math.Min(1, t))
+	scaled := t * float64(len(heatmapPalette)-1)
+	i := min(int(scaled), len(heatmapPalette)-2)
+	f := scaled - float64(i)
+	from, to := heatmapPalette[i], heatmapPalette[i+1]
+	return fmt.Sprintf("#%02x%02x%02x",
+		int(math.Round(from[0]+(to[0]-from[0])*f)),
+		int(math.Round(from[1]+(to[1]-from[1])*f)),
+		int(math.Round(from[2]+(to[2]-from[2])*f)),
+	)
+}
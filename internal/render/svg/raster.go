@@ -0,0 +1,281 @@
+package svg
+
+import (
+	"coastal-geometry/internal/domain/geometry"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultRasterWidth  = 960
+	defaultRasterHeight = 600
+	rasterPadding       = 24.0
+	progressBarHeight   = 6
+	rasterBackground    = "#fcfbf7"
+	progressTrack       = "#ddd6c8"
+)
+
+// RasterOptions sizes raster frames; zero values use 960×600 pixels.
+type RasterOptions struct {
+	Width  int
+	Height int
+}
+
+func (o RasterOptions) size() (int, int) {
+	width, height := o.Width, o.Height
+	if width <= 0 {
+		width = defaultRasterWidth
+	}
+	if height <= 0 {
+		height = defaultRasterHeight
+	}
+	return width, height
+}
+
+// rasterView maps lat/lon to pixels the same way DrawDocument does: the
+// projected points fitted into the frame. One view is shared by all frames of
+// an animation so that the coastline does not jump between frames.
+type rasterView struct {
+	mapView
+	width, height   int
+	progressReserve float64
+}
+
+func newRasterView(docs []Document, opts RasterOptions, progress bool) (rasterView, error) {
+	var points []geometry.LatLon
+	for _, doc := range docs {
+		points = append(points, flattenLayers(doc.Layers)...)
+	}
+	if len(points) < 2 {
+		return rasterView{}, fmt.Errorf("need at least 2 points to rasterize")
+	}
+
+	width, height := opts.size()
+	view := rasterView{width: width, height: height}
+	if progress {
+		view.progressReserve = progressBarHeight + rasterPadding/2
+	}
+
+	plotWidth := float64(width) - 2*rasterPadding
+	plotHeight := float64(height) - 2*rasterPadding - view.progressReserve
+	view.mapView = newMapView(docs[0].Projection, points, rasterPadding, rasterPadding, plotWidth, plotHeight)
+	return view, nil
+}
+
+func (v rasterView) project(p geometry.LatLon) rasterPoint {
+	x, y := v.pixel(p)
+	return rasterPoint{X: x, Y: y}
+}
+
+type rasterPoint struct {
+	X, Y float64
+}
+
+// RenderRaster draws the layers and highlights of doc into an image. Text
+// parts of the document (title, legend, charts) are not rasterized.
+func RenderRaster(doc Document, opts RasterOptions) (*image.RGBA, error) {
+	view, err := newRasterView([]Document{doc}, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	return renderFrame(doc, view, -1, 0), nil
+}
+
+// renderFrame draws doc with the given view. With total > 0 a progress bar
+// at the bottom shows frame index out of total.
+func renderFrame(doc Document, view rasterView, index, total int) *image.RGBA {
+	c := newCanvas(view.width, view.height)
+	c.fill(image.Rect(0, 0, view.width, view.height), parseColor(rasterBackground))
+
+	for _, layer := range doc.Layers {
+		style := strokeStyle{
+			color:   parseColor(layerStroke(layer)),
+			width:   layerWidth(layer),
+			opacity: layerOpacity(layer),
+			dash:    parseDash(layer.DashArray),
+		}
+		for _, points := range layerPolylines(layer) {
+			c.stroke(projectRaster(view, points), style)
+		}
+	}
+	for _, highlight := range doc.Highlights {
+		c.stroke(projectRaster(view, []geometry.LatLon{highlight.Start, highlight.End}), strokeStyle{
+			color:   parseColor(highlightStroke(highlight.Stroke)),
+			width:   highlightWidth(highlight.StrokeWidth),
+			opacity: highlightOpacity(highlight.Opacity),
+		})
+	}
+
+	if total > 0 {
+		top := view.height - int(rasterPadding/2) - progressBarHeight
+		left, right := int(rasterPadding), view.width-int(rasterPadding)
+		c.fill(image.Rect(left, top, right, top+progressBarHeight), parseColor(progressTrack))
+		done := left + int(math.Round(float64(right-left)*float64(index+1)/float64(total)))
+		c.fill(image.Rect(left, top, done, top+progressBarHeight), parseColor(defaultStroke))
+	}
+	return c.img
+}
+
+func projectRaster(view rasterView, points []geometry.LatLon) []rasterPoint {
+	out := make([]rasterPoint, len(points))
+	for i, p := range points {
+		out[i] = view.project(p)
+	}
+	return out
+}
+
+type strokeStyle struct {
+	color   color.RGBA
+	width   float64
+	opacity float64
+	dash    []float64
+}
+
+// canvas keeps a per-stroke coverage buffer so that the joints of one
+// polyline are not blended twice.
+type canvas struct {
+	img      *image.RGBA
+	coverage []float32
+}
+
+func newCanvas(width, height int) *canvas {
+	return &canvas{
+		img:      image.NewRGBA(image.Rect(0, 0, width, height)),
+		coverage: make([]float32, width*height),
+	}
+}
+
+func (c *canvas) fill(rect image.Rectangle, col color.RGBA) {
+	rect = rect.Intersect(c.img.Rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c.img.SetRGBA(x, y, col)
+		}
+	}
+}
+
+// stroke rasterizes a polyline with anti-aliasing: the coverage of a pixel
+// is how far its center lies inside the stroke, clamped to [0, 1], and the
+// stroke is composited once with the maximum coverage of its segments.
+func (c *canvas) stroke(points []rasterPoint, style strokeStyle) {
+	if len(points) < 2 {
+		return
+	}
+	half := style.width / 2
+	width, height := c.img.Rect.Dx(), c.img.Rect.Dy()
+	dirty := image.Rectangle{}
+	walked := 0.0
+
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		length2 := dx*dx + dy*dy
+		length := math.Sqrt(length2)
+
+		box := image.Rect(
+			int(math.Floor(math.Min(a.X, b.X)-half-1)),
+			int(math.Floor(math.Min(a.Y, b.Y)-half-1)),
+			int(math.Ceil(math.Max(a.X, b.X)+half+1)),
+			int(math.Ceil(math.Max(a.Y, b.Y)+half+1)),
+		).Intersect(image.Rect(0, 0, width, height))
+		if box.Empty() {
+			walked += length
+			continue
+		}
+		dirty = dirty.Union(box)
+
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			py := float64(y) + 0.5
+			for x := box.Min.X; x < box.Max.X; x++ {
+				px := float64(x) + 0.5
+				t := 0.0
+				if length2 > 0 {
+					t = math.Max(0, math.Min(1, ((px-a.X)*dx+(py-a.Y)*dy)/length2))
+				}
+				distance := math.Hypot(px-(a.X+t*dx), py-(a.Y+t*dy))
+				cover := math.Max(0, math.Min(1, half+0.5-distance))
+				if cover == 0 || !dashOn(style.dash, walked+t*length) {
+					continue
+				}
+				if idx := y*width + x; float32(cover) > c.coverage[idx] {
+					c.coverage[idx] = float32(cover)
+				}
+			}
+		}
+		walked += length
+	}
+
+	for y := dirty.Min.Y; y < dirty.Max.Y; y++ {
+		for x := dirty.Min.X; x < dirty.Max.X; x++ {
+			idx := y*width + x
+			if cover := c.coverage[idx]; cover > 0 {
+				c.img.SetRGBA(x, y, blend(c.img.RGBAAt(x, y), style.color, float64(cover)*style.opacity))
+				c.coverage[idx] = 0
+			}
+		}
+	}
+}
+
+func dashOn(dash []float64, position float64) bool {
+	if len(dash) == 0 {
+		return true
+	}
+	period := 0.0
+	for _, d := range dash {
+		period += d
+	}
+	if period <= 0 {
+		return true
+	}
+	position = math.Mod(position, period)
+	for i, d := range dash {
+		if position < d {
+			return i%2 == 0
+		}
+		position -= d
+	}
+	return true
+}
+
+func blend(dst, src color.RGBA, alpha float64) color.RGBA {
+	mix := func(d, s uint8) uint8 {
+		return uint8(math.Round(float64(d) + (float64(s)-float64(d))*alpha))
+	}
+	return color.RGBA{R: mix(dst.R, src.R), G: mix(dst.G, src.G), B: mix(dst.B, src.B), A: 255}
+}
+
+// parseColor reads #rgb and #rrggbb colors; anything else falls back to the
+// default stroke.
+func parseColor(value string) color.RGBA {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+		}
+	}
+	return color.RGBA{R: 0x1f, G: 0x6f, B: 0x8b, A: 255}
+}
+
+// parseDash reads an SVG stroke-dasharray in pixels.
+func parseDash(value string) []float64 {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' })
+	var dash []float64
+	for _, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil || v < 0 {
+			return nil
+		}
+		dash = append(dash, v)
+	}
+	if len(dash)%2 == 1 {
+		dash = append(dash, dash...)
+	}
+	return dash
+}
@@ -0,0 +1,88 @@
+package svg
+
+import (
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"coastal-geometry/internal/domain/geometry"
+)
+
+func horizontalLineDoc(stroke, dash string) Document {
+	return Document{
+		Layers: []Layer{
+			{Points: []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 10}}, Stroke: "#888888", StrokeWidth: 1},
+			{Points: []geometry.LatLon{{Lat: 5, Lon: 0}, {Lat: 5, Lon: 10}}, Stroke: stroke, StrokeWidth: 3, DashArray: dash},
+			{Points: []geometry.LatLon{{Lat: 10, Lon: 0}, {Lat: 10, Lon: 10}}, Stroke: "#888888", StrokeWidth: 1},
+		},
+	}
+}
+
+func TestRenderRasterAntiAliasesStrokeEdges(t *testing.T) {
+	img, err := RenderRaster(horizontalLineDoc("#ff0000", ""), RasterOptions{Width: 200, Height: 200})
+	if err != nil {
+		t.Fatalf("RenderRaster returned error: %v", err)
+	}
+
+	// The middle line runs through y = 100 of the 200×200 frame.
+	red := color.RGBA{R: 255, A: 255}
+	if got := img.RGBAAt(100, 99); got != red {
+		t.Fatalf("expected the stroke center to be fully red, got %+v", got)
+	}
+	edge := img.RGBAAt(100, 101)
+	if edge == red || edge == parseColor(rasterBackground) {
+		t.Fatalf("expected a partially covered edge pixel, got %+v", edge)
+	}
+	if got := img.RGBAAt(100, 60); got != parseColor(rasterBackground) {
+		t.Fatalf("expected background away from the lines, got %+v", got)
+	}
+}
+
+func TestRenderRasterLeavesDashGapsEmpty(t *testing.T) {
+	img, err := RenderRaster(horizontalLineDoc("#ff0000", "10 10"), RasterOptions{Width: 200, Height: 200})
+	if err != nil {
+		t.Fatalf("RenderRaster returned error: %v", err)
+	}
+
+	// The line starts at x = 24: pixels 24..33 are a dash, 34..43 a gap.
+	if got := img.RGBAAt(28, 99); got.R != 255 || got.G != 0 {
+		t.Fatalf("expected a dash at x=28, got %+v", got)
+	}
+	if got := img.RGBAAt(39, 99); got != parseColor(rasterBackground) {
+		t.Fatalf("expected a gap at x=39, got %+v", got)
+	}
+}
+
+func TestDrawAnimationWritesLoopingGIF(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "series.gif")
+	docs := []Document{
+		horizontalLineDoc("#ff0000", ""),
+		horizontalLineDoc("#00aa00", "6 4"),
+		horizontalLineDoc("#0000ff", ""),
+	}
+	if err := DrawAnimation(docs, filename, AnimationOptions{RasterOptions: RasterOptions{Width: 160, Height: 120}, DelayCS: 50}); err != nil {
+		t.Fatalf("DrawAnimation returned error: %v", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("expected gif file: %v", err)
+	}
+	defer file.Close()
+	anim, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("invalid gif: %v", err)
+	}
+
+	if len(anim.Image) != 3 || anim.LoopCount != 0 {
+		t.Fatalf("expected 3 looping frames, got %d frames, loop %d", len(anim.Image), anim.LoopCount)
+	}
+	if anim.Delay[0] != 50 || anim.Delay[2] != 150 {
+		t.Fatalf("expected 0.5 s frames and a held last frame, got %v", anim.Delay)
+	}
+	if bounds := anim.Image[0].Bounds(); bounds.Dx() != 160 || bounds.Dy() != 120 {
+		t.Fatalf("unexpected frame size %v", bounds)
+	}
+}
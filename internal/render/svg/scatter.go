@@ -0,0 +1,250 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const scatterDiagonalNote = "Диагональ y = x — точное совпадение; точки ниже неё занижают величину, выше — завышают."
+
+// scatterPalette colours the series in order and wraps around.
+var scatterPalette = []string{"#1f6f8b", "#c06c3f", "#8b3f5c", "#4d8b31", "#b5651d", "#5b4e9e", "#444444"}
+
+// Scatter plots Y against X for several series. Series are nudged apart
+// horizontally by a few pixels, so equal X values stay visible side by side.
+type Scatter struct {
+	Title    string
+	Subtitle string
+	XLabel   string
+	YLabel   string
+	Series   []ScatterSeries
+	// Diagonal draws y = x and puts both axes on the same range.
+	Diagonal  bool
+	StatCards []StatCard
+	Meta      []string
+}
+
+// ScatterSeries is one set of points; NaN skips a point. Err is the
+// half-width of a vertical whisker, nil or NaN draws none.
+type ScatterSeries struct {
+	Label string
+	X     []float64
+	Y     []float64
+	Err   []float64
+	// Fill is the marker colour; empty picks the next palette colour.
+	Fill string
+}
+
+func DrawScatter(s Scatter, filename string) error {
+	xLow, xHigh, yLow, yHigh, ok := scatterBounds(s.Series)
+	if !ok {
+		return fmt.Errorf("scatter %q has no points", s.Title)
+	}
+	if s.Diagonal {
+		xLow, yLow = math.Min(xLow, yLow), math.Min(xLow, yLow)
+		xHigh, yHigh = math.Max(xHigh, yHigh), math.Max(xHigh, yHigh)
+	}
+	xLow, xHigh = padRange(xLow, xHigh)
+	yLow, yHigh = padRange(yLow, yHigh)
+
+	note := ""
+	if s.Diagonal {
+		note = scatterDiagonalNote
+	}
+	plotWidth := float64(canvasWidth) - sidebarWidth - 2*padding
+	header, headerBottom := buildHeaderWithNote(s.Title, s.Subtitle, note, padding, plotWidth)
+	areaX := padding + 84
+	areaY := headerBottom + 36
+	areaWidth := plotWidth - 96
+	areaHeight := float64(canvasHeight) - areaY - padding - 64
+	px := func(x float64) float64 { return areaX + (x-xLow)/(xHigh-xLow)*areaWidth }
+	py := func(y float64) float64 { return areaY + areaHeight - (y-yLow)/(yHigh-yLow)*areaHeight }
+
+	var axes strings.Builder
+	axes.WriteString(fmt.Sprintf(
+		`    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="#fcfbf7" stroke="#8a9aa6" stroke-width="1.4"/>`+"\n",
+		areaX, areaY, areaWidth, areaHeight,
+	))
+	for _, tick := range scatterTicks(xLow, xHigh) {
+		axes.WriteString(fmt.Sprintf(
+			`    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#ddd6c8" stroke-width="1"/>`+"\n",
+			px(tick), areaY, px(tick), areaY+areaHeight,
+		))
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="#4f6d7a">%s</text>`+"\n",
+			px(tick), areaY+areaHeight+20, escapeText(formatChartValue(tick)),
+		))
+	}
+	for _, tick := range scatterTicks(yLow, yHigh) {
+		axes.WriteString(fmt.Sprintf(
+			`    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#ddd6c8" stroke-width="1"/>`+"\n",
+			areaX, py(tick), areaX+areaWidth, py(tick),
+		))
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="end" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="#4f6d7a">%s</text>`+"\n",
+			areaX-10, py(tick)+4, escapeText(formatChartValue(tick)),
+		))
+	}
+	if s.Diagonal {
+		low, high := math.Max(xLow, yLow), math.Min(xHigh, yHigh)
+		axes.WriteString(fmt.Sprintf(
+			`    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#6b7a87" stroke-width="1.6" stroke-dasharray="6 5"/>`+"\n",
+			px(low), py(low), px(high), py(high),
+		))
+	}
+	axes.WriteString(fmt.Sprintf(
+		`    <text x="%.2f" y="%.2f" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">%s</text>`+"\n",
+		areaX+areaWidth/2, areaY+areaHeight+48, escapeText(s.XLabel),
+	))
+	if s.YLabel != "" {
+		labelX, labelY := padding+14, areaY+areaHeight/2
+		axes.WriteString(fmt.Sprintf(
+			`    <text x="%.2f" y="%.2f" text-anchor="middle" transform="rotate(-90 %.2f %.2f)" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">%s</text>`+"\n",
+			labelX, labelY, labelX, labelY, escapeText(s.YLabel),
+		))
+	}
+
+	var points strings.Builder
+	for i, series := range s.Series {
+		fill := scatterFill(series, i)
+		dodge := (float64(i) - float64(len(s.Series)-1)/2) * 5
+		for j := range series.X {
+			if j >= len(series.Y) || !isFinite(series.X[j]) || !isFinite(series.Y[j]) {
+				continue
+			}
+			x, y := px(series.X[j])+dodge, py(series.Y[j])
+			if j < len(series.Err) && isFinite(series.Err[j]) && series.Err[j] > 0 {
+				points.WriteString(fmt.Sprintf(
+					`    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="1.2" opacity="0.7"/>`+"\n",
+					x, py(series.Y[j]+series.Err[j]), x, py(series.Y[j]-series.Err[j]), escapeText(fill),
+				))
+			}
+			points.WriteString(fmt.Sprintf(
+				`    <circle cx="%.2f" cy="%.2f" r="4.5" fill="%s" fill-opacity="0.85" stroke="#fcfbf7" stroke-width="1"/>`+"\n",
+				x, y, escapeText(fill),
+			))
+		}
+	}
+
+	sidebarX := padding + plotWidth + 28
+	legend, legendBottom := buildScatterLegend(s.Series, sidebarX, headerBottom+34, sidebarWidth-56)
+	statCards, statCardsBottom := buildStatCards(s.StatCards, sidebarX, legendBottom+20, sidebarWidth-56)
+	meta, metaBottom := buildMetaCard(s.Meta, sidebarX, statCardsBottom+20, sidebarWidth-56)
+
+	documentHeight := max(canvasHeight, int(math.Ceil(metaBottom+padding)))
+
+	svg := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
+  <rect width="100%%" height="100%%" fill="#f7f4ea"/>
+  <rect x="20" y="20" width="%d" height="%d" rx="28" fill="#fcfbf7" stroke="#d6d0c4"/>
+  <rect x="%.0f" y="20" width="%.0f" height="%d" rx="24" fill="#f0ece2" stroke="#d6d0c4"/>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+  <g>
+%s  </g>
+</svg>
+`, canvasWidth, documentHeight, canvasWidth, documentHeight,
+		canvasWidth-40, documentHeight-40,
+		padding+plotWidth+8, sidebarWidth-16, documentHeight-40,
+		header,
+		axes.String(),
+		points.String(),
+		legend,
+		statCards,
+		meta,
+	)
+
+	if err := os.WriteFile(filename, []byte(svg), 0o644); err != nil {
+		return fmt.Errorf("write svg %q: %w", filename, err)
+	}
+	return nil
+}
+
+func buildScatterLegend(series []ScatterSeries, x, y, width float64) (string, float64) {
+	if len(series) == 0 {
+		return "", y
+	}
+	height := 42 + float64(len(series))*20
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf(
+		`    <rect x="%.0f" y="%.0f" width="%.0f" height="%.0f" rx="18" fill="#fcfbf7" stroke="#d6d0c4"/>`+"\n",
+		x, y, width, height,
+	))
+	out.WriteString(fmt.Sprintf(
+		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="14" font-weight="700" fill="#16324f">Серии</text>`+"\n",
+		x+14, y+20,
+	))
+	for i, s := range series {
+		rowY := y + 42 + float64(i)*20
+		out.WriteString(fmt.Sprintf(
+			`    <circle cx="%.0f" cy="%.0f" r="5" fill="%s"/>`+"\n",
+			x+20, rowY, escapeText(scatterFill(s, i)),
+		))
+		out.WriteString(fmt.Sprintf(
+			`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="12" fill="#4f6d7a">%s</text>`+"\n",
+			x+34, rowY+4, escapeText(s.Label),
+		))
+	}
+	return out.String(), y + height
+}
+
+func scatterFill(series ScatterSeries, index int) string {
+	if series.Fill != "" {
+		return series.Fill
+	}
+	return scatterPalette[index%len(scatterPalette)]
+}
+
+func scatterBounds(series []ScatterSeries) (xLow, xHigh, yLow, yHigh float64, ok bool) {
+	xLow, yLow = math.Inf(1), math.Inf(1)
+	xHigh, yHigh = math.Inf(-1), math.Inf(-1)
+	for _, s := range series {
+		for j := range s.X {
+			if j >= len(s.Y) || !isFinite(s.X[j]) || !isFinite(s.Y[j]) {
+				continue
+			}
+			xLow, xHigh = math.Min(xLow, s.X[j]), math.Max(xHigh, s.X[j])
+			yLow, yHigh = math.Min(yLow, s.Y[j]), math.Max(yHigh, s.Y[j])
+			ok = true
+		}
+	}
+	return xLow, xHigh, yLow, yHigh, ok
+}
+
+// padRange widens [low, high] by 5% on each side, or by 0.5 when it is a
+// single value.
+func padRange(low, high float64) (float64, float64) {
+	if high == low {
+		return low - 0.5, high + 0.5
+	}
+	pad := (high - low) * 0.05
+	return low - pad, high + pad
+}
+
+// scatterTicks returns about five round values inside [low, high].
+func scatterTicks(low, high float64) []float64 {
+	raw := (high - low) / 5
+	step := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{2, 5, 10} {
+		if step*1.5 >= raw {
+			break
+		}
+		step = math.Pow(10, math.Floor(math.Log10(raw))) * m
+	}
+	var ticks []float64
+	for v := math.Ceil(low/step) * step; v <= high+step*1e-9; v += step {
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	return ticks
+}
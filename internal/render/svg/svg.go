@@ -2,6 +2,7 @@ package svg
 
 import (
 	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
 	"fmt"
 	"math"
 	"os"
@@ -19,8 +20,10 @@ const (
 )
 
 type Layer struct {
-	Label       string
-	Points      []geometry.LatLon
+	Label  string
+	Points []geometry.LatLon
+	// Parts are extra polylines drawn with the layer style, e.g. islands or inner rings.
+	Parts       [][]geometry.LatLon
 	LengthKM    float64
 	Stroke      string
 	StrokeWidth float64
@@ -41,11 +44,18 @@ type ChartSeries struct {
 	Values    []float64
 	Stroke    string
 	DashArray string
+	// Lower and Upper, when set, bound a translucent band drawn under the
+	// line, such as a confidence interval; NaN leaves a gap.
+	Lower []float64
+	Upper []float64
 }
 
 type Chart struct {
 	Title  string
 	Series []ChartSeries
+	// XMinLabel and XMaxLabel replace the default index labels on the x axis.
+	XMinLabel string
+	XMaxLabel string
 }
 
 type StatItem struct {
@@ -68,6 +78,9 @@ type Document struct {
 	Charts     []Chart
 	Alerts     []string
 	Meta       []string
+	// Projection maps layers onto the canvas; nil means the default
+	// projection centred on the layers.
+	Projection projection.Projector
 }
 
 func DrawSVG(points []geometry.LatLon, filename, title string) error {
@@ -96,45 +109,31 @@ func DrawDocument(doc Document, filename string) error {
 		return fmt.Errorf("need at least 2 points to draw svg")
 	}
 
-	minLat, maxLat, minLon, maxLon := bounds(allPoints)
-	lonSpan := maxLon - minLon
-	latSpan := maxLat - minLat
-	if lonSpan == 0 {
-		lonSpan = 1
-	}
-	if latSpan == 0 {
-		latSpan = 1
-	}
-
 	plotWidth := float64(canvasWidth) - sidebarWidth - 2*padding
 	header, headerBottom := buildHeader(doc.Title, doc.Subtitle, padding, plotWidth)
 	plotTopY := headerBottom + 24
 	plotHeight := float64(canvasHeight) - plotTopY - padding
-	scale := math.Min(plotWidth/lonSpan, plotHeight/latSpan)
-	contentWidth := lonSpan * scale
-	contentHeight := latSpan * scale
-	originX := padding + (plotWidth-contentWidth)/2
-	originY := plotTopY + (plotHeight-contentHeight)/2
+	view := newMapView(doc.Projection, allPoints, padding, plotTopY, plotWidth, plotHeight)
 
 	var layers strings.Builder
 	for _, layer := range doc.Layers {
-		polyline := projectPolyline(layer.Points, minLat, minLon, originX, originY, contentHeight, scale)
-		layers.WriteString(fmt.Sprintf(
-			`    <polyline fill="none" stroke="%s" stroke-width="%.2f" stroke-opacity="%.2f" stroke-linejoin="round" stroke-linecap="round"%s points="%s"/>`+"\n",
-			escapeText(layerStroke(layer)),
-			layerWidth(layer),
-			layerOpacity(layer),
-			layerDashAttribute(layer),
-			polyline,
-		))
+		for _, points := range layerPolylines(layer) {
+			polyline := projectPolyline(points, view)
+			layers.WriteString(fmt.Sprintf(
+				`    <polyline fill="none" stroke="%s" stroke-width="%.2f" stroke-opacity="%.2f" stroke-linejoin="round" stroke-linecap="round"%s points="%s"/>`+"\n",
+				escapeText(layerStroke(layer)),
+				layerWidth(layer),
+				layerOpacity(layer),
+				layerDashAttribute(layer),
+				polyline,
+			))
+		}
 	}
 
 	var highlights strings.Builder
 	for _, highlight := range doc.Highlights {
-		x1 := originX + (highlight.Start.Lon-minLon)*scale
-		y1 := originY + contentHeight - (highlight.Start.Lat-minLat)*scale
-		x2 := originX + (highlight.End.Lon-minLon)*scale
-		y2 := originY + contentHeight - (highlight.End.Lat-minLat)*scale
+		x1, y1 := view.pixel(highlight.Start)
+		x2, y2 := view.pixel(highlight.End)
 
 		highlights.WriteString(fmt.Sprintf(
 			`    <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="%.2f" stroke-opacity="%.2f" stroke-linecap="round"/>`+"\n",
@@ -174,7 +173,7 @@ func DrawDocument(doc Document, filename string) error {
 		documentHeight = requiredHeight
 	}
 
-	scaleBar := buildScaleBar(minLat, maxLat, minLon, maxLon, plotWidth, scale, padding, float64(documentHeight)-padding-scaleBarYGap)
+	scaleBar := buildScaleBar(view, plotWidth, padding, float64(documentHeight)-padding-scaleBarYGap)
 
 	svg := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
@@ -224,21 +223,93 @@ func DrawDocument(doc Document, filename string) error {
 func flattenLayers(layers []Layer) []geometry.LatLon {
 	total := 0
 	for _, layer := range layers {
-		total += len(layer.Points)
+		for _, points := range layerPolylines(layer) {
+			total += len(points)
+		}
 	}
 
 	points := make([]geometry.LatLon, 0, total)
 	for _, layer := range layers {
-		points = append(points, layer.Points...)
+		for _, part := range layerPolylines(layer) {
+			points = append(points, part...)
+		}
 	}
 	return points
 }
 
-func projectPolyline(points []geometry.LatLon, minLat, minLon, originX, originY, contentHeight, scale float64) string {
+func layerPolylines(layer Layer) [][]geometry.LatLon {
+	polylines := make([][]geometry.LatLon, 0, len(layer.Parts)+1)
+	if len(layer.Points) > 0 {
+		polylines = append(polylines, layer.Points)
+	}
+	for _, part := range layer.Parts {
+		if len(part) > 1 {
+			polylines = append(polylines, part)
+		}
+	}
+	return polylines
+}
+
+// mapView fits the projected points of a drawing into a pixel rectangle,
+// keeping one scale for both axes.
+type mapView struct {
+	proj          projection.Projector
+	minX, minY    float64
+	spanX, spanY  float64
+	scale         float64
+	originX       float64
+	originY       float64
+	contentHeight float64
+}
+
+func newMapView(proj projection.Projector, points []geometry.LatLon, x, y, width, height float64) mapView {
+	view := mapView{proj: proj}
+	if view.proj == nil {
+		view.proj, _ = geometry.ProjectionFor(projection.Default, points)
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		px, py := view.proj.Forward(p.Lat, p.Lon)
+		minX, maxX = math.Min(minX, px), math.Max(maxX, px)
+		minY, maxY = math.Min(minY, py), math.Max(maxY, py)
+	}
+	spanX := math.Max(maxX-minX, 1e-3)
+	spanY := math.Max(maxY-minY, 1e-3)
+
+	view.minX, view.minY = minX, minY
+	view.spanX, view.spanY = spanX, spanY
+	view.scale = math.Min(width/spanX, height/spanY)
+	view.contentHeight = spanY * view.scale
+	view.originX = x + (width-spanX*view.scale)/2
+	view.originY = y + (height-view.contentHeight)/2
+	return view
+}
+
+func (v mapView) pixel(p geometry.LatLon) (float64, float64) {
+	px, py := v.proj.Forward(p.Lat, p.Lon)
+	return v.originX + (px-v.minX)*v.scale, v.originY + v.contentHeight - (py-v.minY)*v.scale
+}
+
+// groundKMPerPixel measures the ground distance across one pixel at the
+// centre of the view, so the scale bar stays true in any projection.
+func (v mapView) groundKMPerPixel() float64 {
+	if v.scale <= 0 || math.IsInf(v.scale, 0) {
+		return 0
+	}
+	cx := v.minX + v.spanX/2
+	cy := v.minY + v.spanY/2
+	const step = 10.0
+	lat1, lon1 := v.proj.Inverse(cx-step/v.scale/2, cy)
+	lat2, lon2 := v.proj.Inverse(cx+step/v.scale/2, cy)
+	return geometry.Haversine(geometry.LatLon{Lat: lat1, Lon: lon1}, geometry.LatLon{Lat: lat2, Lon: lon2}) / step
+}
+
+func projectPolyline(points []geometry.LatLon, view mapView) string {
 	var polyline strings.Builder
 	for i, point := range points {
-		x := originX + (point.Lon-minLon)*scale
-		y := originY + contentHeight - (point.Lat-minLat)*scale
+		x, y := view.pixel(point)
 		if i > 0 {
 			polyline.WriteByte(' ')
 		}
@@ -248,13 +319,17 @@ func projectPolyline(points []geometry.LatLon, minLat, minLon, originX, originY,
 }
 
 func buildHeader(title, subtitle string, x, width float64) (string, float64) {
+	return buildHeaderWithNote(title, subtitle, defaultHeaderNote, x, width)
+}
+
+func buildHeaderWithNote(title, subtitle, note string, x, width float64) (string, float64) {
 	titleLines := wrapText(title, estimateCharLimit(width, 30))
 	if len(titleLines) == 0 {
 		titleLines = []string{title}
 	}
 
 	subtitleLines := wrapText(subtitle, estimateCharLimit(width, 14))
-	noteLines := wrapText(defaultHeaderNote, estimateCharLimit(width, 13))
+	noteLines := wrapText(note, estimateCharLimit(width, 13))
 
 	var out strings.Builder
 	titleY := 58.0
@@ -614,15 +689,31 @@ func buildChart(chart Chart, x, y, width, height float64) string {
 		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%s</text>`+"\n",
 		plotX, plotY+plotHeight+14, escapeText(bottomLabel),
 	))
+	xMinLabel := "0"
+	if chart.XMinLabel != "" {
+		xMinLabel = chart.XMinLabel
+	}
+	xMaxLabel := fmt.Sprintf("%d", max(maxLen-1, 0))
+	if chart.XMaxLabel != "" {
+		xMaxLabel = chart.XMaxLabel
+	}
 	out.WriteString(fmt.Sprintf(
-		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">0</text>`+"\n",
-		plotX, y+height-10,
+		`    <text x="%.0f" y="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%s</text>`+"\n",
+		plotX, y+height-10, escapeText(xMinLabel),
 	))
 	out.WriteString(fmt.Sprintf(
-		`    <text x="%.0f" y="%.0f" text-anchor="end" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%d</text>`+"\n",
-		plotX+plotWidth, y+height-10, max(maxLen-1, 0),
+		`    <text x="%.0f" y="%.0f" text-anchor="end" font-family="Helvetica, Arial, sans-serif" font-size="11" fill="#6b7a87">%s</text>`+"\n",
+		plotX+plotWidth, y+height-10, escapeText(xMaxLabel),
 	))
 
+	for _, series := range chart.Series {
+		for _, band := range chartBands(series.Lower, series.Upper, minValue, maxValue, plotX, plotY, plotWidth, plotHeight, maxLen) {
+			out.WriteString(fmt.Sprintf(
+				`    <polygon fill="%s" fill-opacity="0.18" stroke="none" points="%s"/>`+"\n",
+				escapeText(chartStroke(series.Stroke)), band,
+			))
+		}
+	}
 	for _, series := range chart.Series {
 		polyline, points := chartPolyline(series.Values, minValue, maxValue, plotX, plotY, plotWidth, plotHeight)
 		if len(points) == 0 {
@@ -686,12 +777,57 @@ func chartPolyline(values []float64, minValue, maxValue, plotX, plotY, plotWidth
 	return polyline.String(), points
 }
 
+// chartBands returns one polygon per run of indices where both bounds are
+// finite; a single-index run becomes a short vertical bar.
+func chartBands(lower, upper []float64, minValue, maxValue, plotX, plotY, plotWidth, plotHeight float64, length int) []string {
+	n := min(len(lower), len(upper))
+	if n == 0 {
+		return nil
+	}
+	denominator := max(length-1, 1)
+	valueSpan := maxValue - minValue
+	if valueSpan <= 0 {
+		valueSpan = 1
+	}
+	project := func(i int, value float64) string {
+		x := plotX + plotWidth*float64(i)/float64(denominator)
+		y := plotY + plotHeight - (value-minValue)/valueSpan*plotHeight
+		return fmt.Sprintf("%.2f,%.2f", x, y)
+	}
+
+	var bands []string
+	for start := 0; start < n; {
+		if !isFinite(lower[start]) || !isFinite(upper[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end+1 < n && isFinite(lower[end+1]) && isFinite(upper[end+1]) {
+			end++
+		}
+		var points []string
+		for i := start; i <= end; i++ {
+			points = append(points, project(i, upper[i]))
+		}
+		for i := end; i >= start; i-- {
+			points = append(points, project(i, lower[i]))
+		}
+		if start == end {
+			points = append(points, project(start, upper[start]))
+		}
+		bands = append(bands, strings.Join(points, " "))
+		start = end + 1
+	}
+	return bands
+}
+
 func chartBounds(series []ChartSeries) (minValue, maxValue float64, maxLen int, ok bool) {
 	for _, line := range series {
 		if len(line.Values) > maxLen {
 			maxLen = len(line.Values)
 		}
-		for _, value := range line.Values {
+		values := append(append(append([]float64(nil), line.Values...), line.Lower...), line.Upper...)
+		for _, value := range values {
 			if !isFinite(value) {
 				continue
 			}
@@ -854,18 +990,12 @@ func runeCount(value string) int {
 	return len([]rune(value))
 }
 
-func buildScaleBar(minLat, maxLat, minLon, maxLon, plotWidth, scale, x, y float64) string {
-	centerLat := (minLat + maxLat) / 2
-	centerLon := (minLon + maxLon) / 2
-	kmPerLonDegree := geometry.Haversine(
-		geometry.LatLon{Lat: centerLat, Lon: centerLon},
-		geometry.LatLon{Lat: centerLat, Lon: centerLon + 1},
-	)
-	if kmPerLonDegree <= 0 || scale <= 0 {
+func buildScaleBar(view mapView, plotWidth, x, y float64) string {
+	kmPerPixel := view.groundKMPerPixel()
+	if kmPerPixel <= 0 {
 		return ""
 	}
 
-	kmPerPixel := kmPerLonDegree / scale
 	targetKM := kmPerPixel * plotWidth * 0.22
 	scaleKM := niceScaleLength(targetKM)
 	barPixels := scaleKM / kmPerPixel
@@ -951,28 +1081,6 @@ func highlightOpacity(opacity float64) float64 {
 	return opacity
 }
 
-func bounds(points []geometry.LatLon) (minLat, maxLat, minLon, maxLon float64) {
-	minLat, maxLat = points[0].Lat, points[0].Lat
-	minLon, maxLon = points[0].Lon, points[0].Lon
-
-	for _, point := range points[1:] {
-		if point.Lat < minLat {
-			minLat = point.Lat
-		}
-		if point.Lat > maxLat {
-			maxLat = point.Lat
-		}
-		if point.Lon < minLon {
-			minLon = point.Lon
-		}
-		if point.Lon > maxLon {
-			maxLon = point.Lon
-		}
-	}
-
-	return minLat, maxLat, minLon, maxLon
-}
-
 func escapeText(value string) string {
 	replacer := strings.NewReplacer(
 		"&", "&amp;",
@@ -1,6 +1,7 @@
 package svg
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -110,3 +111,162 @@ func TestWrapTextRespectsLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestDrawDocumentDrawsLayerParts(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "parts.svg")
+
+	err := DrawDocument(Document{
+		Title: "Части",
+		Layers: []Layer{
+			{
+				Label:  "Береговая линия",
+				Points: []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}},
+				Parts: [][]geometry.LatLon{
+					{{Lat: 0.5, Lon: 0.2}, {Lat: 0.6, Lon: 0.3}, {Lat: 0.5, Lon: 0.2}},
+					{{Lat: 0.8, Lon: 0.7}, {Lat: 0.9, Lon: 0.8}},
+				},
+				LengthKM: 150,
+			},
+		},
+	}, filename)
+	if err != nil {
+		t.Fatalf("DrawDocument returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+
+	if count := strings.Count(string(content), `<polyline fill="none"`); count != 3 {
+		t.Fatalf("expected 3 layer polylines (main + 2 parts), got %d", count)
+	}
+}
+
+func TestDrawDocumentShadesConfidenceBands(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bands.svg")
+
+	nan := math.NaN()
+	err := DrawDocument(Document{
+		Title: "Интервалы",
+		Layers: []Layer{
+			{Label: "Кривая", Points: []geometry.LatLon{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}}, LengthKM: 111},
+		},
+		Charts: []Chart{
+			{
+				Title: "Размерность по итерациям",
+				Series: []ChartSeries{
+					{
+						Label:  "Box counting",
+						Values: []float64{1, 1.1, 1.2, 1.25},
+						Lower:  []float64{0.95, nan, 1.15, 1.2},
+						Upper:  []float64{1.05, nan, 1.25, 1.3},
+						Stroke: "#000000",
+					},
+					{Label: "Теория", Values: []float64{1, 1.13, 1.2, 1.26}, Stroke: "#ff0000"},
+				},
+			},
+		},
+	}, filename)
+	if err != nil {
+		t.Fatalf("DrawDocument returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+
+	svg := string(content)
+	// The missing interval of iteration 1 splits the band in two; the
+	// series without bounds adds none.
+	if count := strings.Count(svg, `fill-opacity="0.18"`); count != 2 {
+		t.Fatalf("expected 2 band polygons, got %d", count)
+	}
+	if strings.Index(svg, `<polygon fill="#000000" fill-opacity="0.18"`) > strings.Index(svg, `<polyline fill="none" stroke="#000000"`) {
+		t.Fatal("expected the band to be drawn under its series line")
+	}
+}
+
+func TestDrawHeatmapColorsCellsAndLeavesGaps(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "heatmap.svg")
+
+	err := DrawHeatmap(Heatmap{
+		Title:       "Размерность D",
+		XLabel:      "angle-jitter",
+		YLabel:      "height-jitter",
+		XTicks:      []string{"0", "15", "30"},
+		YTicks:      []string{"0.1", "0.3"},
+		Values:      [][]float64{{1.20, 1.25, 1.30}, {1.22, math.NaN(), 1.34}},
+		ValueLabel:  "D",
+		ValueFormat: "%.3f",
+		Meta:        []string{"Прогонов: 5"},
+	}, filename)
+	if err != nil {
+		t.Fatalf("DrawHeatmap returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+	svg := string(content)
+	for _, expected := range []string{"angle-jitter", "height-jitter", "1.340", heatmapColor(0), heatmapColor(1), `fill="#ece7db"`, "Прогонов: 5"} {
+		if !strings.Contains(svg, expected) {
+			t.Fatalf("expected heatmap svg to contain %q", expected)
+		}
+	}
+
+	if err := DrawHeatmap(Heatmap{Values: [][]float64{{math.NaN()}}}, filename); err == nil {
+		t.Fatal("expected a heatmap without values to be rejected")
+	}
+}
+
+func TestDrawScatterPlotsSeriesAndDiagonal(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "scatter.svg")
+
+	err := DrawScatter(Scatter{
+		Title:  "Оценка D против истинной",
+		XLabel: "истинная D",
+		YLabel: "оценка D",
+		Series: []ScatterSeries{
+			{Label: "box", X: []float64{1.26, 1.5}, Y: []float64{1.25, 1.42}, Err: []float64{0.01, math.NaN()}},
+			{Label: "mass-radius", X: []float64{1.26, 1.5}, Y: []float64{1.19, math.NaN()}},
+		},
+		Diagonal: true,
+		Meta:     []string{"Ориентаций сетки: 4"},
+	}, filename)
+	if err != nil {
+		t.Fatalf("DrawScatter returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+	svg := string(content)
+	for _, expected := range []string{"истинная D", "mass-radius", scatterPalette[0], scatterPalette[1], `stroke-dasharray="6 5"`, "Ориентаций сетки: 4"} {
+		if !strings.Contains(svg, expected) {
+			t.Fatalf("expected scatter svg to contain %q", expected)
+		}
+	}
+	if markers := strings.Count(svg, `r="4.5"`); markers != 3 {
+		t.Fatalf("expected 3 markers without the NaN point, got %d", markers)
+	}
+
+	if err := DrawScatter(Scatter{Series: []ScatterSeries{{X: []float64{1}, Y: []float64{math.NaN()}}}}, filename); err == nil {
+		t.Fatal("expected a scatter without points to be rejected")
+	}
+}
+
+func TestScatterTicksAreRound(t *testing.T) {
+	ticks := scatterTicks(0.97, 2.03)
+	if len(ticks) < 4 || len(ticks) > 8 || ticks[0] < 0.97 || ticks[len(ticks)-1] > 2.03 {
+		t.Fatalf("expected a handful of ticks inside the range, got %v", ticks)
+	}
+	for _, tick := range ticks {
+		if math.Abs(tick*10-math.Round(tick*10)) > 1e-9 {
+			t.Fatalf("expected round ticks, got %v", ticks)
+		}
+	}
+}
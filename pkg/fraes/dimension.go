@@ -0,0 +1,47 @@
+package fraes
+
+import "coastal-geometry/internal/domain/fractal"
+
+// Dimension estimators accepted by EstimateDimensionWith.
+const (
+	EstimatorBox          = fractal.EstimatorBox
+	EstimatorBoxFilled    = fractal.EstimatorBoxFilled
+	EstimatorMassRadius   = fractal.EstimatorMassRadius
+	EstimatorInformation  = fractal.EstimatorInformation
+	EstimatorCorrelation  = fractal.EstimatorCorrelation
+	EstimatorMultifractal = fractal.EstimatorMultifractal
+)
+
+const (
+	// DefaultBootstrapReplicates keeps the interval of
+	// BootstrapBoxCountingWith stable to about 0.005 in D on the bundled
+	// coastline.
+	DefaultBootstrapReplicates = fractal.DefaultBootstrapReplicates
+	// DefaultQRange is the q range of the multifractal spectrum in the form
+	// read by ParseQRange.
+	DefaultQRange = fractal.DefaultQRange
+)
+
+// EstimatorNames lists the estimators accepted by EstimateDimensionWith in
+// report order.
+func EstimatorNames() []string {
+	return append([]string(nil), fractal.Estimators...)
+}
+
+// ParseQRange reads "min:max:step" or a comma separated list of q values.
+func ParseQRange(spec string) ([]float64, error) {
+	return fractal.ParseQRange(spec)
+}
+
+// AnalyzeRichardson walks the polyline with geodesic dividers of decreasing
+// length and fits log L(ε) against log ε; the divider dimension is
+// 1 - slope.
+func AnalyzeRichardson(points []LatLon) RichardsonAnalysis {
+	return fractal.AnalyzeRichardson(points)
+}
+
+// DividerWalk returns the pivots of the divider walk with an opening of
+// rulerKM, starting at the first vertex.
+func DividerWalk(points []LatLon, rulerKM float64) []LatLon {
+	return fractal.DividerWalk(points, rulerKM)
+}
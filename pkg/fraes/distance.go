@@ -0,0 +1,97 @@
+package fraes
+
+import "coastal-geometry/internal/domain/geometry"
+
+// Distance is a strategy measuring the shortest path between two points in
+// kilometers.
+type Distance = geometry.Distance
+
+// Ellipsoid is a reference ellipsoid: equatorial radius A in meters and
+// flattening F.
+type Ellipsoid = geometry.Ellipsoid
+
+// Names of the distance strategies accepted by NewDistance.
+const (
+	GeodesicHaversine = geometry.DistanceHaversine
+	GeodesicVincenty  = geometry.DistanceVincenty
+	GeodesicKarney    = geometry.DistanceKarney
+)
+
+// WGS84 is the ellipsoid of GPS and the default of NewDistance and
+// NewAreaMeasure.
+func WGS84() Ellipsoid { return geometry.WGS84 }
+
+// GRS80 differs from WGS84 by 0.1 mm in the polar radius.
+func GRS80() Ellipsoid { return geometry.GRS80 }
+
+// Krassovsky1940 is the ellipsoid of the Soviet and Russian maps.
+func Krassovsky1940() Ellipsoid { return geometry.Krassovsky1940 }
+
+// EllipsoidByName returns WGS84, GRS80 or Krassovsky1940, ignoring case.
+func EllipsoidByName(name string) (Ellipsoid, error) {
+	return geometry.EllipsoidByName(name)
+}
+
+// DistanceOption configures NewDistance.
+type DistanceOption func(*Ellipsoid)
+
+// WithEllipsoid selects the reference ellipsoid of vincenty and karney.
+// Defaults to WGS84.
+func WithEllipsoid(ellipsoid Ellipsoid) DistanceOption {
+	return func(e *Ellipsoid) { *e = ellipsoid }
+}
+
+// NewDistance builds the haversine, vincenty or karney strategy. Karney's
+// method converges everywhere; Vincenty falls back to it for nearly
+// antipodal points.
+func NewDistance(method string, opts ...DistanceOption) (Distance, error) {
+	ellipsoid := WGS84()
+	for _, opt := range opts {
+		opt(&ellipsoid)
+	}
+	return geometry.NewDistance(method, ellipsoid)
+}
+
+// DistanceEllipsoid reports the ellipsoid of the vincenty and karney
+// strategies; haversine has none.
+func DistanceEllipsoid(d Distance) (Ellipsoid, bool) {
+	return geometry.DistanceEllipsoid(d)
+}
+
+// PolylineLengthWith is PolylineLength measured with d; nil means haversine.
+func PolylineLengthWith(points []LatLon, d Distance) float64 {
+	return geometry.PolylineLengthWith(points, d)
+}
+
+// AreaMeasure is a strategy measuring the area enclosed by a ring in square
+// kilometers, independent of winding order.
+type AreaMeasure = geometry.AreaMeasure
+
+// Names of the area measures accepted by NewAreaMeasure.
+const (
+	AreaPlanar      = geometry.AreaPlanar
+	AreaSpherical   = geometry.AreaSpherical
+	AreaEllipsoidal = geometry.AreaEllipsoidal
+)
+
+// NewAreaMeasure builds the planar (Area), spherical excess or ellipsoidal
+// (geodesic polygon, WGS84 by default) measure.
+func NewAreaMeasure(method string, opts ...DistanceOption) (AreaMeasure, error) {
+	ellipsoid := WGS84()
+	for _, opt := range opts {
+		opt(&ellipsoid)
+	}
+	return geometry.NewAreaMeasure(method, ellipsoid)
+}
+
+// AreaEllipsoid reports the ellipsoid of the ellipsoidal measure; planar and
+// spherical have none.
+func AreaEllipsoid(m AreaMeasure) (Ellipsoid, bool) {
+	return geometry.AreaEllipsoid(m)
+}
+
+// PolygonArea is the area of outer minus its holes measured with m; nil
+// means planar.
+func PolygonArea(outer []LatLon, holes [][]LatLon, m AreaMeasure) float64 {
+	return geometry.PolygonAreaWith(outer, holes, m)
+}
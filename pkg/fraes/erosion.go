@@ -0,0 +1,19 @@
+package fraes
+
+import "coastal-geometry/internal/domain/geometry"
+
+// Erode shifts every point by Gaussian noise with a standard deviation of
+// strength meters in the plane of p; nil means the default projection
+// centred on the points. Closed rings stay closed and equal seeds give
+// equal results; seed 0 draws a new one.
+func Erode(points []LatLon, strength float64, seed int64, p Projector) []LatLon {
+	return geometry.ErodeProjected(points, strength, seed, p)
+}
+
+// ErosionStep applies step number step of a seeded erosion run, so callers
+// can process the line between steps and still get the same noise for the
+// same seed. weights scale the displacement of every point; missing weights
+// default to 1.
+func ErosionStep(points []LatLon, strength float64, seed int64, step int, weights []float64, p Projector) []LatLon {
+	return geometry.ErosionStep(points, strength, seed, step, weights, p)
+}
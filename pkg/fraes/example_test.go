@@ -0,0 +1,159 @@
+package fraes_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"coastal-geometry/pkg/fraes"
+)
+
+var segment = []fraes.LatLon{
+	{Lat: 44.0, Lon: 30.0},
+	{Lat: 44.0, Lon: 31.0},
+}
+
+var square = []fraes.LatLon{
+	{Lat: 44.0, Lon: 30.0},
+	{Lat: 44.0, Lon: 31.0},
+	{Lat: 45.0, Lon: 31.0},
+	{Lat: 45.0, Lon: 30.0},
+	{Lat: 44.0, Lon: 30.0},
+}
+
+func ExampleLoad() {
+	result, err := fraes.Load(fraes.WithLocalPath("../../data/black-sea.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: %d points, %.0f km\n", result.DatasetName, len(result.Points), fraes.PolylineLength(result.Points))
+	// Output:
+	// black-sea.json: 15 points, 2104 km
+}
+
+func ExampleLoad_rings() {
+	result, err := fraes.Load(fraes.WithLocalPath("../../data/black-sea.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var main fraes.Ring
+	for _, part := range result.Coastline.Parts {
+		for _, ring := range part.Rings {
+			if ring.Main {
+				main = ring
+			}
+		}
+	}
+	fmt.Printf("%s ring, %d points, closed=%t\n", main.Role, len(main.Points), main.Role != fraes.RingLine)
+	// Output:
+	// line ring, 15 points, closed=false
+}
+
+func ExampleInspectSource() {
+	dir, err := os.MkdirTemp("", "fraes-snapshot")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	inspection, err := fraes.InspectSource(
+		fraes.WithLocalPath("../../data/black-sea.json"),
+		fraes.WithSnapshotPath(filepath.Join(dir, "black-sea.json")),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s, %d points\n", inspection.Metadata.Format, inspection.Metadata.CoastlinePointCount)
+	// Output:
+	// point-array, 15 points
+}
+
+func ExamplePolylineLength() {
+	fmt.Printf("%.1f km\n", fraes.PolylineLength(segment))
+	// Output:
+	// 80.0 km
+}
+
+func ExampleArea() {
+	fmt.Printf("%.0f km²\n", fraes.Area(square))
+	// Output:
+	// 8834 km²
+}
+
+func ExampleSimplifyPolyline() {
+	curve := fraes.KochCurve(segment, 3)
+	result := fraes.SimplifyPolyline(curve, fraes.WithMaxPoints(20))
+	fmt.Printf("%d -> %d points, applied=%t\n", result.OriginalCount, result.SimplifiedCount, result.Applied)
+	// Output:
+	// 65 -> 19 points, applied=true
+}
+
+func ExampleAnalyzeBoxCounting() {
+	analysis := fraes.AnalyzeBoxCounting(fraes.KochCurve(segment, 6))
+	fmt.Printf("valid=%t D=%.2f\n", analysis.Valid, analysis.Dimension)
+	// Output:
+	// valid=true D=1.27
+}
+
+func ExampleKochCurve() {
+	for iteration := 0; iteration <= 3; iteration++ {
+		curve := fraes.KochCurve(segment, iteration)
+		fmt.Printf("%d: %d points, %.1f km\n", iteration, len(curve), fraes.PolylineLength(curve))
+	}
+	// Output:
+	// 0: 2 points, 80.0 km
+	// 1: 5 points, 122.8 km
+	// 2: 17 points, 169.1 km
+	// 3: 65 points, 227.2 km
+}
+
+func ExampleOrganicKochCurve() {
+	curve := fraes.OrganicKochCurve(segment, 3,
+		fraes.WithSeed(42),
+		fraes.WithAngleJitter(12),
+		fraes.WithHeightJitter(0.15),
+	)
+	fmt.Printf("%d points, %.1f km\n", len(curve), fraes.PolylineLength(curve))
+	// Output:
+	// 65 points, 213.4 km
+}
+
+func ExampleNewDistance() {
+	karney, err := fraes.NewDistance(fraes.GeodesicKarney, fraes.WithEllipsoid(fraes.WGS84()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("sphere %.3f km, WGS84 %.3f km\n", fraes.PolylineLength(segment), fraes.PolylineLengthWith(segment, karney))
+	// Output:
+	// sphere 79.986 km, WGS84 80.206 km
+}
+
+func ExampleNewAreaMeasure() {
+	for _, method := range []string{fraes.AreaPlanar, fraes.AreaSpherical, fraes.AreaEllipsoidal} {
+		m, err := fraes.NewAreaMeasure(method)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s %.0f km²\n", m.Name(), m.Area(square))
+	}
+	// Output:
+	// planar 8834 km²
+	// spherical 8819 km²
+	// ellipsoidal 8837 km²
+}
+
+func ExampleNewProjection() {
+	for _, name := range fraes.ProjectionNames() {
+		p, err := fraes.NewProjection(name, square)
+		if err != nil {
+			log.Fatal(err)
+		}
+		x, y := p.Forward(square[0].Lat, square[0].Lon)
+		fmt.Printf("%s: %.0f, %.0f m\n", p, x, y)
+	}
+	// Output:
+	// UTM 36N: 259474, 4876249 m
+	// LAEA 44.50°, 30.50°: -40103, -55437 m
+	// Web Mercator: 3339585, 5465442 m
+}
@@ -0,0 +1,104 @@
+// Package fraes is the stable public API of the coastline toolkit: loading
+// coastlines, measuring them on the sphere, estimating the fractal
+// dimension and generating Koch curves.
+//
+// Data types are aliases of the internal domain types, so values returned
+// here can be passed back to any function of the package without copying.
+// Every type reachable through a field or result is re-exported here, so
+// callers outside the module can name all of them. Shared defaults are
+// constants or functions returning a fresh copy; no package variable can
+// be reassigned to change the behaviour for other callers.
+package fraes
+
+import (
+	"coastal-geometry/internal/domain/coastline"
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/generators/koch"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+// LatLon is a point in degrees.
+type LatLon = geometry.LatLon
+
+// Coastline is a loaded dataset split into named parts. A Part holds the
+// rings of one feature; Ring.Role tells outer boundaries, holes and open
+// lines apart and Ring.Main marks the ring analysed as a single polyline.
+type (
+	Coastline = coastline.Coastline
+	Part      = coastline.Part
+	Ring      = coastline.Ring
+	RingRole  = coastline.RingRole
+)
+
+// Roles of a ring within its part.
+const (
+	RingOuter = coastline.RingOuter
+	RingInner = coastline.RingInner
+	RingLine  = coastline.RingLine
+)
+
+// GeoBounds is a latitude/longitude box in degrees.
+type GeoBounds = coastline.GeoBounds
+
+// ValidationReport lists the fixes applied to the raw points and the
+// problems that were left as warnings.
+type ValidationReport = coastline.ValidationReport
+
+// LoadResult is a loaded coastline. Points is the main ring of Coastline.
+type LoadResult = coastline.LoadResult
+
+// SourceInspection describes a coastline source without parsing it into a
+// polyline; SourceMetadata holds what was read from the payload.
+type (
+	SourceInspection = coastline.SourceInspection
+	SourceMetadata   = coastline.SourceMetadata
+)
+
+// SimplifyResult is a simplified polyline with the tolerance that produced it.
+type SimplifyResult = geometry.SimplifyResult
+
+// BoxCountingAnalysis is the box-counting dimension estimate together with
+// the per-scale samples and the regression quality.
+type (
+	BoxCountingAnalysis = fractal.BoxCountingAnalysis
+	BoxCountingSample   = fractal.BoxCountingSample
+)
+
+// DimensionEstimate is the result of one dimension estimator;
+// MultifractalAnalysis is the D(q) and f(α) spectrum.
+type (
+	DimensionEstimate    = fractal.DimensionEstimate
+	MultifractalAnalysis = fractal.MultifractalAnalysis
+	MultifractalPoint    = fractal.MultifractalPoint
+)
+
+// ConfidenceInterval is the bootstrap interval of a box-counting estimate.
+type ConfidenceInterval = fractal.ConfidenceInterval
+
+// RichardsonAnalysis is the divider (ruler) estimate of the dimension;
+// every RichardsonSample reports the forward and backward walks of one
+// ruler.
+type (
+	RichardsonAnalysis = fractal.RichardsonAnalysis
+	RichardsonSample   = fractal.RichardsonSample
+)
+
+const (
+	// DefaultLocalPath is the coastline file read when no path is given.
+	DefaultLocalPath = coastline.DefaultCoastlineJSONPath
+	// DefaultSnapshotDir is where InspectSource saves snapshots by default.
+	DefaultSnapshotDir = coastline.DefaultCoastlineSnapshotDir
+	// MaxKochIterations caps the iterations of the Koch generators.
+	MaxKochIterations = koch.MaxIterations
+)
+
+// DefaultSourceURL is the Marine Regions request for the Black Sea outline.
+// Load and InspectSource stay offline unless it is passed to WithRemoteURL.
+func DefaultSourceURL() string {
+	return coastline.DefaultCoastlineGeoJSONURL
+}
+
+// DefaultBlackSeaBounds keeps only the Black Sea rings of the default source.
+func DefaultBlackSeaBounds() GeoBounds {
+	return coastline.DefaultBlackSeaBounds
+}
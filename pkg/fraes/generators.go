@@ -0,0 +1,110 @@
+package fraes
+
+import "coastal-geometry/internal/domain/generators/koch"
+
+// Generator replaces every segment of a polyline by a scaled copy of its
+// motif, iteration after iteration; GeneratorOptions configures NewGenerator.
+type (
+	Generator        = koch.Generator
+	GeneratorOptions = koch.GeneratorOptions
+)
+
+// Deterministic curves accepted by NewGenerator.
+const (
+	GeneratorKoch       = koch.GeneratorKoch
+	GeneratorQuadratic1 = koch.GeneratorQuadratic1
+	GeneratorQuadratic2 = koch.GeneratorQuadratic2
+	GeneratorMinkowski  = koch.GeneratorMinkowski
+	GeneratorCesaro     = koch.GeneratorCesaro
+	GeneratorLevy       = koch.GeneratorLevy
+	GeneratorGosper     = koch.GeneratorGosper
+)
+
+const (
+	// DefaultCesaroAngle is the base angle of the Cesàro spike in degrees.
+	DefaultCesaroAngle = koch.DefaultCesaroAngle
+	// MaxTheoryErrorPct is the length error above which a theory check
+	// sample is reported as a deviation.
+	MaxTheoryErrorPct = koch.MaxTheoryErrorPct
+)
+
+// TheoryCheckReport compares the length of every iteration with L₀ × factorⁿ.
+type (
+	TheoryCheckReport = koch.TheoryCheckReport
+	TheoryCheckSample = koch.TheoryCheckSample
+)
+
+// OrganicOptions holds the seed and jitter set by the OrganicOption
+// functions; OrganicReport measures every iteration of the organic curve.
+type (
+	OrganicOptions = koch.OrganicOptions
+	OrganicReport  = koch.OrganicReport
+	OrganicSample  = koch.OrganicSample
+)
+
+// GeneratorNames lists the generators accepted by NewGenerator in report
+// order.
+func GeneratorNames() []string {
+	return append([]string(nil), koch.Generators...)
+}
+
+// NewGenerator builds a generator by name, ignoring case.
+func NewGenerator(name string, opts GeneratorOptions) (Generator, error) {
+	return koch.NewGenerator(name, opts)
+}
+
+// KochCurve replaces every segment of base with the Koch generator
+// iterations times. iterations is clamped to [0, MaxKochIterations].
+func KochCurve(base []LatLon, iterations int) []LatLon {
+	return koch.KochCurve(base, iterations)
+}
+
+// CheckTheoryConsistency compares every iteration of KochCurve up to
+// maxIterations with L₀ × (4/3)ⁿ.
+func CheckTheoryConsistency(base []LatLon, maxIterations int) TheoryCheckReport {
+	return koch.CheckTheoryConsistency(base, maxIterations)
+}
+
+// CheckGeneratorTheory compares every iteration of gen up to maxIterations
+// with L₀ × factorⁿ of the generator.
+func CheckGeneratorTheory(gen Generator, base []LatLon, maxIterations int) TheoryCheckReport {
+	return koch.CheckGeneratorTheory(gen, base, maxIterations)
+}
+
+// OrganicOption configures OrganicKochCurve and AnalyzeOrganic.
+type OrganicOption func(*OrganicOptions)
+
+// WithSeed fixes the jitter sequence; equal seeds give equal curves.
+func WithSeed(seed int64) OrganicOption {
+	return func(o *OrganicOptions) { o.Seed = seed }
+}
+
+// WithAngleJitter sets the peak angle jitter in degrees.
+func WithAngleJitter(degrees float64) OrganicOption {
+	return func(o *OrganicOptions) { o.AngleJitterDeg = degrees }
+}
+
+// WithHeightJitter sets the peak height jitter as a fraction, 0.1 for ±10%.
+func WithHeightJitter(fraction float64) OrganicOption {
+	return func(o *OrganicOptions) { o.HeightJitterPct = fraction }
+}
+
+func newOrganicOptions(opts []OrganicOption) OrganicOptions {
+	var o OrganicOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// OrganicKochCurve is KochCurve with seeded jitter of the peak angle and
+// height. Without options it matches KochCurve.
+func OrganicKochCurve(base []LatLon, iterations int, opts ...OrganicOption) []LatLon {
+	return koch.OrganicKochCurve(base, iterations, newOrganicOptions(opts))
+}
+
+// AnalyzeOrganic measures OrganicKochCurve for every iteration up to
+// maxIterations.
+func AnalyzeOrganic(base []LatLon, maxIterations int, opts ...OrganicOption) OrganicReport {
+	return koch.AnalyzeOrganic(base, maxIterations, newOrganicOptions(opts))
+}
@@ -0,0 +1,49 @@
+package fraes
+
+import (
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/geometry"
+)
+
+// Haversine is the great-circle distance between a and b in kilometers on
+// a sphere of radius 6371 km.
+func Haversine(a, b LatLon) float64 {
+	return geometry.Haversine(a, b)
+}
+
+// PolylineLength is the haversine length of the polyline in kilometers.
+func PolylineLength(points []LatLon) float64 {
+	return geometry.PolylineLength(points)
+}
+
+// Area is the shoelace area of the ring in square kilometers on a local
+// metric grid around the mean latitude. Open rings are closed implicitly.
+func Area(points []LatLon) float64 {
+	return geometry.Area(points)
+}
+
+// SimplifyOption configures SimplifyPolyline.
+type SimplifyOption func(*geometry.SimplifyOptions)
+
+// WithMaxPoints sets the point budget. Zero or a budget above the input size
+// leaves the polyline unchanged.
+func WithMaxPoints(maxPoints int) SimplifyOption {
+	return func(o *geometry.SimplifyOptions) { o.MaxPoints = maxPoints }
+}
+
+// SimplifyPolyline reduces the polyline with Ramer-Douglas-Peucker, searching
+// for the smallest tolerance that fits the point budget. Closed rings stay
+// closed. The input is never modified.
+func SimplifyPolyline(points []LatLon, opts ...SimplifyOption) SimplifyResult {
+	var o geometry.SimplifyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return geometry.SimplifyPolyline(points, o)
+}
+
+// AnalyzeBoxCounting estimates the box-counting dimension of the polyline.
+// The result is Valid only when enough scales fit the curve.
+func AnalyzeBoxCounting(points []LatLon) BoxCountingAnalysis {
+	return fractal.AnalyzeBoxCounting(points)
+}
@@ -0,0 +1,62 @@
+package fraes
+
+import (
+	"coastal-geometry/internal/domain/fractal"
+	"coastal-geometry/internal/domain/geometry"
+	"coastal-geometry/internal/domain/projection"
+)
+
+// Projector maps degrees to plane meters and back. One projector is meant to
+// be shared by simplification, box counting, erosion and rendering of a run.
+type Projector = projection.Projector
+
+// Names of the projections accepted by NewProjection.
+const (
+	ProjectionUTM         = projection.UTM
+	ProjectionLAEA        = projection.LAEA
+	ProjectionWebMercator = projection.WebMercator
+	DefaultProjection     = projection.Default
+)
+
+// ProjectionNames lists the projections accepted by NewProjection.
+func ProjectionNames() []string {
+	return projection.Names()
+}
+
+// NewProjection builds the UTM (zone of the centre), Lambert azimuthal
+// equal-area (centred on the data) or Web Mercator projection for the
+// bounding box of the point sets.
+func NewProjection(name string, sets ...[]LatLon) (Projector, error) {
+	return geometry.ProjectionFor(name, sets...)
+}
+
+// WithProjection measures the simplification tolerance in the plane of p.
+// Without it the default projection centred on the polyline is used.
+func WithProjection(p Projector) SimplifyOption {
+	return func(o *geometry.SimplifyOptions) { o.Projection = p }
+}
+
+// AnalyzeBoxCountingWith is AnalyzeBoxCounting with boxes laid out in the
+// plane of p; nil means the default projection centred on the polyline.
+func AnalyzeBoxCountingWith(points []LatLon, p Projector) BoxCountingAnalysis {
+	return fractal.AnalyzeBoxCountingWith(points, p)
+}
+
+// BootstrapBoxCountingWith resamples grid rotation, grid offset and
+// regression points around a valid analysis of the same points and returns
+// the 95% interval and standard error of D.
+func BootstrapBoxCountingWith(points []LatLon, p Projector, analysis BoxCountingAnalysis, replicates int, seed int64) ConfidenceInterval {
+	return fractal.BootstrapBoxCountingWith(points, p, analysis, replicates, seed)
+}
+
+// EstimateDimensionWith runs one of the Estimators in the plane of p and
+// reports it in the form shared by all of them.
+func EstimateDimensionWith(points []LatLon, p Projector, estimator string) (DimensionEstimate, error) {
+	return fractal.EstimateDimensionWith(points, p, estimator)
+}
+
+// AnalyzeMultifractalWith estimates D(q) and f(α) of the length measure of
+// the polyline for every q in qs.
+func AnalyzeMultifractalWith(points []LatLon, p Projector, qs []float64) MultifractalAnalysis {
+	return fractal.AnalyzeMultifractalWith(points, p, qs)
+}
@@ -0,0 +1,45 @@
+package fraes
+
+import "coastal-geometry/internal/domain/coastline"
+
+// CoastlineReport summarises a polyline: length with the chosen distance and
+// on the sphere, segment statistics, a sanity check of the length and a
+// sample of named key points.
+type (
+	CoastlineReport   = coastline.Report
+	SanityCheckResult = coastline.SanityCheckResult
+	KeyPoint          = coastline.KeyPoint
+)
+
+// ValidationSummary counts the warning types found in a polyline;
+// VisualizationHints lists the segments worth highlighting on a map.
+type (
+	ValidationSummary        = coastline.ValidationSummary
+	ValidationIssueSummary   = coastline.ValidationIssueSummary
+	DuplicateLocationSummary = coastline.DuplicateLocationSummary
+	VisualizationHints       = coastline.VisualizationHints
+	SegmentHighlight         = coastline.SegmentHighlight
+)
+
+// Warning types of ValidationIssueSummary.
+const (
+	WarningTypeLongSegment       = coastline.WarningTypeLongSegment
+	WarningTypeDuplicateLocation = coastline.WarningTypeDuplicateLocation
+)
+
+// BuildCoastlineReport measures points with d; nil means haversine.
+// dataset and source only label the report.
+func BuildCoastlineReport(points []LatLon, dataset, source string, d Distance) CoastlineReport {
+	return coastline.BuildReport(points, dataset, source, d)
+}
+
+// BuildValidationSummary counts suspiciously long segments and landmarks
+// matched by more than one point.
+func BuildValidationSummary(points []LatLon) ValidationSummary {
+	return coastline.BuildValidationSummary(points)
+}
+
+// BuildVisualizationHints finds the suspiciously long segments of points.
+func BuildVisualizationHints(points []LatLon) VisualizationHints {
+	return coastline.BuildVisualizationHints(points)
+}
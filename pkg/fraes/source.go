@@ -0,0 +1,95 @@
+package fraes
+
+import (
+	"net/http"
+
+	"coastal-geometry/internal/domain/coastline"
+)
+
+// SourceOption configures Load and InspectSource.
+type SourceOption func(*sourceOptions)
+
+type sourceOptions struct {
+	localPath    string
+	remoteURL    string
+	remoteBounds GeoBounds
+	cachePath    string
+	snapshotPath string
+	refresh      bool
+	httpClient   *http.Client
+}
+
+// WithLocalPath sets the JSON/GeoJSON, KML, GPX, WKT file or .shp shapefile
+// to read, or to fall back to when the remote source is unavailable. Defaults
+// to DefaultLocalPath.
+func WithLocalPath(path string) SourceOption {
+	return func(o *sourceOptions) { o.localPath = path }
+}
+
+// WithRemoteURL enables HTTP loading from url. The response is cached and
+// the local file is used when the request fails. An empty url keeps the
+// source offline.
+func WithRemoteURL(url string) SourceOption {
+	return func(o *sourceOptions) { o.remoteURL = url }
+}
+
+// WithRemoteBounds drops rings of the remote payload outside bounds.
+func WithRemoteBounds(bounds GeoBounds) SourceOption {
+	return func(o *sourceOptions) { o.remoteBounds = bounds }
+}
+
+// WithCachePath overrides the cache file of the remote payload.
+func WithCachePath(path string) SourceOption {
+	return func(o *sourceOptions) { o.cachePath = path }
+}
+
+// WithSnapshotPath sets the file or directory InspectSource saves the raw
+// payload to. Load ignores it.
+func WithSnapshotPath(path string) SourceOption {
+	return func(o *sourceOptions) { o.snapshotPath = path }
+}
+
+// WithRefresh bypasses the cache and downloads the remote source again.
+func WithRefresh(refresh bool) SourceOption {
+	return func(o *sourceOptions) { o.refresh = refresh }
+}
+
+// WithHTTPClient sets the client used for remote requests.
+func WithHTTPClient(client *http.Client) SourceOption {
+	return func(o *sourceOptions) { o.httpClient = client }
+}
+
+func newSourceOptions(opts []SourceOption) sourceOptions {
+	var o sourceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Load reads, validates and normalizes a coastline.
+func Load(opts ...SourceOption) (LoadResult, error) {
+	o := newSourceOptions(opts)
+	return coastline.Load(coastline.LoadOptions{
+		LocalPath:    o.localPath,
+		RemoteURL:    o.remoteURL,
+		RemoteBounds: o.remoteBounds,
+		CachePath:    o.cachePath,
+		Refresh:      o.refresh,
+		HTTPClient:   o.httpClient,
+	})
+}
+
+// InspectSource reads the source metadata and saves the raw payload as a
+// snapshot, by default under DefaultSnapshotDir.
+func InspectSource(opts ...SourceOption) (SourceInspection, error) {
+	o := newSourceOptions(opts)
+	return coastline.InspectSource(coastline.InspectOptions{
+		LocalPath:    o.localPath,
+		RemoteURL:    o.remoteURL,
+		CachePath:    o.cachePath,
+		SnapshotPath: o.snapshotPath,
+		Refresh:      o.refresh,
+		HTTPClient:   o.httpClient,
+	})
+}